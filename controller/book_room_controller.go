@@ -8,7 +8,7 @@ import (
 	"hotel_ip-p2/service"
 	"log"
 	"net/http"
-	"time"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 )
@@ -25,7 +25,7 @@ func NewBookRoomController(bookRoomService service.BookRoomService) *BookRoomCon
 
 // Create godoc
 // @Summary Book a room
-// @Description Create a new room booking
+// @Description Book a room for a stay from check-in up to (not including) check-out, charging every night at once. Pass the quote_token from POST /book-rooms/quote to be charged the quoted price, and promo_code to apply a discount.
 // @Tags bookings
 // @Accept json
 // @Produce json
@@ -34,6 +34,8 @@ func NewBookRoomController(bookRoomService service.BookRoomService) *BookRoomCon
 // @Success 201 {object} web.WebResponse{data=response.BookRoomResponse} "Room booked successfully"
 // @Failure 400 {object} web.WebResponse "Invalid request body or validation error"
 // @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Email address not verified"
+// @Router /book-rooms [post]
 func (controller *BookRoomController) Create(c echo.Context) error {
 	log.Println("Request to create new room booking")
 	var req request.BookRoomRequest
@@ -48,28 +50,17 @@ func (controller *BookRoomController) Create(c echo.Context) error {
 		return exception.NewCustomError(http.StatusBadRequest, err.Error())
 	}
 
-	bookingDate, err := time.Parse("2006-01-02", req.Date)
+	checkIn, checkOut, err := parseStayDates(req.CheckIn, req.CheckOut)
 	if err != nil {
-		log.Printf("Invalid date format: %v", err)
-		return exception.NewCustomError(http.StatusBadRequest, "Invalid date format, use YYYY-MM-DD")
-	}
-
-	today := time.Now().Truncate(24 * time.Hour)
-	if bookingDate.Before(today) {
-		log.Println("Booking date is in the past")
-		return exception.NewCustomError(http.StatusBadRequest, "Booking date must be today or in the future")
+		return err
 	}
 
 	userID := c.Get("user_id").(int)
 	log.Printf("Creating booking for user ID: %d", userID)
 
-	bookRoomDomain, err := mapper.ToBookRoomDomain(req, userID)
-	if err != nil {
-		log.Printf("Failed to map request to domain: %v", err)
-		return exception.NewCustomError(http.StatusBadRequest, "Invalid date format")
-	}
+	bookRoomDomain := mapper.ToBookRoomDomain(req, userID, checkIn, checkOut)
 
-	result, err := controller.BookRoomService.Create(bookRoomDomain)
+	result, err := controller.BookRoomService.Create(bookRoomDomain, mapper.ToBookingOptions(req))
 	if err != nil {
 		log.Printf("Failed to create room booking: %v", err)
 		return err
@@ -84,21 +75,81 @@ func (controller *BookRoomController) Create(c echo.Context) error {
 	})
 }
 
+// Quote godoc
+// @Summary Quote a booking
+// @Description Price a stay the way booking it would, without booking it, including the discount of an optional promo_code. The response itemizes every charge, says whether the wallet balance covers the total, and carries a short-lived quote_token that guarantees the price when passed to POST /book-rooms.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.BookRoomQuoteRequest true "Stay to quote"
+// @Success 200 {object} web.WebResponse{data=response.BookRoomQuoteResponse} "Quote created successfully"
+// @Failure 400 {object} web.WebResponse "Invalid request body or validation error"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 404 {object} web.WebResponse "Room not found"
+// @Failure 409 {object} web.WebResponse "Room is already booked"
+// @Router /book-rooms/quote [post]
+func (controller *BookRoomController) Quote(c echo.Context) error {
+	log.Println("Request to quote a room booking")
+	var req request.BookRoomQuoteRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	checkIn, checkOut, err := parseStayDates(req.CheckIn, req.CheckOut)
+	if err != nil {
+		return err
+	}
+
+	userID := c.Get("user_id").(int)
+	log.Printf("Quoting booking for user ID: %d", userID)
+
+	bookRoomDomain := mapper.ToBookRoomQuoteDomain(req, userID, checkIn, checkOut)
+
+	result, err := controller.BookRoomService.Quote(bookRoomDomain, mapper.ToBookingQuoteOptions(req))
+	if err != nil {
+		log.Printf("Failed to quote room booking: %v", err)
+		return err
+	}
+
+	log.Printf("Quoted %s for room ID: %d", result.Total(), result.RoomID)
+	quoteResponse := mapper.ToBookRoomQuoteResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Quote created successfully",
+		Data:    quoteResponse,
+	})
+}
+
 // FindByUserId godoc
 // @Summary Get my bookings
-// @Description Get all bookings for the authenticated user
+// @Description Get the stays booked by the authenticated user, newest check-in first, with each booking's current status: confirmed, checked_in, checked_out, cancelled or no_show. Pages are cursor based: pass meta.next_cursor as cursor to fetch the next page. Filter with filter[status] and filter[room_id].
 // @Tags bookings
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param per_page query int false "Items per page (max 100)"
 // @Success 200 {object} web.WebResponse{data=[]response.BookRoomResponse} "Bookings retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid query parameters"
 // @Failure 401 {object} web.WebResponse "Unauthorized"
 // @Router /book-rooms/my-bookings [get]
 func (controller *BookRoomController) FindByUserId(c echo.Context) error {
 	userID := c.Get("user_id").(int)
 	log.Printf("Request to retrieve bookings for user ID: %d", userID)
+	query, err := bindCursorQuery(c)
+	if err != nil {
+		return err
+	}
 
-	result, err := controller.BookRoomService.FindByUserId(userID)
+	result, next, err := controller.BookRoomService.FindByUserId(userID, query)
 	if err != nil {
 		log.Printf("Failed to retrieve bookings: %v", err)
 		return err
@@ -110,5 +161,164 @@ func (controller *BookRoomController) FindByUserId(c echo.Context) error {
 	return c.JSON(http.StatusOK, web.WebResponse{
 		Message: "Bookings retrieved successfully",
 		Data:    bookRoomResponses,
+		Meta:    cursorPageMeta(c, query.PerPage, next),
+	})
+}
+
+// Cancel godoc
+// @Summary Cancel a booking
+// @Description Cancel one of the authenticated user's bookings, release its nights and refund the wallet according to the cancellation policy
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} web.WebResponse{data=response.BookRoomResponse} "Booking cancelled successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID or booking cannot be cancelled"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 404 {object} web.WebResponse "Booking not found"
+// @Router /book-rooms/{id} [delete]
+func (controller *BookRoomController) Cancel(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid booking ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	userID := c.Get("user_id").(int)
+	log.Printf("Request to cancel booking ID: %d for user ID: %d", id, userID)
+
+	result, err := controller.BookRoomService.Cancel(id, userID)
+	if err != nil {
+		log.Printf("Failed to cancel booking: %v", err)
+		return err
+	}
+
+	log.Printf("Booking cancelled successfully with ID: %d, refunded: %s", result.ID, result.RefundAmount)
+	bookRoomResponse := mapper.ToBookRoomResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Booking cancelled successfully",
+		Data:    bookRoomResponse,
+	})
+}
+
+// CheckIn godoc
+// @Summary Check a guest in
+// @Description Mark a confirmed booking as checked in. Allowed from the arrival date until the day before check-out. Staff and admins only.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} web.WebResponse{data=response.BookRoomResponse} "Booking checked in successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID or outside the stay dates"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Booking not found"
+// @Failure 409 {object} web.WebResponse "Booking status does not allow this change"
+// @Router /book-rooms/{id}/check-in [post]
+func (controller *BookRoomController) CheckIn(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid booking ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	staffID := c.Get("user_id").(int)
+	log.Printf("Request to check in booking ID: %d by staff user ID: %d", id, staffID)
+
+	result, err := controller.BookRoomService.CheckIn(id, staffID)
+	if err != nil {
+		log.Printf("Failed to check in booking: %v", err)
+		return err
+	}
+
+	log.Printf("Booking checked in successfully with ID: %d", result.ID)
+	bookRoomResponse := mapper.ToBookRoomResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Booking checked in successfully",
+		Data:    bookRoomResponse,
+	})
+}
+
+// CheckOut godoc
+// @Summary Check a guest out
+// @Description Mark a checked-in booking as checked out. Nights from today on are released, so an early departure frees the room for new bookings. Staff and admins only.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} web.WebResponse{data=response.BookRoomResponse} "Booking checked out successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Booking not found"
+// @Failure 409 {object} web.WebResponse "Booking status does not allow this change"
+// @Router /book-rooms/{id}/check-out [post]
+func (controller *BookRoomController) CheckOut(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid booking ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	staffID := c.Get("user_id").(int)
+	log.Printf("Request to check out booking ID: %d by staff user ID: %d", id, staffID)
+
+	result, err := controller.BookRoomService.CheckOut(id, staffID)
+	if err != nil {
+		log.Printf("Failed to check out booking: %v", err)
+		return err
+	}
+
+	log.Printf("Booking checked out successfully with ID: %d", result.ID)
+	bookRoomResponse := mapper.ToBookRoomResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Booking checked out successfully",
+		Data:    bookRoomResponse,
+	})
+}
+
+// NoShow godoc
+// @Summary Mark a booking as no-show
+// @Description Close a confirmed booking whose guest never arrived. Allowed from the arrival date; the nights are released and the configured no-show share of the price is refunded. Bookings still unclaimed the day after arrival are marked automatically each night. Staff and admins only.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Success 200 {object} web.WebResponse{data=response.BookRoomResponse} "Booking marked as no-show successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID or before the arrival date"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Booking not found"
+// @Failure 409 {object} web.WebResponse "Booking status does not allow this change"
+// @Router /book-rooms/{id}/no-show [post]
+func (controller *BookRoomController) NoShow(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid booking ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	staffID := c.Get("user_id").(int)
+	log.Printf("Request to mark as no-show booking ID: %d by staff user ID: %d", id, staffID)
+
+	result, err := controller.BookRoomService.MarkNoShow(id, staffID)
+	if err != nil {
+		log.Printf("Failed to mark as no-show booking: %v", err)
+		return err
+	}
+
+	log.Printf("Booking marked as no-show successfully with ID: %d", result.ID)
+	bookRoomResponse := mapper.ToBookRoomResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Booking marked as no-show successfully",
+		Data:    bookRoomResponse,
 	})
 }
@@ -0,0 +1,228 @@
+package controller
+
+import (
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/mapper"
+	"hotel_ip-p2/model/web"
+	"hotel_ip-p2/model/web/request"
+	"hotel_ip-p2/service"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type FeeRuleController struct {
+	FeeRuleService service.FeeRuleService
+}
+
+func NewFeeRuleController(feeRuleService service.FeeRuleService) *FeeRuleController {
+	return &FeeRuleController{
+		FeeRuleService: feeRuleService,
+	}
+}
+
+// Create godoc
+// @Summary Create a fee rule
+// @Description Create a tax or service charge added to every new booking (admin only). Percent fees are charged on the room nights after discounts; fixed fees once per stay or per night. Inclusive fees are already part of the room price and are itemized without raising the total.
+// @Tags fee-rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.FeeRuleRequest true "Fee rule details"
+// @Success 201 {object} web.WebResponse{data=response.FeeRuleResponse} "Fee rule created successfully"
+// @Failure 400 {object} web.WebResponse "Invalid request body or validation error"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Router /admin/fee-rules [post]
+func (controller *FeeRuleController) Create(c echo.Context) error {
+	log.Println("Request to create new fee rule")
+	var req request.FeeRuleRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := controller.FeeRuleService.Create(mapper.ToFeeRuleDomain(req))
+	if err != nil {
+		log.Printf("Failed to create fee rule: %v", err)
+		return err
+	}
+
+	log.Printf("Fee rule created successfully with ID: %d", result.ID)
+	feeRuleResponse := mapper.ToFeeRuleResponse(result)
+
+	return c.JSON(http.StatusCreated, web.WebResponse{
+		Message: "Fee rule created successfully",
+		Data:    feeRuleResponse,
+	})
+}
+
+// FindAll godoc
+// @Summary List fee rules
+// @Description List fee rules a page at a time (admin only). Filter with filter[type]; sort by id, name or created_at, prefixed with - for descending order.
+// @Tags fee-rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Items per page (max 100)"
+// @Param sort query string false "Comma-separated sort fields, e.g. -created_at"
+// @Success 200 {object} web.WebResponse{data=[]response.FeeRuleResponse} "Fee rules retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid query parameters"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Router /admin/fee-rules [get]
+func (controller *FeeRuleController) FindAll(c echo.Context) error {
+	log.Println("Request to retrieve all fee rules")
+	query, err := bindListQuery(c)
+	if err != nil {
+		return err
+	}
+
+	result, total, err := controller.FeeRuleService.FindAll(query)
+	if err != nil {
+		log.Printf("Failed to retrieve fee rules: %v", err)
+		return err
+	}
+
+	log.Printf("Successfully retrieved %d of %d fee rules", len(result), total)
+	feeRuleResponses := mapper.ToFeeRuleResponses(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Fee rules retrieved successfully",
+		Data:    feeRuleResponses,
+		Meta:    offsetPageMeta(c, query.Pagination, total),
+	})
+}
+
+// FindById godoc
+// @Summary Get fee rule by ID
+// @Description Get a fee rule by its ID (admin only)
+// @Tags fee-rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Fee Rule ID"
+// @Success 200 {object} web.WebResponse{data=response.FeeRuleResponse} "Fee rule retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Fee rule not found"
+// @Router /admin/fee-rules/{id} [get]
+func (controller *FeeRuleController) FindById(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid fee rule ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to retrieve fee rule with ID: %d", id)
+	result, err := controller.FeeRuleService.FindById(id)
+	if err != nil {
+		log.Printf("Failed to retrieve fee rule: %v", err)
+		return err
+	}
+
+	log.Printf("Fee rule retrieved successfully with ID: %d", id)
+	feeRuleResponse := mapper.ToFeeRuleResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Fee rule retrieved successfully",
+		Data:    feeRuleResponse,
+	})
+}
+
+// Update godoc
+// @Summary Update a fee rule
+// @Description Replace a fee rule (admin only). Bookings already made keep the fees they were charged.
+// @Tags fee-rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Fee Rule ID"
+// @Param request body request.FeeRuleRequest true "Updated fee rule details"
+// @Success 200 {object} web.WebResponse{data=response.FeeRuleResponse} "Fee rule updated successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID, request body or validation error"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Fee rule not found"
+// @Router /admin/fee-rules/{id} [put]
+func (controller *FeeRuleController) Update(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid fee rule ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to update fee rule with ID: %d", id)
+	var req request.FeeRuleRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	feeRuleDomain := mapper.ToFeeRuleDomain(req)
+	feeRuleDomain.ID = id
+
+	result, err := controller.FeeRuleService.Update(feeRuleDomain)
+	if err != nil {
+		log.Printf("Failed to update fee rule: %v", err)
+		return err
+	}
+
+	log.Printf("Fee rule updated successfully with ID: %d", id)
+	feeRuleResponse := mapper.ToFeeRuleResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Fee rule updated successfully",
+		Data:    feeRuleResponse,
+	})
+}
+
+// Delete godoc
+// @Summary Delete a fee rule
+// @Description Delete a fee rule (admin only). Bookings already made keep the fees they were charged.
+// @Tags fee-rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Fee Rule ID"
+// @Success 200 {object} web.WebResponse "Fee rule deleted successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Fee rule not found"
+// @Router /admin/fee-rules/{id} [delete]
+func (controller *FeeRuleController) Delete(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid fee rule ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to delete fee rule with ID: %d", id)
+	err = controller.FeeRuleService.Delete(id)
+	if err != nil {
+		log.Printf("Failed to delete fee rule: %v", err)
+		return err
+	}
+
+	log.Printf("Fee rule deleted successfully with ID: %d", id)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Fee rule deleted successfully",
+	})
+}
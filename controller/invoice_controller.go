@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"fmt"
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/helper"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/service"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type InvoiceController struct {
+	InvoiceService service.InvoiceService
+}
+
+func NewInvoiceController(invoiceService service.InvoiceService) *InvoiceController {
+	return &InvoiceController{
+		InvoiceService: invoiceService,
+	}
+}
+
+// BookRoomInvoice godoc
+// @Summary Get a booking invoice
+// @Description Render the invoice of one of the authenticated user's bookings as PDF (default) or HTML. The invoice number is assigned the first time the invoice is requested and never changes. Admins and staff can fetch the invoice of any booking.
+// @Tags bookings
+// @Produce application/pdf
+// @Produce html
+// @Security BearerAuth
+// @Param id path int true "Booking ID"
+// @Param format query string false "pdf or html" Enums(pdf, html)
+// @Success 200 {file} file "Invoice document"
+// @Failure 400 {object} web.WebResponse "Invalid ID or format"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 404 {object} web.WebResponse "Booking not found"
+// @Router /book-rooms/{id}/invoice [get]
+func (controller *InvoiceController) BookRoomInvoice(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid booking ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	userID := c.Get("user_id").(int)
+	log.Printf("Request to retrieve invoice of booking ID: %d for user ID: %d", id, userID)
+
+	document, err := controller.InvoiceService.BookRoomInvoice(id, invoiceOwner(c))
+	if err != nil {
+		log.Printf("Failed to retrieve invoice: %v", err)
+		return err
+	}
+
+	log.Printf("Invoice %s retrieved successfully for booking ID: %d", document.Invoice.Number, id)
+	return renderInvoice(c, document)
+}
+
+// TopupReceipt godoc
+// @Summary Get a topup receipt
+// @Description Render the receipt of one of the authenticated user's paid topups as PDF (default) or HTML. The receipt number is assigned the first time the receipt is requested and never changes. Admins and staff can fetch the receipt of any topup.
+// @Tags topup
+// @Produce application/pdf
+// @Produce html
+// @Security BearerAuth
+// @Param id path int true "Topup ID"
+// @Param format query string false "pdf or html" Enums(pdf, html)
+// @Success 200 {file} file "Receipt document"
+// @Failure 400 {object} web.WebResponse "Invalid ID or format, or topup not paid"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 404 {object} web.WebResponse "Topup not found"
+// @Router /users/me/topups/{id}/receipt [get]
+func (controller *InvoiceController) TopupReceipt(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid topup ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	userID := c.Get("user_id").(int)
+	log.Printf("Request to retrieve receipt of topup ID: %d for user ID: %d", id, userID)
+
+	document, err := controller.InvoiceService.TopupReceipt(id, invoiceOwner(c))
+	if err != nil {
+		log.Printf("Failed to retrieve receipt: %v", err)
+		return err
+	}
+
+	log.Printf("Receipt %s retrieved successfully for topup ID: %d", document.Invoice.Number, id)
+	return renderInvoice(c, document)
+}
+
+// invoiceOwner returns the user whose documents the caller may read, or 0
+// for admins and staff, who may read everyone's so the front desk can hand
+// guests their invoices at check-out.
+func invoiceOwner(c echo.Context) int {
+	if role, _ := c.Get("role").(string); role == domain.RoleAdmin || role == domain.RoleStaff {
+		return 0
+	}
+	return c.Get("user_id").(int)
+}
+
+func renderInvoice(c echo.Context, document domain.InvoiceDocument) error {
+	switch c.QueryParam("format") {
+	case "", "pdf":
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", document.Invoice.Number+".pdf"))
+		return c.Blob(http.StatusOK, "application/pdf", helper.RenderInvoicePDF(document))
+	case "html":
+		page, err := helper.RenderInvoiceHTML(document)
+		if err != nil {
+			return err
+		}
+		return c.HTMLBlob(http.StatusOK, page)
+	default:
+		return exception.NewCustomError(http.StatusBadRequest, "Format must be pdf or html")
+	}
+}
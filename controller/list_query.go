@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/mapper"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/model/web"
+	"hotel_ip-p2/model/web/request"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// bindListQuery reads the page, per_page, sort and filter[name] query
+// parameters of an offset-paged list endpoint.
+func bindListQuery(c echo.Context) (domain.ListQuery, error) {
+	var req request.ListRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind query parameters: %v", err)
+		return domain.ListQuery{}, exception.NewCustomError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return domain.ListQuery{}, exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	return domain.ListQuery{
+		Pagination: domain.NewPagination(req.Page, req.PerPage),
+		Sort:       domain.ParseSort(req.Sort),
+		Filters:    bindFilters(c),
+	}, nil
+}
+
+// bindCursorQuery reads the cursor, per_page and filter[name] query
+// parameters of a cursor-paged list endpoint.
+func bindCursorQuery(c echo.Context) (domain.CursorQuery, error) {
+	var req request.CursorRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind query parameters: %v", err)
+		return domain.CursorQuery{}, exception.NewCustomError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return domain.CursorQuery{}, exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	query := domain.CursorQuery{
+		PerPage: domain.NewPagination(1, req.PerPage).PerPage,
+		Filters: bindFilters(c),
+	}
+
+	if req.Cursor != "" {
+		after, err := domain.DecodeCursor(req.Cursor)
+		if err != nil {
+			log.Printf("Failed to decode cursor: %v", err)
+			return domain.CursorQuery{}, exception.NewCustomError(http.StatusBadRequest, "Invalid cursor")
+		}
+		query.After = &after
+	}
+
+	return query, nil
+}
+
+func bindFilters(c echo.Context) map[string]string {
+	filters := map[string]string{}
+	for key, values := range c.QueryParams() {
+		if strings.HasPrefix(key, "filter[") && strings.HasSuffix(key, "]") && len(values) > 0 {
+			filters[key[len("filter["):len(key)-1]] = values[0]
+		}
+	}
+	return filters
+}
+
+// offsetPageMeta builds the metadata of an offset page, with links to the
+// first, last and neighbouring pages of the current request.
+func offsetPageMeta(c echo.Context, pagination domain.Pagination, total int64) *web.PageMeta {
+	meta := mapper.ToPageMeta(pagination, total)
+	lastPage := meta.TotalPages
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	meta.Links = &web.PageLinks{
+		Self:  pageLink(c, "page", strconv.Itoa(pagination.Page)),
+		First: pageLink(c, "page", "1"),
+		Last:  pageLink(c, "page", strconv.Itoa(lastPage)),
+	}
+	if pagination.Page > 1 {
+		meta.Links.Prev = pageLink(c, "page", strconv.Itoa(pagination.Page-1))
+	}
+	if pagination.Page < lastPage {
+		meta.Links.Next = pageLink(c, "page", strconv.Itoa(pagination.Page+1))
+	}
+	return meta
+}
+
+// cursorPageMeta builds the metadata of a cursor page, linking to the page
+// after it when there is one.
+func cursorPageMeta(c echo.Context, perPage int, next *domain.Cursor) *web.PageMeta {
+	meta := mapper.ToCursorPageMeta(perPage, next)
+	meta.Links = &web.PageLinks{
+		Self: c.Request().URL.RequestURI(),
+	}
+	if meta.NextCursor != "" {
+		meta.Links.Next = pageLink(c, "cursor", meta.NextCursor)
+	}
+	return meta
+}
+
+// pageLink returns the current request URI with one query parameter replaced.
+func pageLink(c echo.Context, key string, value string) string {
+	link := *c.Request().URL
+	query := link.Query()
+	query.Set(key, value)
+	link.RawQuery = query.Encode()
+	return link.RequestURI()
+}
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/mapper"
+	"hotel_ip-p2/model/web"
+	"hotel_ip-p2/model/web/request"
+	"hotel_ip-p2/service"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type MaintenanceBlockController struct {
+	MaintenanceBlockService service.MaintenanceBlockService
+}
+
+func NewMaintenanceBlockController(maintenanceBlockService service.MaintenanceBlockService) *MaintenanceBlockController {
+	return &MaintenanceBlockController{
+		MaintenanceBlockService: maintenanceBlockService,
+	}
+}
+
+// Create godoc
+// @Summary Take a room out of service
+// @Description Block a room for maintenance from start_date to end_date inclusive (admin or staff only). No night in that range can be booked and the room is left out of availability searches. A block overlapping existing bookings is refused unless force is set and relocations moves every one of those bookings to another free room.
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room ID"
+// @Param request body request.MaintenanceBlockRequest true "Maintenance block details"
+// @Success 201 {object} web.WebResponse{data=response.MaintenanceBlockResponse} "Maintenance block created successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID, request body, dates or relocation plan"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Room not found"
+// @Failure 409 {object} web.WebResponse "Room is booked during the maintenance period"
+// @Router /rooms/{id}/maintenance-blocks [post]
+func (controller *MaintenanceBlockController) Create(c echo.Context) error {
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid room ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	staffID := c.Get("user_id").(int)
+	log.Printf("Request to create maintenance block for room ID: %d by user ID: %d", roomID, staffID)
+	var req request.MaintenanceBlockRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	blockDomain, plan, err := mapper.ToMaintenanceBlockDomain(req)
+	if err != nil {
+		log.Printf("Failed to map request to domain: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid date format")
+	}
+	blockDomain.RoomID = roomID
+	blockDomain.CreatedBy = staffID
+
+	result, err := controller.MaintenanceBlockService.Create(blockDomain, plan)
+	if err != nil {
+		log.Printf("Failed to create maintenance block: %v", err)
+		return err
+	}
+
+	log.Printf("Maintenance block created successfully with ID: %d, relocated %d bookings", result.ID, len(result.Relocations))
+	blockResponse := mapper.ToMaintenanceBlockResponse(result)
+
+	return c.JSON(http.StatusCreated, web.WebResponse{
+		Message: "Maintenance block created successfully",
+		Data:    blockResponse,
+	})
+}
+
+// FindByRoomId godoc
+// @Summary List maintenance blocks of a room
+// @Description Get every maintenance block of a room, earliest first (admin or staff only)
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room ID"
+// @Success 200 {object} web.WebResponse{data=[]response.MaintenanceBlockResponse} "Maintenance blocks retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Room not found"
+// @Router /rooms/{id}/maintenance-blocks [get]
+func (controller *MaintenanceBlockController) FindByRoomId(c echo.Context) error {
+	roomID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid room ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to retrieve maintenance blocks for room ID: %d", roomID)
+	result, err := controller.MaintenanceBlockService.FindByRoomId(roomID)
+	if err != nil {
+		log.Printf("Failed to retrieve maintenance blocks: %v", err)
+		return err
+	}
+
+	log.Printf("Successfully retrieved %d maintenance blocks", len(result))
+	blockResponses := mapper.ToMaintenanceBlockResponses(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Maintenance blocks retrieved successfully",
+		Data:    blockResponses,
+	})
+}
+
+// FindById godoc
+// @Summary Get maintenance block by ID
+// @Description Get a maintenance block by its ID (admin or staff only)
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Maintenance Block ID"
+// @Success 200 {object} web.WebResponse{data=response.MaintenanceBlockResponse} "Maintenance block retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Maintenance block not found"
+// @Router /maintenance-blocks/{id} [get]
+func (controller *MaintenanceBlockController) FindById(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid maintenance block ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to retrieve maintenance block with ID: %d", id)
+	result, err := controller.MaintenanceBlockService.FindById(id)
+	if err != nil {
+		log.Printf("Failed to retrieve maintenance block: %v", err)
+		return err
+	}
+
+	log.Printf("Maintenance block retrieved successfully with ID: %d", id)
+	blockResponse := mapper.ToMaintenanceBlockResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Maintenance block retrieved successfully",
+		Data:    blockResponse,
+	})
+}
+
+// Delete godoc
+// @Summary Put a room back in service
+// @Description Delete a maintenance block by ID (admin or staff only). Bookings relocated for the block stay in their new rooms.
+// @Tags maintenance
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Maintenance Block ID"
+// @Success 200 {object} web.WebResponse "Maintenance block deleted successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Maintenance block not found"
+// @Router /maintenance-blocks/{id} [delete]
+func (controller *MaintenanceBlockController) Delete(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid maintenance block ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to delete maintenance block with ID: %d", id)
+	err = controller.MaintenanceBlockService.Delete(id)
+	if err != nil {
+		log.Printf("Failed to delete maintenance block: %v", err)
+		return err
+	}
+
+	log.Printf("Maintenance block deleted successfully with ID: %d", id)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Maintenance block deleted successfully",
+	})
+}
@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/mapper"
+	"hotel_ip-p2/model/web"
+	"hotel_ip-p2/model/web/request"
+	"hotel_ip-p2/service"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PromoCodeController struct {
+	PromoCodeService service.PromoCodeService
+}
+
+func NewPromoCodeController(promoCodeService service.PromoCodeService) *PromoCodeController {
+	return &PromoCodeController{
+		PromoCodeService: promoCodeService,
+	}
+}
+
+// Create godoc
+// @Summary Create a promo code
+// @Description Create a percent or fixed discount code (admin only). Codes are case-insensitive. Leave room_type_ids empty to allow every room type, and set max_redemptions or max_redemptions_per_user to 0 for no limit.
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.PromoCodeRequest true "Promo code details"
+// @Success 201 {object} web.WebResponse{data=response.PromoCodeResponse} "Promo code created successfully"
+// @Failure 400 {object} web.WebResponse "Invalid request body or validation error"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Room type not found"
+// @Router /admin/promo-codes [post]
+func (controller *PromoCodeController) Create(c echo.Context) error {
+	log.Println("Request to create new promo code")
+	var req request.PromoCodeRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := controller.PromoCodeService.Create(mapper.ToPromoCodeDomain(req))
+	if err != nil {
+		log.Printf("Failed to create promo code: %v", err)
+		return err
+	}
+
+	log.Printf("Promo code created successfully with ID: %d", result.ID)
+	promoCodeResponse := mapper.ToPromoCodeResponse(result)
+
+	return c.JSON(http.StatusCreated, web.WebResponse{
+		Message: "Promo code created successfully",
+		Data:    promoCodeResponse,
+	})
+}
+
+// FindAll godoc
+// @Summary List promo codes
+// @Description List promo codes a page at a time (admin only). Filter with filter[code] and filter[discount_type]; sort by id, code, valid_until or created_at, prefixed with - for descending order.
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Items per page (max 100)"
+// @Param sort query string false "Comma-separated sort fields, e.g. -created_at"
+// @Success 200 {object} web.WebResponse{data=[]response.PromoCodeResponse} "Promo codes retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid query parameters"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Router /admin/promo-codes [get]
+func (controller *PromoCodeController) FindAll(c echo.Context) error {
+	log.Println("Request to retrieve all promo codes")
+	query, err := bindListQuery(c)
+	if err != nil {
+		return err
+	}
+
+	result, total, err := controller.PromoCodeService.FindAll(query)
+	if err != nil {
+		log.Printf("Failed to retrieve promo codes: %v", err)
+		return err
+	}
+
+	log.Printf("Successfully retrieved %d of %d promo codes", len(result), total)
+	promoCodeResponses := mapper.ToPromoCodeResponses(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Promo codes retrieved successfully",
+		Data:    promoCodeResponses,
+		Meta:    offsetPageMeta(c, query.Pagination, total),
+	})
+}
+
+// FindById godoc
+// @Summary Get promo code by ID
+// @Description Get a promo code by its ID (admin only)
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promo Code ID"
+// @Success 200 {object} web.WebResponse{data=response.PromoCodeResponse} "Promo code retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Promo code not found"
+// @Router /admin/promo-codes/{id} [get]
+func (controller *PromoCodeController) FindById(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid promo code ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to retrieve promo code with ID: %d", id)
+	result, err := controller.PromoCodeService.FindById(id)
+	if err != nil {
+		log.Printf("Failed to retrieve promo code: %v", err)
+		return err
+	}
+
+	log.Printf("Promo code retrieved successfully with ID: %d", id)
+	promoCodeResponse := mapper.ToPromoCodeResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Promo code retrieved successfully",
+		Data:    promoCodeResponse,
+	})
+}
+
+// Update godoc
+// @Summary Update a promo code
+// @Description Replace the terms of a promo code (admin only). Bookings that already used it keep their discount.
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promo Code ID"
+// @Param request body request.PromoCodeRequest true "Updated promo code details"
+// @Success 200 {object} web.WebResponse{data=response.PromoCodeResponse} "Promo code updated successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID, request body or validation error"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Promo code or room type not found"
+// @Router /admin/promo-codes/{id} [put]
+func (controller *PromoCodeController) Update(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid promo code ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to update promo code with ID: %d", id)
+	var req request.PromoCodeRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	promoCodeDomain := mapper.ToPromoCodeDomain(req)
+	promoCodeDomain.ID = id
+
+	result, err := controller.PromoCodeService.Update(promoCodeDomain)
+	if err != nil {
+		log.Printf("Failed to update promo code: %v", err)
+		return err
+	}
+
+	log.Printf("Promo code updated successfully with ID: %d", id)
+	promoCodeResponse := mapper.ToPromoCodeResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Promo code updated successfully",
+		Data:    promoCodeResponse,
+	})
+}
+
+// Delete godoc
+// @Summary Delete a promo code
+// @Description Delete a promo code that has never been redeemed (admin only). Deactivate redeemed codes instead.
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promo Code ID"
+// @Success 200 {object} web.WebResponse "Promo code deleted successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID or promo code already redeemed"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Promo code not found"
+// @Router /admin/promo-codes/{id} [delete]
+func (controller *PromoCodeController) Delete(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid promo code ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to delete promo code with ID: %d", id)
+	err = controller.PromoCodeService.Delete(id)
+	if err != nil {
+		log.Printf("Failed to delete promo code: %v", err)
+		return err
+	}
+
+	log.Printf("Promo code deleted successfully with ID: %d", id)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Promo code deleted successfully",
+	})
+}
@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/mapper"
+	"hotel_ip-p2/model/web"
+	"hotel_ip-p2/model/web/request"
+	"hotel_ip-p2/service"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RatePlanController struct {
+	RatePlanService service.RatePlanService
+}
+
+func NewRatePlanController(ratePlanService service.RatePlanService) *RatePlanController {
+	return &RatePlanController{
+		RatePlanService: ratePlanService,
+	}
+}
+
+// Create godoc
+// @Summary Create a rate plan
+// @Description Add a price override to a room type (admin only). The plan applies to the nights from start_date to end_date inclusive and on the listed weekdays (0 = Sunday); leave either out to not restrict on it. When several plans cover a night, the highest priority wins.
+// @Tags rate-plans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room Type ID"
+// @Param request body request.RatePlanRequest true "Rate plan details"
+// @Success 201 {object} web.WebResponse{data=response.RatePlanResponse} "Rate plan created successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID, request body or validation error"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Room type not found"
+// @Router /admin/room-types/{id}/rate-plans [post]
+func (controller *RatePlanController) Create(c echo.Context) error {
+	roomTypeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid room type ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to create rate plan for room type ID: %d", roomTypeID)
+	var req request.RatePlanRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	ratePlanDomain, err := mapper.ToRatePlanDomain(req)
+	if err != nil {
+		log.Printf("Failed to map request to domain: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid date format")
+	}
+	ratePlanDomain.RoomTypeID = roomTypeID
+
+	result, err := controller.RatePlanService.Create(ratePlanDomain)
+	if err != nil {
+		log.Printf("Failed to create rate plan: %v", err)
+		return err
+	}
+
+	log.Printf("Rate plan created successfully with ID: %d", result.ID)
+	ratePlanResponse := mapper.ToRatePlanResponse(result)
+
+	return c.JSON(http.StatusCreated, web.WebResponse{
+		Message: "Rate plan created successfully",
+		Data:    ratePlanResponse,
+	})
+}
+
+// FindByRoomTypeId godoc
+// @Summary List rate plans of a room type
+// @Description Get every rate plan of a room type, highest priority first (admin only)
+// @Tags rate-plans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room Type ID"
+// @Success 200 {object} web.WebResponse{data=[]response.RatePlanResponse} "Rate plans retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Room type not found"
+// @Router /admin/room-types/{id}/rate-plans [get]
+func (controller *RatePlanController) FindByRoomTypeId(c echo.Context) error {
+	roomTypeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid room type ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to retrieve rate plans for room type ID: %d", roomTypeID)
+	result, err := controller.RatePlanService.FindByRoomTypeId(roomTypeID)
+	if err != nil {
+		log.Printf("Failed to retrieve rate plans: %v", err)
+		return err
+	}
+
+	log.Printf("Successfully retrieved %d rate plans", len(result))
+	ratePlanResponses := mapper.ToRatePlanResponses(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Rate plans retrieved successfully",
+		Data:    ratePlanResponses,
+	})
+}
+
+// FindById godoc
+// @Summary Get rate plan by ID
+// @Description Get a rate plan by its ID (admin only)
+// @Tags rate-plans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Rate Plan ID"
+// @Success 200 {object} web.WebResponse{data=response.RatePlanResponse} "Rate plan retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Rate plan not found"
+// @Router /admin/rate-plans/{id} [get]
+func (controller *RatePlanController) FindById(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid rate plan ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to retrieve rate plan with ID: %d", id)
+	result, err := controller.RatePlanService.FindById(id)
+	if err != nil {
+		log.Printf("Failed to retrieve rate plan: %v", err)
+		return err
+	}
+
+	log.Printf("Rate plan retrieved successfully with ID: %d", id)
+	ratePlanResponse := mapper.ToRatePlanResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Rate plan retrieved successfully",
+		Data:    ratePlanResponse,
+	})
+}
+
+// Update godoc
+// @Summary Update a rate plan
+// @Description Replace the dates, weekdays, price and priority of a rate plan (admin only)
+// @Tags rate-plans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Rate Plan ID"
+// @Param request body request.RatePlanRequest true "Updated rate plan details"
+// @Success 200 {object} web.WebResponse{data=response.RatePlanResponse} "Rate plan updated successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID, request body or validation error"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Rate plan not found"
+// @Router /admin/rate-plans/{id} [put]
+func (controller *RatePlanController) Update(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid rate plan ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to update rate plan with ID: %d", id)
+	var req request.RatePlanRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	ratePlanDomain, err := mapper.ToRatePlanDomain(req)
+	if err != nil {
+		log.Printf("Failed to map request to domain: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid date format")
+	}
+	ratePlanDomain.ID = id
+
+	result, err := controller.RatePlanService.Update(ratePlanDomain)
+	if err != nil {
+		log.Printf("Failed to update rate plan: %v", err)
+		return err
+	}
+
+	log.Printf("Rate plan updated successfully with ID: %d", id)
+	ratePlanResponse := mapper.ToRatePlanResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Rate plan updated successfully",
+		Data:    ratePlanResponse,
+	})
+}
+
+// Delete godoc
+// @Summary Delete a rate plan
+// @Description Delete a rate plan by ID (admin only). Bookings already made keep the price they were charged.
+// @Tags rate-plans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Rate Plan ID"
+// @Success 200 {object} web.WebResponse "Rate plan deleted successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Rate plan not found"
+// @Router /admin/rate-plans/{id} [delete]
+func (controller *RatePlanController) Delete(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid rate plan ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to delete rate plan with ID: %d", id)
+	err = controller.RatePlanService.Delete(id)
+	if err != nil {
+		log.Printf("Failed to delete rate plan: %v", err)
+		return err
+	}
+
+	log.Printf("Rate plan deleted successfully with ID: %d", id)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Rate plan deleted successfully",
+	})
+}
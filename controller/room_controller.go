@@ -25,7 +25,7 @@ func NewRoomController(roomService service.RoomService) *RoomController {
 
 // Create godoc
 // @Summary Create a new room
-// @Description Create a new room
+// @Description Create a new room (admin or staff only)
 // @Tags rooms
 // @Accept json
 // @Produce json
@@ -34,6 +34,8 @@ func NewRoomController(roomService service.RoomService) *RoomController {
 // @Success 201 {object} web.WebResponse{data=response.RoomResponse} "Room created successfully"
 // @Failure 400 {object} web.WebResponse "Invalid request body or validation error"
 // @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Router /rooms [post]
 func (controller *RoomController) Create(c echo.Context) error {
 	log.Println("Request to create new room")
 	var req request.RoomRequest
@@ -67,28 +69,38 @@ func (controller *RoomController) Create(c echo.Context) error {
 
 // FindAll godoc
 // @Summary Get all rooms
-// @Description Get a list of all rooms
+// @Description Get a page of rooms. Filter with filter[room_type_id] and filter[room_number]; sort by id, room_number, room_type_id or created_at, prefixed with - for descending order.
 // @Tags rooms
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Items per page (max 100)"
+// @Param sort query string false "Comma-separated sort fields, e.g. room_type_id,-room_number"
 // @Success 200 {object} web.WebResponse{data=[]response.RoomResponse} "Rooms retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid query parameters"
 // @Failure 401 {object} web.WebResponse "Unauthorized"
 // @Router /rooms [get]
 func (controller *RoomController) FindAll(c echo.Context) error {
 	log.Println("Request to retrieve all rooms")
-	result, err := controller.RoomService.FindAll()
+	query, err := bindListQuery(c)
+	if err != nil {
+		return err
+	}
+
+	result, total, err := controller.RoomService.FindAll(query)
 	if err != nil {
 		log.Printf("Failed to retrieve rooms: %v", err)
 		return err
 	}
 
-	log.Printf("Successfully retrieved %d rooms", len(result))
+	log.Printf("Successfully retrieved %d of %d rooms", len(result), total)
 	roomResponses := mapper.ToRoomResponses(result)
 
 	return c.JSON(http.StatusOK, web.WebResponse{
 		Message: "Rooms retrieved successfully",
 		Data:    roomResponses,
+		Meta:    offsetPageMeta(c, query.Pagination, total),
 	})
 }
 
@@ -134,7 +146,7 @@ func (controller *RoomController) FindById(c echo.Context) error {
 
 // Update godoc
 // @Summary Update a room
-// @Description Update an existing room by ID
+// @Description Update an existing room by ID (admin or staff only)
 // @Tags rooms
 // @Accept json
 // @Produce json
@@ -144,6 +156,7 @@ func (controller *RoomController) FindById(c echo.Context) error {
 // @Success 200 {object} web.WebResponse{data=response.RoomResponse} "Room updated successfully"
 // @Failure 400 {object} web.WebResponse "Invalid ID or request body"
 // @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
 // @Failure 404 {object} web.WebResponse "Room not found"
 // @Router /rooms/{id} [put]
 func (controller *RoomController) Update(c echo.Context) error {
@@ -186,7 +199,7 @@ func (controller *RoomController) Update(c echo.Context) error {
 
 // Delete godoc
 // @Summary Delete a room
-// @Description Delete a room by ID
+// @Description Delete a room by ID (admin or staff only). The room is archived rather than removed, so past bookings keep showing it; an admin can restore it. A room booked for tonight or later cannot be deleted.
 // @Tags rooms
 // @Accept json
 // @Produce json
@@ -195,7 +208,9 @@ func (controller *RoomController) Update(c echo.Context) error {
 // @Success 200 {object} web.WebResponse "Room deleted successfully"
 // @Failure 400 {object} web.WebResponse "Invalid ID"
 // @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
 // @Failure 404 {object} web.WebResponse "Room not found"
+// @Failure 409 {object} web.WebResponse "Room has upcoming bookings"
 // @Router /rooms/{id} [delete]
 func (controller *RoomController) Delete(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -216,3 +231,128 @@ func (controller *RoomController) Delete(c echo.Context) error {
 		Message: "Room deleted successfully",
 	})
 }
+
+// FindAvailable godoc
+// @Summary Search room availability
+// @Description List rooms that are free and in service for every night between check-in and check-out, grouped by room type with the price of the stay
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param check_in query string true "Check-in date (YYYY-MM-DD)"
+// @Param check_out query string true "Check-out date (YYYY-MM-DD)"
+// @Param room_type_id query int false "Only search this room type"
+// @Success 200 {object} web.WebResponse{data=[]response.RoomAvailabilityResponse} "Availability retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid query parameters"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 404 {object} web.WebResponse "Room type not found"
+// @Router /rooms/availability [get]
+func (controller *RoomController) FindAvailable(c echo.Context) error {
+	log.Println("Request to search room availability")
+	var req request.RoomAvailabilityRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind query parameters: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	checkIn, checkOut, err := parseStayDates(req.CheckIn, req.CheckOut)
+	if err != nil {
+		return err
+	}
+
+	result, err := controller.RoomService.FindAvailable(checkIn, checkOut, req.RoomTypeID)
+	if err != nil {
+		log.Printf("Failed to search room availability: %v", err)
+		return err
+	}
+
+	log.Printf("Found %d room types with availability", len(result))
+	availabilityResponses := mapper.ToRoomAvailabilityResponses(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Availability retrieved successfully",
+		Data:    availabilityResponses,
+	})
+}
+
+// FindDeleted godoc
+// @Summary List deleted rooms
+// @Description Get a page of deleted rooms (admin only). Accepts the same filters and sort fields as the room list.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Items per page (max 100)"
+// @Param sort query string false "Comma-separated sort fields, e.g. room_number"
+// @Success 200 {object} web.WebResponse{data=[]response.RoomResponse} "Deleted rooms retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid query parameters"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Router /admin/rooms/deleted [get]
+func (controller *RoomController) FindDeleted(c echo.Context) error {
+	log.Println("Request to retrieve deleted rooms")
+	query, err := bindListQuery(c)
+	if err != nil {
+		return err
+	}
+
+	result, total, err := controller.RoomService.FindDeleted(query)
+	if err != nil {
+		log.Printf("Failed to retrieve deleted rooms: %v", err)
+		return err
+	}
+
+	log.Printf("Successfully retrieved %d of %d deleted rooms", len(result), total)
+	roomResponses := mapper.ToRoomResponses(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Deleted rooms retrieved successfully",
+		Data:    roomResponses,
+		Meta:    offsetPageMeta(c, query.Pagination, total),
+	})
+}
+
+// Restore godoc
+// @Summary Restore a deleted room
+// @Description Put a deleted room back in use (admin only). Its room type must not be deleted and its room number must still be free.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room ID"
+// @Success 200 {object} web.WebResponse{data=response.RoomResponse} "Room restored successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID or room type is deleted"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Deleted room not found"
+// @Failure 409 {object} web.WebResponse "Room number already exists"
+// @Router /admin/rooms/{id}/restore [post]
+func (controller *RoomController) Restore(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid room ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to restore room with ID: %d", id)
+	result, err := controller.RoomService.Restore(id)
+	if err != nil {
+		log.Printf("Failed to restore room: %v", err)
+		return err
+	}
+
+	log.Printf("Room restored successfully with ID: %d", id)
+	roomResponse := mapper.ToRoomResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Room restored successfully",
+		Data:    roomResponse,
+	})
+}
@@ -25,7 +25,7 @@ func NewRoomTypeController(roomTypeService service.RoomTypeService) *RoomTypeCon
 
 // Create godoc
 // @Summary Create a new room type
-// @Description Create a new room type
+// @Description Create a new room type (admin or staff only)
 // @Tags room-types
 // @Accept json
 // @Produce json
@@ -34,6 +34,8 @@ func NewRoomTypeController(roomTypeService service.RoomTypeService) *RoomTypeCon
 // @Success 201 {object} web.WebResponse{data=response.RoomTypeResponse} "Room type created successfully"
 // @Failure 400 {object} web.WebResponse "Invalid request body or validation error"
 // @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Router /room-types [post]
 func (controller *RoomTypeController) Create(c echo.Context) error {
 	log.Println("Request to create new room type")
 	var req request.RoomTypeRequest
@@ -67,28 +69,38 @@ func (controller *RoomTypeController) Create(c echo.Context) error {
 
 // FindAll godoc
 // @Summary Get all room types
-// @Description Get a list of all room types
+// @Description Get a page of room types. Filter with filter[name], filter[min_price] and filter[max_price]; sort by id, name, price or created_at, prefixed with - for descending order.
 // @Tags room-types
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Items per page (max 100)"
+// @Param sort query string false "Comma-separated sort fields, e.g. -price,name"
 // @Success 200 {object} web.WebResponse{data=[]response.RoomTypeResponse} "Room types retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid query parameters"
 // @Failure 401 {object} web.WebResponse "Unauthorized"
 // @Router /room-types [get]
 func (controller *RoomTypeController) FindAll(c echo.Context) error {
 	log.Println("Request to retrieve all room types")
-	result, err := controller.RoomTypeService.FindAll()
+	query, err := bindListQuery(c)
+	if err != nil {
+		return err
+	}
+
+	result, total, err := controller.RoomTypeService.FindAll(query)
 	if err != nil {
 		log.Printf("Failed to retrieve room types: %v", err)
 		return err
 	}
 
-	log.Printf("Successfully retrieved %d room types", len(result))
+	log.Printf("Successfully retrieved %d of %d room types", len(result), total)
 	roomTypeResponses := mapper.ToRoomTypeResponses(result)
 
 	return c.JSON(http.StatusOK, web.WebResponse{
 		Message: "Room types retrieved successfully",
 		Data:    roomTypeResponses,
+		Meta:    offsetPageMeta(c, query.Pagination, total),
 	})
 }
 
@@ -134,7 +146,7 @@ func (controller *RoomTypeController) FindById(c echo.Context) error {
 
 // Update godoc
 // @Summary Update a room type
-// @Description Update an existing room type by ID
+// @Description Update an existing room type by ID (admin or staff only)
 // @Tags room-types
 // @Accept json
 // @Produce json
@@ -144,6 +156,7 @@ func (controller *RoomTypeController) FindById(c echo.Context) error {
 // @Success 200 {object} web.WebResponse{data=response.RoomTypeResponse} "Room type updated successfully"
 // @Failure 400 {object} web.WebResponse "Invalid ID or request body"
 // @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
 // @Failure 404 {object} web.WebResponse "Room type not found"
 // @Router /room-types/{id} [put]
 func (controller *RoomTypeController) Update(c echo.Context) error {
@@ -186,7 +199,7 @@ func (controller *RoomTypeController) Update(c echo.Context) error {
 
 // Delete godoc
 // @Summary Delete a room type
-// @Description Delete a room type by ID
+// @Description Delete a room type by ID (admin or staff only). The room type is archived rather than removed and an admin can restore it. A room type still used by rooms cannot be deleted.
 // @Tags room-types
 // @Accept json
 // @Produce json
@@ -195,6 +208,7 @@ func (controller *RoomTypeController) Update(c echo.Context) error {
 // @Success 200 {object} web.WebResponse "Room type deleted successfully"
 // @Failure 400 {object} web.WebResponse "Invalid ID"
 // @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
 // @Failure 404 {object} web.WebResponse "Room type not found"
 // @Router /room-types/{id} [delete]
 func (controller *RoomTypeController) Delete(c echo.Context) error {
@@ -216,3 +230,134 @@ func (controller *RoomTypeController) Delete(c echo.Context) error {
 		Message: "Room type deleted successfully",
 	})
 }
+
+// Quote godoc
+// @Summary Quote the price of a stay
+// @Description Price each night of a stay in a room type from its rate plans, falling back to the room type price on nights no plan covers
+// @Tags room-types
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room Type ID"
+// @Param check_in query string true "Check-in date (YYYY-MM-DD)"
+// @Param check_out query string true "Check-out date (YYYY-MM-DD)"
+// @Success 200 {object} web.WebResponse{data=response.RateQuoteResponse} "Quote retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID or query parameters"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 404 {object} web.WebResponse "Room type not found"
+// @Router /room-types/{id}/quote [get]
+func (controller *RoomTypeController) Quote(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid room type ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to quote a stay in room type ID: %d", id)
+	var req request.RateQuoteRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind query parameters: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	checkIn, checkOut, err := parseStayDates(req.CheckIn, req.CheckOut)
+	if err != nil {
+		return err
+	}
+
+	result, err := controller.RoomTypeService.Quote(id, checkIn, checkOut)
+	if err != nil {
+		log.Printf("Failed to quote stay: %v", err)
+		return err
+	}
+
+	log.Printf("Quoted %d nights in room type ID: %d", len(result.Nights), id)
+	quoteResponse := mapper.ToRateQuoteResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Quote retrieved successfully",
+		Data:    quoteResponse,
+	})
+}
+
+// FindDeleted godoc
+// @Summary List deleted room types
+// @Description Get a page of deleted room types (admin only). Accepts the same filters and sort fields as the room type list.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Items per page (max 100)"
+// @Param sort query string false "Comma-separated sort fields, e.g. name"
+// @Success 200 {object} web.WebResponse{data=[]response.RoomTypeResponse} "Deleted room types retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid query parameters"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Router /admin/room-types/deleted [get]
+func (controller *RoomTypeController) FindDeleted(c echo.Context) error {
+	log.Println("Request to retrieve deleted room types")
+	query, err := bindListQuery(c)
+	if err != nil {
+		return err
+	}
+
+	result, total, err := controller.RoomTypeService.FindDeleted(query)
+	if err != nil {
+		log.Printf("Failed to retrieve deleted room types: %v", err)
+		return err
+	}
+
+	log.Printf("Successfully retrieved %d of %d deleted room types", len(result), total)
+	roomTypeResponses := mapper.ToRoomTypeResponses(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Deleted room types retrieved successfully",
+		Data:    roomTypeResponses,
+		Meta:    offsetPageMeta(c, query.Pagination, total),
+	})
+}
+
+// Restore godoc
+// @Summary Restore a deleted room type
+// @Description Put a deleted room type back in use (admin only). Its name must still be free. Rooms deleted along with it are restored separately.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Room Type ID"
+// @Success 200 {object} web.WebResponse{data=response.RoomTypeResponse} "Room type restored successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "Deleted room type not found"
+// @Failure 409 {object} web.WebResponse "Room type name already exists"
+// @Router /admin/room-types/{id}/restore [post]
+func (controller *RoomTypeController) Restore(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid room type ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to restore room type with ID: %d", id)
+	result, err := controller.RoomTypeService.Restore(id)
+	if err != nil {
+		log.Printf("Failed to restore room type: %v", err)
+		return err
+	}
+
+	log.Printf("Room type restored successfully with ID: %d", id)
+	roomTypeResponse := mapper.ToRoomTypeResponse(result)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Room type restored successfully",
+		Data:    roomTypeResponse,
+	})
+}
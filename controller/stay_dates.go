@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"fmt"
+	"hotel_ip-p2/exception"
+	"log"
+	"net/http"
+	"time"
+)
+
+// maxStayNights caps a single stay so one request cannot reserve or search a
+// room for an unbounded number of nights.
+const maxStayNights = 30
+
+// parseStayDates parses and validates a check-in/check-out pair given as
+// YYYY-MM-DD strings.
+func parseStayDates(checkInValue string, checkOutValue string) (time.Time, time.Time, error) {
+	checkIn, err := time.Parse("2006-01-02", checkInValue)
+	if err != nil {
+		log.Printf("Invalid check-in date format: %v", err)
+		return time.Time{}, time.Time{}, exception.NewCustomError(http.StatusBadRequest, "Invalid check-in date format, use YYYY-MM-DD")
+	}
+
+	checkOut, err := time.Parse("2006-01-02", checkOutValue)
+	if err != nil {
+		log.Printf("Invalid check-out date format: %v", err)
+		return time.Time{}, time.Time{}, exception.NewCustomError(http.StatusBadRequest, "Invalid check-out date format, use YYYY-MM-DD")
+	}
+
+	// The parsed dates are midnight UTC; compare them with the local calendar
+	// day, which is what a stay night is everywhere else.
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if checkIn.Before(today) {
+		log.Println("Check-in date is in the past")
+		return time.Time{}, time.Time{}, exception.NewCustomError(http.StatusBadRequest, "Check-in date must be today or in the future")
+	}
+
+	if !checkOut.After(checkIn) {
+		log.Println("Check-out date is not after check-in date")
+		return time.Time{}, time.Time{}, exception.NewCustomError(http.StatusBadRequest, "Check-out date must be after check-in date")
+	}
+
+	if checkOut.Sub(checkIn) > maxStayNights*24*time.Hour {
+		log.Printf("Stay exceeds %d nights", maxStayNights)
+		return time.Time{}, time.Time{}, exception.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Stay cannot exceed %d nights", maxStayNights))
+	}
+
+	return checkIn, checkOut, nil
+}
@@ -1,12 +1,14 @@
 package controller
 
 import (
+	"encoding/json"
 	"hotel_ip-p2/exception"
 	"hotel_ip-p2/helper"
 	"hotel_ip-p2/mapper"
 	"hotel_ip-p2/model/web"
 	"hotel_ip-p2/model/web/request"
 	"hotel_ip-p2/service"
+	"io"
 	"log"
 	"net/http"
 
@@ -23,21 +25,76 @@ func NewTopupController(topupService service.TopupService) *TopupController {
 	}
 }
 
+// Create godoc
+// @Summary Start a topup
+// @Description Create a pending topup for the current user and open a Midtrans Snap payment for it. The amount is a decimal string or number of whole rupiah, at least 10000. The balance is credited once Midtrans confirms the payment through the webhook.
+// @Tags topup
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.TopupRequest true "Topup amount"
+// @Success 201 {object} web.WebResponse{data=response.TopupResponse} "Topup created successfully"
+// @Failure 400 {object} web.WebResponse "Invalid request body"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Email address not verified"
+// @Failure 404 {object} web.WebResponse "User not found"
+// @Failure 502 {object} web.WebResponse "Failed to create payment"
+// @Router /users/me/topups [post]
+func (controller *TopupController) Create(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+	log.Printf("Request to create topup for user ID: %d", userID)
+	var req request.TopupRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := controller.TopupService.Create(userID, req.Amount)
+	if err != nil {
+		log.Printf("Failed to create topup: %v", err)
+		return err
+	}
+
+	log.Printf("Topup created successfully with order ID: %s", result.MidtransOrderID)
+	topupResponse := mapper.ToTopupResponse(result)
+
+	return c.JSON(http.StatusCreated, web.WebResponse{
+		Message: "Topup created successfully",
+		Data:    topupResponse,
+	})
+}
+
 // TopupWebhook godoc
 // @Summary Process topup webhook
-// @Description Process Midtrans payment webhook for user balance topup
+// @Description Process Midtrans payment webhook for user balance topup. The order must have been opened through POST /users/me/topups. The signature is checked against gross_amount exactly as sent, and the amount must match the order to the cent. The transaction_status moves the topup through pending, capture, settlement, deny, cancel, expire, refund, partial_refund, chargeback and partial_chargeback; paying credits the balance, except for a capture whose fraud_status is not accept, and cancelling, refunding or charging back a paid topup reverses it. Repeated notifications for a status the topup already holds return the current record without touching the balance.
 // @Tags topup
 // @Accept json
 // @Produce json
 // @Param request body request.TopupWebhookRequest true "Midtrans webhook payload"
 // @Success 200 {object} web.WebResponse{data=response.TopupResponse} "Topup processed successfully"
 // @Failure 400 {object} web.WebResponse "Invalid request body"
+// @Failure 404 {object} web.WebResponse "Topup order not found"
+// @Failure 409 {object} web.WebResponse "Illegal status transition"
 // @Router /users/topup [post]
 func (controller *TopupController) TopupWebhook(c echo.Context) error {
 	log.Println("Request to process topup webhook")
 	var req request.TopupWebhookRequest
 
-	if err := c.Bind(&req); err != nil {
+	// The raw payload is kept in the topup status history, so the body is
+	// read once and decoded by hand instead of through Bind.
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		log.Printf("Failed to read request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := json.Unmarshal(payload, &req); err != nil {
 		log.Printf("Failed to bind request body: %v", err)
 		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
 	}
@@ -53,15 +110,15 @@ func (controller *TopupController) TopupWebhook(c echo.Context) error {
 		return exception.NewCustomError(http.StatusBadRequest, "Invalid amount format")
 	}
 
-	result, err := controller.TopupService.ProcessWebhook(topupDomain)
+	result, err := controller.TopupService.ProcessWebhook(topupDomain, string(payload))
 	if err != nil {
 		log.Printf("Failed to process webhook: %v", err)
 		return err
 	}
 
 	if result.ID == 0 {
-		log.Println("Notification ignored - not settlement")
-		return exception.NewCustomError(http.StatusOK, "Notification ignored - not settlement")
+		log.Printf("Notification ignored - unknown transaction status: %s", req.TransactionStatus)
+		return exception.NewCustomError(http.StatusOK, "Notification ignored - unknown transaction status")
 	}
 
 	log.Printf("Topup processed successfully with ID: %d for order ID: %s", result.ID, req.OrderID)
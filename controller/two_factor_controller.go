@@ -0,0 +1,331 @@
+package controller
+
+import (
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/mapper"
+	"hotel_ip-p2/model/web"
+	"hotel_ip-p2/model/web/request"
+	"hotel_ip-p2/model/web/response"
+	"hotel_ip-p2/service"
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TwoFactorController struct {
+	TwoFactorService service.TwoFactorService
+	TokenService     service.TokenService
+}
+
+func NewTwoFactorController(twoFactorService service.TwoFactorService, tokenService service.TokenService) *TwoFactorController {
+	return &TwoFactorController{
+		TwoFactorService: twoFactorService,
+		TokenService:     tokenService,
+	}
+}
+
+// VerifyLogin godoc
+// @Summary Complete a two-factor login
+// @Description Exchange the challenge token from /users/login and a code from the user's authenticator app, or one of their recovery codes, for an access token and a refresh token. When the challenge was for enrolment the code confirms the new authenticator and the user's recovery codes are returned once. Wrong codes count as failed logins.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body request.TwoFactorLoginRequest true "Challenge token and code"
+// @Success 200 {object} web.WebResponse{data=response.TwoFactorLoginResponse} "Login successful"
+// @Failure 400 {object} web.WebResponse "Invalid request body, validation error or enrolment not started"
+// @Failure 401 {object} web.WebResponse "Invalid or expired login challenge, or invalid two-factor code"
+// @Failure 429 {object} web.WebResponse "Too many failed login attempts"
+// @Router /users/login/2fa [post]
+func (controller *TwoFactorController) VerifyLogin(c echo.Context) error {
+	log.Println("Request to complete two-factor login")
+	var req request.TwoFactorLoginRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	user, recoveryCodes, err := controller.TwoFactorService.VerifyLogin(req.ChallengeToken, req.Code, c.RealIP())
+	if err != nil {
+		log.Printf("Two-factor login failed: %v", err)
+		return err
+	}
+
+	tokens, err := controller.TokenService.Issue(user)
+	if err != nil {
+		log.Printf("Failed to generate token: %v", err)
+		return exception.NewCustomError(http.StatusInternalServerError, "Failed to generate token")
+	}
+
+	log.Printf("User logged in successfully with two-factor authentication with ID: %d", user.ID)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Login successful",
+		Data:    mapper.ToTwoFactorLoginResponse(tokens, recoveryCodes),
+	})
+}
+
+// EnrollWithChallenge godoc
+// @Summary Set up two-factor authentication during login
+// @Description For users whose role requires two-factor authentication but who have not set it up yet. Exchange the challenge token from /users/login for a new authenticator secret and its otpauth URI, then complete the login at /users/login/2fa with a first code.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body request.TwoFactorChallengeRequest true "Challenge token"
+// @Success 200 {object} web.WebResponse{data=response.TOTPEnrollmentResponse} "Two-factor enrolment started"
+// @Failure 400 {object} web.WebResponse "Invalid request body, validation error or challenge not for enrolment"
+// @Failure 401 {object} web.WebResponse "Invalid or expired login challenge"
+// @Router /users/login/2fa/enroll [post]
+func (controller *TwoFactorController) EnrollWithChallenge(c echo.Context) error {
+	log.Println("Request to start two-factor enrolment during login")
+	var req request.TwoFactorChallengeRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	enrollment, err := controller.TwoFactorService.EnrollWithChallenge(req.ChallengeToken)
+	if err != nil {
+		log.Printf("Failed to start two-factor enrolment: %v", err)
+		return err
+	}
+
+	log.Println("Two-factor enrolment started")
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Two-factor enrolment started",
+		Data:    mapper.ToTOTPEnrollmentResponse(enrollment),
+	})
+}
+
+// Enroll godoc
+// @Summary Set up two-factor authentication
+// @Description Start setting up two-factor authentication for the current user. Returns a new authenticator secret and its otpauth URI, usually shown as a QR code. Two-factor authentication is not turned on until it is confirmed with a first code at /users/me/2fa/confirm.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} web.WebResponse{data=response.TOTPEnrollmentResponse} "Two-factor enrolment started"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 409 {object} web.WebResponse "Two-factor authentication is already enabled"
+// @Router /users/me/2fa/enroll [post]
+func (controller *TwoFactorController) Enroll(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+	log.Printf("Request to start two-factor enrolment for user ID: %d", userID)
+
+	enrollment, err := controller.TwoFactorService.Enroll(userID)
+	if err != nil {
+		log.Printf("Failed to start two-factor enrolment: %v", err)
+		return err
+	}
+
+	log.Printf("Two-factor enrolment started for user ID: %d", userID)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Two-factor enrolment started",
+		Data:    mapper.ToTOTPEnrollmentResponse(enrollment),
+	})
+}
+
+// Confirm godoc
+// @Summary Turn on two-factor authentication
+// @Description Confirm the authenticator set up at /users/me/2fa/enroll with a first code. Returns the user's recovery codes, which are shown only once.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.TwoFactorCodeRequest true "Code from the authenticator app"
+// @Success 200 {object} web.WebResponse{data=response.RecoveryCodesResponse} "Two-factor authentication enabled"
+// @Failure 400 {object} web.WebResponse "Invalid request body, validation error, enrolment not started or invalid two-factor code"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 409 {object} web.WebResponse "Two-factor authentication is already enabled"
+// @Router /users/me/2fa/confirm [post]
+func (controller *TwoFactorController) Confirm(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+	log.Printf("Request to confirm two-factor authentication for user ID: %d", userID)
+	var req request.TwoFactorCodeRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	recoveryCodes, err := controller.TwoFactorService.Confirm(userID, req.Code)
+	if err != nil {
+		log.Printf("Failed to confirm two-factor authentication: %v", err)
+		return err
+	}
+
+	log.Printf("Two-factor authentication enabled for user ID: %d", userID)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Two-factor authentication enabled",
+		Data:    response.RecoveryCodesResponse{RecoveryCodes: recoveryCodes},
+	})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Replace recovery codes
+// @Description Replace all of the current user's recovery codes with new ones. Codes issued before stop working.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.TwoFactorCodeRequest true "Code from the authenticator app or a recovery code"
+// @Success 200 {object} web.WebResponse{data=response.RecoveryCodesResponse} "Recovery codes regenerated"
+// @Failure 400 {object} web.WebResponse "Invalid request body, validation error, two-factor authentication not enabled or invalid two-factor code"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 429 {object} web.WebResponse "Too many wrong two-factor codes"
+// @Router /users/me/2fa/recovery-codes [post]
+func (controller *TwoFactorController) RegenerateRecoveryCodes(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+	log.Printf("Request to regenerate recovery codes for user ID: %d", userID)
+	var req request.TwoFactorCodeRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	recoveryCodes, err := controller.TwoFactorService.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		log.Printf("Failed to regenerate recovery codes: %v", err)
+		return err
+	}
+
+	log.Printf("Recovery codes regenerated for user ID: %d", userID)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Recovery codes regenerated",
+		Data:    response.RecoveryCodesResponse{RecoveryCodes: recoveryCodes},
+	})
+}
+
+// Disable godoc
+// @Summary Turn off two-factor authentication
+// @Description Turn off two-factor authentication for the current user and delete their recovery codes. Not allowed when the user's role requires two-factor authentication.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.TwoFactorCodeRequest true "Code from the authenticator app or a recovery code"
+// @Success 200 {object} web.WebResponse "Two-factor authentication disabled"
+// @Failure 400 {object} web.WebResponse "Invalid request body, validation error, two-factor authentication not enabled or invalid two-factor code"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Two-factor authentication is required for the user's role"
+// @Failure 429 {object} web.WebResponse "Too many wrong two-factor codes"
+// @Router /users/me/2fa [delete]
+func (controller *TwoFactorController) Disable(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+	log.Printf("Request to disable two-factor authentication for user ID: %d", userID)
+	var req request.TwoFactorCodeRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	err := controller.TwoFactorService.Disable(userID, req.Code)
+	if err != nil {
+		log.Printf("Failed to disable two-factor authentication: %v", err)
+		return err
+	}
+
+	log.Printf("Two-factor authentication disabled for user ID: %d", userID)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Two-factor authentication disabled",
+	})
+}
+
+// FindPolicies godoc
+// @Summary List two-factor requirements
+// @Description List, for every role, whether its users must use two-factor authentication (admin only).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} web.WebResponse{data=[]response.TwoFactorPolicyResponse} "Two-factor policies retrieved successfully"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Router /admin/two-factor/roles [get]
+func (controller *TwoFactorController) FindPolicies(c echo.Context) error {
+	log.Println("Request to retrieve two-factor policies")
+
+	policies, err := controller.TwoFactorService.FindPolicies()
+	if err != nil {
+		log.Printf("Failed to retrieve two-factor policies: %v", err)
+		return err
+	}
+
+	log.Println("Two-factor policies retrieved successfully")
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Two-factor policies retrieved successfully",
+		Data:    mapper.ToTwoFactorPolicyResponses(policies),
+	})
+}
+
+// UpdatePolicy godoc
+// @Summary Require two-factor authentication for a role
+// @Description Set whether users of a role must use two-factor authentication (admin only). Users already logged in keep their sessions; those without an authenticator must set one up the next time they log in.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role path string true "Role" Enums(admin, staff, guest)
+// @Param request body request.TwoFactorPolicyRequest true "Whether two-factor authentication is required"
+// @Success 200 {object} web.WebResponse{data=response.TwoFactorPolicyResponse} "Two-factor policy updated successfully"
+// @Failure 400 {object} web.WebResponse "Invalid request body, validation error or invalid role"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Router /admin/two-factor/roles/{role} [put]
+func (controller *TwoFactorController) UpdatePolicy(c echo.Context) error {
+	role := c.Param("role")
+	adminID := c.Get("user_id").(int)
+	log.Printf("Request to update two-factor policy of role %s by admin ID: %d", role, adminID)
+	var req request.TwoFactorPolicyRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	policy, err := controller.TwoFactorService.UpdatePolicy(adminID, role, *req.Required)
+	if err != nil {
+		log.Printf("Failed to update two-factor policy: %v", err)
+		return err
+	}
+
+	log.Printf("Two-factor policy of role %s updated successfully", role)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Two-factor policy updated successfully",
+		Data:    mapper.ToTwoFactorPolicyResponse(policy),
+	})
+}
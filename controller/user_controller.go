@@ -3,31 +3,37 @@ package controller
 import (
 	"errors"
 	"hotel_ip-p2/exception"
-	"hotel_ip-p2/helper"
 	"hotel_ip-p2/mapper"
 	"hotel_ip-p2/model/web"
 	"hotel_ip-p2/model/web/request"
-	"hotel_ip-p2/model/web/response"
 	"hotel_ip-p2/service"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 )
 
 type UserController struct {
-	UserService service.UserService
+	UserService      service.UserService
+	TokenService     service.TokenService
+	AccountService   service.AccountService
+	TwoFactorService service.TwoFactorService
 }
 
-func NewUserController(userService service.UserService) *UserController {
+func NewUserController(userService service.UserService, tokenService service.TokenService, accountService service.AccountService, twoFactorService service.TwoFactorService) *UserController {
 	return &UserController{
-		UserService: userService,
+		UserService:      userService,
+		TokenService:     tokenService,
+		AccountService:   accountService,
+		TwoFactorService: twoFactorService,
 	}
 }
 
 // Register godoc
 // @Summary Register a new user
-// @Description Register a new user account
+// @Description Register a new user account. A link to verify the email address is mailed to it; topping up and booking need a verified email address.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -35,6 +41,7 @@ func NewUserController(userService service.UserService) *UserController {
 // @Success 201 {object} web.WebResponse{data=response.UserResponse} "User registered successfully"
 // @Failure 400 {object} web.WebResponse "Invalid request body or validation error"
 // @Failure 500 {object} web.WebResponse "Internal server error"
+// @Router /users/register [post]
 func (controller *UserController) Register(c echo.Context) error {
 	log.Println("Request to register new user")
 	var req request.UserRequest
@@ -62,6 +69,9 @@ func (controller *UserController) Register(c echo.Context) error {
 	}
 
 	log.Printf("User registered successfully with ID: %d", result.ID)
+	if err := controller.AccountService.SendVerificationEmail(result); err != nil {
+		log.Printf("Failed to send verification email to user ID: %d: %v", result.ID, err)
+	}
 	userResponse := mapper.ToUserResponse(result)
 
 	return c.JSON(http.StatusCreated, web.WebResponse{
@@ -72,11 +82,17 @@ func (controller *UserController) Register(c echo.Context) error {
 
 // Login godoc
 // @Summary Login user
-// @Description Authenticate user and get JWT token
+// @Description Authenticate user and start a session. Returns a short-lived JWT access token and a refresh token to exchange for the next pair at /users/refresh. Repeated failed logins for the same email or from the same IP are held back for increasingly long and then locked out for a while. Users with two-factor authentication, or whose role requires it, get a short-lived challenge token instead, to exchange together with a code at /users/login/2fa. When enrollment_required is set they must first enrol at /users/login/2fa/enroll.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param request body request.LoginRequest true "Login credentials"
+// @Success 200 {object} web.WebResponse{data=response.LoginResponse} "Login successful"
+// @Success 202 {object} web.WebResponse{data=response.LoginChallengeResponse} "Two-factor authentication required"
+// @Failure 400 {object} web.WebResponse "Invalid request body or validation error"
+// @Failure 401 {object} web.WebResponse "Invalid email or password"
+// @Failure 429 {object} web.WebResponse "Too many failed login attempts"
+// @Router /users/login [post]
 func (controller *UserController) Login(c echo.Context) error {
 	log.Println("Request to login user")
 	var req request.LoginRequest
@@ -91,13 +107,26 @@ func (controller *UserController) Login(c echo.Context) error {
 		return exception.NewCustomError(http.StatusBadRequest, err.Error())
 	}
 
-	user, err := controller.UserService.Login(req.Email, req.Password)
+	user, err := controller.UserService.Login(req.Email, req.Password, c.RealIP())
 	if err != nil {
 		log.Printf("Login failed for email %s: %v", req.Email, err)
 		return err
 	}
 
-	token, err := helper.GenerateToken(user.ID)
+	challenge, err := controller.TwoFactorService.Challenge(user)
+	if err != nil {
+		log.Printf("Failed to check two-factor authentication: %v", err)
+		return err
+	}
+	if challenge != nil {
+		log.Printf("Two-factor authentication required for user ID: %d", user.ID)
+		return c.JSON(http.StatusAccepted, web.WebResponse{
+			Message: "Two-factor authentication required",
+			Data:    mapper.ToLoginChallengeResponse(*challenge),
+		})
+	}
+
+	tokens, err := controller.TokenService.Issue(user)
 	if err != nil {
 		log.Printf("Failed to generate token: %v", err)
 		return exception.NewCustomError(http.StatusInternalServerError, "Failed to generate token")
@@ -105,9 +134,7 @@ func (controller *UserController) Login(c echo.Context) error {
 
 	log.Printf("User logged in successfully with ID: %d", user.ID)
 
-	loginResponse := response.LoginResponse{
-		Token: token,
-	}
+	loginResponse := mapper.ToLoginResponse(tokens)
 
 	return c.JSON(http.StatusOK, web.WebResponse{
 		Message: "Login successful",
@@ -150,3 +177,593 @@ func (controller *UserController) GetById(c echo.Context) error {
 		Message: "Get user by id",
 	})
 }
+
+// FindAll godoc
+// @Summary List users
+// @Description List registered users a page at a time (admin only). Filter with filter[role] and filter[email]; sort by id, name, email or created_at, prefixed with - for descending order.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Items per page (max 100)"
+// @Param sort query string false "Comma-separated sort fields, e.g. -created_at"
+// @Success 200 {object} web.WebResponse{data=[]response.UserResponse} "Users retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid query parameters"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Router /admin/users [get]
+func (controller *UserController) FindAll(c echo.Context) error {
+	log.Println("Request to retrieve all users")
+	query, err := bindListQuery(c)
+	if err != nil {
+		return err
+	}
+
+	users, total, err := controller.UserService.FindAll(query)
+	if err != nil {
+		log.Printf("Failed to retrieve users: %v", err)
+		return err
+	}
+
+	log.Printf("Successfully retrieved %d of %d users", len(users), total)
+	userResponses := mapper.ToUserResponses(users)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Users retrieved successfully",
+		Data:    userResponses,
+		Meta:    offsetPageMeta(c, query.Pagination, total),
+	})
+}
+
+// UpdateRole godoc
+// @Summary Change a user's role
+// @Description Promote or demote a user to admin, staff or guest (admin only). All of the user's sessions end, so they log in again with their new role.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body request.UpdateRoleRequest true "New role"
+// @Success 200 {object} web.WebResponse{data=response.UserResponse} "User role updated successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID or role"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "User not found"
+// @Router /admin/users/{id}/role [put]
+func (controller *UserController) UpdateRole(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid user ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to update role of user ID: %d", id)
+	var req request.UpdateRoleRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	user, err := controller.UserService.UpdateRole(id, req.Role)
+	if err != nil {
+		log.Printf("Failed to update user role: %v", err)
+		return err
+	}
+
+	log.Printf("User ID: %d is now %s", user.ID, user.Role)
+	userResponse := mapper.ToUserResponse(user)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "User role updated successfully",
+		Data:    userResponse,
+	})
+}
+
+// Delete godoc
+// @Summary Delete a user
+// @Description Delete a user by ID (admin only). The account is archived rather than removed, so its bookings and wallet history stay in place, and it can no longer log in. All of the user's sessions end. Users with money in their wallet, upcoming bookings or a pending topup, and the last admin, cannot be deleted; pay out a remaining balance and debit it with POST /admin/users/{id}/adjustments first.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} web.WebResponse "User deleted successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID or last admin"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "User not found"
+// @Failure 409 {object} web.WebResponse "Wallet not empty, upcoming bookings or pending topup"
+// @Router /admin/users/{id} [delete]
+func (controller *UserController) Delete(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid user ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to delete user with ID: %d", id)
+	err = controller.UserService.Delete(id)
+	if err != nil {
+		log.Printf("Failed to delete user: %v", err)
+		return err
+	}
+
+	log.Printf("User deleted successfully with ID: %d", id)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "User deleted successfully",
+	})
+}
+
+// FindDeleted godoc
+// @Summary List deleted users
+// @Description List deleted users a page at a time (admin only). Accepts the same filters and sort fields as the user list.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Items per page (max 100)"
+// @Param sort query string false "Comma-separated sort fields, e.g. -created_at"
+// @Success 200 {object} web.WebResponse{data=[]response.UserResponse} "Deleted users retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid query parameters"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Router /admin/users/deleted [get]
+func (controller *UserController) FindDeleted(c echo.Context) error {
+	log.Println("Request to retrieve deleted users")
+	query, err := bindListQuery(c)
+	if err != nil {
+		return err
+	}
+
+	users, total, err := controller.UserService.FindDeleted(query)
+	if err != nil {
+		log.Printf("Failed to retrieve deleted users: %v", err)
+		return err
+	}
+
+	log.Printf("Successfully retrieved %d of %d deleted users", len(users), total)
+	userResponses := mapper.ToUserResponses(users)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Deleted users retrieved successfully",
+		Data:    userResponses,
+		Meta:    offsetPageMeta(c, query.Pagination, total),
+	})
+}
+
+// Restore godoc
+// @Summary Restore a deleted user
+// @Description Let a deleted user log in again (admin only). Their email must not have been registered by someone else since.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} web.WebResponse{data=response.UserResponse} "User restored successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "User not found"
+// @Failure 409 {object} web.WebResponse "Email already exists"
+// @Router /admin/users/{id}/restore [post]
+func (controller *UserController) Restore(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid user ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to restore user with ID: %d", id)
+	user, err := controller.UserService.Restore(id)
+	if err != nil {
+		log.Printf("Failed to restore user: %v", err)
+		return err
+	}
+
+	log.Printf("User restored successfully with ID: %d", id)
+	userResponse := mapper.ToUserResponse(user)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "User restored successfully",
+		Data:    userResponse,
+	})
+}
+
+// Unlock godoc
+// @Summary Unlock a user's login
+// @Description Clear the failed logins of a user's email so they can log in again straight away (admin only). Lockouts of IP addresses are not affected.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} web.WebResponse{data=response.UserResponse} "User unlocked successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "User not found"
+// @Router /admin/users/{id}/unlock [post]
+func (controller *UserController) Unlock(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid user ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	adminID := c.Get("user_id").(int)
+	log.Printf("Request to unlock user with ID: %d by admin ID: %d", id, adminID)
+	user, err := controller.UserService.Unlock(adminID, id)
+	if err != nil {
+		log.Printf("Failed to unlock user: %v", err)
+		return err
+	}
+
+	log.Printf("User unlocked successfully with ID: %d", id)
+	userResponse := mapper.ToUserResponse(user)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "User unlocked successfully",
+		Data:    userResponse,
+	})
+}
+
+// Refresh godoc
+// @Summary Refresh the access token
+// @Description Exchange a refresh token for a new access token and refresh token. Each refresh token works once; presenting one again revokes the whole session.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body request.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} web.WebResponse{data=response.LoginResponse} "Token refreshed successfully"
+// @Failure 400 {object} web.WebResponse "Invalid request body or validation error"
+// @Failure 401 {object} web.WebResponse "Invalid, expired, revoked or reused refresh token"
+// @Router /users/refresh [post]
+func (controller *UserController) Refresh(c echo.Context) error {
+	log.Println("Request to refresh token")
+	var req request.RefreshTokenRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	tokens, err := controller.TokenService.Refresh(req.RefreshToken)
+	if err != nil {
+		log.Printf("Failed to refresh token: %v", err)
+		return err
+	}
+
+	log.Println("Token refreshed successfully")
+	loginResponse := mapper.ToLoginResponse(tokens)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Token refreshed successfully",
+		Data:    loginResponse,
+	})
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description End the current session. Its refresh token stops working and its access token is revoked.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} web.WebResponse "Logged out successfully"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Router /users/logout [post]
+func (controller *UserController) Logout(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+	sessionID := c.Get("session_id").(string)
+	log.Printf("Request to log out user ID: %d", userID)
+
+	err := controller.TokenService.Logout(userID, sessionID)
+	if err != nil {
+		log.Printf("Failed to log out: %v", err)
+		return err
+	}
+
+	log.Printf("User ID: %d logged out successfully", userID)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Logged out successfully",
+	})
+}
+
+// LogoutAll godoc
+// @Summary Log out of all sessions
+// @Description End every session of the current user, on every device, including this one.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} web.WebResponse "Logged out of all sessions successfully"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Router /users/logout-all [post]
+func (controller *UserController) LogoutAll(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+	log.Printf("Request to log out all sessions of user ID: %d", userID)
+
+	err := controller.TokenService.LogoutAll(userID)
+	if err != nil {
+		log.Printf("Failed to log out all sessions: %v", err)
+		return err
+	}
+
+	log.Printf("User ID: %d logged out of all sessions successfully", userID)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Logged out of all sessions successfully",
+	})
+}
+
+// VerifyEmail godoc
+// @Summary Verify an email address
+// @Description Confirm the email address of an account with the token from the verification link. Each token works once.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body request.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} web.WebResponse{data=response.UserResponse} "Email address verified successfully"
+// @Failure 400 {object} web.WebResponse "Invalid request body or invalid or expired token"
+// @Router /users/verify-email [post]
+func (controller *UserController) VerifyEmail(c echo.Context) error {
+	log.Println("Request to verify email address")
+	var req request.VerifyEmailRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := controller.AccountService.VerifyEmail(req.Token)
+	if err != nil {
+		log.Printf("Failed to verify email address: %v", err)
+		return err
+	}
+
+	log.Printf("Email address of user ID: %d verified successfully", result.ID)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Email address verified successfully",
+		Data:    mapper.ToUserResponse(result),
+	})
+}
+
+// ResendVerificationEmail godoc
+// @Summary Resend the verification email
+// @Description Mail a new email verification link to the current user. Links mailed earlier stop working.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} web.WebResponse "Verification email sent successfully"
+// @Failure 400 {object} web.WebResponse "Email address is already verified"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Router /users/me/verification-email [post]
+func (controller *UserController) ResendVerificationEmail(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+	log.Printf("Request to resend verification email to user ID: %d", userID)
+
+	err := controller.AccountService.ResendVerificationEmail(userID)
+	if err != nil {
+		log.Printf("Failed to resend verification email: %v", err)
+		return err
+	}
+
+	log.Printf("Verification email sent successfully to user ID: %d", userID)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Verification email sent successfully",
+	})
+}
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Mail a password reset link to the given email address. The response is the same whether or not an account uses that address, and the mail is sent in the background. Requests are throttled per email address and per client IP.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body request.ForgotPasswordRequest true "Account email address"
+// @Success 200 {object} web.WebResponse "Password reset email sent if the account exists"
+// @Failure 400 {object} web.WebResponse "Invalid request body or validation error"
+// @Failure 429 {object} web.WebResponse "Too many password reset requests"
+// @Router /users/forgot-password [post]
+func (controller *UserController) ForgotPassword(c echo.Context) error {
+	log.Println("Request to reset a forgotten password")
+	var req request.ForgotPasswordRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	err := controller.AccountService.ForgotPassword(req.Email, c.RealIP())
+	if err != nil {
+		log.Printf("Failed to request password reset: %v", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "If an account uses this email address, a password reset link has been sent to it",
+	})
+}
+
+// ResetPassword godoc
+// @Summary Reset a password
+// @Description Set a new password with the token from the password reset link. Each token works once, and every session of the user is logged out.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body request.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} web.WebResponse "Password reset successfully"
+// @Failure 400 {object} web.WebResponse "Invalid request body or invalid or expired token"
+// @Router /users/reset-password [post]
+func (controller *UserController) ResetPassword(c echo.Context) error {
+	log.Println("Request to reset password")
+	var req request.ResetPasswordRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	err := controller.AccountService.ResetPassword(req.Token, req.Password)
+	if err != nil {
+		log.Printf("Failed to reset password: %v", err)
+		return err
+	}
+
+	log.Println("Password reset successfully")
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Password reset successfully",
+	})
+}
+
+// UpdateMe godoc
+// @Summary Update the current user's profile
+// @Description Change the name and/or email address of the current user; omitted fields stay the same. Changing the email address requires current_password, and wrong passwords count as failed logins for the account. A new email address has to be verified again before topping up or booking.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.UpdateProfileRequest true "Profile fields to change"
+// @Success 200 {object} web.WebResponse{data=response.UserResponse} "Profile updated successfully"
+// @Failure 400 {object} web.WebResponse "Invalid request body, validation error, or current password missing or incorrect"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 409 {object} web.WebResponse "Email already exists"
+// @Failure 429 {object} web.WebResponse "Too many failed login attempts"
+// @Router /users/me [patch]
+func (controller *UserController) UpdateMe(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+	log.Printf("Request to update profile of user ID: %d", userID)
+	var req request.UpdateProfileRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := controller.AccountService.UpdateProfile(userID, strings.TrimSpace(req.Name), req.Email, req.CurrentPassword)
+	if err != nil {
+		log.Printf("Failed to update profile: %v", err)
+		return err
+	}
+
+	log.Printf("Profile of user ID: %d updated successfully", userID)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Profile updated successfully",
+		Data:    mapper.ToUserResponse(result),
+	})
+}
+
+// ChangePassword godoc
+// @Summary Change the current user's password
+// @Description Set a new password after confirming the current one. Every other session of the user is logged out; this one stays logged in. Wrong current passwords count as failed logins for the account.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} web.WebResponse "Password changed successfully"
+// @Failure 400 {object} web.WebResponse "Invalid request body or current password is incorrect"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 429 {object} web.WebResponse "Too many failed login attempts"
+// @Router /users/me/password [put]
+func (controller *UserController) ChangePassword(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+	sessionID := c.Get("session_id").(string)
+	log.Printf("Request to change password of user ID: %d", userID)
+	var req request.ChangePasswordRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	err := controller.AccountService.ChangePassword(userID, sessionID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		log.Printf("Failed to change password: %v", err)
+		return err
+	}
+
+	log.Printf("Password of user ID: %d changed successfully", userID)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Password changed successfully",
+	})
+}
+
+// DeleteMe godoc
+// @Summary Delete the current user's account
+// @Description Close the current user's account after confirming their password. Personal data is anonymised, the email address is scrubbed from login throttles and audit logs, and two-factor credentials are deleted; bookings, wallet entries and invoices are kept for accounting. Refused while the user has upcoming bookings, money left in their wallet or a topup payment still pending. A remaining balance has to be paid out by an admin, who debits it with POST /admin/users/{id}/adjustments. Wrong passwords count as failed logins for the account.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body request.DeleteAccountRequest true "Current password"
+// @Success 200 {object} web.WebResponse "Account deleted successfully"
+// @Failure 400 {object} web.WebResponse "Invalid request body or password is incorrect"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 409 {object} web.WebResponse "Account has upcoming bookings, a positive balance or a pending topup"
+// @Failure 429 {object} web.WebResponse "Too many failed login attempts"
+// @Router /users/me [delete]
+func (controller *UserController) DeleteMe(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+	log.Printf("Request to delete account of user ID: %d", userID)
+	var req request.DeleteAccountRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	err := controller.AccountService.DeleteAccount(userID, req.Password)
+	if err != nil {
+		log.Printf("Failed to delete account: %v", err)
+		return err
+	}
+
+	log.Printf("Account of user ID: %d deleted successfully", userID)
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Account deleted successfully",
+	})
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/mapper"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/model/web"
+	"hotel_ip-p2/model/web/request"
+	"hotel_ip-p2/service"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WalletController struct {
+	WalletService service.WalletService
+}
+
+func NewWalletController(walletService service.WalletService) *WalletController {
+	return &WalletController{
+		WalletService: walletService,
+	}
+}
+
+// FindMyTransactions godoc
+// @Summary Get my wallet transactions
+// @Description Get the authenticated user's wallet ledger, newest first
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number, starting at 1"
+// @Param per_page query int false "Items per page (max 100)"
+// @Success 200 {object} web.WebResponse{data=[]response.WalletTransactionResponse} "Transactions retrieved successfully"
+// @Failure 400 {object} web.WebResponse "Invalid query parameters"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Router /users/me/transactions [get]
+func (controller *WalletController) FindMyTransactions(c echo.Context) error {
+	userID := c.Get("user_id").(int)
+	log.Printf("Request to retrieve wallet transactions for user ID: %d", userID)
+	var req request.PageRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind query parameters: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	pagination := domain.NewPagination(req.Page, req.PerPage)
+
+	transactions, total, err := controller.WalletService.FindTransactions(userID, pagination)
+	if err != nil {
+		log.Printf("Failed to retrieve wallet transactions: %v", err)
+		return err
+	}
+
+	log.Printf("Successfully retrieved %d of %d wallet transactions for user ID: %d", len(transactions), total, userID)
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Transactions retrieved successfully",
+		Data:    mapper.ToWalletTransactionResponses(transactions),
+		Meta:    offsetPageMeta(c, pagination, total),
+	})
+}
+
+// Adjust godoc
+// @Summary Adjust a user's wallet
+// @Description Post a manual credit (positive amount) or debit (negative amount) to a user's wallet ledger (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body request.WalletAdjustmentRequest true "Adjustment details"
+// @Success 201 {object} web.WebResponse{data=response.WalletTransactionResponse} "Adjustment recorded successfully"
+// @Failure 400 {object} web.WebResponse "Invalid ID or request body"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Failure 404 {object} web.WebResponse "User not found"
+// @Router /admin/users/{id}/adjustments [post]
+func (controller *WalletController) Adjust(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Printf("Invalid user ID parameter: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid ID")
+	}
+
+	log.Printf("Request to adjust wallet of user ID: %d", id)
+	var req request.WalletAdjustmentRequest
+
+	if err := c.Bind(&req); err != nil {
+		log.Printf("Failed to bind request body: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		log.Printf("Validation failed: %v", err)
+		return exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := controller.WalletService.Adjust(id, req.Amount, req.Description)
+	if err != nil {
+		log.Printf("Failed to adjust wallet: %v", err)
+		return err
+	}
+
+	log.Printf("Wallet adjustment recorded with ID: %d for user ID: %d", result.ID, id)
+
+	return c.JSON(http.StatusCreated, web.WebResponse{
+		Message: "Adjustment recorded successfully",
+		Data:    mapper.ToWalletTransactionResponse(result),
+	})
+}
+
+// FindUnreconciled godoc
+// @Summary Reconcile wallets
+// @Description List users whose stored balance does not match the sum of their ledger entries (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} web.WebResponse{data=[]response.WalletReconciliationResponse} "Reconciliation completed"
+// @Failure 401 {object} web.WebResponse "Unauthorized"
+// @Failure 403 {object} web.WebResponse "Insufficient permissions"
+// @Router /admin/wallets/reconciliation [get]
+func (controller *WalletController) FindUnreconciled(c echo.Context) error {
+	log.Println("Request to reconcile wallet balances")
+	result, err := controller.WalletService.FindUnreconciled()
+	if err != nil {
+		log.Printf("Failed to reconcile wallet balances: %v", err)
+		return err
+	}
+
+	log.Printf("Found %d unreconciled wallets", len(result))
+
+	return c.JSON(http.StatusOK, web.WebResponse{
+		Message: "Reconciliation completed",
+		Data:    mapper.ToWalletReconciliationResponses(result),
+	})
+}
@@ -1,7 +1,10 @@
 package helper
 
 import (
+	"hotel_ip-p2/model/domain"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -18,7 +21,22 @@ type DatabaseConfig struct {
 type Config struct {
 	jwtSecretKey      string
 	midtransServerKey string
+	midtransSnapURL   string
 	databaseConfig    DatabaseConfig
+	refundPolicy      domain.RefundPolicy
+	bootstrapAdmin    string
+	quoteTokenTTL     time.Duration
+	hotel             domain.HotelDetails
+	noShowSweepAt     time.Duration
+	accessTokenTTL    time.Duration
+	refreshTokenTTL   time.Duration
+	denylistSync      time.Duration
+	mail              MailConfig
+	accountMail       domain.AccountMailPolicy
+	loginPolicy       domain.LoginPolicy
+	trustProxyHeaders bool
+	challengeTTL      time.Duration
+	twoFactorKey      string
 }
 
 var AppConfig *Config
@@ -27,6 +45,31 @@ func InitConfig() {
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
 
+	viper.SetDefault("MIDTRANS_SNAP_URL", "https://app.sandbox.midtrans.com/snap/v1/transactions")
+	viper.SetDefault("CANCELLATION_FULL_REFUND_HOURS", 48)
+	viper.SetDefault("CANCELLATION_PARTIAL_REFUND_PERCENT", 50)
+	viper.SetDefault("QUOTE_TOKEN_TTL_MINUTES", 15)
+	viper.SetDefault("HOTEL_NAME", "Hotel")
+	viper.SetDefault("NO_SHOW_REFUND_PERCENT", 0)
+	viper.SetDefault("NO_SHOW_SWEEP_TIME", "02:00")
+	viper.SetDefault("ACCESS_TOKEN_TTL_MINUTES", 15)
+	viper.SetDefault("REFRESH_TOKEN_TTL_DAYS", 30)
+	viper.SetDefault("TOKEN_DENYLIST_SYNC_SECONDS", 10)
+	viper.SetDefault("MAIL_DRIVER", "file")
+	viper.SetDefault("MAIL_FILE_DIR", "mail")
+	viper.SetDefault("MAIL_FROM", "no-reply@localhost")
+	viper.SetDefault("SMTP_PORT", "587")
+	viper.SetDefault("APP_URL", "http://localhost:8080")
+	viper.SetDefault("EMAIL_VERIFICATION_TTL_HOURS", 48)
+	viper.SetDefault("PASSWORD_RESET_TTL_MINUTES", 60)
+	viper.SetDefault("LOGIN_FREE_ATTEMPTS", 3)
+	viper.SetDefault("LOGIN_BACKOFF_MAX_SECONDS", 300)
+	viper.SetDefault("LOGIN_ACCOUNT_LOCKOUT_FAILURES", 10)
+	viper.SetDefault("LOGIN_IP_LOCKOUT_FAILURES", 50)
+	viper.SetDefault("LOGIN_LOCKOUT_MINUTES", 15)
+	viper.SetDefault("TRUST_PROXY_HEADERS", false)
+	viper.SetDefault("TWO_FACTOR_CHALLENGE_TTL_MINUTES", 5)
+
 	if err := viper.ReadInConfig(); err != nil {
 		log.Println("Warning: .env file not found, using environment variables")
 	}
@@ -34,6 +77,7 @@ func InitConfig() {
 	AppConfig = &Config{
 		jwtSecretKey:      viper.GetString("JWT_SECRET_KEY"),
 		midtransServerKey: viper.GetString("MIDTRANS_SERVER_KEY"),
+		midtransSnapURL:   viper.GetString("MIDTRANS_SNAP_URL"),
 		databaseConfig: DatabaseConfig{
 			Host:     viper.GetString("DB_HOST"),
 			Port:     viper.GetString("DB_PORT"),
@@ -42,16 +86,135 @@ func InitConfig() {
 			DBName:   viper.GetString("DB_NAME"),
 			SSLMode:  viper.GetString("DB_SSLMODE"),
 		},
+		bootstrapAdmin:  viper.GetString("BOOTSTRAP_ADMIN_EMAIL"),
+		quoteTokenTTL:   time.Duration(viper.GetInt("QUOTE_TOKEN_TTL_MINUTES")) * time.Minute,
+		accessTokenTTL:  time.Duration(viper.GetInt("ACCESS_TOKEN_TTL_MINUTES")) * time.Minute,
+		refreshTokenTTL: time.Duration(viper.GetInt("REFRESH_TOKEN_TTL_DAYS")) * 24 * time.Hour,
+		denylistSync:    time.Duration(viper.GetInt("TOKEN_DENYLIST_SYNC_SECONDS")) * time.Second,
+		mail: MailConfig{
+			Driver:   viper.GetString("MAIL_DRIVER"),
+			Host:     viper.GetString("SMTP_HOST"),
+			Port:     viper.GetString("SMTP_PORT"),
+			Username: viper.GetString("SMTP_USERNAME"),
+			Password: viper.GetString("SMTP_PASSWORD"),
+			From:     viper.GetString("MAIL_FROM"),
+			FileDir:  viper.GetString("MAIL_FILE_DIR"),
+		},
+		accountMail: domain.AccountMailPolicy{
+			AppURL:               strings.TrimRight(viper.GetString("APP_URL"), "/"),
+			EmailVerificationTTL: time.Duration(viper.GetInt("EMAIL_VERIFICATION_TTL_HOURS")) * time.Hour,
+			PasswordResetTTL:     time.Duration(viper.GetInt("PASSWORD_RESET_TTL_MINUTES")) * time.Minute,
+		},
+		loginPolicy: domain.LoginPolicy{
+			FreeAttempts:           viper.GetInt("LOGIN_FREE_ATTEMPTS"),
+			MaxBackoff:             time.Duration(viper.GetInt("LOGIN_BACKOFF_MAX_SECONDS")) * time.Second,
+			AccountLockoutFailures: viper.GetInt("LOGIN_ACCOUNT_LOCKOUT_FAILURES"),
+			IPLockoutFailures:      viper.GetInt("LOGIN_IP_LOCKOUT_FAILURES"),
+			LockoutDuration:        time.Duration(viper.GetInt("LOGIN_LOCKOUT_MINUTES")) * time.Minute,
+		},
+		trustProxyHeaders: viper.GetBool("TRUST_PROXY_HEADERS"),
+		challengeTTL:      time.Duration(viper.GetInt("TWO_FACTOR_CHALLENGE_TTL_MINUTES")) * time.Minute,
+		twoFactorKey:      viper.GetString("TWO_FACTOR_ENCRYPTION_KEY"),
+		hotel: domain.HotelDetails{
+			Name:    viper.GetString("HOTEL_NAME"),
+			Address: viper.GetString("HOTEL_ADDRESS"),
+			Phone:   viper.GetString("HOTEL_PHONE"),
+			Email:   viper.GetString("HOTEL_EMAIL"),
+			TaxID:   viper.GetString("HOTEL_TAX_ID"),
+		},
+		refundPolicy: domain.RefundPolicy{
+			FullRefundHours: viper.GetInt("CANCELLATION_FULL_REFUND_HOURS"),
+		},
+	}
+
+	sweepAt, err := time.Parse("15:04", viper.GetString("NO_SHOW_SWEEP_TIME"))
+	if err != nil {
+		log.Fatal("NO_SHOW_SWEEP_TIME must be a time of day such as 02:00")
+	}
+	AppConfig.noShowSweepAt = time.Duration(sweepAt.Hour())*time.Hour + time.Duration(sweepAt.Minute())*time.Minute
+
+	AppConfig.refundPolicy.PartialRefundPercent, err = domain.ParsePercent(viper.GetString("CANCELLATION_PARTIAL_REFUND_PERCENT"))
+	if err != nil {
+		log.Fatal("CANCELLATION_PARTIAL_REFUND_PERCENT must be a percentage with at most two decimal places")
+	}
+
+	AppConfig.refundPolicy.NoShowRefundPercent, err = domain.ParsePercent(viper.GetString("NO_SHOW_REFUND_PERCENT"))
+	if err != nil {
+		log.Fatal("NO_SHOW_REFUND_PERCENT must be a percentage with at most two decimal places")
 	}
 
 	if AppConfig.jwtSecretKey == "" {
 		log.Fatal("JWT_SECRET_KEY is required")
 	}
 
+	if AppConfig.twoFactorKey == "" {
+		AppConfig.twoFactorKey = AppConfig.jwtSecretKey
+	}
+
 	if AppConfig.midtransServerKey == "" {
 		log.Fatal("MIDTRANS_SERVER_KEY is required")
 	}
 
+	if AppConfig.refundPolicy.PartialRefundPercent < 0 || AppConfig.refundPolicy.PartialRefundPercent > domain.NewPercent(100) {
+		log.Fatal("CANCELLATION_PARTIAL_REFUND_PERCENT must be between 0 and 100")
+	}
+
+	if AppConfig.refundPolicy.NoShowRefundPercent < 0 || AppConfig.refundPolicy.NoShowRefundPercent > domain.NewPercent(100) {
+		log.Fatal("NO_SHOW_REFUND_PERCENT must be between 0 and 100")
+	}
+
+	if AppConfig.quoteTokenTTL <= 0 {
+		log.Fatal("QUOTE_TOKEN_TTL_MINUTES must be positive")
+	}
+
+	if AppConfig.accessTokenTTL <= 0 {
+		log.Fatal("ACCESS_TOKEN_TTL_MINUTES must be positive")
+	}
+
+	if AppConfig.refreshTokenTTL <= AppConfig.accessTokenTTL {
+		log.Fatal("REFRESH_TOKEN_TTL_DAYS must be longer than the access token TTL")
+	}
+
+	if AppConfig.denylistSync <= 0 {
+		log.Fatal("TOKEN_DENYLIST_SYNC_SECONDS must be positive")
+	}
+
+	if AppConfig.mail.Driver != "smtp" && AppConfig.mail.Driver != "file" {
+		log.Fatal("MAIL_DRIVER must be smtp or file")
+	}
+
+	if AppConfig.mail.Driver == "smtp" && AppConfig.mail.Host == "" {
+		log.Fatal("SMTP_HOST is required when MAIL_DRIVER is smtp")
+	}
+
+	if AppConfig.accountMail.EmailVerificationTTL <= 0 {
+		log.Fatal("EMAIL_VERIFICATION_TTL_HOURS must be positive")
+	}
+
+	if AppConfig.accountMail.PasswordResetTTL <= 0 {
+		log.Fatal("PASSWORD_RESET_TTL_MINUTES must be positive")
+	}
+
+	if AppConfig.loginPolicy.FreeAttempts < 0 {
+		log.Fatal("LOGIN_FREE_ATTEMPTS must not be negative")
+	}
+
+	if AppConfig.loginPolicy.MaxBackoff <= 0 {
+		log.Fatal("LOGIN_BACKOFF_MAX_SECONDS must be positive")
+	}
+
+	if AppConfig.loginPolicy.AccountLockoutFailures <= AppConfig.loginPolicy.FreeAttempts || AppConfig.loginPolicy.IPLockoutFailures <= AppConfig.loginPolicy.FreeAttempts {
+		log.Fatal("LOGIN_ACCOUNT_LOCKOUT_FAILURES and LOGIN_IP_LOCKOUT_FAILURES must be greater than LOGIN_FREE_ATTEMPTS")
+	}
+
+	if AppConfig.loginPolicy.LockoutDuration <= 0 {
+		log.Fatal("LOGIN_LOCKOUT_MINUTES must be positive")
+	}
+
+	if AppConfig.challengeTTL <= 0 {
+		log.Fatal("TWO_FACTOR_CHALLENGE_TTL_MINUTES must be positive")
+	}
+
 	log.Println("Configuration loaded successfully")
 }
 
@@ -66,3 +229,86 @@ func (c *Config) GetDatabaseConfig() DatabaseConfig {
 func (c *Config) GetMidtransServerKey() string {
 	return c.midtransServerKey
 }
+
+func (c *Config) GetMidtransSnapURL() string {
+	return c.midtransSnapURL
+}
+
+func (c *Config) GetRefundPolicy() domain.RefundPolicy {
+	return c.refundPolicy
+}
+
+// GetBootstrapAdminEmail returns the email of the registered user to promote
+// to admin on start-up while the system has no admin yet.
+func (c *Config) GetBootstrapAdminEmail() string {
+	return c.bootstrapAdmin
+}
+
+// GetQuoteTokenTTL returns how long a booking quote guarantees its price.
+func (c *Config) GetQuoteTokenTTL() time.Duration {
+	return c.quoteTokenTTL
+}
+
+// GetHotelDetails returns the hotel details printed on invoices and receipts.
+func (c *Config) GetHotelDetails() domain.HotelDetails {
+	return c.hotel
+}
+
+// GetNoShowSweepTime returns the time after midnight, in server local time,
+// at which bookings whose guests never arrived are marked as no-shows.
+func (c *Config) GetNoShowSweepTime() time.Duration {
+	return c.noShowSweepAt
+}
+
+// GetAccessTokenTTL returns how long an access token is valid. It also bounds
+// how long a revoked token stays on the denylist.
+func (c *Config) GetAccessTokenTTL() time.Duration {
+	return c.accessTokenTTL
+}
+
+// GetRefreshTokenTTL returns how long a refresh token can be exchanged for a
+// new token pair.
+func (c *Config) GetRefreshTokenTTL() time.Duration {
+	return c.refreshTokenTTL
+}
+
+// GetDenylistSyncInterval returns how often each instance reloads the access
+// tokens revoked by other instances.
+func (c *Config) GetDenylistSyncInterval() time.Duration {
+	return c.denylistSync
+}
+
+func (c *Config) GetMailConfig() MailConfig {
+	return c.mail
+}
+
+// GetAccountMailPolicy returns how long email verification and password
+// reset links stay valid and the base URL they point to.
+func (c *Config) GetAccountMailPolicy() domain.AccountMailPolicy {
+	return c.accountMail
+}
+
+// GetLoginPolicy returns how failed logins are throttled and locked out.
+func (c *Config) GetLoginPolicy() domain.LoginPolicy {
+	return c.loginPolicy
+}
+
+// TrustProxyHeaders reports whether the server runs behind a reverse proxy
+// whose X-Forwarded-For header can be trusted for the client IP.
+func (c *Config) TrustProxyHeaders() bool {
+	return c.trustProxyHeaders
+}
+
+// GetTwoFactorChallengeTTL returns how long a user has to enter their
+// two-factor code after entering their password.
+func (c *Config) GetTwoFactorChallengeTTL() time.Duration {
+	return c.challengeTTL
+}
+
+// GetTwoFactorEncryptionKey returns the key TOTP secrets are encrypted with.
+// It defaults to the JWT secret; set TWO_FACTOR_ENCRYPTION_KEY so the JWT
+// secret can be rotated without locking out users with two-factor
+// authentication.
+func (c *Config) GetTwoFactorEncryptionKey() string {
+	return c.twoFactorKey
+}
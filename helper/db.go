@@ -23,7 +23,8 @@ func InitDB() *gorm.DB {
 
 	log.Println("Initializing database connection with PrepareStmt disabled")
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		PrepareStmt: false,
+		PrepareStmt:    false,
+		TranslateError: true,
 	})
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
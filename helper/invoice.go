@@ -0,0 +1,191 @@
+package helper
+
+import (
+	"bytes"
+	"hotel_ip-p2/model/domain"
+	"html/template"
+	"strings"
+)
+
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"amount": FormatInvoiceAmount,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} {{.Invoice.Number}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 14px; color: #222; max-width: 800px; margin: 40px auto; }
+header { display: flex; justify-content: space-between; border-bottom: 2px solid #222; padding-bottom: 16px; }
+h1 { margin: 0; font-size: 24px; }
+h2 { margin: 0; font-size: 20px; text-transform: uppercase; text-align: right; }
+.muted { color: #666; }
+table { width: 100%; border-collapse: collapse; margin-top: 24px; }
+th, td { padding: 6px 0; text-align: left; }
+th { border-bottom: 1px solid #222; }
+.amount { text-align: right; white-space: nowrap; }
+.inclusive td { color: #666; font-style: italic; }
+.totals td { border-top: 1px solid #ddd; }
+.totals tr:last-child td { font-weight: bold; }
+dl { display: grid; grid-template-columns: max-content auto; gap: 4px 16px; }
+dt { color: #666; }
+dd { margin: 0; }
+</style>
+</head>
+<body>
+<header>
+<div>
+<h1>{{.Hotel.Name}}</h1>
+{{with .Hotel.Address}}<div>{{.}}</div>{{end}}
+{{with .Hotel.Phone}}<div>{{.}}</div>{{end}}
+{{with .Hotel.Email}}<div>{{.}}</div>{{end}}
+{{with .Hotel.TaxID}}<div class="muted">Tax ID {{.}}</div>{{end}}
+</div>
+<div>
+<h2>{{.Title}}</h2>
+<div class="amount">{{.Invoice.Number}}</div>
+<div class="amount muted">Issued {{.Invoice.IssuedAt.Format "2006-01-02"}}</div>
+</div>
+</header>
+<section>
+<p><strong>Billed to</strong><br>{{.GuestName}}<br><span class="muted">{{.GuestEmail}}</span></p>
+<dl>
+{{range .Details}}<dt>{{.Label}}</dt><dd>{{.Value}}</dd>
+{{end}}</dl>
+</section>
+<table>
+<thead><tr><th>Description</th><th class="amount">Amount</th></tr></thead>
+<tbody>
+{{range .Lines}}<tr{{if .Inclusive}} class="inclusive"{{end}}><td>{{.Description}}</td><td class="amount">{{amount .Amount}}</td></tr>
+{{end}}</tbody>
+</table>
+<table class="totals">
+{{range .Totals}}<tr><td>{{.Description}}</td><td class="amount">{{amount .Amount}}</td></tr>
+{{end}}</table>
+{{with .Refunds}}<table>
+{{range .}}<tr class="inclusive"><td>{{.Description}}</td><td class="amount">{{amount .Amount}}</td></tr>
+{{end}}</table>{{end}}
+<p class="muted">Amounts in IDR.</p>
+</body>
+</html>
+`))
+
+// RenderInvoiceHTML renders an invoice or receipt as a standalone web page.
+func RenderInvoiceHTML(document domain.InvoiceDocument) ([]byte, error) {
+	var out bytes.Buffer
+	err := invoiceTemplate.Execute(&out, document)
+	return out.Bytes(), err
+}
+
+// RenderInvoicePDF renders an invoice or receipt as a PDF, continuing onto
+// further pages when the line items do not fit on one.
+func RenderInvoicePDF(document domain.InvoiceDocument) []byte {
+	const (
+		left   = 50.0
+		right  = pdfPageWidth - 50
+		top    = pdfPageHeight - 60
+		bottom = 60.0
+	)
+
+	pdf := newPDFWriter()
+	y := top
+
+	line := func(height float64) {
+		y -= height
+		if y < bottom {
+			pdf.addPage()
+			y = top
+		}
+	}
+
+	pdf.text(left, y, 18, true, document.Hotel.Name)
+	pdf.textRight(right, y, 16, true, strings.ToUpper(document.Title))
+	var hotelLines []string
+	for _, detail := range []string{document.Hotel.Address, document.Hotel.Phone, document.Hotel.Email} {
+		if detail != "" {
+			hotelLines = append(hotelLines, detail)
+		}
+	}
+	if document.Hotel.TaxID != "" {
+		hotelLines = append(hotelLines, "Tax ID "+document.Hotel.TaxID)
+	}
+	invoiceLines := []string{document.Invoice.Number, "Issued " + document.Invoice.IssuedAt.Format("2006-01-02")}
+	for i := 0; i < len(hotelLines) || i < len(invoiceLines); i++ {
+		line(14)
+		if i < len(hotelLines) {
+			pdf.text(left, y, 9, false, hotelLines[i])
+		}
+		if i < len(invoiceLines) {
+			pdf.textRight(right, y, 10, false, invoiceLines[i])
+		}
+	}
+	line(10)
+	pdf.rule(left, right, y)
+
+	line(24)
+	pdf.text(left, y, 10, true, "Billed to")
+	line(14)
+	pdf.text(left, y, 10, false, document.GuestName)
+	line(14)
+	pdf.text(left, y, 10, false, document.GuestEmail)
+
+	line(10)
+	for _, field := range document.Details {
+		line(14)
+		pdf.text(left, y, 10, false, field.Label)
+		pdf.text(left+110, y, 10, false, field.Value)
+	}
+
+	line(28)
+	pdf.text(left, y, 10, true, "Description")
+	pdf.textRight(right, y, 10, true, "Amount")
+	line(6)
+	pdf.rule(left, right, y)
+	for _, item := range document.Lines {
+		line(16)
+		pdf.text(left, y, 10, false, pdfFit(item.Description, 10, right-left-110))
+		pdf.textRight(right, y, 10, false, FormatInvoiceAmount(item.Amount))
+	}
+	line(8)
+	pdf.rule(left, right, y)
+
+	for i, total := range document.Totals {
+		line(16)
+		bold := i == len(document.Totals)-1
+		pdf.text(right-220, y, 10, bold, total.Description)
+		pdf.textRight(right, y, 10, bold, FormatInvoiceAmount(total.Amount))
+	}
+
+	if len(document.Refunds) > 0 {
+		line(8)
+		for _, refund := range document.Refunds {
+			line(16)
+			pdf.text(right-220, y, 10, false, refund.Description)
+			pdf.textRight(right, y, 10, false, FormatInvoiceAmount(refund.Amount))
+		}
+	}
+
+	line(32)
+	pdf.text(left, y, 8, false, "Amounts in IDR.")
+
+	return pdf.bytes()
+}
+
+// FormatInvoiceAmount writes m with thousands separators, e.g. 1,250,000.00.
+func FormatInvoiceAmount(m domain.Money) string {
+	s := m.String()
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	return sign + grouped.String() + "." + fraction
+}
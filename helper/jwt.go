@@ -1,28 +1,60 @@
 package helper
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"hotel_ip-p2/model/domain"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type JWTClaims struct {
-	UserID int `json:"user_id"`
+	UserID    int    `json:"user_id"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID int) (string, error) {
+// TokenIssuer signs the access tokens handed out at login and on refresh.
+type TokenIssuer interface {
+	Issue(userID int, role string, sessionID string) (domain.AccessToken, error)
+}
+
+type jwtTokenIssuer struct {
+	key []byte
+	ttl time.Duration
+}
+
+// NewTokenIssuer returns an issuer whose access tokens are valid for ttl.
+func NewTokenIssuer(secret string, ttl time.Duration) TokenIssuer {
+	return &jwtTokenIssuer{key: []byte(secret), ttl: ttl}
+}
+
+func (i *jwtTokenIssuer) Issue(userID int, role string, sessionID string) (domain.AccessToken, error) {
+	jti, err := RandomToken(16)
+	if err != nil {
+		return domain.AccessToken{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(i.ttl)
 	claims := JWTClaims{
-		UserID: userID,
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	secretKey := AppConfig.GetJWTSecret()
-	return token.SignedString([]byte(secretKey))
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.key)
+	if err != nil {
+		return domain.AccessToken{}, err
+	}
+	return domain.AccessToken{Token: token, JTI: jti, ExpiresAt: expiresAt.Truncate(time.Second)}, nil
 }
 
 func ValidateToken(tokenString string) (*JWTClaims, error) {
@@ -30,7 +62,7 @@ func ValidateToken(tokenString string) (*JWTClaims, error) {
 
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte(secretKey), nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}))
 
 	if err != nil {
 		return nil, err
@@ -42,3 +74,12 @@ func ValidateToken(tokenString string) (*JWTClaims, error) {
 
 	return nil, jwt.ErrSignatureInvalid
 }
+
+// RandomToken returns n random bytes, hex encoded.
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,78 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"hotel_ip-p2/model/domain"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type LoginChallengeClaims struct {
+	UserID int  `json:"user_id"`
+	Enroll bool `json:"enroll,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// LoginChallengeSigner signs the short-lived tokens that stand for a login
+// whose password was right but whose second factor is still to be checked.
+type LoginChallengeSigner interface {
+	Sign(userID int, enroll bool) (domain.LoginChallenge, error)
+	Verify(token string) (domain.LoginChallenge, error)
+}
+
+type jwtLoginChallengeSigner struct {
+	key []byte
+	ttl time.Duration
+}
+
+// NewLoginChallengeSigner returns a signer whose tokens are valid for ttl.
+// The signing key is derived from secret so that challenge tokens cannot be
+// used as access tokens or quote tokens.
+func NewLoginChallengeSigner(secret string, ttl time.Duration) LoginChallengeSigner {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("login-challenge"))
+	return &jwtLoginChallengeSigner{key: mac.Sum(nil), ttl: ttl}
+}
+
+func (s *jwtLoginChallengeSigner) Sign(userID int, enroll bool) (domain.LoginChallenge, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.ttl)
+	claims := LoginChallengeClaims{
+		UserID: userID,
+		Enroll: enroll,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.key)
+	if err != nil {
+		return domain.LoginChallenge{}, err
+	}
+	return domain.LoginChallenge{
+		UserID:             userID,
+		EnrollmentRequired: enroll,
+		Token:              token,
+		ExpiresAt:          expiresAt.Truncate(time.Second),
+	}, nil
+}
+
+func (s *jwtLoginChallengeSigner) Verify(tokenString string) (domain.LoginChallenge, error) {
+	claims := &LoginChallengeClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}), jwt.WithExpirationRequired())
+	if err != nil {
+		return domain.LoginChallenge{}, err
+	}
+
+	return domain.LoginChallenge{
+		UserID:             claims.UserID,
+		EnrollmentRequired: claims.Enroll,
+		Token:              tokenString,
+		ExpiresAt:          claims.ExpiresAt.Time,
+	}, nil
+}
@@ -1,8 +1,13 @@
 package helper
 
 import (
+	"bytes"
 	"crypto/sha512"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 )
 
 func ValidateMidtransSignature(orderID, statusCode, grossAmount, signatureKey string) bool {
@@ -16,3 +21,101 @@ func ValidateMidtransSignature(orderID, statusCode, grossAmount, signatureKey st
 
 	return calculatedSignature == signatureKey
 }
+
+type SnapTransaction struct {
+	OrderID       string
+	GrossAmount   int64
+	CustomerName  string
+	CustomerEmail string
+}
+
+type SnapResult struct {
+	Token       string `json:"token"`
+	RedirectURL string `json:"redirect_url"`
+}
+
+// SnapClient creates payment pages through the Midtrans Snap API.
+type SnapClient interface {
+	CreateTransaction(transaction SnapTransaction) (SnapResult, error)
+}
+
+type midtransSnapClient struct {
+	url        string
+	serverKey  string
+	httpClient *http.Client
+}
+
+// NewSnapClient returns a SnapClient that posts transactions to url, which
+// is the Snap transactions endpoint of the sandbox or production API.
+func NewSnapClient(url string, serverKey string) SnapClient {
+	return &midtransSnapClient{
+		url:        url,
+		serverKey:  serverKey,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type snapRequest struct {
+	TransactionDetails snapTransactionDetails `json:"transaction_details"`
+	CustomerDetails    snapCustomerDetails    `json:"customer_details"`
+}
+
+type snapTransactionDetails struct {
+	OrderID     string `json:"order_id"`
+	GrossAmount int64  `json:"gross_amount"`
+}
+
+type snapCustomerDetails struct {
+	FirstName string `json:"first_name"`
+	Email     string `json:"email"`
+}
+
+type snapErrorResponse struct {
+	ErrorMessages []string `json:"error_messages"`
+}
+
+func (client *midtransSnapClient) CreateTransaction(transaction SnapTransaction) (SnapResult, error) {
+	body, err := json.Marshal(snapRequest{
+		TransactionDetails: snapTransactionDetails{
+			OrderID:     transaction.OrderID,
+			GrossAmount: transaction.GrossAmount,
+		},
+		CustomerDetails: snapCustomerDetails{
+			FirstName: transaction.CustomerName,
+			Email:     transaction.CustomerEmail,
+		},
+	})
+	if err != nil {
+		return SnapResult{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, client.url, bytes.NewReader(body))
+	if err != nil {
+		return SnapResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(client.serverKey, "")
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return SnapResult{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		var errorResponse snapErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&errorResponse)
+		return SnapResult{}, fmt.Errorf("snap returned status %d: %s", resp.StatusCode, strings.Join(errorResponse.ErrorMessages, "; "))
+	}
+
+	var result SnapResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return SnapResult{}, err
+	}
+	if result.Token == "" {
+		return SnapResult{}, fmt.Errorf("snap response did not include a token")
+	}
+
+	return result, nil
+}
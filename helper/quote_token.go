@@ -0,0 +1,91 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"hotel_ip-p2/model/domain"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type QuoteClaims struct {
+	UserID    int               `json:"user_id"`
+	RoomID    int               `json:"room_id"`
+	CheckIn   string            `json:"check_in"`
+	CheckOut  string            `json:"check_out"`
+	PromoCode string            `json:"promo_code,omitempty"`
+	LineItems []domain.LineItem `json:"line_items"`
+	jwt.RegisteredClaims
+}
+
+// QuoteTokenSigner signs booking quotes so a later booking can prove the
+// price it was quoted.
+type QuoteTokenSigner interface {
+	Sign(quote domain.BookingQuote) (string, time.Time, error)
+	Verify(token string) (domain.BookingQuote, error)
+}
+
+type jwtQuoteTokenSigner struct {
+	key []byte
+	ttl time.Duration
+}
+
+// NewQuoteTokenSigner returns a signer whose tokens are valid for ttl. The
+// signing key is derived from secret so that quote tokens and login tokens
+// can never be used in place of each other.
+func NewQuoteTokenSigner(secret string, ttl time.Duration) QuoteTokenSigner {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("book-room-quote"))
+	return &jwtQuoteTokenSigner{key: mac.Sum(nil), ttl: ttl}
+}
+
+func (s *jwtQuoteTokenSigner) Sign(quote domain.BookingQuote) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.ttl)
+	claims := QuoteClaims{
+		UserID:    quote.UserID,
+		RoomID:    quote.RoomID,
+		CheckIn:   quote.CheckIn.Format("2006-01-02"),
+		CheckOut:  quote.CheckOut.Format("2006-01-02"),
+		PromoCode: quote.PromoCode,
+		LineItems: quote.LineItems,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.key)
+	return token, expiresAt.Truncate(time.Second), err
+}
+
+func (s *jwtQuoteTokenSigner) Verify(tokenString string) (domain.BookingQuote, error) {
+	claims := &QuoteClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}), jwt.WithExpirationRequired())
+	if err != nil {
+		return domain.BookingQuote{}, err
+	}
+
+	checkIn, err := time.Parse("2006-01-02", claims.CheckIn)
+	if err != nil {
+		return domain.BookingQuote{}, err
+	}
+	checkOut, err := time.Parse("2006-01-02", claims.CheckOut)
+	if err != nil {
+		return domain.BookingQuote{}, err
+	}
+
+	return domain.BookingQuote{
+		UserID:    claims.UserID,
+		RoomID:    claims.RoomID,
+		CheckIn:   checkIn,
+		CheckOut:  checkOut,
+		PromoCode: claims.PromoCode,
+		LineItems: claims.LineItems,
+		Token:     tokenString,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
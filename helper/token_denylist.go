@@ -0,0 +1,45 @@
+package helper
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenDenylist holds the ids of revoked access tokens in memory so that
+// every request can be checked without a database round trip. Entries are
+// kept until the token they name would have expired anyway.
+type TokenDenylist struct {
+	mu      sync.RWMutex
+	entries map[string]time.Time
+}
+
+func NewTokenDenylist() *TokenDenylist {
+	return &TokenDenylist{entries: map[string]time.Time{}}
+}
+
+// RevokedTokens is the denylist AuthMiddleware checks access tokens against.
+var RevokedTokens = NewTokenDenylist()
+
+func (d *TokenDenylist) Add(jti string, expiresAt time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries[jti] = expiresAt
+}
+
+func (d *TokenDenylist) Contains(jti string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	_, ok := d.entries[jti]
+	return ok
+}
+
+// Prune drops the entries of tokens that have expired by now.
+func (d *TokenDenylist) Prune(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for jti, expiresAt := range d.entries {
+		if !expiresAt.After(now) {
+			delete(d.entries, jti)
+		}
+	}
+}
@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"hotel_ip-p2/controller"
 	"hotel_ip-p2/helper"
 	"hotel_ip-p2/middleware"
 	"hotel_ip-p2/repository"
 	"hotel_ip-p2/route"
+	"hotel_ip-p2/scheduler"
 	"hotel_ip-p2/service"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	_ "hotel_ip-p2/docs"
 
@@ -48,20 +56,81 @@ func main() {
 	roomTypeRepository := repository.NewRoomTypeRepository()
 	roomRepository := repository.NewRoomRepository()
 	bookRoomRepository := repository.NewBookRoomRepository()
+	walletTransactionRepository := repository.NewWalletTransactionRepository()
+	ratePlanRepository := repository.NewRatePlanRepository()
+	promoCodeRepository := repository.NewPromoCodeRepository()
+	feeRuleRepository := repository.NewFeeRuleRepository()
+	invoiceRepository := repository.NewInvoiceRepository()
+	maintenanceBlockRepository := repository.NewMaintenanceBlockRepository()
+	refreshTokenRepository := repository.NewRefreshTokenRepository()
+	revokedTokenRepository := repository.NewRevokedTokenRepository()
+	userTokenRepository := repository.NewUserTokenRepository()
+	loginThrottleRepository := repository.NewLoginThrottleRepository()
+	auditLogRepository := repository.NewAuditLogRepository()
+	userTOTPRepository := repository.NewUserTOTPRepository()
+	recoveryCodeRepository := repository.NewRecoveryCodeRepository()
+	twoFactorPolicyRepository := repository.NewTwoFactorPolicyRepository()
+
+	log.Println("Initializing Midtrans Snap client")
+	snapClient := helper.NewSnapClient(helper.AppConfig.GetMidtransSnapURL(), helper.AppConfig.GetMidtransServerKey())
+
+	quoteTokenSigner := helper.NewQuoteTokenSigner(helper.AppConfig.GetJWTSecret(), helper.AppConfig.GetQuoteTokenTTL())
+	tokenIssuer := helper.NewTokenIssuer(helper.AppConfig.GetJWTSecret(), helper.AppConfig.GetAccessTokenTTL())
+	loginChallengeSigner := helper.NewLoginChallengeSigner(helper.AppConfig.GetJWTSecret(), helper.AppConfig.GetTwoFactorChallengeTTL())
+	secretBox, err := helper.NewSecretBox(helper.AppConfig.GetTwoFactorEncryptionKey())
+	if err != nil {
+		log.Fatal("Failed to initialize two-factor secret encryption:", err)
+	}
+
+	log.Println("Initializing mailer")
+	mailer, err := helper.NewMailer(helper.AppConfig.GetMailConfig())
+	if err != nil {
+		log.Fatal("Failed to initialize mailer:", err)
+	}
 
 	log.Println("Initializing services")
-	userService := service.NewUserService(userRepository, db)
-	topupService := service.NewTopupService(topupRepository, userRepository, db)
-	roomTypeService := service.NewRoomTypeService(roomTypeRepository, roomRepository, db)
-	roomService := service.NewRoomService(roomRepository, roomTypeRepository, db)
-	bookRoomService := service.NewBookRoomService(bookRoomRepository, roomRepository, userRepository, db)
+	tokenService := service.NewTokenService(refreshTokenRepository, revokedTokenRepository, userRepository, tokenIssuer, helper.RevokedTokens, helper.AppConfig.GetRefreshTokenTTL(), db)
+	userService := service.NewUserService(userRepository, bookRoomRepository, topupRepository, loginThrottleRepository, auditLogRepository, tokenService, helper.AppConfig.GetLoginPolicy(), db)
+	twoFactorService := service.NewTwoFactorService(userRepository, userTOTPRepository, recoveryCodeRepository, twoFactorPolicyRepository, loginThrottleRepository, auditLogRepository, helper.AppConfig.GetLoginPolicy(), loginChallengeSigner, secretBox, helper.AppConfig.GetHotelDetails().Name, db)
+	accountService := service.NewAccountService(userRepository, userTokenRepository, bookRoomRepository, topupRepository, loginThrottleRepository, auditLogRepository, userTOTPRepository, recoveryCodeRepository, tokenService, mailer, helper.AppConfig.GetAccountMailPolicy(), helper.AppConfig.GetLoginPolicy(), db)
+	walletService := service.NewWalletService(walletTransactionRepository, userRepository, db)
+	topupService := service.NewTopupService(topupRepository, userRepository, walletTransactionRepository, snapClient, db)
+	roomTypeService := service.NewRoomTypeService(roomTypeRepository, roomRepository, ratePlanRepository, db)
+	roomService := service.NewRoomService(roomRepository, roomTypeRepository, ratePlanRepository, db)
+	ratePlanService := service.NewRatePlanService(ratePlanRepository, roomTypeRepository, db)
+	promoCodeService := service.NewPromoCodeService(promoCodeRepository, roomTypeRepository, db)
+	feeRuleService := service.NewFeeRuleService(feeRuleRepository, db)
+	maintenanceBlockService := service.NewMaintenanceBlockService(maintenanceBlockRepository, roomRepository, bookRoomRepository, db)
+	invoiceService := service.NewInvoiceService(invoiceRepository, bookRoomRepository, topupRepository, userRepository, helper.AppConfig.GetHotelDetails(), db)
+	bookRoomService := service.NewBookRoomService(bookRoomRepository, roomRepository, maintenanceBlockRepository, ratePlanRepository, promoCodeRepository, feeRuleRepository, userRepository, walletTransactionRepository, helper.AppConfig.GetRefundPolicy(), quoteTokenSigner, db)
+
+	if email := helper.AppConfig.GetBootstrapAdminEmail(); email != "" {
+		admin, err := userService.BootstrapAdmin(email)
+		if err != nil {
+			log.Printf("Failed to bootstrap admin %s: %v", email, err)
+		} else if admin.ID != 0 {
+			log.Printf("Promoted user ID: %d to admin", admin.ID)
+		}
+	}
 
 	log.Println("Initializing controllers")
-	userController := controller.NewUserController(userService)
+	userController := controller.NewUserController(userService, tokenService, accountService, twoFactorService)
+	twoFactorController := controller.NewTwoFactorController(twoFactorService, tokenService)
 	topupController := controller.NewTopupController(topupService)
 	roomTypeController := controller.NewRoomTypeController(roomTypeService)
 	roomController := controller.NewRoomController(roomService)
 	bookRoomController := controller.NewBookRoomController(bookRoomService)
+	walletController := controller.NewWalletController(walletService)
+	ratePlanController := controller.NewRatePlanController(ratePlanService)
+	promoCodeController := controller.NewPromoCodeController(promoCodeService)
+	feeRuleController := controller.NewFeeRuleController(feeRuleService)
+	invoiceController := controller.NewInvoiceController(invoiceService)
+	maintenanceBlockController := controller.NewMaintenanceBlockController(maintenanceBlockService)
+
+	log.Println("Loading revoked tokens")
+	if err := tokenService.SyncDenylist(context.Background()); err != nil {
+		log.Fatal("Failed to load revoked tokens:", err)
+	}
 
 	log.Println("Setting up Echo framework")
 	e := echo.New()
@@ -70,6 +139,14 @@ func main() {
 	e.Use(echomiddleware.Recover())
 	e.Use(echomiddleware.CORS())
 
+	// Failed logins are throttled per client IP, so the IP must not come
+	// from headers a client can set unless a trusted proxy sets them.
+	if helper.AppConfig.TrustProxyHeaders() {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	} else {
+		e.IPExtractor = echo.ExtractIPDirect()
+	}
+
 	e.Validator = helper.NewValidator()
 	e.HTTPErrorHandler = middleware.ErrorHandler
 
@@ -78,14 +155,76 @@ func main() {
 
 	log.Println("Registering API routes")
 	api := e.Group("/api")
-	route.UserRoutes(api, userController, topupController)
+	route.UserRoutes(api, userController, twoFactorController, topupController, invoiceController)
 	route.RoomTypeRoutes(api, roomTypeController)
 	route.RoomRoutes(api, roomController)
-	route.BookRoomRoutes(api, bookRoomController)
+	route.MaintenanceBlockRoutes(api, maintenanceBlockController)
+	route.BookRoomRoutes(api, bookRoomController, invoiceController)
+	route.WalletRoutes(api, walletController)
+	route.AdminRoutes(api, userController, twoFactorController, roomController, roomTypeController, walletController, ratePlanController, promoCodeController, feeRuleController)
+
+	log.Println("Starting background jobs")
+	jobScheduler := scheduler.NewScheduler(db)
+	jobScheduler.Add(scheduler.Job{
+		Name: "no-show-sweep",
+		Next: scheduler.Daily(helper.AppConfig.GetNoShowSweepTime()),
+		Run: func(ctx context.Context) error {
+			marked, err := bookRoomService.SweepNoShows(ctx)
+			log.Printf("No-show sweep marked %d bookings", marked)
+			return err
+		},
+	})
+	jobScheduler.Add(scheduler.Job{
+		Name: "token-denylist-sync",
+		Next: scheduler.Every(helper.AppConfig.GetDenylistSyncInterval()),
+		Run: func(ctx context.Context) error {
+			return tokenService.SyncDenylist(ctx)
+		},
+		EveryReplica: true,
+	})
+	jobScheduler.Add(scheduler.Job{
+		Name: "expired-token-purge",
+		Next: scheduler.Every(time.Hour),
+		Run: func(ctx context.Context) error {
+			purged, err := tokenService.PurgeExpired(ctx)
+			if err != nil {
+				return err
+			}
+			purgedAccountTokens, err := accountService.PurgeExpired(ctx)
+			if err != nil {
+				return err
+			}
+			purgedThrottles, err := userService.PurgeLoginThrottles(ctx)
+			log.Printf("Purged %d expired tokens and %d expired login throttles", purged+purgedAccountTokens, purgedThrottles)
+			return err
+		},
+	})
+	jobScheduler.Start()
 
 	port := ":8080"
-	log.Printf("Server starting on port %s", port)
-	if err := e.Start(port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	go func() {
+		log.Printf("Server starting on port %s", port)
+		if err := e.Start(port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Println("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to shut down server gracefully: %v", err)
 	}
+
+	log.Println("Waiting for emails still being sent")
+	if err := accountService.Drain(shutdownCtx); err != nil {
+		log.Printf("Gave up waiting for emails to be sent: %v", err)
+	}
+
+	log.Println("Stopping background jobs")
+	jobScheduler.Stop()
 }
@@ -7,41 +7,67 @@ import (
 	"time"
 )
 
-func ToBookRoomDomain(req request.BookRoomRequest, userID int) (domain.BookRoom, error) {
-	date, err := time.Parse("2006-01-02", req.Date)
-	if err != nil {
-		return domain.BookRoom{}, err
+// ToBookRoomDomain builds the booking from the request and the stay dates
+// the controller has already parsed and validated.
+func ToBookRoomDomain(req request.BookRoomRequest, userID int, checkIn time.Time, checkOut time.Time) domain.BookRoom {
+	return domain.BookRoom{
+		RoomID:   req.RoomID,
+		UserID:   userID,
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
 	}
+}
 
+func ToBookRoomQuoteDomain(req request.BookRoomQuoteRequest, userID int, checkIn time.Time, checkOut time.Time) domain.BookRoom {
 	return domain.BookRoom{
-		RoomID: req.RoomID,
-		UserID: userID,
-		Date:   date,
-	}, nil
+		RoomID:   req.RoomID,
+		UserID:   userID,
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
+	}
+}
+
+func ToBookingOptions(req request.BookRoomRequest) domain.BookingOptions {
+	return domain.BookingOptions{
+		QuoteToken: req.QuoteToken,
+		PromoCode:  req.PromoCode,
+	}
+}
+
+func ToBookingQuoteOptions(req request.BookRoomQuoteRequest) domain.BookingOptions {
+	return domain.BookingOptions{
+		PromoCode: req.PromoCode,
+	}
 }
 
 func ToBookRoomResponse(bookRoom domain.BookRoom) response.BookRoomResponse {
+	breakdown := bookRoom.Breakdown()
 	return response.BookRoomResponse{
-		ID:     bookRoom.ID,
-		RoomID: bookRoom.RoomID,
-		UserID: bookRoom.UserID,
-		Date:   bookRoom.Date.Format("2006-01-02"),
-		Price:  bookRoom.Price,
-		Room: response.RoomResponse{
-			ID:         bookRoom.Room.ID,
-			RoomTypeID: bookRoom.Room.RoomTypeID,
-			RoomNumber: bookRoom.Room.RoomNumber,
-			RoomType: response.RoomTypeResponse{
-				ID:    bookRoom.Room.RoomType.ID,
-				Name:  bookRoom.Room.RoomType.Name,
-				Price: bookRoom.Room.RoomType.Price,
-			},
-		},
+		ID:            bookRoom.ID,
+		RoomID:        bookRoom.RoomID,
+		UserID:        bookRoom.UserID,
+		CheckIn:       bookRoom.CheckIn.Format("2006-01-02"),
+		CheckOut:      bookRoom.CheckOut.Format("2006-01-02"),
+		Nights:        len(bookRoom.NightDates()),
+		Price:         bookRoom.Price,
+		LineItems:     ToLineItemResponses(breakdown),
+		Subtotal:      breakdown.Subtotal(),
+		Discount:      breakdown.Discount(),
+		Tax:           breakdown.Tax(),
+		ServiceCharge: breakdown.ServiceCharge(),
+		NetAmount:     breakdown.Net(),
+		Status:        bookRoom.Status,
+		RefundAmount:  bookRoom.RefundAmount,
+		CancelledAt:   bookRoom.CancelledAt,
+		CheckedInAt:   bookRoom.CheckedInAt,
+		CheckedOutAt:  bookRoom.CheckedOutAt,
+		Room:          ToRoomResponse(bookRoom.Room),
 		User: response.UserResponse{
-			ID:      bookRoom.User.ID,
-			Name:    bookRoom.User.Name,
-			Email:   bookRoom.User.Email,
-			Balance: bookRoom.User.Balance,
+			ID:        bookRoom.User.ID,
+			Name:      bookRoom.User.Name,
+			Email:     bookRoom.User.Email,
+			Balance:   bookRoom.User.Balance,
+			DeletedAt: toDeletedAt(bookRoom.User.DeletedAt),
 		},
 	}
 }
@@ -53,3 +79,41 @@ func ToBookRoomResponses(bookRooms []domain.BookRoom) []response.BookRoomRespons
 	}
 	return responses
 }
+
+func ToLineItemResponses(items domain.LineItems) []response.LineItemResponse {
+	responses := []response.LineItemResponse{}
+	for _, item := range items {
+		itemResponse := response.LineItemResponse{
+			Type:        item.Type,
+			Description: item.Description,
+			Inclusive:   item.Inclusive,
+			Amount:      item.Amount,
+		}
+		if item.Date != nil {
+			date := item.Date.Format("2006-01-02")
+			itemResponse.Date = &date
+		}
+		responses = append(responses, itemResponse)
+	}
+	return responses
+}
+
+func ToBookRoomQuoteResponse(quote domain.BookingQuote) response.BookRoomQuoteResponse {
+	return response.BookRoomQuoteResponse{
+		RoomID:        quote.RoomID,
+		CheckIn:       quote.CheckIn.Format("2006-01-02"),
+		CheckOut:      quote.CheckOut.Format("2006-01-02"),
+		Nights:        len(domain.BookRoom{CheckIn: quote.CheckIn, CheckOut: quote.CheckOut}.NightDates()),
+		PromoCode:     quote.PromoCode,
+		LineItems:     ToLineItemResponses(quote.LineItems),
+		Subtotal:      quote.Subtotal(),
+		Discount:      quote.Discount(),
+		Tax:           quote.Tax(),
+		ServiceCharge: quote.ServiceCharge(),
+		Total:         quote.Total(),
+		Balance:       quote.Balance,
+		BalanceCovers: quote.BalanceCovers(),
+		QuoteToken:    quote.Token,
+		ExpiresAt:     quote.ExpiresAt,
+	}
+}
@@ -0,0 +1,47 @@
+package mapper
+
+import (
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/model/web/request"
+	"hotel_ip-p2/model/web/response"
+)
+
+func ToFeeRuleDomain(req request.FeeRuleRequest) domain.FeeRule {
+	basis := req.Basis
+	if basis == "" {
+		basis = domain.FeeBasisPerStay
+	}
+
+	return domain.FeeRule{
+		Name:        req.Name,
+		Type:        req.Type,
+		Calculation: req.Calculation,
+		Rate:        req.Rate,
+		Amount:      req.Amount,
+		Basis:       basis,
+		Inclusive:   req.Inclusive,
+		Active:      req.Active == nil || *req.Active,
+	}
+}
+
+func ToFeeRuleResponse(feeRule domain.FeeRule) response.FeeRuleResponse {
+	return response.FeeRuleResponse{
+		ID:          feeRule.ID,
+		Name:        feeRule.Name,
+		Type:        feeRule.Type,
+		Calculation: feeRule.Calculation,
+		Rate:        feeRule.Rate,
+		Amount:      feeRule.Amount,
+		Basis:       feeRule.Basis,
+		Inclusive:   feeRule.Inclusive,
+		Active:      feeRule.Active,
+	}
+}
+
+func ToFeeRuleResponses(feeRules []domain.FeeRule) []response.FeeRuleResponse {
+	var responses []response.FeeRuleResponse
+	for _, feeRule := range feeRules {
+		responses = append(responses, ToFeeRuleResponse(feeRule))
+	}
+	return responses
+}
@@ -0,0 +1,63 @@
+package mapper
+
+import (
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/model/web/request"
+	"hotel_ip-p2/model/web/response"
+	"time"
+)
+
+func ToMaintenanceBlockDomain(req request.MaintenanceBlockRequest) (domain.MaintenanceBlock, domain.RelocationPlan, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return domain.MaintenanceBlock{}, domain.RelocationPlan{}, err
+	}
+
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return domain.MaintenanceBlock{}, domain.RelocationPlan{}, err
+	}
+
+	plan := domain.RelocationPlan{Force: req.Force}
+	for _, relocation := range req.Relocations {
+		plan.Relocations = append(plan.Relocations, domain.Relocation{
+			BookRoomID: relocation.BookRoomID,
+			RoomID:     relocation.RoomID,
+		})
+	}
+
+	return domain.MaintenanceBlock{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Reason:    req.Reason,
+	}, plan, nil
+}
+
+func ToMaintenanceBlockResponse(block domain.MaintenanceBlock) response.MaintenanceBlockResponse {
+	blockResponse := response.MaintenanceBlockResponse{
+		ID:        block.ID,
+		RoomID:    block.RoomID,
+		StartDate: block.StartDate.Format("2006-01-02"),
+		EndDate:   block.EndDate.Format("2006-01-02"),
+		Reason:    block.Reason,
+		CreatedBy: block.CreatedBy,
+		CreatedAt: block.CreatedAt,
+	}
+
+	for _, relocation := range block.Relocations {
+		blockResponse.Relocations = append(blockResponse.Relocations, response.RelocationResponse{
+			BookRoomID: relocation.BookRoomID,
+			RoomID:     relocation.RoomID,
+		})
+	}
+
+	return blockResponse
+}
+
+func ToMaintenanceBlockResponses(blocks []domain.MaintenanceBlock) []response.MaintenanceBlockResponse {
+	var responses []response.MaintenanceBlockResponse
+	for _, block := range blocks {
+		responses = append(responses, ToMaintenanceBlockResponse(block))
+	}
+	return responses
+}
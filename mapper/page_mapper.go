@@ -0,0 +1,24 @@
+package mapper
+
+import (
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/model/web"
+)
+
+func ToPageMeta(pagination domain.Pagination, total int64) *web.PageMeta {
+	totalPages := int((total + int64(pagination.PerPage) - 1) / int64(pagination.PerPage))
+	return &web.PageMeta{
+		Page:       pagination.Page,
+		PerPage:    pagination.PerPage,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
+
+func ToCursorPageMeta(perPage int, next *domain.Cursor) *web.PageMeta {
+	meta := &web.PageMeta{PerPage: perPage}
+	if next != nil {
+		meta.NextCursor = next.Encode()
+	}
+	return meta
+}
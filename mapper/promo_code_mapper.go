@@ -0,0 +1,57 @@
+package mapper
+
+import (
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/model/web/request"
+	"hotel_ip-p2/model/web/response"
+)
+
+func ToPromoCodeDomain(req request.PromoCodeRequest) domain.PromoCode {
+	promoCode := domain.PromoCode{
+		Code:                  req.Code,
+		Description:           req.Description,
+		DiscountType:          req.DiscountType,
+		PercentOff:            req.PercentOff,
+		AmountOff:             req.AmountOff,
+		ValidFrom:             req.ValidFrom,
+		ValidUntil:            req.ValidUntil,
+		MinNights:             req.MinNights,
+		MaxRedemptions:        req.MaxRedemptions,
+		MaxRedemptionsPerUser: req.MaxRedemptionsPerUser,
+		Active:                req.Active == nil || *req.Active,
+	}
+	for _, roomTypeID := range req.RoomTypeIDs {
+		promoCode.RoomTypes = append(promoCode.RoomTypes, domain.RoomType{ID: roomTypeID})
+	}
+	return promoCode
+}
+
+func ToPromoCodeResponse(promoCode domain.PromoCode) response.PromoCodeResponse {
+	promoCodeResponse := response.PromoCodeResponse{
+		ID:                    promoCode.ID,
+		Code:                  promoCode.Code,
+		Description:           promoCode.Description,
+		DiscountType:          promoCode.DiscountType,
+		PercentOff:            promoCode.PercentOff,
+		AmountOff:             promoCode.AmountOff,
+		ValidFrom:             promoCode.ValidFrom,
+		ValidUntil:            promoCode.ValidUntil,
+		MinNights:             promoCode.MinNights,
+		MaxRedemptions:        promoCode.MaxRedemptions,
+		MaxRedemptionsPerUser: promoCode.MaxRedemptionsPerUser,
+		RoomTypeIDs:           []int{},
+		Active:                promoCode.Active,
+	}
+	for _, roomType := range promoCode.RoomTypes {
+		promoCodeResponse.RoomTypeIDs = append(promoCodeResponse.RoomTypeIDs, roomType.ID)
+	}
+	return promoCodeResponse
+}
+
+func ToPromoCodeResponses(promoCodes []domain.PromoCode) []response.PromoCodeResponse {
+	var responses []response.PromoCodeResponse
+	for _, promoCode := range promoCodes {
+		responses = append(responses, ToPromoCodeResponse(promoCode))
+	}
+	return responses
+}
@@ -0,0 +1,94 @@
+package mapper
+
+import (
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/model/web/request"
+	"hotel_ip-p2/model/web/response"
+	"time"
+)
+
+func ToRatePlanDomain(req request.RatePlanRequest) (domain.RatePlan, error) {
+	ratePlan := domain.RatePlan{
+		Name:     req.Name,
+		Price:    req.Price,
+		Priority: req.Priority,
+	}
+
+	if req.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			return domain.RatePlan{}, err
+		}
+		ratePlan.StartDate = &startDate
+	}
+
+	if req.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			return domain.RatePlan{}, err
+		}
+		ratePlan.EndDate = &endDate
+	}
+
+	for _, day := range req.Weekdays {
+		ratePlan.Weekdays |= domain.NewWeekdays(time.Weekday(day))
+	}
+
+	return ratePlan, nil
+}
+
+func ToRatePlanResponse(ratePlan domain.RatePlan) response.RatePlanResponse {
+	ratePlanResponse := response.RatePlanResponse{
+		ID:         ratePlan.ID,
+		RoomTypeID: ratePlan.RoomTypeID,
+		Name:       ratePlan.Name,
+		Weekdays:   []int{},
+		Price:      ratePlan.Price,
+		Priority:   ratePlan.Priority,
+	}
+
+	if ratePlan.StartDate != nil {
+		startDate := ratePlan.StartDate.Format("2006-01-02")
+		ratePlanResponse.StartDate = &startDate
+	}
+	if ratePlan.EndDate != nil {
+		endDate := ratePlan.EndDate.Format("2006-01-02")
+		ratePlanResponse.EndDate = &endDate
+	}
+	for _, day := range ratePlan.Weekdays.Days() {
+		ratePlanResponse.Weekdays = append(ratePlanResponse.Weekdays, int(day))
+	}
+
+	return ratePlanResponse
+}
+
+func ToRatePlanResponses(ratePlans []domain.RatePlan) []response.RatePlanResponse {
+	var responses []response.RatePlanResponse
+	for _, ratePlan := range ratePlans {
+		responses = append(responses, ToRatePlanResponse(ratePlan))
+	}
+	return responses
+}
+
+func ToRateQuoteResponse(quote domain.RateQuote) response.RateQuoteResponse {
+	quoteResponse := response.RateQuoteResponse{
+		RoomType: ToRoomTypeResponse(quote.RoomType),
+		CheckIn:  quote.CheckIn.Format("2006-01-02"),
+		CheckOut: quote.CheckOut.Format("2006-01-02"),
+		Total:    quote.Total,
+	}
+
+	for _, night := range quote.Nights {
+		nightResponse := response.NightPriceResponse{
+			Date:  night.Date.Format("2006-01-02"),
+			Price: night.Price,
+		}
+		if night.RatePlan != nil {
+			nightResponse.RatePlanID = &night.RatePlan.ID
+			nightResponse.RatePlan = night.RatePlan.Name
+		}
+		quoteResponse.Nights = append(quoteResponse.Nights, nightResponse)
+	}
+
+	return quoteResponse
+}
@@ -0,0 +1,24 @@
+package mapper
+
+import (
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/model/web/response"
+)
+
+func ToRoomAvailabilityResponse(availability domain.RoomAvailability) response.RoomAvailabilityResponse {
+	return response.RoomAvailabilityResponse{
+		RoomType:       ToRoomTypeResponse(availability.RoomType),
+		Nights:         availability.Nights,
+		TotalPrice:     availability.TotalPrice,
+		AvailableRooms: len(availability.Rooms),
+		Rooms:          ToRoomResponses(availability.Rooms),
+	}
+}
+
+func ToRoomAvailabilityResponses(availabilities []domain.RoomAvailability) []response.RoomAvailabilityResponse {
+	var responses []response.RoomAvailabilityResponse
+	for _, availability := range availabilities {
+		responses = append(responses, ToRoomAvailabilityResponse(availability))
+	}
+	return responses
+}
@@ -4,6 +4,9 @@ import (
 	"hotel_ip-p2/model/domain"
 	"hotel_ip-p2/model/web/request"
 	"hotel_ip-p2/model/web/response"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 func ToRoomDomain(req request.RoomRequest) domain.Room {
@@ -18,12 +21,18 @@ func ToRoomResponse(room domain.Room) response.RoomResponse {
 		ID:         room.ID,
 		RoomTypeID: room.RoomTypeID,
 		RoomNumber: room.RoomNumber,
-		RoomType: response.RoomTypeResponse{
-			ID:    room.RoomType.ID,
-			Name:  room.RoomType.Name,
-			Price: room.RoomType.Price,
-		},
+		RoomType:   ToRoomTypeResponse(room.RoomType),
+		DeletedAt:  toDeletedAt(room.DeletedAt),
+	}
+}
+
+// toDeletedAt returns when a soft-deleted row was deleted, or nil if it has
+// not been.
+func toDeletedAt(deletedAt gorm.DeletedAt) *time.Time {
+	if !deletedAt.Valid {
+		return nil
 	}
+	return &deletedAt.Time
 }
 
 func ToRoomResponses(rooms []domain.Room) []response.RoomResponse {
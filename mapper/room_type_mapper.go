@@ -15,9 +15,10 @@ func ToRoomTypeDomain(req request.RoomTypeRequest) domain.RoomType {
 
 func ToRoomTypeResponse(roomType domain.RoomType) response.RoomTypeResponse {
 	return response.RoomTypeResponse{
-		ID:    roomType.ID,
-		Name:  roomType.Name,
-		Price: roomType.Price,
+		ID:        roomType.ID,
+		Name:      roomType.Name,
+		Price:     roomType.Price,
+		DeletedAt: toDeletedAt(roomType.DeletedAt),
 	}
 }
 
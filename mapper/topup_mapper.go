@@ -4,20 +4,31 @@ import (
 	"hotel_ip-p2/model/domain"
 	"hotel_ip-p2/model/web/request"
 	"hotel_ip-p2/model/web/response"
-	"strconv"
 )
 
 func ToTopupDomain(req request.TopupWebhookRequest) (domain.Topup, error) {
-	amount, err := strconv.ParseFloat(req.GrossAmount, 64)
+	amount, err := domain.ParseMoney(req.GrossAmount)
 	if err != nil {
 		return domain.Topup{}, err
 	}
 
+	// Refund notifications carry the total refunded so far for the order.
+	var refundedAmount domain.Money
+	if req.RefundAmount != "" {
+		refundedAmount, err = domain.ParseMoney(req.RefundAmount)
+		if err != nil {
+			return domain.Topup{}, err
+		}
+	}
+
 	return domain.Topup{
 		MidtransTransactionID: req.TransactionID,
 		MidtransOrderID:       req.OrderID,
 		Amount:                amount,
+		RefundedAmount:        refundedAmount,
 		Status:                req.TransactionStatus,
+		FraudStatus:           req.FraudStatus,
+		PaymentType:           req.PaymentType,
 	}, nil
 }
 
@@ -28,7 +39,12 @@ func ToTopupResponse(topup domain.Topup) response.TopupResponse {
 		MidtransTransactionID: topup.MidtransTransactionID,
 		MidtransOrderID:       topup.MidtransOrderID,
 		Amount:                topup.Amount,
+		RefundedAmount:        topup.RefundedAmount,
 		Status:                topup.Status,
+		PaymentType:           topup.PaymentType,
+		FraudStatus:           topup.FraudStatus,
+		SnapToken:             topup.SnapToken,
+		RedirectURL:           topup.RedirectURL,
 		CreatedAt:             topup.CreatedAt,
 		UpdatedAt:             topup.UpdatedAt,
 	}
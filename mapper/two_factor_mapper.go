@@ -0,0 +1,50 @@
+package mapper
+
+import (
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/model/web/response"
+	"time"
+)
+
+func ToLoginChallengeResponse(challenge domain.LoginChallenge) response.LoginChallengeResponse {
+	return response.LoginChallengeResponse{
+		ChallengeToken:     challenge.Token,
+		ExpiresAt:          challenge.ExpiresAt,
+		EnrollmentRequired: challenge.EnrollmentRequired,
+	}
+}
+
+func ToTwoFactorLoginResponse(tokens domain.TokenPair, recoveryCodes []string) response.TwoFactorLoginResponse {
+	return response.TwoFactorLoginResponse{
+		LoginResponse: ToLoginResponse(tokens),
+		RecoveryCodes: recoveryCodes,
+	}
+}
+
+func ToTOTPEnrollmentResponse(enrollment domain.TOTPEnrollment) response.TOTPEnrollmentResponse {
+	return response.TOTPEnrollmentResponse{
+		Secret: enrollment.Secret,
+		URI:    enrollment.URI,
+	}
+}
+
+func ToTwoFactorPolicyResponse(policy domain.TwoFactorPolicy) response.TwoFactorPolicyResponse {
+	var updatedAt *time.Time
+	if !policy.UpdatedAt.IsZero() {
+		updatedAt = &policy.UpdatedAt
+	}
+	return response.TwoFactorPolicyResponse{
+		Role:      policy.Role,
+		Required:  policy.Required,
+		UpdatedBy: policy.UpdatedBy,
+		UpdatedAt: updatedAt,
+	}
+}
+
+func ToTwoFactorPolicyResponses(policies []domain.TwoFactorPolicy) []response.TwoFactorPolicyResponse {
+	var responses []response.TwoFactorPolicyResponse
+	for _, policy := range policies {
+		responses = append(responses, ToTwoFactorPolicyResponse(policy))
+	}
+	return responses
+}
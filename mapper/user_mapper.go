@@ -16,11 +16,31 @@ func ToUserDomain(req request.UserRequest) domain.User {
 
 func ToUserResponse(user domain.User) response.UserResponse {
 	return response.UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		Balance:   user.Balance,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:              user.ID,
+		Name:            user.Name,
+		Email:           user.Email,
+		Balance:         user.Balance,
+		Role:            user.Role,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+		DeletedAt:       toDeletedAt(user.DeletedAt),
+	}
+}
+
+func ToUserResponses(users []domain.User) []response.UserResponse {
+	var responses []response.UserResponse
+	for _, user := range users {
+		responses = append(responses, ToUserResponse(user))
+	}
+	return responses
+}
+
+func ToLoginResponse(tokens domain.TokenPair) response.LoginResponse {
+	return response.LoginResponse{
+		Token:            tokens.AccessToken.Token,
+		ExpiresAt:        tokens.AccessToken.ExpiresAt,
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
 	}
 }
@@ -0,0 +1,40 @@
+package mapper
+
+import (
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/model/web/response"
+)
+
+func ToWalletTransactionResponse(transaction domain.WalletTransaction) response.WalletTransactionResponse {
+	return response.WalletTransactionResponse{
+		ID:            transaction.ID,
+		Type:          transaction.Type,
+		Amount:        transaction.Amount,
+		BalanceAfter:  transaction.BalanceAfter,
+		ContraAccount: transaction.ContraAccount,
+		ReferenceType: transaction.ReferenceType,
+		ReferenceID:   transaction.ReferenceID,
+		Description:   transaction.Description,
+		CreatedAt:     transaction.CreatedAt,
+	}
+}
+
+func ToWalletTransactionResponses(transactions []domain.WalletTransaction) []response.WalletTransactionResponse {
+	var responses []response.WalletTransactionResponse
+	for _, transaction := range transactions {
+		responses = append(responses, ToWalletTransactionResponse(transaction))
+	}
+	return responses
+}
+
+func ToWalletReconciliationResponses(reconciliations []domain.WalletReconciliation) []response.WalletReconciliationResponse {
+	var responses []response.WalletReconciliationResponse
+	for _, reconciliation := range reconciliations {
+		responses = append(responses, response.WalletReconciliationResponse{
+			UserID:        reconciliation.UserID,
+			Balance:       reconciliation.Balance,
+			LedgerBalance: reconciliation.LedgerBalance,
+		})
+	}
+	return responses
+}
@@ -23,11 +23,17 @@ func AuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 
 		token := tokenParts[1]
 		claims, err := helper.ValidateToken(token)
-		if err != nil {
+		if err != nil || claims.ID == "" {
 			return exception.NewCustomError(http.StatusUnauthorized, "Invalid or expired token")
 		}
 
+		if helper.RevokedTokens.Contains(claims.ID) {
+			return exception.NewCustomError(http.StatusUnauthorized, "Token has been revoked")
+		}
+
 		c.Set("user_id", claims.UserID)
+		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
 
 		return next(c)
 	}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"hotel_ip-p2/exception"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireRole only lets the request through when the authenticated user holds
+// one of roles. It must run after AuthMiddleware.
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, _ := c.Get("role").(string)
+			for _, allowed := range roles {
+				if role == allowed {
+					return next(c)
+				}
+			}
+
+			return exception.NewCustomError(http.StatusForbidden, "Insufficient permissions")
+		}
+	}
+}
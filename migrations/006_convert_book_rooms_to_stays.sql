@@ -0,0 +1,34 @@
+-- Convert single-night bookings into stays with a check-in and check-out date.
+-- Each booked night is tracked in book_room_nights so overlapping stays are
+-- rejected by the unique (room_id, date) constraint.
+ALTER TABLE book_rooms ADD COLUMN IF NOT EXISTS check_in DATE;
+ALTER TABLE book_rooms ADD COLUMN IF NOT EXISTS check_out DATE;
+
+UPDATE book_rooms SET check_in = date, check_out = date + 1 WHERE check_in IS NULL;
+
+ALTER TABLE book_rooms ALTER COLUMN check_in SET NOT NULL;
+ALTER TABLE book_rooms ALTER COLUMN check_out SET NOT NULL;
+ALTER TABLE book_rooms ADD CONSTRAINT check_stay_range CHECK (check_out > check_in);
+
+CREATE TABLE IF NOT EXISTS book_room_nights (
+    id SERIAL PRIMARY KEY,
+    book_room_id INT NOT NULL,
+    room_id INT NOT NULL,
+    date DATE NOT NULL,
+    price DECIMAL(19,2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_book_room_nights_book_room FOREIGN KEY (book_room_id)
+        REFERENCES book_rooms(id) ON DELETE CASCADE,
+    CONSTRAINT fk_book_room_nights_room FOREIGN KEY (room_id)
+        REFERENCES rooms(id) ON DELETE CASCADE,
+    CONSTRAINT unique_room_date UNIQUE(room_id, date),
+    CONSTRAINT check_night_price_positive CHECK (price > 0)
+);
+
+INSERT INTO book_room_nights (book_room_id, room_id, date, price)
+SELECT id, room_id, date, price FROM book_rooms;
+
+ALTER TABLE book_rooms DROP COLUMN date;
+
+CREATE INDEX IF NOT EXISTS idx_book_rooms_user_id ON book_rooms(user_id);
@@ -0,0 +1,7 @@
+-- Track cancellation and the amount refunded to the wallet on each booking.
+ALTER TABLE book_rooms ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'confirmed';
+ALTER TABLE book_rooms ADD COLUMN IF NOT EXISTS refund_amount DECIMAL(19,2) NOT NULL DEFAULT 0;
+ALTER TABLE book_rooms ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE book_rooms ADD CONSTRAINT check_book_room_status_valid CHECK (status IN ('confirmed', 'cancelled'));
+ALTER TABLE book_rooms ADD CONSTRAINT check_refund_amount_range CHECK (refund_amount >= 0 AND refund_amount <= price);
@@ -0,0 +1,2 @@
+-- Availability search looks up booked nights by date range across all rooms.
+CREATE INDEX IF NOT EXISTS idx_book_room_nights_date ON book_room_nights(date);
@@ -0,0 +1,4 @@
+-- Every user holds exactly one role. Existing accounts become guests; the
+-- first admin is promoted on start-up through BOOTSTRAP_ADMIN_EMAIL.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'guest';
+ALTER TABLE users ADD CONSTRAINT check_role_valid CHECK (role IN ('admin', 'staff', 'guest'));
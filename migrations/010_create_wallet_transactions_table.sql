@@ -0,0 +1,59 @@
+-- Append-only wallet ledger. Every change to users.balance is written here in
+-- the same transaction, posted against a contra account.
+CREATE TABLE IF NOT EXISTS wallet_transactions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    amount DECIMAL(19,2) NOT NULL,
+    balance_after DECIMAL(19,2) NOT NULL,
+    contra_account VARCHAR(50) NOT NULL,
+    reference_type VARCHAR(30),
+    reference_id INT,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CONSTRAINT check_wallet_transaction_type_valid CHECK (type IN ('topup_credit', 'booking_debit', 'refund_credit', 'adjustment')),
+    CONSTRAINT check_wallet_transaction_amount_non_zero CHECK (amount <> 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_wallet_transactions_user_id ON wallet_transactions(user_id, id);
+
+-- Backfill the ledger from the history we already have.
+INSERT INTO wallet_transactions (user_id, type, amount, balance_after, contra_account, reference_type, reference_id, description, created_at)
+SELECT user_id, 'topup_credit', amount, 0, 'payment_gateway', 'topup', id, 'Topup ' || midtrans_order_id, created_at
+FROM topups WHERE status = 'settlement';
+
+INSERT INTO wallet_transactions (user_id, type, amount, balance_after, contra_account, reference_type, reference_id, description, created_at)
+SELECT user_id, 'booking_debit', -price, 0, 'room_revenue', 'book_room', id, 'Booking #' || id, created_at
+FROM book_rooms;
+
+INSERT INTO wallet_transactions (user_id, type, amount, balance_after, contra_account, reference_type, reference_id, description, created_at)
+SELECT user_id, 'refund_credit', refund_amount, 0, 'room_revenue', 'book_room', id, 'Refund for cancelled booking #' || id, cancelled_at
+FROM book_rooms WHERE refund_amount > 0;
+
+-- Whatever the history does not explain becomes an opening balance so the
+-- ledger sums to the current balance of every user.
+INSERT INTO wallet_transactions (user_id, type, amount, balance_after, contra_account, description, created_at)
+SELECT users.id, 'adjustment', users.balance - COALESCE(SUM(wallet_transactions.amount), 0), 0, 'adjustments', 'Opening balance', users.created_at
+FROM users
+LEFT JOIN wallet_transactions ON wallet_transactions.user_id = users.id
+GROUP BY users.id, users.balance, users.created_at
+HAVING users.balance <> COALESCE(SUM(wallet_transactions.amount), 0);
+
+UPDATE wallet_transactions
+SET balance_after = running.balance
+FROM (
+    SELECT id, SUM(amount) OVER (PARTITION BY user_id ORDER BY created_at, id) AS balance
+    FROM wallet_transactions
+) running
+WHERE wallet_transactions.id = running.id;
+
+CREATE OR REPLACE FUNCTION prevent_wallet_transaction_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'wallet_transactions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER wallet_transactions_append_only
+    BEFORE UPDATE OR DELETE ON wallet_transactions
+    FOR EACH ROW EXECUTE FUNCTION prevent_wallet_transaction_change();
@@ -0,0 +1,6 @@
+-- Topups are now opened by the API before payment, so the row carries the
+-- Snap token and redirect URL handed back to the client. The Midtrans
+-- transaction id is only known once the webhook arrives.
+ALTER TABLE topups ADD COLUMN IF NOT EXISTS snap_token VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE topups ADD COLUMN IF NOT EXISTS redirect_url VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE topups ALTER COLUMN midtrans_transaction_id SET DEFAULT '';
@@ -0,0 +1,33 @@
+-- Topups follow the Midtrans transaction_status values. The old 'cancelled'
+-- status becomes Midtrans' 'cancel'.
+ALTER TABLE topups DROP CONSTRAINT IF EXISTS check_status_valid;
+UPDATE topups SET status = 'cancel' WHERE status = 'cancelled';
+ALTER TABLE topups ADD CONSTRAINT check_status_valid CHECK (status IN (
+    'pending', 'capture', 'settlement', 'deny', 'cancel', 'expire',
+    'refund', 'partial_refund', 'chargeback', 'partial_chargeback', 'failed'
+));
+
+ALTER TABLE topups ADD COLUMN IF NOT EXISTS refunded_amount DECIMAL(19,2) NOT NULL DEFAULT 0;
+ALTER TABLE topups ADD CONSTRAINT check_refunded_amount_range CHECK (refunded_amount >= 0 AND refunded_amount <= amount);
+
+-- Every accepted status change, with the notification that caused it.
+CREATE TABLE IF NOT EXISTS topup_status_histories (
+    id SERIAL PRIMARY KEY,
+    topup_id INT NOT NULL,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_topup_status_histories_topup FOREIGN KEY (topup_id)
+        REFERENCES topups(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_topup_status_histories_topup_id ON topup_status_histories(topup_id);
+
+-- Refunds and chargebacks on a topup that was already spent leave the wallet
+-- in debt, so the balance may now go below zero.
+ALTER TABLE wallet_transactions DROP CONSTRAINT IF EXISTS check_wallet_transaction_type_valid;
+ALTER TABLE wallet_transactions ADD CONSTRAINT check_wallet_transaction_type_valid
+    CHECK (type IN ('topup_credit', 'topup_reversal', 'booking_debit', 'refund_credit', 'adjustment'));
+ALTER TABLE users DROP CONSTRAINT IF EXISTS check_balance_non_negative;
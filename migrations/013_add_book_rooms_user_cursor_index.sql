@@ -0,0 +1,4 @@
+-- My-bookings pages by (check_in, id) descending within a user, so the
+-- cursor condition and the ordering can both be served from this index.
+DROP INDEX IF EXISTS idx_book_rooms_user_id;
+CREATE INDEX IF NOT EXISTS idx_book_rooms_user_check_in ON book_rooms(user_id, check_in DESC, id DESC);
@@ -0,0 +1,23 @@
+-- Date-ranged and day-of-week price overrides per room type. weekdays is a
+-- bit mask with bit n set for day n (0 = Sunday); 0 means every day.
+CREATE TABLE IF NOT EXISTS rate_plans (
+    id SERIAL PRIMARY KEY,
+    room_type_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    start_date DATE,
+    end_date DATE,
+    weekdays SMALLINT NOT NULL DEFAULT 0,
+    price DECIMAL(19,2) NOT NULL,
+    priority INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (room_type_id) REFERENCES room_types(id) ON DELETE CASCADE,
+    CONSTRAINT check_rate_plan_price_positive CHECK (price > 0),
+    CONSTRAINT check_rate_plan_date_range CHECK (start_date IS NULL OR end_date IS NULL OR end_date >= start_date),
+    CONSTRAINT check_rate_plan_weekdays_valid CHECK (weekdays BETWEEN 0 AND 127)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_plans_room_type_id ON rate_plans(room_type_id);
+
+-- Remember which plan priced each booked night.
+ALTER TABLE book_room_nights ADD COLUMN IF NOT EXISTS rate_plan_id INT REFERENCES rate_plans(id) ON DELETE SET NULL;
@@ -0,0 +1,54 @@
+-- Promo codes discount the room nights of a booking by a percentage or a
+-- fixed amount. A code with no rows in promo_code_room_types applies to
+-- every room type; limits of 0 mean unlimited.
+CREATE TABLE IF NOT EXISTS promo_codes (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    discount_type VARCHAR(10) NOT NULL,
+    percent_off DECIMAL(5,2) NOT NULL DEFAULT 0,
+    amount_off DECIMAL(19,2) NOT NULL DEFAULT 0,
+    valid_from TIMESTAMP WITH TIME ZONE,
+    valid_until TIMESTAMP WITH TIME ZONE,
+    min_nights INT NOT NULL DEFAULT 0,
+    max_redemptions INT NOT NULL DEFAULT 0,
+    max_redemptions_per_user INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_promo_code_discount_type_valid CHECK (discount_type IN ('percent', 'fixed')),
+    CONSTRAINT check_promo_code_percent_off_range CHECK (percent_off >= 0 AND percent_off <= 100),
+    CONSTRAINT check_promo_code_amount_off_non_negative CHECK (amount_off >= 0),
+    CONSTRAINT check_promo_code_validity_window CHECK (valid_from IS NULL OR valid_until IS NULL OR valid_until > valid_from),
+    CONSTRAINT check_promo_code_limits_non_negative CHECK (min_nights >= 0 AND max_redemptions >= 0 AND max_redemptions_per_user >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS promo_code_room_types (
+    promo_code_id INT NOT NULL,
+    room_type_id INT NOT NULL,
+    PRIMARY KEY (promo_code_id, room_type_id),
+    FOREIGN KEY (promo_code_id) REFERENCES promo_codes(id) ON DELETE CASCADE,
+    FOREIGN KEY (room_type_id) REFERENCES room_types(id) ON DELETE CASCADE
+);
+
+-- One row per booking that used a code. Redemptions of cancelled bookings
+-- are kept but no longer count towards the limits.
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+    id SERIAL PRIMARY KEY,
+    promo_code_id INT NOT NULL,
+    user_id INT NOT NULL,
+    book_room_id INT NOT NULL,
+    discount DECIMAL(19,2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (promo_code_id) REFERENCES promo_codes(id) ON DELETE RESTRICT,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (book_room_id) REFERENCES book_rooms(id) ON DELETE CASCADE,
+    CONSTRAINT check_promo_redemption_discount_positive CHECK (discount > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_promo_code_user ON promo_redemptions(promo_code_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_promo_redemptions_book_room_id ON promo_redemptions(book_room_id);
+
+-- A code can discount a stay down to nothing.
+ALTER TABLE book_rooms DROP CONSTRAINT IF EXISTS check_price_positive;
+ALTER TABLE book_rooms ADD CONSTRAINT check_price_non_negative CHECK (price >= 0);
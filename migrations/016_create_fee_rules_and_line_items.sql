@@ -0,0 +1,54 @@
+-- Taxes and service charges added to new bookings. Percent rules take rate
+-- percent of the room nights after discounts; fixed rules charge amount per
+-- stay or per night. Inclusive rules are already part of the room price.
+CREATE TABLE IF NOT EXISTS fee_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    calculation VARCHAR(10) NOT NULL,
+    rate DECIMAL(5,2) NOT NULL DEFAULT 0,
+    amount DECIMAL(19,2) NOT NULL DEFAULT 0,
+    basis VARCHAR(10) NOT NULL DEFAULT 'per_stay',
+    inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_fee_rule_type_valid CHECK (type IN ('tax', 'service_charge')),
+    CONSTRAINT check_fee_rule_calculation_valid CHECK (calculation IN ('percent', 'fixed')),
+    CONSTRAINT check_fee_rule_basis_valid CHECK (basis IN ('per_night', 'per_stay')),
+    CONSTRAINT check_fee_rule_rate_range CHECK (rate >= 0 AND rate <= 100),
+    CONSTRAINT check_fee_rule_amount_non_negative CHECK (amount >= 0)
+);
+
+-- The itemized price each booking was charged. price on book_rooms is the
+-- sum of the items that are not inclusive.
+CREATE TABLE IF NOT EXISTS book_room_line_items (
+    id SERIAL PRIMARY KEY,
+    book_room_id INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    date DATE,
+    rate_plan_id INT,
+    inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    amount DECIMAL(19,2) NOT NULL,
+    FOREIGN KEY (book_room_id) REFERENCES book_rooms(id) ON DELETE CASCADE,
+    FOREIGN KEY (rate_plan_id) REFERENCES rate_plans(id) ON DELETE SET NULL,
+    CONSTRAINT check_book_room_line_item_type_valid CHECK (type IN ('room_night', 'discount', 'tax', 'service_charge'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_room_line_items_book_room_id ON book_room_line_items(book_room_id);
+
+-- Itemize existing bookings from their nights, or as a single line when
+-- cancellation has already released the nights.
+INSERT INTO book_room_line_items (book_room_id, type, description, date, rate_plan_id, amount)
+SELECT n.book_room_id, 'room_night', 'Room ' || r.room_number || ', night of ' || to_char(n.date, 'YYYY-MM-DD'), n.date, n.rate_plan_id, n.price
+FROM book_room_nights n
+JOIN rooms r ON r.id = n.room_id
+WHERE NOT EXISTS (SELECT 1 FROM book_room_line_items li WHERE li.book_room_id = n.book_room_id)
+ORDER BY n.book_room_id, n.date;
+
+INSERT INTO book_room_line_items (book_room_id, type, description, amount)
+SELECT b.id, 'room_night', 'Room ' || r.room_number || ', ' || to_char(b.check_in, 'YYYY-MM-DD') || ' to ' || to_char(b.check_out, 'YYYY-MM-DD'), b.price
+FROM book_rooms b
+JOIN rooms r ON r.id = b.room_id
+WHERE NOT EXISTS (SELECT 1 FROM book_room_line_items li WHERE li.book_room_id = b.id);
@@ -0,0 +1,25 @@
+-- One counter per invoice series and year. Numbers are taken inside the
+-- transaction that stores the invoice, so a rollback leaves no gap.
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    series VARCHAR(10) NOT NULL,
+    year INT NOT NULL,
+    last_number BIGINT NOT NULL,
+    PRIMARY KEY (series, year)
+);
+
+-- Invoices for bookings and receipts for topups, issued the first time they
+-- are requested. A subject keeps its number for good.
+CREATE TABLE IF NOT EXISTS invoices (
+    id SERIAL PRIMARY KEY,
+    number VARCHAR(30) NOT NULL UNIQUE,
+    subject_type VARCHAR(20) NOT NULL,
+    subject_id INT NOT NULL,
+    user_id INT NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CONSTRAINT unique_invoice_subject UNIQUE (subject_type, subject_id),
+    CONSTRAINT check_invoice_subject_type_valid CHECK (subject_type IN ('book_room', 'topup'))
+);
+
+-- How the guest paid, as reported by Midtrans, for topup receipts.
+ALTER TABLE topups ADD COLUMN IF NOT EXISTS payment_type VARCHAR(50) NOT NULL DEFAULT '';
@@ -0,0 +1,24 @@
+-- Bookings now move through check-in and check-out, or end as a no-show.
+ALTER TABLE book_rooms DROP CONSTRAINT IF EXISTS check_book_room_status_valid;
+ALTER TABLE book_rooms ADD CONSTRAINT check_book_room_status_valid
+    CHECK (status IN ('confirmed', 'checked_in', 'checked_out', 'cancelled', 'no_show'));
+
+ALTER TABLE book_rooms ADD COLUMN IF NOT EXISTS checked_in_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE book_rooms ADD COLUMN IF NOT EXISTS checked_out_at TIMESTAMP WITH TIME ZONE;
+
+-- Every status change of a booking and the user who made it.
+CREATE TABLE IF NOT EXISTS book_room_status_histories (
+    id SERIAL PRIMARY KEY,
+    book_room_id INT NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    changed_by INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_book_room_status_histories_book_room FOREIGN KEY (book_room_id)
+        REFERENCES book_rooms(id) ON DELETE CASCADE,
+    CONSTRAINT fk_book_room_status_histories_changed_by FOREIGN KEY (changed_by)
+        REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_room_status_histories_book_room_id ON book_room_status_histories(book_room_id);
@@ -0,0 +1,5 @@
+-- Status changes made by the nightly no-show sweep have no acting user.
+ALTER TABLE book_room_status_histories ALTER COLUMN changed_by DROP NOT NULL;
+
+-- Lets the sweep find bookings still waiting for their guest.
+CREATE INDEX IF NOT EXISTS idx_book_rooms_awaiting_arrival ON book_rooms(check_in) WHERE status = 'confirmed';
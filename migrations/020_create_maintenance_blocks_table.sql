@@ -0,0 +1,20 @@
+-- Date ranges during which a room is out of service and cannot be booked.
+-- Both dates are inclusive.
+CREATE TABLE IF NOT EXISTS maintenance_blocks (
+    id SERIAL PRIMARY KEY,
+    room_id INT NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    created_by INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_maintenance_blocks_room FOREIGN KEY (room_id)
+        REFERENCES rooms(id) ON DELETE CASCADE,
+    CONSTRAINT fk_maintenance_blocks_created_by FOREIGN KEY (created_by)
+        REFERENCES users(id),
+    CONSTRAINT check_maintenance_block_date_range CHECK (end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_blocks_room_dates ON maintenance_blocks(room_id, start_date, end_date);
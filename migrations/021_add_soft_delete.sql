@@ -0,0 +1,31 @@
+-- Rooms, room types and users are soft deleted so bookings keep the room and
+-- guest they were made for. Names only need to be unique among rows that
+-- have not been deleted.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE room_types ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+ALTER TABLE room_types DROP CONSTRAINT IF EXISTS room_types_name_key;
+ALTER TABLE rooms DROP CONSTRAINT IF EXISTS rooms_room_number_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_users_email ON users(email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_room_types_name ON room_types(name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_rooms_room_number ON rooms(room_number) WHERE deleted_at IS NULL;
+
+-- Rows are no longer hard deleted, and if one ever is, its booking history
+-- must not disappear with it.
+ALTER TABLE book_rooms DROP CONSTRAINT IF EXISTS book_rooms_room_id_fkey;
+ALTER TABLE book_rooms DROP CONSTRAINT IF EXISTS fk_book_rooms_room;
+ALTER TABLE book_rooms ADD CONSTRAINT fk_book_rooms_room FOREIGN KEY (room_id)
+    REFERENCES rooms(id) ON DELETE RESTRICT;
+
+ALTER TABLE book_rooms DROP CONSTRAINT IF EXISTS book_rooms_user_id_fkey;
+ALTER TABLE book_rooms DROP CONSTRAINT IF EXISTS fk_book_rooms_user;
+ALTER TABLE book_rooms ADD CONSTRAINT fk_book_rooms_user FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE book_room_nights DROP CONSTRAINT IF EXISTS book_room_nights_room_id_fkey;
+ALTER TABLE book_room_nights DROP CONSTRAINT IF EXISTS fk_book_room_nights_room;
+ALTER TABLE book_room_nights ADD CONSTRAINT fk_book_room_nights_room FOREIGN KEY (room_id)
+    REFERENCES rooms(id) ON DELETE RESTRICT;
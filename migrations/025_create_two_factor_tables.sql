@@ -0,0 +1,46 @@
+-- TOTP authenticators. secret is encrypted by the application; last_used_step
+-- is the time step of the last accepted code so codes cannot be replayed.
+-- Until confirmed_at is set the user is still enrolling.
+CREATE TABLE IF NOT EXISTS user_totps (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL UNIQUE,
+    secret TEXT NOT NULL,
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_user_totps_user FOREIGN KEY (user_id)
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Single-use recovery codes, stored as SHA-256 hashes.
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    code_hash VARCHAR(64) NOT NULL UNIQUE,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_recovery_codes_user FOREIGN KEY (user_id)
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes(user_id);
+
+-- Whether users of a role must use two-factor authentication. Roles without
+-- a row do not require it.
+CREATE TABLE IF NOT EXISTS two_factor_policies (
+    role VARCHAR(20) PRIMARY KEY,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by INT,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT check_two_factor_policy_role CHECK (role IN ('admin', 'staff', 'guest')),
+    CONSTRAINT fk_two_factor_policies_updated_by FOREIGN KEY (updated_by)
+        REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Wrong two-factor codes are throttled per user like failed logins.
+ALTER TABLE login_throttles DROP CONSTRAINT IF EXISTS check_login_throttle_scope;
+ALTER TABLE login_throttles ADD CONSTRAINT check_login_throttle_scope CHECK (scope IN ('account', 'ip', 'two_factor'));
@@ -0,0 +1,4 @@
+-- Card captures carry the Midtrans fraud_status. A capture is only credited
+-- once it is accepted, so a challenged capture has to be remembered until the
+-- review settles or denies it.
+ALTER TABLE topups ADD COLUMN IF NOT EXISTS fraud_status VARCHAR(20) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- Password reset requests are throttled per email address and per client IP
+-- in the same table as failed logins, under scopes of their own.
+ALTER TABLE login_throttles DROP CONSTRAINT IF EXISTS check_login_throttle_scope;
+ALTER TABLE login_throttles ADD CONSTRAINT check_login_throttle_scope CHECK (scope IN ('account', 'ip', 'two_factor', 'reset_email', 'reset_ip'));
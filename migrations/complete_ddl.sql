@@ -2,71 +2,470 @@
 CREATE TABLE users (
     id SERIAL PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
-    email VARCHAR(255) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL,
     password VARCHAR(255) NOT NULL,
     balance DECIMAL(19,2) NOT NULL DEFAULT 0,
+    role VARCHAR(20) NOT NULL DEFAULT 'guest',
+    email_verified_at TIMESTAMP WITH TIME ZONE,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
     
-    CONSTRAINT check_balance_non_negative CHECK (balance >= 0)
+    CONSTRAINT check_role_valid CHECK (role IN ('admin', 'staff', 'guest'))
+);
+
+CREATE UNIQUE INDEX unique_users_email ON users(email) WHERE deleted_at IS NULL;
+
+
+CREATE TABLE refresh_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    family_id VARCHAR(64) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    access_token_jti VARCHAR(64) NOT NULL,
+    access_expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT fk_refresh_tokens_user FOREIGN KEY (user_id) 
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX idx_refresh_tokens_user_expires_at ON refresh_tokens(user_id, expires_at);
+
+
+CREATE TABLE revoked_tokens (
+    id SERIAL PRIMARY KEY,
+    jti VARCHAR(64) NOT NULL UNIQUE,
+    user_id INT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT fk_revoked_tokens_user FOREIGN KEY (user_id) 
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+
+CREATE TABLE user_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    purpose VARCHAR(32) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT fk_user_tokens_user FOREIGN KEY (user_id) 
+        REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT check_user_token_purpose CHECK (purpose IN ('email_verification', 'password_reset'))
+);
+
+CREATE INDEX idx_user_tokens_user_purpose ON user_tokens(user_id, purpose);
+CREATE INDEX idx_user_tokens_expires_at ON user_tokens(expires_at);
+
+
+CREATE TABLE login_throttles (
+    id SERIAL PRIMARY KEY,
+    scope VARCHAR(16) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT unique_login_throttles_scope_key UNIQUE (scope, key),
+    CONSTRAINT check_login_throttle_scope CHECK (scope IN ('account', 'ip', 'two_factor', 'reset_email', 'reset_ip'))
+);
+
+
+CREATE TABLE audit_logs (
+    id SERIAL PRIMARY KEY,
+    action VARCHAR(50) NOT NULL,
+    user_id INT,
+    actor_id INT,
+    ip_address VARCHAR(64),
+    details TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT fk_audit_logs_user FOREIGN KEY (user_id) 
+        REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_audit_logs_actor FOREIGN KEY (actor_id) 
+        REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_audit_logs_user_id ON audit_logs(user_id);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+
+CREATE TABLE user_totps (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL UNIQUE,
+    secret TEXT NOT NULL,
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT fk_user_totps_user FOREIGN KEY (user_id) 
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    code_hash VARCHAR(64) NOT NULL UNIQUE,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT fk_recovery_codes_user FOREIGN KEY (user_id) 
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes(user_id);
+
+CREATE TABLE two_factor_policies (
+    role VARCHAR(20) PRIMARY KEY,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by INT,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT check_two_factor_policy_role CHECK (role IN ('admin', 'staff', 'guest')),
+    CONSTRAINT fk_two_factor_policies_updated_by FOREIGN KEY (updated_by) 
+        REFERENCES users(id) ON DELETE SET NULL
 );
 
 
 CREATE TABLE topups (
     id SERIAL PRIMARY KEY,
     user_id INT NOT NULL,
-    midtrans_transaction_id VARCHAR(255) NOT NULL,
+    midtrans_transaction_id VARCHAR(255) NOT NULL DEFAULT '',
     midtrans_order_id VARCHAR(255) NOT NULL UNIQUE,
     amount DECIMAL(19,2) NOT NULL,
+    refunded_amount DECIMAL(19,2) NOT NULL DEFAULT 0,
     status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    payment_type VARCHAR(50) NOT NULL DEFAULT '',
+    fraud_status VARCHAR(20) NOT NULL DEFAULT '',
+    snap_token VARCHAR(255) NOT NULL DEFAULT '',
+    redirect_url VARCHAR(500) NOT NULL DEFAULT '',
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     
     CONSTRAINT fk_topups_user FOREIGN KEY (user_id) 
         REFERENCES users(id) ON DELETE CASCADE,
     CONSTRAINT check_amount_positive CHECK (amount > 0),
-    CONSTRAINT check_status_valid CHECK (status IN ('pending', 'settlement', 'failed', 'cancelled'))
+    CONSTRAINT check_refunded_amount_range CHECK (refunded_amount >= 0 AND refunded_amount <= amount),
+    CONSTRAINT check_status_valid CHECK (status IN (
+        'pending', 'capture', 'settlement', 'deny', 'cancel', 'expire',
+        'refund', 'partial_refund', 'chargeback', 'partial_chargeback', 'failed'
+    ))
+);
+
+
+CREATE TABLE topup_status_histories (
+    id SERIAL PRIMARY KEY,
+    topup_id INT NOT NULL,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT fk_topup_status_histories_topup FOREIGN KEY (topup_id) 
+        REFERENCES topups(id) ON DELETE CASCADE
 );
 
+CREATE INDEX idx_topup_status_histories_topup_id ON topup_status_histories(topup_id);
+
 
 CREATE TABLE room_types (
     id SERIAL PRIMARY KEY,
-    name VARCHAR(100) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
     price DECIMAL(19,2) NOT NULL,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
     
     CONSTRAINT check_price_positive CHECK (price > 0)
 );
 
+CREATE UNIQUE INDEX unique_room_types_name ON room_types(name) WHERE deleted_at IS NULL;
+
+
+CREATE TABLE rate_plans (
+    id SERIAL PRIMARY KEY,
+    room_type_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    start_date DATE,
+    end_date DATE,
+    weekdays SMALLINT NOT NULL DEFAULT 0,
+    price DECIMAL(19,2) NOT NULL,
+    priority INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT fk_rate_plans_room_type FOREIGN KEY (room_type_id) 
+        REFERENCES room_types(id) ON DELETE CASCADE,
+    CONSTRAINT check_rate_plan_price_positive CHECK (price > 0),
+    CONSTRAINT check_rate_plan_date_range CHECK (start_date IS NULL OR end_date IS NULL OR end_date >= start_date),
+    CONSTRAINT check_rate_plan_weekdays_valid CHECK (weekdays BETWEEN 0 AND 127)
+);
+
+CREATE INDEX idx_rate_plans_room_type_id ON rate_plans(room_type_id);
+
 
 CREATE TABLE rooms (
     id SERIAL PRIMARY KEY,
     room_type_id INT NOT NULL,
-    room_number VARCHAR(50) NOT NULL UNIQUE,
+    room_number VARCHAR(50) NOT NULL,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
     
     CONSTRAINT fk_rooms_room_type FOREIGN KEY (room_type_id) 
         REFERENCES room_types(id) ON DELETE RESTRICT
 );
 
+CREATE UNIQUE INDEX unique_rooms_room_number ON rooms(room_number) WHERE deleted_at IS NULL;
+
+
+CREATE TABLE maintenance_blocks (
+    id SERIAL PRIMARY KEY,
+    room_id INT NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    created_by INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT fk_maintenance_blocks_room FOREIGN KEY (room_id) 
+        REFERENCES rooms(id) ON DELETE CASCADE,
+    CONSTRAINT fk_maintenance_blocks_created_by FOREIGN KEY (created_by) 
+        REFERENCES users(id),
+    CONSTRAINT check_maintenance_block_date_range CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_maintenance_blocks_room_dates ON maintenance_blocks(room_id, start_date, end_date);
+
 
 CREATE TABLE book_rooms (
     id SERIAL PRIMARY KEY,
     room_id INT NOT NULL,
     user_id INT NOT NULL,
-    date DATE NOT NULL,
+    check_in DATE NOT NULL,
+    check_out DATE NOT NULL,
     price DECIMAL(19,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'confirmed',
+    refund_amount DECIMAL(19,2) NOT NULL DEFAULT 0,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    checked_in_at TIMESTAMP WITH TIME ZONE,
+    checked_out_at TIMESTAMP WITH TIME ZONE,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     
     CONSTRAINT fk_book_rooms_room FOREIGN KEY (room_id) 
-        REFERENCES rooms(id) ON DELETE CASCADE,
+        REFERENCES rooms(id) ON DELETE RESTRICT,
     CONSTRAINT fk_book_rooms_user FOREIGN KEY (user_id) 
-        REFERENCES users(id) ON DELETE CASCADE,
+        REFERENCES users(id) ON DELETE RESTRICT,
+    CONSTRAINT check_price_non_negative CHECK (price >= 0),
+    CONSTRAINT check_stay_range CHECK (check_out > check_in),
+    CONSTRAINT check_book_room_status_valid CHECK (status IN ('confirmed', 'checked_in', 'checked_out', 'cancelled', 'no_show')),
+    CONSTRAINT check_refund_amount_range CHECK (refund_amount >= 0 AND refund_amount <= price)
+);
+
+CREATE INDEX idx_book_rooms_user_check_in ON book_rooms(user_id, check_in DESC, id DESC);
+CREATE INDEX idx_book_rooms_awaiting_arrival ON book_rooms(check_in) WHERE status = 'confirmed';
+
+
+CREATE TABLE book_room_status_histories (
+    id SERIAL PRIMARY KEY,
+    book_room_id INT NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    changed_by INT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT fk_book_room_status_histories_book_room FOREIGN KEY (book_room_id) 
+        REFERENCES book_rooms(id) ON DELETE CASCADE,
+    CONSTRAINT fk_book_room_status_histories_changed_by FOREIGN KEY (changed_by) 
+        REFERENCES users(id)
+);
+
+CREATE INDEX idx_book_room_status_histories_book_room_id ON book_room_status_histories(book_room_id);
+
+
+CREATE TABLE book_room_nights (
+    id SERIAL PRIMARY KEY,
+    book_room_id INT NOT NULL,
+    room_id INT NOT NULL,
+    date DATE NOT NULL,
+    price DECIMAL(19,2) NOT NULL,
+    rate_plan_id INT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT fk_book_room_nights_book_room FOREIGN KEY (book_room_id) 
+        REFERENCES book_rooms(id) ON DELETE CASCADE,
+    CONSTRAINT fk_book_room_nights_room FOREIGN KEY (room_id) 
+        REFERENCES rooms(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_book_room_nights_rate_plan FOREIGN KEY (rate_plan_id) 
+        REFERENCES rate_plans(id) ON DELETE SET NULL,
     CONSTRAINT unique_room_date UNIQUE(room_id, date),
-    CONSTRAINT check_price_positive CHECK (price > 0),
-    CONSTRAINT check_date_not_past CHECK (date >= CURRENT_DATE)
+    CONSTRAINT check_night_price_positive CHECK (price > 0)
+);
+
+CREATE INDEX idx_book_room_nights_date ON book_room_nights(date);
+
+
+CREATE TABLE promo_codes (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    discount_type VARCHAR(10) NOT NULL,
+    percent_off DECIMAL(5,2) NOT NULL DEFAULT 0,
+    amount_off DECIMAL(19,2) NOT NULL DEFAULT 0,
+    valid_from TIMESTAMP WITH TIME ZONE,
+    valid_until TIMESTAMP WITH TIME ZONE,
+    min_nights INT NOT NULL DEFAULT 0,
+    max_redemptions INT NOT NULL DEFAULT 0,
+    max_redemptions_per_user INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT check_promo_code_discount_type_valid CHECK (discount_type IN ('percent', 'fixed')),
+    CONSTRAINT check_promo_code_percent_off_range CHECK (percent_off >= 0 AND percent_off <= 100),
+    CONSTRAINT check_promo_code_amount_off_non_negative CHECK (amount_off >= 0),
+    CONSTRAINT check_promo_code_validity_window CHECK (valid_from IS NULL OR valid_until IS NULL OR valid_until > valid_from),
+    CONSTRAINT check_promo_code_limits_non_negative CHECK (min_nights >= 0 AND max_redemptions >= 0 AND max_redemptions_per_user >= 0)
+);
+
+
+CREATE TABLE promo_code_room_types (
+    promo_code_id INT NOT NULL,
+    room_type_id INT NOT NULL,
+    
+    PRIMARY KEY (promo_code_id, room_type_id),
+    CONSTRAINT fk_promo_code_room_types_promo_code FOREIGN KEY (promo_code_id) 
+        REFERENCES promo_codes(id) ON DELETE CASCADE,
+    CONSTRAINT fk_promo_code_room_types_room_type FOREIGN KEY (room_type_id) 
+        REFERENCES room_types(id) ON DELETE CASCADE
 );
 
+
+CREATE TABLE promo_redemptions (
+    id SERIAL PRIMARY KEY,
+    promo_code_id INT NOT NULL,
+    user_id INT NOT NULL,
+    book_room_id INT NOT NULL,
+    discount DECIMAL(19,2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT fk_promo_redemptions_promo_code FOREIGN KEY (promo_code_id) 
+        REFERENCES promo_codes(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_promo_redemptions_user FOREIGN KEY (user_id) 
+        REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_promo_redemptions_book_room FOREIGN KEY (book_room_id) 
+        REFERENCES book_rooms(id) ON DELETE CASCADE,
+    CONSTRAINT check_promo_redemption_discount_positive CHECK (discount > 0)
+);
+
+CREATE INDEX idx_promo_redemptions_promo_code_user ON promo_redemptions(promo_code_id, user_id);
+CREATE INDEX idx_promo_redemptions_book_room_id ON promo_redemptions(book_room_id);
+
+CREATE TABLE fee_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    calculation VARCHAR(10) NOT NULL,
+    rate DECIMAL(5,2) NOT NULL DEFAULT 0,
+    amount DECIMAL(19,2) NOT NULL DEFAULT 0,
+    basis VARCHAR(10) NOT NULL DEFAULT 'per_stay',
+    inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT check_fee_rule_type_valid CHECK (type IN ('tax', 'service_charge')),
+    CONSTRAINT check_fee_rule_calculation_valid CHECK (calculation IN ('percent', 'fixed')),
+    CONSTRAINT check_fee_rule_basis_valid CHECK (basis IN ('per_night', 'per_stay')),
+    CONSTRAINT check_fee_rule_rate_range CHECK (rate >= 0 AND rate <= 100),
+    CONSTRAINT check_fee_rule_amount_non_negative CHECK (amount >= 0)
+);
+
+
+CREATE TABLE book_room_line_items (
+    id SERIAL PRIMARY KEY,
+    book_room_id INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    date DATE,
+    rate_plan_id INT,
+    inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    amount DECIMAL(19,2) NOT NULL,
+    
+    CONSTRAINT fk_book_room_line_items_book_room FOREIGN KEY (book_room_id) 
+        REFERENCES book_rooms(id) ON DELETE CASCADE,
+    CONSTRAINT fk_book_room_line_items_rate_plan FOREIGN KEY (rate_plan_id) 
+        REFERENCES rate_plans(id) ON DELETE SET NULL,
+    CONSTRAINT check_book_room_line_item_type_valid CHECK (type IN ('room_night', 'discount', 'tax', 'service_charge'))
+);
+
+CREATE INDEX idx_book_room_line_items_book_room_id ON book_room_line_items(book_room_id);
+
+CREATE TABLE invoice_sequences (
+    series VARCHAR(10) NOT NULL,
+    year INT NOT NULL,
+    last_number BIGINT NOT NULL,
+    
+    PRIMARY KEY (series, year)
+);
+
+
+CREATE TABLE invoices (
+    id SERIAL PRIMARY KEY,
+    number VARCHAR(30) NOT NULL UNIQUE,
+    subject_type VARCHAR(20) NOT NULL,
+    subject_id INT NOT NULL,
+    user_id INT NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    
+    CONSTRAINT fk_invoices_user FOREIGN KEY (user_id) 
+        REFERENCES users(id) ON DELETE RESTRICT,
+    CONSTRAINT unique_invoice_subject UNIQUE (subject_type, subject_id),
+    CONSTRAINT check_invoice_subject_type_valid CHECK (subject_type IN ('book_room', 'topup'))
+);
+
+CREATE TABLE wallet_transactions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    amount DECIMAL(19,2) NOT NULL,
+    balance_after DECIMAL(19,2) NOT NULL,
+    contra_account VARCHAR(50) NOT NULL,
+    reference_type VARCHAR(30),
+    reference_id INT,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    
+    CONSTRAINT fk_wallet_transactions_user FOREIGN KEY (user_id) 
+        REFERENCES users(id) ON DELETE RESTRICT,
+    CONSTRAINT check_wallet_transaction_type_valid CHECK (type IN ('topup_credit', 'topup_reversal', 'booking_debit', 'refund_credit', 'adjustment')),
+    CONSTRAINT check_wallet_transaction_amount_non_zero CHECK (amount <> 0)
+);
+
+CREATE INDEX idx_wallet_transactions_user_id ON wallet_transactions(user_id, id);
+
+CREATE OR REPLACE FUNCTION prevent_wallet_transaction_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'wallet_transactions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER wallet_transactions_append_only
+    BEFORE UPDATE OR DELETE ON wallet_transactions
+    FOR EACH ROW EXECUTE FUNCTION prevent_wallet_transaction_change();
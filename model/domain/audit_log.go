@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+const (
+	AuditActionLoginLockout = "login_lockout"
+	AuditActionLoginUnlock  = "login_unlock"
+
+	AuditActionTwoFactorEnabled         = "two_factor_enabled"
+	AuditActionTwoFactorDisabled        = "two_factor_disabled"
+	AuditActionRecoveryCodeUsed         = "recovery_code_used"
+	AuditActionRecoveryCodesRegenerated = "recovery_codes_regenerated"
+	AuditActionTwoFactorPolicyUpdated   = "two_factor_policy_updated"
+)
+
+// AuditLog records a security-relevant event. UserID is the account the
+// event concerns and ActorID the user who caused it; either is nil when
+// there is no such user, as for a lockout of an unknown email or an IP.
+type AuditLog struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	Action    string    `gorm:"type:varchar(50);not null"`
+	UserID    *int      `gorm:"default:null"`
+	ActorID   *int      `gorm:"default:null"`
+	IPAddress string    `gorm:"type:varchar(64)"`
+	Details   string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
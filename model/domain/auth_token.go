@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// AccessToken is the short-lived bearer token sent with every request. JTI
+// identifies it so it can be revoked before it expires.
+type AccessToken struct {
+	Token     string
+	JTI       string
+	ExpiresAt time.Time
+}
+
+// TokenPair is what a login or refresh hands out: an access token and the
+// refresh token that can be exchanged, once, for the next pair.
+type TokenPair struct {
+	AccessToken      AccessToken
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// RefreshToken records one issued token pair. Only a hash of the refresh
+// token is stored. Every refresh token descending from the same login shares
+// a FamilyID, which is also the session id carried by the access tokens.
+type RefreshToken struct {
+	ID              int        `gorm:"primaryKey;autoIncrement"`
+	UserID          int        `gorm:"not null"`
+	FamilyID        string     `gorm:"type:varchar(64);not null"`
+	TokenHash       string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	AccessTokenJTI  string     `gorm:"column:access_token_jti;type:varchar(64);not null"`
+	AccessExpiresAt time.Time  `gorm:"not null"`
+	ExpiresAt       time.Time  `gorm:"not null"`
+	UsedAt          *time.Time `gorm:"default:null"`
+	RevokedAt       *time.Time `gorm:"default:null"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// RevokedToken is an entry of the access token denylist. It can be dropped
+// once ExpiresAt passes, since the token it names is no longer valid anyway.
+type RevokedToken struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	JTI       string    `gorm:"column:jti;type:varchar(64);not null;uniqueIndex"`
+	UserID    int       `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
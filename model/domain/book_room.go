@@ -2,16 +2,95 @@ package domain
 
 import "time"
 
+const (
+	BookRoomStatusConfirmed  = "confirmed"
+	BookRoomStatusCheckedIn  = "checked_in"
+	BookRoomStatusCheckedOut = "checked_out"
+	BookRoomStatusCancelled  = "cancelled"
+	BookRoomStatusNoShow     = "no_show"
+)
+
+var bookRoomTransitions = map[string][]string{
+	BookRoomStatusConfirmed: {BookRoomStatusCheckedIn, BookRoomStatusCancelled, BookRoomStatusNoShow},
+	BookRoomStatusCheckedIn: {BookRoomStatusCheckedOut},
+}
+
+// CanTransitionBookRoom reports whether a booking in status from may move to
+// to. Checked out, cancelled and no-show bookings are final.
+func CanTransitionBookRoom(from string, to string) bool {
+	for _, target := range bookRoomTransitions[from] {
+		if target == to {
+			return true
+		}
+	}
+	return false
+}
+
 type BookRoom struct {
-	ID     int       `gorm:"primaryKey;autoIncrement"`
-	RoomID int       `gorm:"not null"`
-	UserID int       `gorm:"not null"`
-	Date   time.Time `gorm:"type:date;not null"`
-	Price  float64   `gorm:"type:decimal(19,2);not null"`
-	Room   Room      `gorm:"foreignKey:RoomID;references:ID"`
-	User   User      `gorm:"foreignKey:UserID;references:ID"`
+	ID           int                `gorm:"primaryKey;autoIncrement"`
+	RoomID       int                `gorm:"not null"`
+	UserID       int                `gorm:"not null"`
+	CheckIn      time.Time          `gorm:"type:date;not null"`
+	CheckOut     time.Time          `gorm:"type:date;not null"`
+	Price        Money              `gorm:"type:decimal(19,2);not null"`
+	Status       string             `gorm:"type:varchar(20);not null;default:confirmed"`
+	RefundAmount Money              `gorm:"type:decimal(19,2);not null;default:0"`
+	CancelledAt  *time.Time         `gorm:"type:timestamp with time zone"`
+	CheckedInAt  *time.Time         `gorm:"type:timestamp with time zone"`
+	CheckedOutAt *time.Time         `gorm:"type:timestamp with time zone"`
+	Room         Room               `gorm:"foreignKey:RoomID;references:ID"`
+	User         User               `gorm:"foreignKey:UserID;references:ID"`
+	Nights       []BookRoomNight    `gorm:"foreignKey:BookRoomID;references:ID"`
+	LineItems    []BookRoomLineItem `gorm:"foreignKey:BookRoomID;references:ID"`
 }
 
 func (BookRoom) TableName() string {
 	return "book_rooms"
 }
+
+// NightDates returns every night of the stay, from check-in up to but not
+// including check-out.
+func (b BookRoom) NightDates() []time.Time {
+	var dates []time.Time
+	for date := b.CheckIn; date.Before(b.CheckOut); date = date.AddDate(0, 0, 1) {
+		dates = append(dates, date)
+	}
+	return dates
+}
+
+// Breakdown returns the itemized price the booking was charged.
+func (b BookRoom) Breakdown() LineItems {
+	items := make(LineItems, 0, len(b.LineItems))
+	for _, item := range b.LineItems {
+		items = append(items, item.LineItem)
+	}
+	return items
+}
+
+// BookRoomLineItem is one line of the price a booking was charged. Unlike
+// its nights, a booking keeps its line items when it is cancelled.
+type BookRoomLineItem struct {
+	ID         int `gorm:"primaryKey;autoIncrement"`
+	BookRoomID int `gorm:"not null"`
+	LineItem   `gorm:"embedded"`
+}
+
+func (BookRoomLineItem) TableName() string {
+	return "book_room_line_items"
+}
+
+// BookRoomStatusHistory records one status change of a booking and the user
+// who made it: the guest for cancellations, front desk staff otherwise.
+// ChangedBy is nil for changes made by the no-show job.
+type BookRoomStatusHistory struct {
+	ID         int    `gorm:"primaryKey;autoIncrement"`
+	BookRoomID int    `gorm:"not null"`
+	FromStatus string `gorm:"type:varchar(20);not null"`
+	ToStatus   string `gorm:"type:varchar(20);not null"`
+	ChangedBy  *int
+	CreatedAt  time.Time
+}
+
+func (BookRoomStatusHistory) TableName() string {
+	return "book_room_status_histories"
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// BookRoomNight occupies a single room for a single night of a stay. The
+// unique (room_id, date) constraint on this table is what prevents two stays
+// from overlapping. RatePlanID records the rate plan that priced the night,
+// if any.
+type BookRoomNight struct {
+	ID         int       `gorm:"primaryKey;autoIncrement"`
+	BookRoomID int       `gorm:"not null"`
+	RoomID     int       `gorm:"not null"`
+	Date       time.Time `gorm:"type:date;not null"`
+	Price      Money     `gorm:"type:decimal(19,2);not null"`
+	RatePlanID *int
+}
+
+func (BookRoomNight) TableName() string {
+	return "book_room_nights"
+}
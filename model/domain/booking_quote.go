@@ -0,0 +1,103 @@
+package domain
+
+import "time"
+
+const (
+	LineItemRoomNight     = "room_night"
+	LineItemDiscount      = "discount"
+	LineItemTax           = "tax"
+	LineItemServiceCharge = "service_charge"
+)
+
+// LineItem is one entry of a booking's price. Room nights, taxes and
+// service charges are positive and discounts are negative, so the price of
+// the booking is the sum of its line items. Inclusive items are fees that
+// are already part of the room price; they are listed so tax can be
+// reported, but are left out of the total.
+type LineItem struct {
+	Type        string     `gorm:"type:varchar(20);not null"`
+	Description string     `gorm:"type:varchar(255);not null"`
+	Date        *time.Time `gorm:"type:date"`
+	RatePlanID  *int
+	Inclusive   bool  `gorm:"not null;default:false"`
+	Amount      Money `gorm:"type:decimal(19,2);not null"`
+}
+
+// LineItems is the itemized price of a booking.
+type LineItems []LineItem
+
+// Sum adds up the line items of the given type.
+func (items LineItems) Sum(itemType string) Money {
+	var sum Money
+	for _, item := range items {
+		if item.Type == itemType {
+			sum += item.Amount
+		}
+	}
+	return sum
+}
+
+// Subtotal is the price of the room nights alone.
+func (items LineItems) Subtotal() Money {
+	return items.Sum(LineItemRoomNight)
+}
+
+// Discount is the total discount as a positive amount.
+func (items LineItems) Discount() Money {
+	return -items.Sum(LineItemDiscount)
+}
+
+// Tax includes taxes that are already part of the room price.
+func (items LineItems) Tax() Money {
+	return items.Sum(LineItemTax)
+}
+
+// ServiceCharge includes service charges that are already part of the room
+// price.
+func (items LineItems) ServiceCharge() Money {
+	return items.Sum(LineItemServiceCharge)
+}
+
+// Total is the amount the booking charges to the wallet.
+func (items LineItems) Total() Money {
+	var total Money
+	for _, item := range items {
+		if !item.Inclusive {
+			total += item.Amount
+		}
+	}
+	return total
+}
+
+// Net is the revenue the hotel keeps once taxes and service charges are
+// taken out of the total.
+func (items LineItems) Net() Money {
+	return items.Total() - items.Tax() - items.ServiceCharge()
+}
+
+// BookingOptions carries the optional parts of a booking request.
+// QuoteToken pins the price to a quote issued earlier and PromoCode applies
+// a discount.
+type BookingOptions struct {
+	QuoteToken string
+	PromoCode  string
+}
+
+// BookingQuote is what a booking would cost if it were made now. Token is a
+// signed copy of the quote that guarantees its price until ExpiresAt.
+type BookingQuote struct {
+	UserID    int
+	RoomID    int
+	CheckIn   time.Time
+	CheckOut  time.Time
+	PromoCode string
+	LineItems
+	Balance   Money
+	Token     string
+	ExpiresAt time.Time
+}
+
+// BalanceCovers reports whether the wallet balance pays for the booking.
+func (q BookingQuote) BalanceCovers() bool {
+	return q.Balance >= q.Total()
+}
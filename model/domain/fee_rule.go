@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	FeeCalculationPercent = "percent"
+	FeeCalculationFixed   = "fixed"
+
+	FeeBasisPerNight = "per_night"
+	FeeBasisPerStay  = "per_stay"
+)
+
+// FeeRule is a tax or service charge added to every booking. Percent rules
+// take Rate percent of the room nights after discounts; fixed rules charge
+// Amount once per stay or once per night. An inclusive rule is already part
+// of the room price, so it is itemized for reporting without raising the
+// total.
+type FeeRule struct {
+	ID          int     `gorm:"primaryKey;autoIncrement"`
+	Name        string  `gorm:"type:varchar(100);not null"`
+	Type        string  `gorm:"type:varchar(20);not null"`
+	Calculation string  `gorm:"type:varchar(10);not null"`
+	Rate        Percent `gorm:"type:decimal(5,2);not null;default:0"`
+	Amount      Money   `gorm:"type:decimal(19,2);not null;default:0"`
+	Basis       string  `gorm:"type:varchar(10);not null;default:per_stay"`
+	Inclusive   bool    `gorm:"not null;default:false"`
+	Active      bool    `gorm:"not null;default:true"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (FeeRule) TableName() string {
+	return "fee_rules"
+}
+
+// Charge returns what the rule adds to a stay of nights whose room nights
+// come to base after discounts. For inclusive percent rules this is the
+// share of base that is the fee, not Rate percent on top of it.
+func (r FeeRule) Charge(base Money, nights int) Money {
+	switch r.Calculation {
+	case FeeCalculationPercent:
+		if r.Inclusive {
+			return base.InclusivePercent(r.Rate)
+		}
+		return base.Percent(r.Rate)
+	case FeeCalculationFixed:
+		if r.Basis == FeeBasisPerNight {
+			return r.Amount.Mul(nights)
+		}
+		return r.Amount
+	}
+	return 0
+}
+
+// LineItem itemizes the rule's charge on a stay.
+func (r FeeRule) LineItem(base Money, nights int) LineItem {
+	description := r.Name
+	switch {
+	case r.Calculation == FeeCalculationPercent:
+		description += fmt.Sprintf(" (%s%%)", r.Rate.Label())
+	case r.Basis == FeeBasisPerNight:
+		description += fmt.Sprintf(" (%s x %d nights)", r.Amount, nights)
+	}
+	if r.Inclusive {
+		description += ", included in price"
+	}
+
+	return LineItem{
+		Type:        r.Type,
+		Description: description,
+		Inclusive:   r.Inclusive,
+		Amount:      r.Charge(base, nights),
+	}
+}
+
+// FeeLineItems itemizes every rule's charge on a stay, leaving out charges
+// that come to nothing.
+func FeeLineItems(rules []FeeRule, base Money, nights int) []LineItem {
+	var items []LineItem
+	for _, rule := range rules {
+		item := rule.LineItem(base, nights)
+		if item.Amount == 0 {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
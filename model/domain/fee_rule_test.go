@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeRule_Charge_InclusivePercent(t *testing.T) {
+	rule := FeeRule{Calculation: FeeCalculationPercent, Rate: NewPercent(10), Inclusive: true}
+
+	assert.Equal(t, "100000.00", rule.Charge(NewMoney(1100000), 2).String())
+	assert.Equal(t, "0.05", rule.Charge(Money(55), 1).String())
+	assert.Equal(t, "0.00", rule.Charge(Money(5), 1).String())
+}
+
+func TestFeeRule_Charge_InclusiveMatchesExactRounding(t *testing.T) {
+	for rate := Percent(1); rate <= NewPercent(100); rate += 7 {
+		rule := FeeRule{Calculation: FeeCalculationPercent, Rate: rate, Inclusive: true}
+		for base := Money(1); base <= NewMoney(200); base++ {
+			bp := int64(rate)
+			want := (2*int64(base)*bp + (10000 + bp)) / (2 * (10000 + bp))
+			if got := rule.Charge(base, 1); int64(got) != want {
+				t.Fatalf("%s%% included in %s = %s, want %s", rate, base, got, Money(want))
+			}
+		}
+	}
+}
+
+func TestFeeRule_LineItem_DescribesRate(t *testing.T) {
+	rule := FeeRule{Name: "VAT", Type: LineItemTax, Calculation: FeeCalculationPercent, Rate: Percent(1250)}
+
+	assert.Equal(t, "VAT (12.5%)", rule.LineItem(NewMoney(100), 1).Description)
+}
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	InvoiceSubjectBookRoom = "book_room"
+	InvoiceSubjectTopup    = "topup"
+
+	InvoiceSeriesBookRoom = "INV"
+	InvoiceSeriesTopup    = "RCT"
+)
+
+// Invoice numbers a document issued for a booking or a topup. Numbers run
+// per series and year without gaps, and a subject keeps the number it was
+// first issued with.
+type Invoice struct {
+	ID          int       `gorm:"primaryKey;autoIncrement"`
+	Number      string    `gorm:"type:varchar(30);uniqueIndex;not null"`
+	SubjectType string    `gorm:"type:varchar(20);not null"`
+	SubjectID   int       `gorm:"not null"`
+	UserID      int       `gorm:"not null"`
+	IssuedAt    time.Time `gorm:"type:timestamp with time zone;not null"`
+}
+
+func (Invoice) TableName() string {
+	return "invoices"
+}
+
+// FormatInvoiceNumber renders the sequence-th number of a series in year,
+// e.g. INV-2026-000042.
+func FormatInvoiceNumber(series string, year int, sequence int64) string {
+	return fmt.Sprintf("%s-%d-%06d", series, year, sequence)
+}
+
+// HotelDetails is printed at the top of every invoice.
+type HotelDetails struct {
+	Name    string
+	Address string
+	Phone   string
+	Email   string
+	TaxID   string
+}
+
+// InvoiceField is a labelled value such as the stay dates or the payment
+// method.
+type InvoiceField struct {
+	Label string
+	Value string
+}
+
+// InvoiceLine is one priced row of an invoice. Inclusive rows show tax that
+// is already part of the price above it.
+type InvoiceLine struct {
+	Description string
+	Inclusive   bool
+	Amount      Money
+}
+
+// InvoiceDocument is everything an invoice or receipt shows, ready to render.
+// The last of Totals is the amount paid; Refunds lists money given back
+// since.
+type InvoiceDocument struct {
+	Title      string
+	Invoice    Invoice
+	Hotel      HotelDetails
+	GuestName  string
+	GuestEmail string
+	Details    []InvoiceField
+	Lines      []InvoiceLine
+	Totals     []InvoiceLine
+	Refunds    []InvoiceLine
+}
+
+// NewBookRoomInvoice lays out the invoice of a booking from the line items it
+// was charged.
+func NewBookRoomInvoice(invoice Invoice, hotel HotelDetails, bookRoom BookRoom) InvoiceDocument {
+	breakdown := bookRoom.Breakdown()
+	document := InvoiceDocument{
+		Title:      "Invoice",
+		Invoice:    invoice,
+		Hotel:      hotel,
+		GuestName:  bookRoom.User.Name,
+		GuestEmail: bookRoom.User.Email,
+		Details: []InvoiceField{
+			{Label: "Booking", Value: fmt.Sprintf("#%d", bookRoom.ID)},
+			{Label: "Room", Value: fmt.Sprintf("%s (%s)", bookRoom.Room.RoomNumber, bookRoom.Room.RoomType.Name)},
+			{Label: "Check-in", Value: bookRoom.CheckIn.Format("2006-01-02")},
+			{Label: "Check-out", Value: bookRoom.CheckOut.Format("2006-01-02")},
+			{Label: "Nights", Value: fmt.Sprintf("%d", len(bookRoom.NightDates()))},
+			{Label: "Status", Value: bookRoom.Status},
+			{Label: "Payment method", Value: "Wallet balance"},
+		},
+	}
+
+	for _, item := range breakdown {
+		document.Lines = append(document.Lines, InvoiceLine{
+			Description: item.Description,
+			Inclusive:   item.Inclusive,
+			Amount:      item.Amount,
+		})
+	}
+
+	document.Totals = []InvoiceLine{
+		{Description: "Subtotal", Amount: breakdown.Subtotal()},
+	}
+	if discount := breakdown.Discount(); discount != 0 {
+		document.Totals = append(document.Totals, InvoiceLine{Description: "Discount", Amount: -discount})
+	}
+	document.Totals = append(document.Totals,
+		InvoiceLine{Description: "Service charge", Amount: breakdown.ServiceCharge()},
+		InvoiceLine{Description: "Tax", Amount: breakdown.Tax()},
+		InvoiceLine{Description: "Total paid", Amount: bookRoom.Price},
+	)
+	if bookRoom.RefundAmount > 0 {
+		document.Refunds = append(document.Refunds, InvoiceLine{Description: "Refunded to wallet", Amount: -bookRoom.RefundAmount})
+	}
+
+	return document
+}
+
+// NewTopupReceipt lays out the receipt of a paid topup.
+func NewTopupReceipt(invoice Invoice, hotel HotelDetails, topup Topup, user User) InvoiceDocument {
+	paymentMethod := "Midtrans"
+	if topup.PaymentType != "" {
+		paymentMethod = fmt.Sprintf("Midtrans (%s)", topup.PaymentType)
+	}
+
+	document := InvoiceDocument{
+		Title:      "Receipt",
+		Invoice:    invoice,
+		Hotel:      hotel,
+		GuestName:  user.Name,
+		GuestEmail: user.Email,
+		Details: []InvoiceField{
+			{Label: "Order", Value: topup.MidtransOrderID},
+			{Label: "Order date", Value: topup.CreatedAt.Format("2006-01-02")},
+			{Label: "Status", Value: topup.Status},
+			{Label: "Payment method", Value: paymentMethod},
+		},
+		Lines: []InvoiceLine{
+			{Description: "Wallet topup", Amount: topup.Amount},
+		},
+		Totals: []InvoiceLine{
+			{Description: "Total paid", Amount: topup.Amount},
+		},
+	}
+	if topup.RefundedAmount > 0 {
+		document.Refunds = append(document.Refunds, InvoiceLine{Description: "Refunded", Amount: -topup.RefundedAmount})
+	}
+
+	return document
+}
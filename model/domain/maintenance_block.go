@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// MaintenanceBlock takes a room out of service from StartDate to EndDate
+// inclusive. No night in that range can be booked.
+type MaintenanceBlock struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	RoomID    int       `gorm:"not null"`
+	StartDate time.Time `gorm:"type:date;not null"`
+	EndDate   time.Time `gorm:"type:date;not null"`
+	Reason    string    `gorm:"type:varchar(255);not null"`
+	CreatedBy int       `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Relocations lists the bookings moved out of the room to make way for
+	// the block. It is only filled in when the block is created.
+	Relocations []Relocation `gorm:"-"`
+}
+
+func (MaintenanceBlock) TableName() string {
+	return "maintenance_blocks"
+}
+
+// Relocation moves a booking, with all of its nights, to another room.
+type Relocation struct {
+	BookRoomID int
+	RoomID     int
+}
+
+// RelocationPlan says what to do with the bookings a new maintenance block
+// overlaps. Without Force the block is refused; with it every overlapping
+// booking must have a Relocation.
+type RelocationPlan struct {
+	Force       bool
+	Relocations []Relocation
+}
+
+// RoomFor returns the room the plan moves a booking to.
+func (p RelocationPlan) RoomFor(bookRoomId int) (int, bool) {
+	for _, relocation := range p.Relocations {
+		if relocation.BookRoomID == bookRoomId {
+			return relocation.RoomID, true
+		}
+	}
+	return 0, false
+}
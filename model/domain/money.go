@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MoneyCurrency is the currency every Money amount is held in.
+const MoneyCurrency = "IDR"
+
+// Money is an exact amount of MoneyCurrency held in minor units (hundredths),
+// matching the DECIMAL(19,2) columns it is stored in. It is written to JSON
+// and to the database as a decimal string such as "150000.00".
+type Money int64
+
+var errInvalidMoney = errors.New("invalid money amount")
+
+// NewMoney returns the Money worth units whole currency units.
+func NewMoney(units int64) Money {
+	return Money(units * 100)
+}
+
+// ParseMoney reads a decimal string such as "150000", "150000.5" or
+// "-20.00". Digits past the second decimal place must be zero.
+func ParseMoney(value string) (Money, error) {
+	minor, err := parseHundredths(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", errInvalidMoney, err)
+	}
+	return Money(minor), nil
+}
+
+// parseHundredths reads a decimal string with at most two significant
+// decimal places as a count of hundredths.
+func parseHundredths(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" && fraction == "" {
+		return 0, errors.New("empty value")
+	}
+	if len(fraction) > 2 {
+		if strings.Trim(fraction[2:], "0") != "" {
+			return 0, fmt.Errorf("%q has more than two decimal places", value)
+		}
+		fraction = fraction[:2]
+	}
+	fraction += strings.Repeat("0", 2-len(fraction))
+	if whole == "" {
+		whole = "0"
+	}
+
+	for _, digits := range []string{whole, fraction} {
+		for _, r := range digits {
+			if r < '0' || r > '9' {
+				return 0, fmt.Errorf("%q is not a decimal number", value)
+			}
+		}
+	}
+
+	hundredths, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is out of range", value)
+	}
+	if negative {
+		hundredths = -hundredths
+	}
+	return hundredths, nil
+}
+
+// String formats m with exactly two decimal places.
+func (m Money) String() string {
+	minor := int64(m)
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/100, minor%100)
+}
+
+// Units returns the whole currency units in m, dropping any minor units.
+func (m Money) Units() int64 {
+	return int64(m) / 100
+}
+
+// IsWhole reports whether m has no minor units.
+func (m Money) IsWhole() bool {
+	return m%100 == 0
+}
+
+// Mul returns m multiplied by n.
+func (m Money) Mul(n int) Money {
+	return m * Money(n)
+}
+
+// Percent returns percent of m, rounded half away from zero to a minor unit.
+// The amount is split at 10000 minor units so the product stays in range for
+// any amount a DECIMAL(19,2) column holds.
+func (m Money) Percent(percent Percent) Money {
+	amount, hundredths := int64(m), int64(percent)
+	negative := (amount < 0) != (hundredths < 0)
+	if amount < 0 {
+		amount = -amount
+	}
+	if hundredths < 0 {
+		hundredths = -hundredths
+	}
+
+	result := amount/10000*hundredths + (amount%10000*hundredths+5000)/10000
+	if negative {
+		result = -result
+	}
+	return Money(result)
+}
+
+// InclusivePercent returns the part of m that is a percent charge already
+// included in it, m*percent/(100+percent), rounded half away from zero to a
+// minor unit.
+func (m Money) InclusivePercent(percent Percent) Money {
+	amount, hundredths := int64(m), int64(percent)
+	negative := amount < 0
+	if negative {
+		amount = -amount
+	}
+
+	divisor := 10000 + hundredths
+	whole, rest := amount/divisor, amount%divisor
+	result := whole*hundredths + (2*rest*hundredths+divisor)/(2*divisor)
+	if negative {
+		result = -result
+	}
+	return Money(result)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(m.String())), nil
+}
+
+// UnmarshalJSON accepts both a decimal string and a bare JSON number.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case string:
+		parsed, err := ParseMoney(v)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case []byte:
+		return m.Scan(string(v))
+	case int64:
+		*m = NewMoney(v)
+		return nil
+	case float64:
+		return m.Scan(strconv.FormatFloat(v, 'f', 2, 64))
+	}
+	return fmt.Errorf("cannot scan %T into Money", value)
+}
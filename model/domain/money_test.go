@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney_Percent_RoundsHalfCentsUp(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  string
+		percent string
+		want    string
+	}{
+		{name: "0.7% of 55.00", amount: "55.00", percent: "0.7", want: "0.39"},
+		{name: "0.35% of 110.00", amount: "110.00", percent: "0.35", want: "0.39"},
+		{name: "12.5% of 0.04", amount: "0.04", percent: "12.5", want: "0.01"},
+		{name: "below half a cent", amount: "0.03", percent: "12.5", want: "0.00"},
+		{name: "negative amount", amount: "-55.00", percent: "0.7", want: "-0.39"},
+		{name: "whole percent", amount: "150000.00", percent: "50", want: "75000.00"},
+		{name: "full amount", amount: "199.99", percent: "100", want: "199.99"},
+		{name: "nothing", amount: "199.99", percent: "0", want: "0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := ParseMoney(tt.amount)
+			assert.NoError(t, err)
+			percent, err := ParsePercent(tt.percent)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.want, amount.Percent(percent).String())
+		})
+	}
+}
+
+func TestMoney_Percent_MatchesExactRounding(t *testing.T) {
+	for percent := Percent(1); percent <= NewPercent(100); percent++ {
+		for amount := Money(1); amount <= NewMoney(200); amount++ {
+			want := (int64(amount)*int64(percent)*2 + 10000) / 20000
+			if got := amount.Percent(percent); int64(got) != want {
+				t.Fatalf("%s%% of %s = %s, want %s", percent, amount, got, Money(want))
+			}
+		}
+	}
+}
+
+func TestMoney_Percent_LargeAmount(t *testing.T) {
+	amount, err := ParseMoney("90000000000000000.00")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "45000000000000000.00", amount.Percent(NewPercent(50)).String())
+}
+
+func TestParsePercent(t *testing.T) {
+	percent, err := ParsePercent("12.5")
+	assert.NoError(t, err)
+	assert.Equal(t, Percent(1250), percent)
+
+	_, err = ParsePercent("12.345")
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+// Pagination selects one page of a list, counting pages from 1.
+type Pagination struct {
+	Page    int
+	PerPage int
+}
+
+// NewPagination fills in defaults for a missing page or page size and caps
+// the page size at MaxPerPage.
+func NewPagination(page int, perPage int) Pagination {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = DefaultPerPage
+	}
+	if perPage > MaxPerPage {
+		perPage = MaxPerPage
+	}
+	return Pagination{Page: page, PerPage: perPage}
+}
+
+func (p Pagination) Offset() int {
+	return (p.Page - 1) * p.PerPage
+}
+
+// SortField orders a list by one field, ascending unless Desc is set.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ListQuery selects, orders and pages a list. Filters map a filter name to
+// the value it must match; which names and sort fields are accepted is up to
+// the repository serving the list.
+type ListQuery struct {
+	Pagination
+	Sort    []SortField
+	Filters map[string]string
+}
+
+// ParseSort reads a comma-separated sort parameter such as "-price,name",
+// where a leading minus sorts that field in descending order.
+func ParseSort(sort string) []SortField {
+	var fields []SortField
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if strings.HasPrefix(part, "-") {
+			fields = append(fields, SortField{Field: part[1:], Desc: true})
+			continue
+		}
+		fields = append(fields, SortField{Field: part})
+	}
+	return fields
+}
+
+// Cursor points at the last row of a keyset page: Key is the value of the
+// list's ordering column and ID breaks ties between rows sharing it.
+type Cursor struct {
+	Key string
+	ID  int
+}
+
+// Encode returns the opaque form of the cursor handed to clients.
+func (c Cursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.Key + "|" + strconv.Itoa(c.ID)))
+}
+
+// DecodeCursor parses a cursor produced by Encode.
+func DecodeCursor(encoded string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	separator := strings.LastIndex(string(raw), "|")
+	if separator < 0 {
+		return Cursor{}, errors.New("malformed cursor")
+	}
+
+	id, err := strconv.Atoi(string(raw[separator+1:]))
+	if err != nil {
+		return Cursor{}, err
+	}
+	return Cursor{Key: string(raw[:separator]), ID: id}, nil
+}
+
+// CursorQuery selects the PerPage rows following After, or the first rows of
+// the list when After is nil.
+type CursorQuery struct {
+	After   *Cursor
+	PerPage int
+	Filters map[string]string
+}
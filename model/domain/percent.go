@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Percent is an exact percentage held in hundredths of a percent, matching
+// the DECIMAL(5,2) columns it is stored in, so 12.5% is Percent(1250). It is
+// written to JSON as a number and to the database as a decimal string.
+type Percent int64
+
+var errInvalidPercent = errors.New("invalid percentage")
+
+// NewPercent returns the Percent worth whole percent.
+func NewPercent(whole int64) Percent {
+	return Percent(whole * 100)
+}
+
+// ParsePercent reads a decimal string such as "10", "12.5" or "0.75". Digits
+// past the second decimal place must be zero.
+func ParsePercent(value string) (Percent, error) {
+	hundredths, err := parseHundredths(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", errInvalidPercent, err)
+	}
+	return Percent(hundredths), nil
+}
+
+// String formats p with exactly two decimal places.
+func (p Percent) String() string {
+	return Money(p).String()
+}
+
+// Label formats p for display, without trailing zeros in the fraction.
+func (p Percent) Label() string {
+	hundredths := int64(p)
+	sign := ""
+	if hundredths < 0 {
+		sign = "-"
+		hundredths = -hundredths
+	}
+	switch {
+	case hundredths%100 == 0:
+		return fmt.Sprintf("%s%d", sign, hundredths/100)
+	case hundredths%10 == 0:
+		return fmt.Sprintf("%s%d.%d", sign, hundredths/100, hundredths%100/10)
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, hundredths/100, hundredths%100)
+}
+
+func (p Percent) MarshalJSON() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalJSON accepts both a bare JSON number and a decimal string.
+func (p *Percent) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	parsed, err := ParsePercent(text)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+func (p Percent) Value() (driver.Value, error) {
+	return p.String(), nil
+}
+
+func (p *Percent) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = 0
+		return nil
+	case string:
+		parsed, err := ParsePercent(v)
+		if err != nil {
+			return err
+		}
+		*p = parsed
+		return nil
+	case []byte:
+		return p.Scan(string(v))
+	case int64:
+		*p = NewPercent(v)
+		return nil
+	case float64:
+		return p.Scan(strconv.FormatFloat(v, 'f', 2, 64))
+	}
+	return fmt.Errorf("cannot scan %T into Percent", value)
+}
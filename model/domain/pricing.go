@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// NightPrice is what one night of a stay costs and the rate plan that set
+// the price, or nil when the room type's base price applies.
+type NightPrice struct {
+	Date     time.Time
+	Price    Money
+	RatePlan *RatePlan
+}
+
+// RateQuote prices a stay in one room type night by night.
+type RateQuote struct {
+	RoomType RoomType
+	CheckIn  time.Time
+	CheckOut time.Time
+	Nights   []NightPrice
+	Total    Money
+}
+
+// PriceNights prices each of dates for a room type with base price base.
+// Among the plans covering a night the one with the highest priority wins,
+// and ties go to the most recently created plan. Nights no plan covers are
+// charged the base price.
+func PriceNights(base Money, plans []RatePlan, dates []time.Time) []NightPrice {
+	nights := make([]NightPrice, 0, len(dates))
+	for _, date := range dates {
+		night := NightPrice{Date: date, Price: base}
+		for i := range plans {
+			plan := &plans[i]
+			if !plan.Covers(date) {
+				continue
+			}
+			if night.RatePlan == nil || plan.Priority > night.RatePlan.Priority ||
+				(plan.Priority == night.RatePlan.Priority && plan.ID > night.RatePlan.ID) {
+				night.RatePlan = plan
+				night.Price = plan.Price
+			}
+		}
+		nights = append(nights, night)
+	}
+	return nights
+}
+
+// TotalNightPrice adds up the price of every night.
+func TotalNightPrice(nights []NightPrice) Money {
+	var total Money
+	for _, night := range nights {
+		total += night.Price
+	}
+	return total
+}
+
+// QuoteStay prices a stay from checkIn up to but not including checkOut.
+func QuoteStay(roomType RoomType, plans []RatePlan, checkIn time.Time, checkOut time.Time) RateQuote {
+	nights := PriceNights(roomType.Price, plans, BookRoom{CheckIn: checkIn, CheckOut: checkOut}.NightDates())
+	return RateQuote{
+		RoomType: roomType,
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
+		Nights:   nights,
+		Total:    TotalNightPrice(nights),
+	}
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	PromoDiscountPercent = "percent"
+	PromoDiscountFixed   = "fixed"
+)
+
+// PromoCode discounts the room nights of a booking, either by PercentOff of
+// their price or by a fixed AmountOff. A code can be limited to a validity
+// window, a minimum number of nights and a set of room types; no room types
+// means every room type. MaxRedemptions and MaxRedemptionsPerUser cap how
+// often the code is used overall and by one guest, with 0 meaning no limit.
+type PromoCode struct {
+	ID                    int        `gorm:"primaryKey;autoIncrement"`
+	Code                  string     `gorm:"type:varchar(50);uniqueIndex;not null"`
+	Description           string     `gorm:"type:varchar(255);not null;default:''"`
+	DiscountType          string     `gorm:"type:varchar(10);not null"`
+	PercentOff            Percent    `gorm:"type:decimal(5,2);not null;default:0"`
+	AmountOff             Money      `gorm:"type:decimal(19,2);not null;default:0"`
+	ValidFrom             *time.Time `gorm:"type:timestamp with time zone"`
+	ValidUntil            *time.Time `gorm:"type:timestamp with time zone"`
+	MinNights             int        `gorm:"not null;default:0"`
+	MaxRedemptions        int        `gorm:"not null;default:0"`
+	MaxRedemptionsPerUser int        `gorm:"not null;default:0"`
+	Active                bool       `gorm:"not null;default:true"`
+	RoomTypes             []RoomType `gorm:"many2many:promo_code_room_types"`
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+func (PromoCode) TableName() string {
+	return "promo_codes"
+}
+
+// NormalizePromoCode puts a code in the form it is stored in, so guests can
+// type it in any case.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// AppliesToRoomType reports whether the code may be used for roomTypeID.
+func (p PromoCode) AppliesToRoomType(roomTypeID int) bool {
+	if len(p.RoomTypes) == 0 {
+		return true
+	}
+	for _, roomType := range p.RoomTypes {
+		if roomType.ID == roomTypeID {
+			return true
+		}
+	}
+	return false
+}
+
+// Discount returns how much the code takes off a subtotal, never more than
+// the subtotal itself.
+func (p PromoCode) Discount(subtotal Money) Money {
+	var discount Money
+	switch p.DiscountType {
+	case PromoDiscountPercent:
+		discount = subtotal.Percent(p.PercentOff)
+	case PromoDiscountFixed:
+		discount = p.AmountOff
+	}
+	if discount > subtotal {
+		return subtotal
+	}
+	return discount
+}
+
+// PromoRedemption records one use of a promo code by a booking.
+type PromoRedemption struct {
+	ID          int   `gorm:"primaryKey;autoIncrement"`
+	PromoCodeID int   `gorm:"not null"`
+	UserID      int   `gorm:"not null"`
+	BookRoomID  int   `gorm:"not null"`
+	Discount    Money `gorm:"type:decimal(19,2);not null"`
+	CreatedAt   time.Time
+}
+
+func (PromoRedemption) TableName() string {
+	return "promo_redemptions"
+}
@@ -0,0 +1,68 @@
+package domain
+
+import "time"
+
+// Weekdays is a set of days of the week stored as a bit mask, with bit n set
+// for time.Weekday(n). The empty set places no restriction on the day.
+type Weekdays uint8
+
+// NewWeekdays builds the set holding days.
+func NewWeekdays(days ...time.Weekday) Weekdays {
+	var weekdays Weekdays
+	for _, day := range days {
+		weekdays |= 1 << uint(day)
+	}
+	return weekdays
+}
+
+// Has reports whether day is in the set.
+func (w Weekdays) Has(day time.Weekday) bool {
+	return w&(1<<uint(day)) != 0
+}
+
+// Days lists the days in the set, starting from Sunday.
+func (w Weekdays) Days() []time.Weekday {
+	var days []time.Weekday
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if w.Has(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// RatePlan overrides the base price of a room type for the nights it covers.
+// A plan may be limited to the dates from StartDate to EndDate inclusive, to
+// the days in Weekdays, or both; a plan with neither covers every night.
+// When several plans cover the same night the highest Priority wins.
+type RatePlan struct {
+	ID         int        `gorm:"primaryKey;autoIncrement"`
+	RoomTypeID int        `gorm:"not null"`
+	Name       string     `gorm:"type:varchar(100);not null"`
+	StartDate  *time.Time `gorm:"type:date"`
+	EndDate    *time.Time `gorm:"type:date"`
+	Weekdays   Weekdays   `gorm:"type:smallint;not null;default:0"`
+	Price      Money      `gorm:"type:decimal(19,2);not null"`
+	Priority   int        `gorm:"not null;default:0"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (RatePlan) TableName() string {
+	return "rate_plans"
+}
+
+// Covers reports whether the plan applies to the night starting on date.
+func (p RatePlan) Covers(date time.Time) bool {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if p.StartDate != nil && day.Before(time.Date(p.StartDate.Year(), p.StartDate.Month(), p.StartDate.Day(), 0, 0, 0, 0, time.UTC)) {
+		return false
+	}
+	if p.EndDate != nil && day.After(time.Date(p.EndDate.Year(), p.EndDate.Month(), p.EndDate.Day(), 0, 0, 0, 0, time.UTC)) {
+		return false
+	}
+	if p.Weekdays != 0 && !p.Weekdays.Has(date.Weekday()) {
+		return false
+	}
+	return true
+}
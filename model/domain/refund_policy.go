@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// RefundPolicy decides how much of a booking is returned to the wallet when
+// a guest cancels. Cancelling at least FullRefundHours before check-in gives
+// a full refund, cancelling later than that gives PartialRefundPercent, and
+// cancelling on the check-in day itself gives nothing. A guest who never
+// arrives gets NoShowRefundPercent back.
+type RefundPolicy struct {
+	FullRefundHours      int
+	PartialRefundPercent Percent
+	NoShowRefundPercent  Percent
+}
+
+// RefundPercent returns the percentage of the price to refund when a stay
+// starting on checkIn is cancelled at now.
+func (p RefundPolicy) RefundPercent(checkIn time.Time, now time.Time) Percent {
+	startOfCheckIn := time.Date(checkIn.Year(), checkIn.Month(), checkIn.Day(), 0, 0, 0, 0, now.Location())
+	if !now.Before(startOfCheckIn) {
+		return 0
+	}
+
+	if startOfCheckIn.Sub(now) >= time.Duration(p.FullRefundHours)*time.Hour {
+		return NewPercent(100)
+	}
+
+	return p.PartialRefundPercent
+}
+
+// RefundAmount applies RefundPercent to price, rounded to whole cents.
+func (p RefundPolicy) RefundAmount(price Money, checkIn time.Time, now time.Time) Money {
+	return price.Percent(p.RefundPercent(checkIn, now))
+}
+
+// NoShowRefundAmount returns what is refunded of price when the guest never
+// arrives, rounded to whole cents.
+func (p RefundPolicy) NoShowRefundAmount(price Money) Money {
+	return price.Percent(p.NoShowRefundPercent)
+}
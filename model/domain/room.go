@@ -1,10 +1,15 @@
 package domain
 
+import "gorm.io/gorm"
+
+// Room is soft deleted: a deleted room drops out of every default query but
+// its row stays, so bookings keep pointing at it.
 type Room struct {
 	ID         int      `gorm:"primaryKey;autoIncrement"`
 	RoomTypeID int      `gorm:"not null"`
-	RoomNumber string   `gorm:"type:varchar(50);not null;unique"`
+	RoomNumber string   `gorm:"type:varchar(50);not null"`
 	RoomType   RoomType `gorm:"foreignKey:RoomTypeID;references:ID"`
+	DeletedAt  gorm.DeletedAt
 }
 
 func (Room) TableName() string {
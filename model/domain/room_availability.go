@@ -0,0 +1,10 @@
+package domain
+
+// RoomAvailability lists the rooms of one room type that are free for every
+// night of a requested stay, together with what the stay would cost.
+type RoomAvailability struct {
+	RoomType   RoomType
+	Nights     int
+	TotalPrice Money
+	Rooms      []Room
+}
@@ -1,9 +1,12 @@
 package domain
 
+import "gorm.io/gorm"
+
 type RoomType struct {
-	ID    int     `gorm:"primaryKey;autoIncrement"`
-	Name  string  `gorm:"type:varchar(100);not null"`
-	Price float64 `gorm:"type:decimal(19,2);not null"`
+	ID        int    `gorm:"primaryKey;autoIncrement"`
+	Name      string `gorm:"type:varchar(100);not null"`
+	Price     Money  `gorm:"type:decimal(19,2);not null"`
+	DeletedAt gorm.DeletedAt
 }
 
 func (RoomType) TableName() string {
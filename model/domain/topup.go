@@ -2,13 +2,132 @@ package domain
 
 import "time"
 
+// Topup statuses follow Midtrans transaction_status values, plus failed for
+// orders whose Snap payment page could not be created.
+const (
+	TopupStatusPending           = "pending"
+	TopupStatusCapture           = "capture"
+	TopupStatusSettlement        = "settlement"
+	TopupStatusDeny              = "deny"
+	TopupStatusCancel            = "cancel"
+	TopupStatusExpire            = "expire"
+	TopupStatusRefund            = "refund"
+	TopupStatusPartialRefund     = "partial_refund"
+	TopupStatusChargeback        = "chargeback"
+	TopupStatusPartialChargeback = "partial_chargeback"
+	TopupStatusFailed            = "failed"
+)
+
+// Fraud statuses follow Midtrans fraud_status values. A card capture is only
+// paid once the fraud detection system accepts it.
+const (
+	FraudStatusAccept    = "accept"
+	FraudStatusChallenge = "challenge"
+	FraudStatusDeny      = "deny"
+)
+
+var topupTransitions = map[string][]string{
+	TopupStatusPending: {
+		TopupStatusCapture, TopupStatusSettlement, TopupStatusDeny,
+		TopupStatusCancel, TopupStatusExpire,
+	},
+	TopupStatusCapture: {
+		TopupStatusSettlement, TopupStatusCancel, TopupStatusRefund,
+		TopupStatusPartialRefund, TopupStatusChargeback, TopupStatusPartialChargeback,
+	},
+	TopupStatusSettlement: {
+		TopupStatusRefund, TopupStatusPartialRefund,
+		TopupStatusChargeback, TopupStatusPartialChargeback,
+	},
+	TopupStatusPartialRefund: {
+		TopupStatusPartialRefund, TopupStatusRefund,
+		TopupStatusChargeback, TopupStatusPartialChargeback,
+	},
+	TopupStatusPartialChargeback: {
+		TopupStatusPartialChargeback, TopupStatusChargeback,
+		TopupStatusPartialRefund, TopupStatusRefund,
+	},
+}
+
+// challengedTopupTransitions apply to a capture held for fraud review. The
+// review ends in an accepted capture or settlement, or in deny or cancel.
+var challengedTopupTransitions = []string{
+	TopupStatusCapture, TopupStatusSettlement, TopupStatusDeny, TopupStatusCancel,
+}
+
 type Topup struct {
 	ID                    int       `json:"id" db:"id"`
 	UserID                int       `json:"user_id" db:"user_id"`
 	MidtransTransactionID string    `json:"midtrans_transaction_id" db:"midtrans_transaction_id"`
 	MidtransOrderID       string    `json:"midtrans_order_id" db:"midtrans_order_id"`
-	Amount                float64   `json:"amount" db:"amount"`
+	Amount                Money     `json:"amount" db:"amount"`
+	RefundedAmount        Money     `json:"refunded_amount" db:"refunded_amount"`
 	Status                string    `json:"status" db:"status"`
+	PaymentType           string    `json:"payment_type" db:"payment_type"`
+	FraudStatus           string    `json:"fraud_status" db:"fraud_status"`
+	SnapToken             string    `json:"snap_token" db:"snap_token"`
+	RedirectURL           string    `json:"redirect_url" db:"redirect_url"`
 	CreatedAt             time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt             time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// TopupStatusHistory records one status change of a topup together with the
+// notification payload that caused it.
+type TopupStatusHistory struct {
+	ID         int       `json:"id" db:"id"`
+	TopupID    int       `json:"topup_id" db:"topup_id"`
+	FromStatus string    `json:"from_status" db:"from_status"`
+	ToStatus   string    `json:"to_status" db:"to_status"`
+	Payload    string    `json:"payload" db:"payload" gorm:"type:jsonb"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// IsKnownTopupStatus reports whether status is one the state machine handles.
+func IsKnownTopupStatus(status string) bool {
+	switch status {
+	case TopupStatusPending, TopupStatusCapture, TopupStatusSettlement, TopupStatusDeny,
+		TopupStatusCancel, TopupStatusExpire, TopupStatusRefund, TopupStatusPartialRefund,
+		TopupStatusChargeback, TopupStatusPartialChargeback:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTopup reports whether topup from may move to the status of to.
+func CanTransitionTopup(from Topup, to Topup) bool {
+	targets := topupTransitions[from.Status]
+	if IsChallengedTopup(from) {
+		targets = challengedTopupTransitions
+	}
+	for _, target := range targets {
+		if target == to.Status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPaidTopupStatus reports whether the topup amount has been credited to the
+// wallet while the topup is in status with fraudStatus. A capture only counts
+// once fraud detection has accepted it.
+func IsPaidTopupStatus(status string, fraudStatus string) bool {
+	switch status {
+	case TopupStatusCapture:
+		return fraudStatus == FraudStatusAccept
+	case TopupStatusSettlement, TopupStatusPartialRefund, TopupStatusPartialChargeback:
+		return true
+	}
+	return false
+}
+
+// IsChallengedTopup reports whether topup is a capture waiting for fraud
+// review.
+func IsChallengedTopup(topup Topup) bool {
+	return topup.Status == TopupStatusCapture && topup.FraudStatus == FraudStatusChallenge
+}
+
+// IsPartialReversalTopupStatus reports whether status returns only part of a
+// paid topup, with the cumulative amount given by the notification.
+func IsPartialReversalTopupStatus(status string) bool {
+	return status == TopupStatusPartialRefund || status == TopupStatusPartialChargeback
+}
@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+// UserTOTP is a user's TOTP authenticator. Secret is stored encrypted. Until
+// ConfirmedAt is set the user is still enrolling and logs in without it.
+// LastUsedStep is the time step of the last accepted code, so a code cannot
+// be replayed.
+type UserTOTP struct {
+	ID           int        `gorm:"primaryKey;autoIncrement"`
+	UserID       int        `gorm:"not null;uniqueIndex"`
+	Secret       string     `gorm:"type:text;not null"`
+	ConfirmedAt  *time.Time `gorm:"default:null"`
+	LastUsedStep int64      `gorm:"not null;default:0"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime"`
+}
+
+func (UserTOTP) TableName() string {
+	return "user_totps"
+}
+
+// IsConfirmed reports whether the user has proven their authenticator works,
+// after which every login needs a code from it.
+func (t UserTOTP) IsConfirmed() bool {
+	return t.ConfirmedAt != nil
+}
+
+// RecoveryCode is a single-use code that stands in for a TOTP code when the
+// authenticator is lost. Only a hash of the code is stored.
+type RecoveryCode struct {
+	ID        int        `gorm:"primaryKey;autoIncrement"`
+	UserID    int        `gorm:"not null"`
+	CodeHash  string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	UsedAt    *time.Time `gorm:"default:null"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
+
+// TwoFactorPolicy says whether every user of a role must use two-factor
+// authentication. Roles without a policy do not require it.
+type TwoFactorPolicy struct {
+	Role      string    `gorm:"type:varchar(20);primaryKey"`
+	Required  bool      `gorm:"not null;default:false"`
+	UpdatedBy *int      `gorm:"default:null"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (TwoFactorPolicy) TableName() string {
+	return "two_factor_policies"
+}
+
+// TOTPEnrollment is what a user adds to their authenticator app.
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// LoginChallenge stands for a login whose password was right but that still
+// needs a second factor. EnrollmentRequired is set when the user's role
+// requires two-factor authentication and they have not set it up yet.
+type LoginChallenge struct {
+	UserID             int
+	EnrollmentRequired bool
+	Token              string
+	ExpiresAt          time.Time
+}
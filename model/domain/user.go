@@ -1,13 +1,37 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RoleAdmin = "admin"
+	RoleStaff = "staff"
+	RoleGuest = "guest"
+)
 
 type User struct {
-	ID        int       `db:"id"`
-	Name      string    `db:"name"`
-	Email     string    `db:"email"`
-	Password  string    `db:"password"`
-	Balance   float64   `db:"balance"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID              int            `db:"id"`
+	Name            string         `db:"name"`
+	Email           string         `db:"email"`
+	Password        string         `db:"password"`
+	Balance         Money          `db:"balance"`
+	Role            string         `db:"role"`
+	EmailVerifiedAt *time.Time     `db:"email_verified_at"`
+	CreatedAt       time.Time      `db:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at"`
+	DeletedAt       gorm.DeletedAt `db:"deleted_at"`
+}
+
+// IsEmailVerified reports whether the user has proven they own their email
+// address. Unverified users cannot top up or book.
+func (u User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsValidRole reports whether role is one of the roles a user can hold.
+func IsValidRole(role string) bool {
+	return role == RoleAdmin || role == RoleStaff || role == RoleGuest
 }
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+)
+
+// UserToken is a single-use token mailed to a user to prove they own their
+// email address. Only a hash of the token is stored. Email is the address the
+// token was sent to, so a token stops working if the user changes it.
+type UserToken struct {
+	ID        int        `gorm:"primaryKey;autoIncrement"`
+	UserID    int        `gorm:"not null"`
+	Purpose   string     `gorm:"type:varchar(32);not null"`
+	Email     string     `gorm:"type:varchar(255);not null"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time `gorm:"default:null"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
+
+// AccountMailPolicy says how long mailed account tokens stay valid and where
+// the links carrying them point.
+type AccountMailPolicy struct {
+	AppURL               string
+	EmailVerificationTTL time.Duration
+	PasswordResetTTL     time.Duration
+}
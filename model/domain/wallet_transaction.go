@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+const (
+	WalletTransactionTopupCredit   = "topup_credit"
+	WalletTransactionTopupReversal = "topup_reversal"
+	WalletTransactionBookingDebit  = "booking_debit"
+	WalletTransactionRefundCredit  = "refund_credit"
+	WalletTransactionAdjustment    = "adjustment"
+)
+
+// Contra accounts a wallet entry is posted against.
+const (
+	LedgerAccountPaymentGateway = "payment_gateway"
+	LedgerAccountRoomRevenue    = "room_revenue"
+	LedgerAccountAdjustments    = "adjustments"
+)
+
+const (
+	ReferenceTypeTopup    = "topup"
+	ReferenceTypeBookRoom = "book_room"
+)
+
+// WalletTransaction is an immutable ledger entry. Every change to a user's
+// balance is recorded as one entry that moves Amount between the user's
+// wallet and ContraAccount: credits to the wallet are positive, debits are
+// negative. BalanceAfter is the wallet balance once the entry is applied.
+type WalletTransaction struct {
+	ID            int       `gorm:"primaryKey;autoIncrement"`
+	UserID        int       `gorm:"not null"`
+	Type          string    `gorm:"type:varchar(30);not null"`
+	Amount        Money     `gorm:"type:decimal(19,2);not null"`
+	BalanceAfter  Money     `gorm:"type:decimal(19,2);not null"`
+	ContraAccount string    `gorm:"type:varchar(50);not null"`
+	ReferenceType string    `gorm:"type:varchar(30)"`
+	ReferenceID   *int      `gorm:"default:null"`
+	Description   string    `gorm:"type:varchar(255);not null"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+func (WalletTransaction) TableName() string {
+	return "wallet_transactions"
+}
+
+// WalletReconciliation compares a user's stored balance with the sum of
+// their ledger entries.
+type WalletReconciliation struct {
+	UserID        int
+	Balance       Money
+	LedgerBalance Money
+}
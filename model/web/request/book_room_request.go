@@ -1,6 +1,16 @@
 package request
 
 type BookRoomRequest struct {
-	RoomID int    `json:"room_id" validate:"required,gt=0"`
-	Date   string `json:"date" validate:"required"`
+	RoomID     int    `json:"room_id" validate:"required,gt=0"`
+	CheckIn    string `json:"check_in" validate:"required"`
+	CheckOut   string `json:"check_out" validate:"required"`
+	QuoteToken string `json:"quote_token"`
+	PromoCode  string `json:"promo_code" validate:"max=50"`
+}
+
+type BookRoomQuoteRequest struct {
+	RoomID    int    `json:"room_id" validate:"required,gt=0"`
+	CheckIn   string `json:"check_in" validate:"required"`
+	CheckOut  string `json:"check_out" validate:"required"`
+	PromoCode string `json:"promo_code" validate:"max=50"`
 }
@@ -0,0 +1,14 @@
+package request
+
+import "hotel_ip-p2/model/domain"
+
+type FeeRuleRequest struct {
+	Name        string         `json:"name" validate:"required,max=100"`
+	Type        string         `json:"type" validate:"required,oneof=tax service_charge"`
+	Calculation string         `json:"calculation" validate:"required,oneof=percent fixed"`
+	Rate        domain.Percent `json:"rate" swaggertype:"number" validate:"gte=0" maximum:"100"`
+	Amount      domain.Money   `json:"amount" swaggertype:"string" validate:"gte=0"`
+	Basis       string         `json:"basis" validate:"omitempty,oneof=per_night per_stay"`
+	Inclusive   bool           `json:"inclusive"`
+	Active      *bool          `json:"active"`
+}
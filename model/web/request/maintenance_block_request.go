@@ -0,0 +1,14 @@
+package request
+
+type MaintenanceBlockRequest struct {
+	StartDate   string              `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate     string              `json:"end_date" validate:"required,datetime=2006-01-02"`
+	Reason      string              `json:"reason" validate:"required,max=255"`
+	Force       bool                `json:"force"`
+	Relocations []RelocationRequest `json:"relocations" validate:"omitempty,dive"`
+}
+
+type RelocationRequest struct {
+	BookRoomID int `json:"book_room_id" validate:"required,gt=0"`
+	RoomID     int `json:"room_id" validate:"required,gt=0"`
+}
@@ -0,0 +1,19 @@
+package request
+
+type PageRequest struct {
+	Page    int `query:"page" validate:"omitempty,gte=1"`
+	PerPage int `query:"per_page" validate:"omitempty,gte=1,lte=100"`
+}
+
+// ListRequest holds the paging and sort parameters of a list endpoint.
+// Filters are passed as filter[name]=value and read separately.
+type ListRequest struct {
+	Page    int    `query:"page" validate:"omitempty,gte=1"`
+	PerPage int    `query:"per_page" validate:"omitempty,gte=1,lte=100"`
+	Sort    string `query:"sort" validate:"omitempty,max=100"`
+}
+
+type CursorRequest struct {
+	Cursor  string `query:"cursor" validate:"omitempty,max=200"`
+	PerPage int    `query:"per_page" validate:"omitempty,gte=1,lte=100"`
+}
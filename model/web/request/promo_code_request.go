@@ -0,0 +1,21 @@
+package request
+
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+)
+
+type PromoCodeRequest struct {
+	Code                  string         `json:"code" validate:"required,max=50"`
+	Description           string         `json:"description" validate:"max=255"`
+	DiscountType          string         `json:"discount_type" validate:"required,oneof=percent fixed"`
+	PercentOff            domain.Percent `json:"percent_off" swaggertype:"number" validate:"gte=0" maximum:"100"`
+	AmountOff             domain.Money   `json:"amount_off" swaggertype:"string" validate:"gte=0"`
+	ValidFrom             *time.Time     `json:"valid_from"`
+	ValidUntil            *time.Time     `json:"valid_until"`
+	MinNights             int            `json:"min_nights" validate:"gte=0"`
+	MaxRedemptions        int            `json:"max_redemptions" validate:"gte=0"`
+	MaxRedemptionsPerUser int            `json:"max_redemptions_per_user" validate:"gte=0"`
+	RoomTypeIDs           []int          `json:"room_type_ids" validate:"omitempty,unique,dive,gt=0"`
+	Active                *bool          `json:"active"`
+}
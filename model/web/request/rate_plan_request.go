@@ -0,0 +1,12 @@
+package request
+
+import "hotel_ip-p2/model/domain"
+
+type RatePlanRequest struct {
+	Name      string       `json:"name" validate:"required,max=100"`
+	StartDate string       `json:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string       `json:"end_date" validate:"omitempty,datetime=2006-01-02"`
+	Weekdays  []int        `json:"weekdays" validate:"omitempty,unique,dive,min=0,max=6"`
+	Price     domain.Money `json:"price" swaggertype:"string" validate:"required,gt=0"`
+	Priority  int          `json:"priority"`
+}
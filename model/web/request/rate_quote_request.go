@@ -0,0 +1,6 @@
+package request
+
+type RateQuoteRequest struct {
+	CheckIn  string `query:"check_in" validate:"required"`
+	CheckOut string `query:"check_out" validate:"required"`
+}
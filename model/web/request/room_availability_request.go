@@ -0,0 +1,7 @@
+package request
+
+type RoomAvailabilityRequest struct {
+	CheckIn    string `query:"check_in" validate:"required"`
+	CheckOut   string `query:"check_out" validate:"required"`
+	RoomTypeID int    `query:"room_type_id" validate:"omitempty,gt=0"`
+}
@@ -1,6 +1,8 @@
 package request
 
+import "hotel_ip-p2/model/domain"
+
 type RoomTypeRequest struct {
-	Name  string  `json:"name" validate:"required"`
-	Price float64 `json:"price" validate:"required,gt=0"`
+	Name  string       `json:"name" validate:"required"`
+	Price domain.Money `json:"price" swaggertype:"string" validate:"required,gt=0"`
 }
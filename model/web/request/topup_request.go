@@ -1,11 +1,20 @@
 package request
 
+import "hotel_ip-p2/model/domain"
+
+type TopupRequest struct {
+	Amount domain.Money `json:"amount" swaggertype:"string" validate:"required,gt=0"`
+}
+
 type TopupWebhookRequest struct {
 	TransactionStatus string `json:"transaction_status"`
+	FraudStatus       string `json:"fraud_status"`
 	StatusCode        string `json:"status_code"`
 	TransactionID     string `json:"transaction_id"`
+	PaymentType       string `json:"payment_type"`
 	OrderID           string `json:"order_id"`
 	GrossAmount       string `json:"gross_amount"`
+	RefundAmount      string `json:"refund_amount"`
 	SignatureKey      string `json:"signature_key"`
 	UserID            int    `json:"user_id"`
 }
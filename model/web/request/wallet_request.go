@@ -0,0 +1,8 @@
+package request
+
+import "hotel_ip-p2/model/domain"
+
+type WalletAdjustmentRequest struct {
+	Amount      domain.Money `json:"amount" swaggertype:"string" validate:"required,ne=0"`
+	Description string       `json:"description" validate:"required,max=255"`
+}
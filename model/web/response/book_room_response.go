@@ -1,11 +1,55 @@
 package response
 
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+)
+
 type BookRoomResponse struct {
-	ID     int          `json:"id"`
-	RoomID int          `json:"room_id"`
-	UserID int          `json:"user_id"`
-	Date   string       `json:"date"`
-	Price  float64      `json:"price"`
-	Room   RoomResponse `json:"room"`
-	User   UserResponse `json:"user"`
+	ID            int                `json:"id"`
+	RoomID        int                `json:"room_id"`
+	UserID        int                `json:"user_id"`
+	CheckIn       string             `json:"check_in"`
+	CheckOut      string             `json:"check_out"`
+	Nights        int                `json:"nights"`
+	Price         domain.Money       `json:"price" swaggertype:"string"`
+	LineItems     []LineItemResponse `json:"line_items"`
+	Subtotal      domain.Money       `json:"subtotal" swaggertype:"string"`
+	Discount      domain.Money       `json:"discount" swaggertype:"string"`
+	Tax           domain.Money       `json:"tax" swaggertype:"string"`
+	ServiceCharge domain.Money       `json:"service_charge" swaggertype:"string"`
+	NetAmount     domain.Money       `json:"net_amount" swaggertype:"string"`
+	Status        string             `json:"status"`
+	RefundAmount  domain.Money       `json:"refund_amount" swaggertype:"string"`
+	CancelledAt   *time.Time         `json:"cancelled_at,omitempty"`
+	CheckedInAt   *time.Time         `json:"checked_in_at,omitempty"`
+	CheckedOutAt  *time.Time         `json:"checked_out_at,omitempty"`
+	Room          RoomResponse       `json:"room"`
+	User          UserResponse       `json:"user"`
+}
+
+type LineItemResponse struct {
+	Type        string       `json:"type"`
+	Description string       `json:"description"`
+	Date        *string      `json:"date,omitempty"`
+	Inclusive   bool         `json:"inclusive"`
+	Amount      domain.Money `json:"amount" swaggertype:"string"`
+}
+
+type BookRoomQuoteResponse struct {
+	RoomID        int                `json:"room_id"`
+	CheckIn       string             `json:"check_in"`
+	CheckOut      string             `json:"check_out"`
+	Nights        int                `json:"nights"`
+	PromoCode     string             `json:"promo_code,omitempty"`
+	LineItems     []LineItemResponse `json:"line_items"`
+	Subtotal      domain.Money       `json:"subtotal" swaggertype:"string"`
+	Discount      domain.Money       `json:"discount" swaggertype:"string"`
+	Tax           domain.Money       `json:"tax" swaggertype:"string"`
+	ServiceCharge domain.Money       `json:"service_charge" swaggertype:"string"`
+	Total         domain.Money       `json:"total" swaggertype:"string"`
+	Balance       domain.Money       `json:"balance" swaggertype:"string"`
+	BalanceCovers bool               `json:"balance_covers"`
+	QuoteToken    string             `json:"quote_token"`
+	ExpiresAt     time.Time          `json:"expires_at"`
 }
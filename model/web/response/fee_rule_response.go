@@ -0,0 +1,15 @@
+package response
+
+import "hotel_ip-p2/model/domain"
+
+type FeeRuleResponse struct {
+	ID          int            `json:"id"`
+	Name        string         `json:"name"`
+	Type        string         `json:"type"`
+	Calculation string         `json:"calculation"`
+	Rate        domain.Percent `json:"rate" swaggertype:"number"`
+	Amount      domain.Money   `json:"amount" swaggertype:"string"`
+	Basis       string         `json:"basis"`
+	Inclusive   bool           `json:"inclusive"`
+	Active      bool           `json:"active"`
+}
@@ -0,0 +1,19 @@
+package response
+
+import "time"
+
+type MaintenanceBlockResponse struct {
+	ID          int                  `json:"id"`
+	RoomID      int                  `json:"room_id"`
+	StartDate   string               `json:"start_date"`
+	EndDate     string               `json:"end_date"`
+	Reason      string               `json:"reason"`
+	CreatedBy   int                  `json:"created_by"`
+	CreatedAt   time.Time            `json:"created_at"`
+	Relocations []RelocationResponse `json:"relocations,omitempty"`
+}
+
+type RelocationResponse struct {
+	BookRoomID int `json:"book_room_id"`
+	RoomID     int `json:"room_id"`
+}
@@ -0,0 +1,22 @@
+package response
+
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+)
+
+type PromoCodeResponse struct {
+	ID                    int            `json:"id"`
+	Code                  string         `json:"code"`
+	Description           string         `json:"description"`
+	DiscountType          string         `json:"discount_type"`
+	PercentOff            domain.Percent `json:"percent_off" swaggertype:"number"`
+	AmountOff             domain.Money   `json:"amount_off" swaggertype:"string"`
+	ValidFrom             *time.Time     `json:"valid_from"`
+	ValidUntil            *time.Time     `json:"valid_until"`
+	MinNights             int            `json:"min_nights"`
+	MaxRedemptions        int            `json:"max_redemptions"`
+	MaxRedemptionsPerUser int            `json:"max_redemptions_per_user"`
+	RoomTypeIDs           []int          `json:"room_type_ids"`
+	Active                bool           `json:"active"`
+}
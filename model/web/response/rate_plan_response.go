@@ -0,0 +1,29 @@
+package response
+
+import "hotel_ip-p2/model/domain"
+
+type RatePlanResponse struct {
+	ID         int          `json:"id"`
+	RoomTypeID int          `json:"room_type_id"`
+	Name       string       `json:"name"`
+	StartDate  *string      `json:"start_date"`
+	EndDate    *string      `json:"end_date"`
+	Weekdays   []int        `json:"weekdays"`
+	Price      domain.Money `json:"price" swaggertype:"string"`
+	Priority   int          `json:"priority"`
+}
+
+type NightPriceResponse struct {
+	Date       string       `json:"date"`
+	Price      domain.Money `json:"price" swaggertype:"string"`
+	RatePlanID *int         `json:"rate_plan_id"`
+	RatePlan   string       `json:"rate_plan,omitempty"`
+}
+
+type RateQuoteResponse struct {
+	RoomType RoomTypeResponse     `json:"room_type"`
+	CheckIn  string               `json:"check_in"`
+	CheckOut string               `json:"check_out"`
+	Nights   []NightPriceResponse `json:"nights"`
+	Total    domain.Money         `json:"total" swaggertype:"string"`
+}
@@ -0,0 +1,11 @@
+package response
+
+import "hotel_ip-p2/model/domain"
+
+type RoomAvailabilityResponse struct {
+	RoomType       RoomTypeResponse `json:"room_type"`
+	Nights         int              `json:"nights"`
+	TotalPrice     domain.Money     `json:"total_price" swaggertype:"string"`
+	AvailableRooms int              `json:"available_rooms"`
+	Rooms          []RoomResponse   `json:"rooms"`
+}
@@ -1,8 +1,11 @@
 package response
 
+import "time"
+
 type RoomResponse struct {
 	ID         int              `json:"id"`
 	RoomTypeID int              `json:"room_type_id"`
 	RoomNumber string           `json:"room_number"`
 	RoomType   RoomTypeResponse `json:"room_type"`
+	DeletedAt  *time.Time       `json:"deleted_at,omitempty"`
 }
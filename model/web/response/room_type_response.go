@@ -1,7 +1,13 @@
 package response
 
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+)
+
 type RoomTypeResponse struct {
-	ID    int     `json:"id"`
-	Name  string  `json:"name"`
-	Price float64 `json:"price"`
+	ID        int          `json:"id"`
+	Name      string       `json:"name"`
+	Price     domain.Money `json:"price" swaggertype:"string"`
+	DeletedAt *time.Time   `json:"deleted_at,omitempty"`
 }
@@ -1,14 +1,22 @@
 package response
 
-import "time"
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+)
 
 type TopupResponse struct {
-	ID                    int       `json:"id"`
-	UserID                int       `json:"user_id"`
-	MidtransTransactionID string    `json:"midtrans_transaction_id"`
-	MidtransOrderID       string    `json:"midtrans_order_id"`
-	Amount                float64   `json:"amount"`
-	Status                string    `json:"status"`
-	CreatedAt             time.Time `json:"created_at"`
-	UpdatedAt             time.Time `json:"updated_at"`
+	ID                    int          `json:"id"`
+	UserID                int          `json:"user_id"`
+	MidtransTransactionID string       `json:"midtrans_transaction_id"`
+	MidtransOrderID       string       `json:"midtrans_order_id"`
+	Amount                domain.Money `json:"amount" swaggertype:"string"`
+	RefundedAmount        domain.Money `json:"refunded_amount" swaggertype:"string"`
+	Status                string       `json:"status"`
+	PaymentType           string       `json:"payment_type,omitempty"`
+	FraudStatus           string       `json:"fraud_status,omitempty"`
+	SnapToken             string       `json:"snap_token,omitempty"`
+	RedirectURL           string       `json:"redirect_url,omitempty"`
+	CreatedAt             time.Time    `json:"created_at"`
+	UpdatedAt             time.Time    `json:"updated_at"`
 }
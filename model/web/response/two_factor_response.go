@@ -0,0 +1,30 @@
+package response
+
+import "time"
+
+type LoginChallengeResponse struct {
+	ChallengeToken     string    `json:"challenge_token"`
+	ExpiresAt          time.Time `json:"expires_at"`
+	EnrollmentRequired bool      `json:"enrollment_required"`
+}
+
+type TwoFactorLoginResponse struct {
+	LoginResponse
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+type TOTPEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorPolicyResponse struct {
+	Role      string     `json:"role"`
+	Required  bool       `json:"required"`
+	UpdatedBy *int       `json:"updated_by"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
@@ -1,16 +1,25 @@
 package response
 
-import "time"
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+)
 
 type UserResponse struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Balance   float64   `json:"balance"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              int          `json:"id"`
+	Name            string       `json:"name"`
+	Email           string       `json:"email"`
+	Balance         domain.Money `json:"balance" swaggertype:"string"`
+	Role            string       `json:"role"`
+	EmailVerifiedAt *time.Time   `json:"email_verified_at"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+	DeletedAt       *time.Time   `json:"deleted_at,omitempty"`
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
 }
@@ -0,0 +1,24 @@
+package response
+
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+)
+
+type WalletTransactionResponse struct {
+	ID            int          `json:"id"`
+	Type          string       `json:"type"`
+	Amount        domain.Money `json:"amount" swaggertype:"string"`
+	BalanceAfter  domain.Money `json:"balance_after" swaggertype:"string"`
+	ContraAccount string       `json:"contra_account"`
+	ReferenceType string       `json:"reference_type,omitempty"`
+	ReferenceID   *int         `json:"reference_id,omitempty"`
+	Description   string       `json:"description"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type WalletReconciliationResponse struct {
+	UserID        int          `json:"user_id"`
+	Balance       domain.Money `json:"balance" swaggertype:"string"`
+	LedgerBalance domain.Money `json:"ledger_balance" swaggertype:"string"`
+}
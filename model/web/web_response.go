@@ -3,4 +3,24 @@ package web
 type WebResponse struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
+	Meta    *PageMeta   `json:"meta,omitempty"`
+}
+
+// PageMeta describes the page a list response holds. Offset pages fill in
+// Page, Total and TotalPages; cursor pages fill in NextCursor instead.
+type PageMeta struct {
+	Page       int        `json:"page,omitempty"`
+	PerPage    int        `json:"per_page"`
+	Total      int64      `json:"total,omitempty"`
+	TotalPages int        `json:"total_pages,omitempty"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	Links      *PageLinks `json:"links,omitempty"`
+}
+
+type PageLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
 }
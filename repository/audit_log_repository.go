@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"hotel_ip-p2/model/domain"
+
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository interface {
+	Create(db *gorm.DB, auditLog domain.AuditLog) (domain.AuditLog, error)
+	ReplaceInDetails(db *gorm.DB, text string, replacement string) error
+}
+
+type AuditLogRepositoryImpl struct{}
+
+func NewAuditLogRepository() AuditLogRepository {
+	return &AuditLogRepositoryImpl{}
+}
+
+func (r *AuditLogRepositoryImpl) Create(db *gorm.DB, auditLog domain.AuditLog) (domain.AuditLog, error) {
+	err := db.Create(&auditLog).Error
+	return auditLog, err
+}
+
+// ReplaceInDetails replaces every occurrence of text in the details of all
+// audit logs with replacement.
+func (r *AuditLogRepositoryImpl) ReplaceInDetails(db *gorm.DB, text string, replacement string) error {
+	return db.Model(&domain.AuditLog{}).
+		Where("strpos(details, ?) > 0", text).
+		Update("details", gorm.Expr("replace(details, ?, ?)", text, replacement)).Error
+}
@@ -1,16 +1,29 @@
 package repository
 
 import (
+	"fmt"
 	"hotel_ip-p2/model/domain"
+	"sort"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BookRoomRepository interface {
 	Create(db *gorm.DB, bookRoom domain.BookRoom) (domain.BookRoom, error)
-	FindByUserId(db *gorm.DB, userId int) ([]domain.BookRoom, error)
-	FindByRoomIdAndDate(db *gorm.DB, roomId int, date time.Time) (domain.BookRoom, error)
+	FindByUserId(db *gorm.DB, userId int, query domain.CursorQuery) ([]domain.BookRoom, *domain.Cursor, error)
+	FindById(db *gorm.DB, id int) (domain.BookRoom, error)
+	LockById(db *gorm.DB, id int) (domain.BookRoom, error)
+	FindIdsAwaitingArrival(db *gorm.DB, before time.Time) ([]int, error)
+	Update(db *gorm.DB, bookRoom domain.BookRoom) (domain.BookRoom, error)
+	UpdateRoom(db *gorm.DB, bookRoomId int, roomId int) error
+	DeleteNightsByBookRoomId(db *gorm.DB, bookRoomId int) error
+	DeleteNightsFromDate(db *gorm.DB, bookRoomId int, from time.Time) error
+	LockNights(db *gorm.DB, roomId int, dates []time.Time) error
+	FindNightsByRoomIdAndDateRange(db *gorm.DB, roomId int, checkIn time.Time, checkOut time.Time) ([]domain.BookRoomNight, error)
+	CreateStatusHistory(db *gorm.DB, history domain.BookRoomStatusHistory) (domain.BookRoomStatusHistory, error)
+	CountActiveByUserId(db *gorm.DB, userId int) (int64, error)
 }
 
 type BookRoomRepositoryImpl struct{}
@@ -24,18 +37,154 @@ func (r *BookRoomRepositoryImpl) Create(db *gorm.DB, bookRoom domain.BookRoom) (
 	if err != nil {
 		return bookRoom, err
 	}
-	err = db.Preload("Room.RoomType").Preload("User").First(&bookRoom, bookRoom.ID).Error
+	err = db.Preload("Room", withDeleted).Preload("Room.RoomType", withDeleted).Preload("User", withDeleted).Preload("Nights").Preload("LineItems", orderLineItems).First(&bookRoom, bookRoom.ID).Error
 	return bookRoom, err
 }
 
-func (r *BookRoomRepositoryImpl) FindByUserId(db *gorm.DB, userId int) ([]domain.BookRoom, error) {
+var bookRoomListFields = listFields{
+	filters: map[string]listFilter{
+		"status":  {condition: "status = ?"},
+		"room_id": {condition: "room_id = ?", numeric: true},
+	},
+}
+
+// FindByUserId returns a user's bookings newest stay first, one keyset page
+// at a time. The returned cursor points at the last booking of the page and
+// is nil when there are no more bookings.
+func (r *BookRoomRepositoryImpl) FindByUserId(db *gorm.DB, userId int, query domain.CursorQuery) ([]domain.BookRoom, *domain.Cursor, error) {
+	filtered, err := bookRoomListFields.applyFilters(db.Where("user_id = ?", userId), query.Filters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if query.After != nil {
+		afterCheckIn, err := time.Parse("2006-01-02", query.After.Key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: malformed cursor", ErrInvalidListQuery)
+		}
+		filtered = filtered.Where("(check_in, id) < (?, ?)", afterCheckIn, query.After.ID)
+	}
+
 	var bookRooms []domain.BookRoom
-	err := db.Preload("Room.RoomType").Preload("User").Where("user_id = ?", userId).Find(&bookRooms).Error
-	return bookRooms, err
+	err = filtered.Preload("Room", withDeleted).Preload("Room.RoomType", withDeleted).Preload("User", withDeleted).Preload("LineItems", orderLineItems).
+		Order("check_in DESC, id DESC").
+		Limit(query.PerPage + 1).
+		Find(&bookRooms).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(bookRooms) <= query.PerPage {
+		return bookRooms, nil, nil
+	}
+
+	bookRooms = bookRooms[:query.PerPage]
+	last := bookRooms[len(bookRooms)-1]
+	return bookRooms, &domain.Cursor{Key: last.CheckIn.Format("2006-01-02"), ID: last.ID}, nil
 }
 
-func (r *BookRoomRepositoryImpl) FindByRoomIdAndDate(db *gorm.DB, roomId int, date time.Time) (domain.BookRoom, error) {
+func (r *BookRoomRepositoryImpl) FindById(db *gorm.DB, id int) (domain.BookRoom, error) {
 	var bookRoom domain.BookRoom
-	err := db.Where("room_id = ? AND date = ?", roomId, date).First(&bookRoom).Error
+	err := db.Preload("Room", withDeleted).Preload("Room.RoomType", withDeleted).Preload("User", withDeleted).Preload("LineItems", orderLineItems).First(&bookRoom, id).Error
+	return bookRoom, err
+}
+
+// LockById loads a booking and locks its row until the transaction ends, so
+// concurrent status changes of the same booking happen one at a time.
+func (r *BookRoomRepositoryImpl) LockById(db *gorm.DB, id int) (domain.BookRoom, error) {
+	var bookRoom domain.BookRoom
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&bookRoom, id).Error
+	return bookRoom, err
+}
+
+// FindIdsAwaitingArrival returns the confirmed bookings whose guests were
+// due to arrive before the given date, oldest arrival first.
+func (r *BookRoomRepositoryImpl) FindIdsAwaitingArrival(db *gorm.DB, before time.Time) ([]int, error) {
+	var ids []int
+	err := db.Model(&domain.BookRoom{}).
+		Where("status = ? AND check_in < ?", domain.BookRoomStatusConfirmed, before).
+		Order("check_in, id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *BookRoomRepositoryImpl) Update(db *gorm.DB, bookRoom domain.BookRoom) (domain.BookRoom, error) {
+	err := db.Model(&domain.BookRoom{}).Where("id = ?", bookRoom.ID).Updates(map[string]interface{}{
+		"status":         bookRoom.Status,
+		"refund_amount":  bookRoom.RefundAmount,
+		"cancelled_at":   bookRoom.CancelledAt,
+		"checked_in_at":  bookRoom.CheckedInAt,
+		"checked_out_at": bookRoom.CheckedOutAt,
+	}).Error
+	if err != nil {
+		return bookRoom, err
+	}
+	err = db.Preload("Room", withDeleted).Preload("Room.RoomType", withDeleted).Preload("User", withDeleted).Preload("LineItems", orderLineItems).First(&bookRoom, bookRoom.ID).Error
 	return bookRoom, err
 }
+
+// UpdateRoom moves a booking and its nights to another room.
+func (r *BookRoomRepositoryImpl) UpdateRoom(db *gorm.DB, bookRoomId int, roomId int) error {
+	err := db.Model(&domain.BookRoomNight{}).Where("book_room_id = ?", bookRoomId).Update("room_id", roomId).Error
+	if err != nil {
+		return err
+	}
+	return db.Model(&domain.BookRoom{}).Where("id = ?", bookRoomId).Update("room_id", roomId).Error
+}
+
+// orderLineItems lists a booking's line items in the order they were
+// priced.
+func orderLineItems(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
+// withDeleted lets a booking load the room, room type and guest it was made
+// for even after they have been deleted.
+func withDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+func (r *BookRoomRepositoryImpl) DeleteNightsByBookRoomId(db *gorm.DB, bookRoomId int) error {
+	return db.Where("book_room_id = ?", bookRoomId).Delete(&domain.BookRoomNight{}).Error
+}
+
+// DeleteNightsFromDate releases the booking's nights dated from on or later.
+func (r *BookRoomRepositoryImpl) DeleteNightsFromDate(db *gorm.DB, bookRoomId int, from time.Time) error {
+	return db.Where("book_room_id = ? AND date >= ?", bookRoomId, from).Delete(&domain.BookRoomNight{}).Error
+}
+
+func (r *BookRoomRepositoryImpl) CreateStatusHistory(db *gorm.DB, history domain.BookRoomStatusHistory) (domain.BookRoomStatusHistory, error) {
+	err := db.Create(&history).Error
+	return history, err
+}
+
+func (r *BookRoomRepositoryImpl) FindNightsByRoomIdAndDateRange(db *gorm.DB, roomId int, checkIn time.Time, checkOut time.Time) ([]domain.BookRoomNight, error) {
+	var nights []domain.BookRoomNight
+	err := db.Where("room_id = ? AND date >= ? AND date < ?", roomId, checkIn, checkOut).Order("date").Find(&nights).Error
+	return nights, err
+}
+
+// LockNights takes a transaction-scoped advisory lock on each (room, night)
+// pair so concurrent bookings of the same night are checked one at a time.
+// Locks are taken in date order so overlapping stays cannot deadlock.
+func (r *BookRoomRepositoryImpl) LockNights(db *gorm.DB, roomId int, dates []time.Time) error {
+	sorted := append([]time.Time(nil), dates...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	for _, date := range sorted {
+		day := int(time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+		err := db.Exec("SELECT pg_advisory_xact_lock(?, ?)", roomId, day).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CountActiveByUserId counts the user's bookings that have not run their
+// course yet: confirmed stays and guests still checked in.
+func (r *BookRoomRepositoryImpl) CountActiveByUserId(db *gorm.DB, userId int) (int64, error) {
+	var count int64
+	err := db.Model(&domain.BookRoom{}).Where("user_id = ? AND status IN ?", userId, []string{domain.BookRoomStatusConfirmed, domain.BookRoomStatusCheckedIn}).Count(&count).Error
+	return count, err
+}
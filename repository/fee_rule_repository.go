@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"hotel_ip-p2/model/domain"
+
+	"gorm.io/gorm"
+)
+
+type FeeRuleRepository interface {
+	Create(db *gorm.DB, feeRule domain.FeeRule) (domain.FeeRule, error)
+	FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.FeeRule, int64, error)
+	FindActive(db *gorm.DB) ([]domain.FeeRule, error)
+	FindById(db *gorm.DB, id int) (domain.FeeRule, error)
+	Update(db *gorm.DB, feeRule domain.FeeRule) (domain.FeeRule, error)
+	Delete(db *gorm.DB, id int) error
+}
+
+type FeeRuleRepositoryImpl struct{}
+
+var feeRuleListFields = listFields{
+	filters: map[string]listFilter{
+		"type": {condition: "type = ?"},
+	},
+	sorts: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"created_at": "created_at",
+	},
+	defaultOrder: "id",
+}
+
+func NewFeeRuleRepository() FeeRuleRepository {
+	return &FeeRuleRepositoryImpl{}
+}
+
+func (r *FeeRuleRepositoryImpl) Create(db *gorm.DB, feeRule domain.FeeRule) (domain.FeeRule, error) {
+	err := db.Create(&feeRule).Error
+	return feeRule, err
+}
+
+func (r *FeeRuleRepositoryImpl) FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.FeeRule, int64, error) {
+	var feeRules []domain.FeeRule
+	total, err := findPage(db, &domain.FeeRule{}, feeRuleListFields, query, &feeRules)
+	return feeRules, total, err
+}
+
+// FindActive returns the rules charged on new bookings, in the order their
+// line items are listed.
+func (r *FeeRuleRepositoryImpl) FindActive(db *gorm.DB) ([]domain.FeeRule, error) {
+	var feeRules []domain.FeeRule
+	err := db.Where("active = ?", true).Order("id").Find(&feeRules).Error
+	return feeRules, err
+}
+
+func (r *FeeRuleRepositoryImpl) FindById(db *gorm.DB, id int) (domain.FeeRule, error) {
+	var feeRule domain.FeeRule
+	err := db.First(&feeRule, id).Error
+	return feeRule, err
+}
+
+func (r *FeeRuleRepositoryImpl) Update(db *gorm.DB, feeRule domain.FeeRule) (domain.FeeRule, error) {
+	err := db.Save(&feeRule).Error
+	return feeRule, err
+}
+
+func (r *FeeRuleRepositoryImpl) Delete(db *gorm.DB, id int) error {
+	return db.Delete(&domain.FeeRule{}, id).Error
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"hotel_ip-p2/model/domain"
+
+	"gorm.io/gorm"
+)
+
+type InvoiceRepository interface {
+	Create(db *gorm.DB, invoice domain.Invoice) (domain.Invoice, error)
+	FindBySubject(db *gorm.DB, subjectType string, subjectId int) (domain.Invoice, error)
+	NextNumber(db *gorm.DB, series string, year int) (int64, error)
+}
+
+type InvoiceRepositoryImpl struct{}
+
+func NewInvoiceRepository() InvoiceRepository {
+	return &InvoiceRepositoryImpl{}
+}
+
+func (r *InvoiceRepositoryImpl) Create(db *gorm.DB, invoice domain.Invoice) (domain.Invoice, error) {
+	err := db.Create(&invoice).Error
+	return invoice, err
+}
+
+func (r *InvoiceRepositoryImpl) FindBySubject(db *gorm.DB, subjectType string, subjectId int) (domain.Invoice, error) {
+	var invoice domain.Invoice
+	err := db.Where("subject_type = ? AND subject_id = ?", subjectType, subjectId).First(&invoice).Error
+	return invoice, err
+}
+
+// NextNumber takes the next number of a series in year. The counter row
+// stays locked until the transaction ends, so numbers are handed out one at
+// a time and a rolled back transaction gives its number back.
+func (r *InvoiceRepositoryImpl) NextNumber(db *gorm.DB, series string, year int) (int64, error) {
+	var next int64
+	err := db.Raw(`INSERT INTO invoice_sequences (series, year, last_number) VALUES (?, ?, 1)
+		ON CONFLICT (series, year) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+		RETURNING last_number`, series, year).Scan(&next).Error
+	return next, err
+}
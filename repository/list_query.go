@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"hotel_ip-p2/model/domain"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidListQuery is returned when a list query filters or sorts on a
+// field the list does not support, or gives a filter a malformed value.
+var ErrInvalidListQuery = errors.New("invalid list query")
+
+// listFilter is a SQL condition with a single placeholder for the value.
+// Numeric filters reject values that are not numbers before querying.
+type listFilter struct {
+	condition string
+	numeric   bool
+}
+
+// listFields describes which filters and sort fields a list accepts. Sorts
+// map the public field name to its column; defaultOrder is appended last so
+// pages are stable.
+type listFields struct {
+	filters      map[string]listFilter
+	sorts        map[string]string
+	defaultOrder string
+}
+
+func (fields listFields) applyFilters(db *gorm.DB, filters map[string]string) (*gorm.DB, error) {
+	for name, value := range filters {
+		filter, ok := fields.filters[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown filter %q", ErrInvalidListQuery, name)
+		}
+		if filter.numeric {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return nil, fmt.Errorf("%w: filter %q must be a number", ErrInvalidListQuery, name)
+			}
+		}
+		db = db.Where(filter.condition, value)
+	}
+	return db, nil
+}
+
+func (fields listFields) applySort(db *gorm.DB, sort []domain.SortField) (*gorm.DB, error) {
+	for _, field := range sort {
+		column, ok := fields.sorts[field.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown sort field %q", ErrInvalidListQuery, field.Field)
+		}
+		if field.Desc {
+			column += " DESC"
+		}
+		db = db.Order(column)
+	}
+	return db.Order(fields.defaultOrder), nil
+}
+
+// findPage counts the rows of model matching query and loads the requested
+// page of them into dest, preloading the given associations.
+func findPage(db *gorm.DB, model interface{}, fields listFields, query domain.ListQuery, dest interface{}, preloads ...string) (int64, error) {
+	filtered, err := fields.applyFilters(db.Model(model), query.Filters)
+	if err != nil {
+		return 0, err
+	}
+	filtered = filtered.Session(&gorm.Session{})
+
+	sorted, err := fields.applySort(filtered, query.Sort)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	err = filtered.Count(&total).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, association := range preloads {
+		sorted = sorted.Preload(association)
+	}
+
+	err = sorted.Offset(query.Offset()).Limit(query.PerPage).Find(dest).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
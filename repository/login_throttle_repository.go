@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginThrottleRepository interface {
+	Find(db *gorm.DB, scope string, key string) (domain.LoginThrottle, error)
+	Lock(db *gorm.DB, scope string, key string) (domain.LoginThrottle, error)
+	Update(db *gorm.DB, throttle domain.LoginThrottle) error
+	Delete(db *gorm.DB, scope string, key string) error
+	DeleteStale(db *gorm.DB, before time.Time) (int64, error)
+}
+
+type LoginThrottleRepositoryImpl struct{}
+
+func NewLoginThrottleRepository() LoginThrottleRepository {
+	return &LoginThrottleRepositoryImpl{}
+}
+
+func (r *LoginThrottleRepositoryImpl) Find(db *gorm.DB, scope string, key string) (domain.LoginThrottle, error) {
+	var throttle domain.LoginThrottle
+	err := db.Where("scope = ? AND key = ?", scope, key).First(&throttle).Error
+	return throttle, err
+}
+
+// Lock loads the throttle for scope and key, creating it first if needed,
+// and locks its row until the transaction ends so concurrent failures are
+// counted one at a time.
+func (r *LoginThrottleRepositoryImpl) Lock(db *gorm.DB, scope string, key string) (domain.LoginThrottle, error) {
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.LoginThrottle{Scope: scope, Key: key}).Error
+	if err != nil {
+		return domain.LoginThrottle{}, err
+	}
+
+	var throttle domain.LoginThrottle
+	err = db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("scope = ? AND key = ?", scope, key).First(&throttle).Error
+	return throttle, err
+}
+
+func (r *LoginThrottleRepositoryImpl) Update(db *gorm.DB, throttle domain.LoginThrottle) error {
+	return db.Model(&domain.LoginThrottle{}).Where("id = ?", throttle.ID).Updates(map[string]interface{}{
+		"failures":        throttle.Failures,
+		"last_failure_at": throttle.LastFailureAt,
+		"locked_until":    throttle.LockedUntil,
+	}).Error
+}
+
+func (r *LoginThrottleRepositoryImpl) Delete(db *gorm.DB, scope string, key string) error {
+	return db.Where("scope = ? AND key = ?", scope, key).Delete(&domain.LoginThrottle{}).Error
+}
+
+// DeleteStale deletes throttles with no failure since before that are not
+// locked any more.
+func (r *LoginThrottleRepositoryImpl) DeleteStale(db *gorm.DB, before time.Time) (int64, error) {
+	result := db.Where("(last_failure_at IS NULL OR last_failure_at < ?) AND (locked_until IS NULL OR locked_until < ?)", before, before).Delete(&domain.LoginThrottle{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MaintenanceBlockRepository interface {
+	Create(db *gorm.DB, block domain.MaintenanceBlock) (domain.MaintenanceBlock, error)
+	FindById(db *gorm.DB, id int) (domain.MaintenanceBlock, error)
+	FindByRoomId(db *gorm.DB, roomId int) ([]domain.MaintenanceBlock, error)
+	FindByRoomIdAndDateRange(db *gorm.DB, roomId int, checkIn time.Time, checkOut time.Time) ([]domain.MaintenanceBlock, error)
+	Delete(db *gorm.DB, id int) error
+}
+
+type MaintenanceBlockRepositoryImpl struct{}
+
+func NewMaintenanceBlockRepository() MaintenanceBlockRepository {
+	return &MaintenanceBlockRepositoryImpl{}
+}
+
+func (r *MaintenanceBlockRepositoryImpl) Create(db *gorm.DB, block domain.MaintenanceBlock) (domain.MaintenanceBlock, error) {
+	err := db.Create(&block).Error
+	return block, err
+}
+
+func (r *MaintenanceBlockRepositoryImpl) FindById(db *gorm.DB, id int) (domain.MaintenanceBlock, error) {
+	var block domain.MaintenanceBlock
+	err := db.First(&block, id).Error
+	return block, err
+}
+
+func (r *MaintenanceBlockRepositoryImpl) FindByRoomId(db *gorm.DB, roomId int) ([]domain.MaintenanceBlock, error) {
+	var blocks []domain.MaintenanceBlock
+	err := db.Where("room_id = ?", roomId).Order("start_date, id").Find(&blocks).Error
+	return blocks, err
+}
+
+// FindByRoomIdAndDateRange returns the blocks of a room that overlap the
+// nights from checkIn up to but not including checkOut.
+func (r *MaintenanceBlockRepositoryImpl) FindByRoomIdAndDateRange(db *gorm.DB, roomId int, checkIn time.Time, checkOut time.Time) ([]domain.MaintenanceBlock, error) {
+	var blocks []domain.MaintenanceBlock
+	err := db.Where("room_id = ? AND start_date < ? AND end_date >= ?", roomId, checkOut, checkIn).
+		Order("start_date, id").
+		Find(&blocks).Error
+	return blocks, err
+}
+
+func (r *MaintenanceBlockRepositoryImpl) Delete(db *gorm.DB, id int) error {
+	return db.Delete(&domain.MaintenanceBlock{}, id).Error
+}
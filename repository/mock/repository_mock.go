@@ -22,6 +22,31 @@ func (m *TopupRepositoryMock) FindByOrderID(db *gorm.DB, orderID string) (domain
 	return args.Get(0).(domain.Topup), args.Error(1)
 }
 
+func (m *TopupRepositoryMock) FindById(db *gorm.DB, id int) (domain.Topup, error) {
+	args := m.Called(db, id)
+	return args.Get(0).(domain.Topup), args.Error(1)
+}
+
+func (m *TopupRepositoryMock) CountPendingByUserId(db *gorm.DB, userId int) (int64, error) {
+	args := m.Called(db, userId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *TopupRepositoryMock) Update(db *gorm.DB, topup domain.Topup) (domain.Topup, error) {
+	args := m.Called(db, topup)
+	return args.Get(0).(domain.Topup), args.Error(1)
+}
+
+func (m *TopupRepositoryMock) CreateStatusHistory(db *gorm.DB, history domain.TopupStatusHistory) (domain.TopupStatusHistory, error) {
+	args := m.Called(db, history)
+	return args.Get(0).(domain.TopupStatusHistory), args.Error(1)
+}
+
+func (m *TopupRepositoryMock) LockOrderID(db *gorm.DB, orderID string) error {
+	args := m.Called(db, orderID)
+	return args.Error(0)
+}
+
 func (m *TopupRepositoryMock) FindByMidtransOrderID(db *gorm.DB, orderID string) (domain.Topup, error) {
 	args := m.Called(db, orderID)
 	return args.Get(0).(domain.Topup), args.Error(1)
@@ -41,9 +66,9 @@ func (m *RoomTypeRepositoryMock) Create(db *gorm.DB, roomType domain.RoomType) (
 	return args.Get(0).(domain.RoomType), args.Error(1)
 }
 
-func (m *RoomTypeRepositoryMock) FindAll(db *gorm.DB) ([]domain.RoomType, error) {
-	args := m.Called(db)
-	return args.Get(0).([]domain.RoomType), args.Error(1)
+func (m *RoomTypeRepositoryMock) FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.RoomType, int64, error) {
+	args := m.Called(db, query)
+	return args.Get(0).([]domain.RoomType), args.Get(1).(int64), args.Error(2)
 }
 
 func (m *RoomTypeRepositoryMock) FindById(db *gorm.DB, id int) (domain.RoomType, error) {
@@ -66,6 +91,21 @@ func (m *RoomTypeRepositoryMock) Delete(db *gorm.DB, id int) error {
 	return args.Error(0)
 }
 
+func (m *RoomTypeRepositoryMock) FindDeleted(db *gorm.DB, query domain.ListQuery) ([]domain.RoomType, int64, error) {
+	args := m.Called(db, query)
+	return args.Get(0).([]domain.RoomType), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RoomTypeRepositoryMock) FindDeletedById(db *gorm.DB, id int) (domain.RoomType, error) {
+	args := m.Called(db, id)
+	return args.Get(0).(domain.RoomType), args.Error(1)
+}
+
+func (m *RoomTypeRepositoryMock) Restore(db *gorm.DB, id int) error {
+	args := m.Called(db, id)
+	return args.Error(0)
+}
+
 type RoomRepositoryMock struct {
 	mock.Mock
 }
@@ -75,9 +115,9 @@ func (m *RoomRepositoryMock) Create(db *gorm.DB, room domain.Room) (domain.Room,
 	return args.Get(0).(domain.Room), args.Error(1)
 }
 
-func (m *RoomRepositoryMock) FindAll(db *gorm.DB) ([]domain.Room, error) {
-	args := m.Called(db)
-	return args.Get(0).([]domain.Room), args.Error(1)
+func (m *RoomRepositoryMock) FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.Room, int64, error) {
+	args := m.Called(db, query)
+	return args.Get(0).([]domain.Room), args.Get(1).(int64), args.Error(2)
 }
 
 func (m *RoomRepositoryMock) FindById(db *gorm.DB, id int) (domain.Room, error) {
@@ -85,6 +125,11 @@ func (m *RoomRepositoryMock) FindById(db *gorm.DB, id int) (domain.Room, error)
 	return args.Get(0).(domain.Room), args.Error(1)
 }
 
+func (m *RoomRepositoryMock) LockById(db *gorm.DB, id int) (domain.Room, error) {
+	args := m.Called(db, id)
+	return args.Get(0).(domain.Room), args.Error(1)
+}
+
 func (m *RoomRepositoryMock) FindByRoomNumber(db *gorm.DB, roomNumber string) (domain.Room, error) {
 	args := m.Called(db, roomNumber)
 	return args.Get(0).(domain.Room), args.Error(1)
@@ -95,6 +140,11 @@ func (m *RoomRepositoryMock) FindByRoomTypeId(db *gorm.DB, roomTypeId int) ([]do
 	return args.Get(0).([]domain.Room), args.Error(1)
 }
 
+func (m *RoomRepositoryMock) FindAvailable(db *gorm.DB, checkIn time.Time, checkOut time.Time, roomTypeId int) ([]domain.Room, error) {
+	args := m.Called(db, checkIn, checkOut, roomTypeId)
+	return args.Get(0).([]domain.Room), args.Error(1)
+}
+
 func (m *RoomRepositoryMock) Update(db *gorm.DB, room domain.Room) (domain.Room, error) {
 	args := m.Called(db, room)
 	return args.Get(0).(domain.Room), args.Error(1)
@@ -105,6 +155,26 @@ func (m *RoomRepositoryMock) Delete(db *gorm.DB, id int) error {
 	return args.Error(0)
 }
 
+func (m *RoomRepositoryMock) CountNightsFrom(db *gorm.DB, roomId int, from time.Time) (int64, error) {
+	args := m.Called(db, roomId, from)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *RoomRepositoryMock) FindDeleted(db *gorm.DB, query domain.ListQuery) ([]domain.Room, int64, error) {
+	args := m.Called(db, query)
+	return args.Get(0).([]domain.Room), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RoomRepositoryMock) FindDeletedById(db *gorm.DB, id int) (domain.Room, error) {
+	args := m.Called(db, id)
+	return args.Get(0).(domain.Room), args.Error(1)
+}
+
+func (m *RoomRepositoryMock) Restore(db *gorm.DB, id int) error {
+	args := m.Called(db, id)
+	return args.Error(0)
+}
+
 type BookRoomRepositoryMock struct {
 	mock.Mock
 }
@@ -114,12 +184,472 @@ func (m *BookRoomRepositoryMock) Create(db *gorm.DB, bookRoom domain.BookRoom) (
 	return args.Get(0).(domain.BookRoom), args.Error(1)
 }
 
-func (m *BookRoomRepositoryMock) FindByUserId(db *gorm.DB, userId int) ([]domain.BookRoom, error) {
+func (m *BookRoomRepositoryMock) FindByUserId(db *gorm.DB, userId int, query domain.CursorQuery) ([]domain.BookRoom, *domain.Cursor, error) {
+	args := m.Called(db, userId, query)
+	var next *domain.Cursor
+	if args.Get(1) != nil {
+		next = args.Get(1).(*domain.Cursor)
+	}
+	return args.Get(0).([]domain.BookRoom), next, args.Error(2)
+}
+
+func (m *BookRoomRepositoryMock) FindById(db *gorm.DB, id int) (domain.BookRoom, error) {
+	args := m.Called(db, id)
+	return args.Get(0).(domain.BookRoom), args.Error(1)
+}
+
+func (m *BookRoomRepositoryMock) LockById(db *gorm.DB, id int) (domain.BookRoom, error) {
+	args := m.Called(db, id)
+	return args.Get(0).(domain.BookRoom), args.Error(1)
+}
+
+func (m *BookRoomRepositoryMock) FindIdsAwaitingArrival(db *gorm.DB, before time.Time) ([]int, error) {
+	args := m.Called(db, before)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *BookRoomRepositoryMock) CreateStatusHistory(db *gorm.DB, history domain.BookRoomStatusHistory) (domain.BookRoomStatusHistory, error) {
+	args := m.Called(db, history)
+	return args.Get(0).(domain.BookRoomStatusHistory), args.Error(1)
+}
+
+func (m *BookRoomRepositoryMock) CountActiveByUserId(db *gorm.DB, userId int) (int64, error) {
 	args := m.Called(db, userId)
-	return args.Get(0).([]domain.BookRoom), args.Error(1)
+	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *BookRoomRepositoryMock) FindByRoomIdAndDate(db *gorm.DB, roomId int, date time.Time) (domain.BookRoom, error) {
-	args := m.Called(db, roomId, date)
+func (m *BookRoomRepositoryMock) Update(db *gorm.DB, bookRoom domain.BookRoom) (domain.BookRoom, error) {
+	args := m.Called(db, bookRoom)
 	return args.Get(0).(domain.BookRoom), args.Error(1)
 }
+
+func (m *BookRoomRepositoryMock) UpdateRoom(db *gorm.DB, bookRoomId int, roomId int) error {
+	args := m.Called(db, bookRoomId, roomId)
+	return args.Error(0)
+}
+
+func (m *BookRoomRepositoryMock) DeleteNightsByBookRoomId(db *gorm.DB, bookRoomId int) error {
+	args := m.Called(db, bookRoomId)
+	return args.Error(0)
+}
+
+func (m *BookRoomRepositoryMock) DeleteNightsFromDate(db *gorm.DB, bookRoomId int, from time.Time) error {
+	args := m.Called(db, bookRoomId, from)
+	return args.Error(0)
+}
+
+func (m *BookRoomRepositoryMock) LockNights(db *gorm.DB, roomId int, dates []time.Time) error {
+	args := m.Called(db, roomId, dates)
+	return args.Error(0)
+}
+
+func (m *BookRoomRepositoryMock) FindNightsByRoomIdAndDateRange(db *gorm.DB, roomId int, checkIn time.Time, checkOut time.Time) ([]domain.BookRoomNight, error) {
+	args := m.Called(db, roomId, checkIn, checkOut)
+	return args.Get(0).([]domain.BookRoomNight), args.Error(1)
+}
+
+type WalletTransactionRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *WalletTransactionRepositoryMock) Create(db *gorm.DB, transaction domain.WalletTransaction) (domain.WalletTransaction, error) {
+	args := m.Called(db, transaction)
+	return args.Get(0).(domain.WalletTransaction), args.Error(1)
+}
+
+func (m *WalletTransactionRepositoryMock) Debit(db *gorm.DB, transaction domain.WalletTransaction) (domain.WalletTransaction, error) {
+	args := m.Called(db, transaction)
+	return args.Get(0).(domain.WalletTransaction), args.Error(1)
+}
+
+func (m *WalletTransactionRepositoryMock) FindByUserId(db *gorm.DB, userId int, pagination domain.Pagination) ([]domain.WalletTransaction, int64, error) {
+	args := m.Called(db, userId, pagination)
+	return args.Get(0).([]domain.WalletTransaction), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *WalletTransactionRepositoryMock) FindUnreconciled(db *gorm.DB) ([]domain.WalletReconciliation, error) {
+	args := m.Called(db)
+	return args.Get(0).([]domain.WalletReconciliation), args.Error(1)
+}
+
+type RatePlanRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *RatePlanRepositoryMock) Create(db *gorm.DB, ratePlan domain.RatePlan) (domain.RatePlan, error) {
+	args := m.Called(db, ratePlan)
+	return args.Get(0).(domain.RatePlan), args.Error(1)
+}
+
+func (m *RatePlanRepositoryMock) FindById(db *gorm.DB, id int) (domain.RatePlan, error) {
+	args := m.Called(db, id)
+	return args.Get(0).(domain.RatePlan), args.Error(1)
+}
+
+func (m *RatePlanRepositoryMock) FindByRoomTypeId(db *gorm.DB, roomTypeId int) ([]domain.RatePlan, error) {
+	args := m.Called(db, roomTypeId)
+	return args.Get(0).([]domain.RatePlan), args.Error(1)
+}
+
+func (m *RatePlanRepositoryMock) FindByRoomTypeIdAndDateRange(db *gorm.DB, roomTypeId int, checkIn time.Time, checkOut time.Time) ([]domain.RatePlan, error) {
+	args := m.Called(db, roomTypeId, checkIn, checkOut)
+	return args.Get(0).([]domain.RatePlan), args.Error(1)
+}
+
+func (m *RatePlanRepositoryMock) Update(db *gorm.DB, ratePlan domain.RatePlan) (domain.RatePlan, error) {
+	args := m.Called(db, ratePlan)
+	return args.Get(0).(domain.RatePlan), args.Error(1)
+}
+
+func (m *RatePlanRepositoryMock) Delete(db *gorm.DB, id int) error {
+	args := m.Called(db, id)
+	return args.Error(0)
+}
+
+type PromoCodeRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *PromoCodeRepositoryMock) Create(db *gorm.DB, promoCode domain.PromoCode) (domain.PromoCode, error) {
+	args := m.Called(db, promoCode)
+	return args.Get(0).(domain.PromoCode), args.Error(1)
+}
+
+func (m *PromoCodeRepositoryMock) FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.PromoCode, int64, error) {
+	args := m.Called(db, query)
+	return args.Get(0).([]domain.PromoCode), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *PromoCodeRepositoryMock) FindById(db *gorm.DB, id int) (domain.PromoCode, error) {
+	args := m.Called(db, id)
+	return args.Get(0).(domain.PromoCode), args.Error(1)
+}
+
+func (m *PromoCodeRepositoryMock) FindByCode(db *gorm.DB, code string) (domain.PromoCode, error) {
+	args := m.Called(db, code)
+	return args.Get(0).(domain.PromoCode), args.Error(1)
+}
+
+func (m *PromoCodeRepositoryMock) LockByCode(db *gorm.DB, code string) (domain.PromoCode, error) {
+	args := m.Called(db, code)
+	return args.Get(0).(domain.PromoCode), args.Error(1)
+}
+
+func (m *PromoCodeRepositoryMock) Update(db *gorm.DB, promoCode domain.PromoCode) (domain.PromoCode, error) {
+	args := m.Called(db, promoCode)
+	return args.Get(0).(domain.PromoCode), args.Error(1)
+}
+
+func (m *PromoCodeRepositoryMock) Delete(db *gorm.DB, id int) error {
+	args := m.Called(db, id)
+	return args.Error(0)
+}
+
+func (m *PromoCodeRepositoryMock) CountRedemptions(db *gorm.DB, promoCodeId int) (int64, error) {
+	args := m.Called(db, promoCodeId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *PromoCodeRepositoryMock) CountActiveRedemptions(db *gorm.DB, promoCodeId int, userId int) (int64, error) {
+	args := m.Called(db, promoCodeId, userId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *PromoCodeRepositoryMock) CreateRedemption(db *gorm.DB, redemption domain.PromoRedemption) (domain.PromoRedemption, error) {
+	args := m.Called(db, redemption)
+	return args.Get(0).(domain.PromoRedemption), args.Error(1)
+}
+
+type FeeRuleRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *FeeRuleRepositoryMock) Create(db *gorm.DB, feeRule domain.FeeRule) (domain.FeeRule, error) {
+	args := m.Called(db, feeRule)
+	return args.Get(0).(domain.FeeRule), args.Error(1)
+}
+
+func (m *FeeRuleRepositoryMock) FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.FeeRule, int64, error) {
+	args := m.Called(db, query)
+	return args.Get(0).([]domain.FeeRule), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *FeeRuleRepositoryMock) FindActive(db *gorm.DB) ([]domain.FeeRule, error) {
+	args := m.Called(db)
+	return args.Get(0).([]domain.FeeRule), args.Error(1)
+}
+
+func (m *FeeRuleRepositoryMock) FindById(db *gorm.DB, id int) (domain.FeeRule, error) {
+	args := m.Called(db, id)
+	return args.Get(0).(domain.FeeRule), args.Error(1)
+}
+
+func (m *FeeRuleRepositoryMock) Update(db *gorm.DB, feeRule domain.FeeRule) (domain.FeeRule, error) {
+	args := m.Called(db, feeRule)
+	return args.Get(0).(domain.FeeRule), args.Error(1)
+}
+
+func (m *FeeRuleRepositoryMock) Delete(db *gorm.DB, id int) error {
+	args := m.Called(db, id)
+	return args.Error(0)
+}
+
+type InvoiceRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *InvoiceRepositoryMock) Create(db *gorm.DB, invoice domain.Invoice) (domain.Invoice, error) {
+	args := m.Called(db, invoice)
+	return args.Get(0).(domain.Invoice), args.Error(1)
+}
+
+func (m *InvoiceRepositoryMock) FindBySubject(db *gorm.DB, subjectType string, subjectId int) (domain.Invoice, error) {
+	args := m.Called(db, subjectType, subjectId)
+	return args.Get(0).(domain.Invoice), args.Error(1)
+}
+
+func (m *InvoiceRepositoryMock) NextNumber(db *gorm.DB, series string, year int) (int64, error) {
+	args := m.Called(db, series, year)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MaintenanceBlockRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *MaintenanceBlockRepositoryMock) Create(db *gorm.DB, block domain.MaintenanceBlock) (domain.MaintenanceBlock, error) {
+	args := m.Called(db, block)
+	return args.Get(0).(domain.MaintenanceBlock), args.Error(1)
+}
+
+func (m *MaintenanceBlockRepositoryMock) FindById(db *gorm.DB, id int) (domain.MaintenanceBlock, error) {
+	args := m.Called(db, id)
+	return args.Get(0).(domain.MaintenanceBlock), args.Error(1)
+}
+
+func (m *MaintenanceBlockRepositoryMock) FindByRoomId(db *gorm.DB, roomId int) ([]domain.MaintenanceBlock, error) {
+	args := m.Called(db, roomId)
+	return args.Get(0).([]domain.MaintenanceBlock), args.Error(1)
+}
+
+func (m *MaintenanceBlockRepositoryMock) FindByRoomIdAndDateRange(db *gorm.DB, roomId int, checkIn time.Time, checkOut time.Time) ([]domain.MaintenanceBlock, error) {
+	args := m.Called(db, roomId, checkIn, checkOut)
+	return args.Get(0).([]domain.MaintenanceBlock), args.Error(1)
+}
+
+func (m *MaintenanceBlockRepositoryMock) Delete(db *gorm.DB, id int) error {
+	args := m.Called(db, id)
+	return args.Error(0)
+}
+
+type RefreshTokenRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *RefreshTokenRepositoryMock) Create(db *gorm.DB, token domain.RefreshToken) (domain.RefreshToken, error) {
+	args := m.Called(db, token)
+	return args.Get(0).(domain.RefreshToken), args.Error(1)
+}
+
+func (m *RefreshTokenRepositoryMock) LockByHash(db *gorm.DB, tokenHash string) (domain.RefreshToken, error) {
+	args := m.Called(db, tokenHash)
+	return args.Get(0).(domain.RefreshToken), args.Error(1)
+}
+
+func (m *RefreshTokenRepositoryMock) FindByFamilyId(db *gorm.DB, familyId string) ([]domain.RefreshToken, error) {
+	args := m.Called(db, familyId)
+	return args.Get(0).([]domain.RefreshToken), args.Error(1)
+}
+
+func (m *RefreshTokenRepositoryMock) FindLiveByUserId(db *gorm.DB, userId int, now time.Time) ([]domain.RefreshToken, error) {
+	args := m.Called(db, userId, now)
+	return args.Get(0).([]domain.RefreshToken), args.Error(1)
+}
+
+func (m *RefreshTokenRepositoryMock) MarkUsed(db *gorm.DB, id int, usedAt time.Time) error {
+	args := m.Called(db, id, usedAt)
+	return args.Error(0)
+}
+
+func (m *RefreshTokenRepositoryMock) Revoke(db *gorm.DB, ids []int, revokedAt time.Time) error {
+	args := m.Called(db, ids, revokedAt)
+	return args.Error(0)
+}
+
+func (m *RefreshTokenRepositoryMock) DeleteExpired(db *gorm.DB, before time.Time) (int64, error) {
+	args := m.Called(db, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type RevokedTokenRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *RevokedTokenRepositoryMock) Create(db *gorm.DB, tokens []domain.RevokedToken) error {
+	args := m.Called(db, tokens)
+	return args.Error(0)
+}
+
+func (m *RevokedTokenRepositoryMock) FindUnexpired(db *gorm.DB, now time.Time) ([]domain.RevokedToken, error) {
+	args := m.Called(db, now)
+	return args.Get(0).([]domain.RevokedToken), args.Error(1)
+}
+
+func (m *RevokedTokenRepositoryMock) DeleteExpired(db *gorm.DB, before time.Time) (int64, error) {
+	args := m.Called(db, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type UserTokenRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *UserTokenRepositoryMock) Create(db *gorm.DB, token domain.UserToken) (domain.UserToken, error) {
+	args := m.Called(db, token)
+	return args.Get(0).(domain.UserToken), args.Error(1)
+}
+
+func (m *UserTokenRepositoryMock) LockByHash(db *gorm.DB, purpose string, tokenHash string) (domain.UserToken, error) {
+	args := m.Called(db, purpose, tokenHash)
+	return args.Get(0).(domain.UserToken), args.Error(1)
+}
+
+func (m *UserTokenRepositoryMock) MarkUsed(db *gorm.DB, id int, usedAt time.Time) error {
+	args := m.Called(db, id, usedAt)
+	return args.Error(0)
+}
+
+func (m *UserTokenRepositoryMock) InvalidateByUserId(db *gorm.DB, userId int, purpose string, at time.Time) error {
+	args := m.Called(db, userId, purpose, at)
+	return args.Error(0)
+}
+
+func (m *UserTokenRepositoryMock) DeleteExpired(db *gorm.DB, before time.Time) (int64, error) {
+	args := m.Called(db, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *UserTokenRepositoryMock) DeleteByUserId(db *gorm.DB, userId int) error {
+	args := m.Called(db, userId)
+	return args.Error(0)
+}
+
+type LoginThrottleRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *LoginThrottleRepositoryMock) Find(db *gorm.DB, scope string, key string) (domain.LoginThrottle, error) {
+	args := m.Called(db, scope, key)
+	return args.Get(0).(domain.LoginThrottle), args.Error(1)
+}
+
+func (m *LoginThrottleRepositoryMock) Lock(db *gorm.DB, scope string, key string) (domain.LoginThrottle, error) {
+	args := m.Called(db, scope, key)
+	return args.Get(0).(domain.LoginThrottle), args.Error(1)
+}
+
+func (m *LoginThrottleRepositoryMock) Update(db *gorm.DB, throttle domain.LoginThrottle) error {
+	args := m.Called(db, throttle)
+	return args.Error(0)
+}
+
+func (m *LoginThrottleRepositoryMock) Delete(db *gorm.DB, scope string, key string) error {
+	args := m.Called(db, scope, key)
+	return args.Error(0)
+}
+
+func (m *LoginThrottleRepositoryMock) DeleteStale(db *gorm.DB, before time.Time) (int64, error) {
+	args := m.Called(db, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type AuditLogRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *AuditLogRepositoryMock) Create(db *gorm.DB, auditLog domain.AuditLog) (domain.AuditLog, error) {
+	args := m.Called(db, auditLog)
+	return args.Get(0).(domain.AuditLog), args.Error(1)
+}
+
+func (m *AuditLogRepositoryMock) ReplaceInDetails(db *gorm.DB, text string, replacement string) error {
+	args := m.Called(db, text, replacement)
+	return args.Error(0)
+}
+
+type UserTOTPRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *UserTOTPRepositoryMock) FindByUserId(db *gorm.DB, userId int) (domain.UserTOTP, error) {
+	args := m.Called(db, userId)
+	return args.Get(0).(domain.UserTOTP), args.Error(1)
+}
+
+func (m *UserTOTPRepositoryMock) LockByUserId(db *gorm.DB, userId int) (domain.UserTOTP, error) {
+	args := m.Called(db, userId)
+	return args.Get(0).(domain.UserTOTP), args.Error(1)
+}
+
+func (m *UserTOTPRepositoryMock) Save(db *gorm.DB, totp domain.UserTOTP) (domain.UserTOTP, error) {
+	args := m.Called(db, totp)
+	return args.Get(0).(domain.UserTOTP), args.Error(1)
+}
+
+func (m *UserTOTPRepositoryMock) Confirm(db *gorm.DB, id int, confirmedAt time.Time, step int64) error {
+	args := m.Called(db, id, confirmedAt, step)
+	return args.Error(0)
+}
+
+func (m *UserTOTPRepositoryMock) UpdateLastUsedStep(db *gorm.DB, id int, step int64) error {
+	args := m.Called(db, id, step)
+	return args.Error(0)
+}
+
+func (m *UserTOTPRepositoryMock) DeleteByUserId(db *gorm.DB, userId int) error {
+	args := m.Called(db, userId)
+	return args.Error(0)
+}
+
+type RecoveryCodeRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *RecoveryCodeRepositoryMock) ReplaceByUserId(db *gorm.DB, userId int, codeHashes []string) error {
+	args := m.Called(db, userId, codeHashes)
+	return args.Error(0)
+}
+
+func (m *RecoveryCodeRepositoryMock) LockUnusedByHash(db *gorm.DB, userId int, codeHash string) (domain.RecoveryCode, error) {
+	args := m.Called(db, userId, codeHash)
+	return args.Get(0).(domain.RecoveryCode), args.Error(1)
+}
+
+func (m *RecoveryCodeRepositoryMock) MarkUsed(db *gorm.DB, id int, usedAt time.Time) error {
+	args := m.Called(db, id, usedAt)
+	return args.Error(0)
+}
+
+func (m *RecoveryCodeRepositoryMock) DeleteByUserId(db *gorm.DB, userId int) error {
+	args := m.Called(db, userId)
+	return args.Error(0)
+}
+
+type TwoFactorPolicyRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *TwoFactorPolicyRepositoryMock) FindAll(db *gorm.DB) ([]domain.TwoFactorPolicy, error) {
+	args := m.Called(db)
+	return args.Get(0).([]domain.TwoFactorPolicy), args.Error(1)
+}
+
+func (m *TwoFactorPolicyRepositoryMock) FindByRole(db *gorm.DB, role string) (domain.TwoFactorPolicy, error) {
+	args := m.Called(db, role)
+	return args.Get(0).(domain.TwoFactorPolicy), args.Error(1)
+}
+
+func (m *TwoFactorPolicyRepositoryMock) Save(db *gorm.DB, policy domain.TwoFactorPolicy) (domain.TwoFactorPolicy, error) {
+	args := m.Called(db, policy)
+	return args.Get(0).(domain.TwoFactorPolicy), args.Error(1)
+}
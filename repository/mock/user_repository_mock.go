@@ -2,6 +2,7 @@ package mock
 
 import (
 	"hotel_ip-p2/model/domain"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
@@ -30,3 +31,63 @@ func (m *UserRepositoryMock) Update(db *gorm.DB, user domain.User) (domain.User,
 	args := m.Called(db, user)
 	return args.Get(0).(domain.User), args.Error(1)
 }
+
+func (m *UserRepositoryMock) FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.User, int64, error) {
+	args := m.Called(db, query)
+	return args.Get(0).([]domain.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *UserRepositoryMock) UpdateRole(db *gorm.DB, id int, role string) error {
+	args := m.Called(db, id, role)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) CountByRole(db *gorm.DB, role string) (int64, error) {
+	args := m.Called(db, role)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *UserRepositoryMock) LockByRole(db *gorm.DB, role string) (int64, error) {
+	args := m.Called(db, role)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *UserRepositoryMock) Delete(db *gorm.DB, id int) error {
+	args := m.Called(db, id)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) FindDeleted(db *gorm.DB, query domain.ListQuery) ([]domain.User, int64, error) {
+	args := m.Called(db, query)
+	return args.Get(0).([]domain.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *UserRepositoryMock) FindDeletedById(db *gorm.DB, id int) (domain.User, error) {
+	args := m.Called(db, id)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *UserRepositoryMock) Restore(db *gorm.DB, id int) error {
+	args := m.Called(db, id)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) MarkEmailVerified(db *gorm.DB, id int, verifiedAt time.Time) error {
+	args := m.Called(db, id, verifiedAt)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) UpdatePassword(db *gorm.DB, id int, password string) error {
+	args := m.Called(db, id, password)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) LockById(db *gorm.DB, id int) (domain.User, error) {
+	args := m.Called(db, id)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *UserRepositoryMock) Anonymize(db *gorm.DB, id int, name string, email string) error {
+	args := m.Called(db, id, name, email)
+	return args.Error(0)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"hotel_ip-p2/model/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PromoCodeRepository interface {
+	Create(db *gorm.DB, promoCode domain.PromoCode) (domain.PromoCode, error)
+	FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.PromoCode, int64, error)
+	FindById(db *gorm.DB, id int) (domain.PromoCode, error)
+	FindByCode(db *gorm.DB, code string) (domain.PromoCode, error)
+	LockByCode(db *gorm.DB, code string) (domain.PromoCode, error)
+	Update(db *gorm.DB, promoCode domain.PromoCode) (domain.PromoCode, error)
+	Delete(db *gorm.DB, id int) error
+	CountRedemptions(db *gorm.DB, promoCodeId int) (int64, error)
+	CountActiveRedemptions(db *gorm.DB, promoCodeId int, userId int) (int64, error)
+	CreateRedemption(db *gorm.DB, redemption domain.PromoRedemption) (domain.PromoRedemption, error)
+}
+
+type PromoCodeRepositoryImpl struct{}
+
+var promoCodeListFields = listFields{
+	filters: map[string]listFilter{
+		"code":          {condition: "code ILIKE '%' || ? || '%'"},
+		"discount_type": {condition: "discount_type = ?"},
+	},
+	sorts: map[string]string{
+		"id":          "id",
+		"code":        "code",
+		"valid_until": "valid_until",
+		"created_at":  "created_at",
+	},
+	defaultOrder: "id",
+}
+
+func NewPromoCodeRepository() PromoCodeRepository {
+	return &PromoCodeRepositoryImpl{}
+}
+
+func (r *PromoCodeRepositoryImpl) Create(db *gorm.DB, promoCode domain.PromoCode) (domain.PromoCode, error) {
+	err := db.Omit("RoomTypes.*").Create(&promoCode).Error
+	if err != nil {
+		return promoCode, err
+	}
+	return r.FindById(db, promoCode.ID)
+}
+
+func (r *PromoCodeRepositoryImpl) FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.PromoCode, int64, error) {
+	var promoCodes []domain.PromoCode
+	total, err := findPage(db, &domain.PromoCode{}, promoCodeListFields, query, &promoCodes, "RoomTypes")
+	return promoCodes, total, err
+}
+
+func (r *PromoCodeRepositoryImpl) FindById(db *gorm.DB, id int) (domain.PromoCode, error) {
+	var promoCode domain.PromoCode
+	err := db.Preload("RoomTypes").First(&promoCode, id).Error
+	return promoCode, err
+}
+
+func (r *PromoCodeRepositoryImpl) FindByCode(db *gorm.DB, code string) (domain.PromoCode, error) {
+	var promoCode domain.PromoCode
+	err := db.Preload("RoomTypes").Where("code = ?", code).First(&promoCode).Error
+	return promoCode, err
+}
+
+// LockByCode loads a promo code and locks its row until the transaction
+// ends, so concurrent bookings count its redemptions one at a time.
+func (r *PromoCodeRepositoryImpl) LockByCode(db *gorm.DB, code string) (domain.PromoCode, error) {
+	var promoCode domain.PromoCode
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(&promoCode).Error
+	if err != nil {
+		return promoCode, err
+	}
+	err = db.Model(&promoCode).Association("RoomTypes").Find(&promoCode.RoomTypes)
+	return promoCode, err
+}
+
+func (r *PromoCodeRepositoryImpl) Update(db *gorm.DB, promoCode domain.PromoCode) (domain.PromoCode, error) {
+	err := db.Omit("RoomTypes").Save(&promoCode).Error
+	if err != nil {
+		return promoCode, err
+	}
+	err = db.Model(&promoCode).Omit("RoomTypes.*").Association("RoomTypes").Replace(promoCode.RoomTypes)
+	if err != nil {
+		return promoCode, err
+	}
+	return r.FindById(db, promoCode.ID)
+}
+
+func (r *PromoCodeRepositoryImpl) Delete(db *gorm.DB, id int) error {
+	return db.Select("RoomTypes").Delete(&domain.PromoCode{ID: id}).Error
+}
+
+func (r *PromoCodeRepositoryImpl) CountRedemptions(db *gorm.DB, promoCodeId int) (int64, error) {
+	var count int64
+	err := db.Model(&domain.PromoRedemption{}).Where("promo_code_id = ?", promoCodeId).Count(&count).Error
+	return count, err
+}
+
+// CountActiveRedemptions counts the redemptions of a promo code, by one user
+// when userId is not 0, whose bookings have not been cancelled. Cancelling a
+// booking gives its use of the code back.
+func (r *PromoCodeRepositoryImpl) CountActiveRedemptions(db *gorm.DB, promoCodeId int, userId int) (int64, error) {
+	query := db.Model(&domain.PromoRedemption{}).
+		Joins("JOIN book_rooms ON book_rooms.id = promo_redemptions.book_room_id").
+		Where("promo_redemptions.promo_code_id = ?", promoCodeId).
+		Where("book_rooms.status <> ?", domain.BookRoomStatusCancelled)
+	if userId != 0 {
+		query = query.Where("promo_redemptions.user_id = ?", userId)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count, err
+}
+
+func (r *PromoCodeRepositoryImpl) CreateRedemption(db *gorm.DB, redemption domain.PromoRedemption) (domain.PromoRedemption, error) {
+	err := db.Create(&redemption).Error
+	return redemption, err
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RatePlanRepository interface {
+	Create(db *gorm.DB, ratePlan domain.RatePlan) (domain.RatePlan, error)
+	FindById(db *gorm.DB, id int) (domain.RatePlan, error)
+	FindByRoomTypeId(db *gorm.DB, roomTypeId int) ([]domain.RatePlan, error)
+	FindByRoomTypeIdAndDateRange(db *gorm.DB, roomTypeId int, checkIn time.Time, checkOut time.Time) ([]domain.RatePlan, error)
+	Update(db *gorm.DB, ratePlan domain.RatePlan) (domain.RatePlan, error)
+	Delete(db *gorm.DB, id int) error
+}
+
+type RatePlanRepositoryImpl struct{}
+
+func NewRatePlanRepository() RatePlanRepository {
+	return &RatePlanRepositoryImpl{}
+}
+
+func (r *RatePlanRepositoryImpl) Create(db *gorm.DB, ratePlan domain.RatePlan) (domain.RatePlan, error) {
+	err := db.Create(&ratePlan).Error
+	return ratePlan, err
+}
+
+func (r *RatePlanRepositoryImpl) FindById(db *gorm.DB, id int) (domain.RatePlan, error) {
+	var ratePlan domain.RatePlan
+	err := db.First(&ratePlan, id).Error
+	return ratePlan, err
+}
+
+func (r *RatePlanRepositoryImpl) FindByRoomTypeId(db *gorm.DB, roomTypeId int) ([]domain.RatePlan, error) {
+	var ratePlans []domain.RatePlan
+	err := db.Where("room_type_id = ?", roomTypeId).Order("priority DESC, id DESC").Find(&ratePlans).Error
+	return ratePlans, err
+}
+
+// FindByRoomTypeIdAndDateRange returns the plans of a room type whose dates
+// overlap the nights from checkIn up to but not including checkOut.
+func (r *RatePlanRepositoryImpl) FindByRoomTypeIdAndDateRange(db *gorm.DB, roomTypeId int, checkIn time.Time, checkOut time.Time) ([]domain.RatePlan, error) {
+	var ratePlans []domain.RatePlan
+	err := db.Where("room_type_id = ?", roomTypeId).
+		Where("start_date IS NULL OR start_date < ?", checkOut).
+		Where("end_date IS NULL OR end_date >= ?", checkIn).
+		Order("priority DESC, id DESC").
+		Find(&ratePlans).Error
+	return ratePlans, err
+}
+
+func (r *RatePlanRepositoryImpl) Update(db *gorm.DB, ratePlan domain.RatePlan) (domain.RatePlan, error) {
+	err := db.Save(&ratePlan).Error
+	return ratePlan, err
+}
+
+func (r *RatePlanRepositoryImpl) Delete(db *gorm.DB, id int) error {
+	return db.Delete(&domain.RatePlan{}, id).Error
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecoveryCodeRepository interface {
+	ReplaceByUserId(db *gorm.DB, userId int, codeHashes []string) error
+	LockUnusedByHash(db *gorm.DB, userId int, codeHash string) (domain.RecoveryCode, error)
+	MarkUsed(db *gorm.DB, id int, usedAt time.Time) error
+	DeleteByUserId(db *gorm.DB, userId int) error
+}
+
+type RecoveryCodeRepositoryImpl struct{}
+
+func NewRecoveryCodeRepository() RecoveryCodeRepository {
+	return &RecoveryCodeRepositoryImpl{}
+}
+
+// ReplaceByUserId deletes the user's recovery codes, used or not, and stores
+// codeHashes in their place.
+func (r *RecoveryCodeRepositoryImpl) ReplaceByUserId(db *gorm.DB, userId int, codeHashes []string) error {
+	if err := db.Where("user_id = ?", userId).Delete(&domain.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	codes := make([]domain.RecoveryCode, len(codeHashes))
+	for i, codeHash := range codeHashes {
+		codes[i] = domain.RecoveryCode{UserID: userId, CodeHash: codeHash}
+	}
+	return db.Create(&codes).Error
+}
+
+// LockUnusedByHash loads one of the user's unused recovery codes and locks
+// its row until the transaction ends, so it cannot be used twice.
+func (r *RecoveryCodeRepositoryImpl) LockUnusedByHash(db *gorm.DB, userId int, codeHash string) (domain.RecoveryCode, error) {
+	var code domain.RecoveryCode
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).First(&code).Error
+	return code, err
+}
+
+func (r *RecoveryCodeRepositoryImpl) MarkUsed(db *gorm.DB, id int, usedAt time.Time) error {
+	return db.Model(&domain.RecoveryCode{}).Where("id = ?", id).Update("used_at", usedAt).Error
+}
+
+func (r *RecoveryCodeRepositoryImpl) DeleteByUserId(db *gorm.DB, userId int) error {
+	return db.Where("user_id = ?", userId).Delete(&domain.RecoveryCode{}).Error
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RefreshTokenRepository interface {
+	Create(db *gorm.DB, token domain.RefreshToken) (domain.RefreshToken, error)
+	LockByHash(db *gorm.DB, tokenHash string) (domain.RefreshToken, error)
+	FindByFamilyId(db *gorm.DB, familyId string) ([]domain.RefreshToken, error)
+	FindLiveByUserId(db *gorm.DB, userId int, now time.Time) ([]domain.RefreshToken, error)
+	MarkUsed(db *gorm.DB, id int, usedAt time.Time) error
+	Revoke(db *gorm.DB, ids []int, revokedAt time.Time) error
+	DeleteExpired(db *gorm.DB, before time.Time) (int64, error)
+}
+
+type RefreshTokenRepositoryImpl struct{}
+
+func NewRefreshTokenRepository() RefreshTokenRepository {
+	return &RefreshTokenRepositoryImpl{}
+}
+
+func (r *RefreshTokenRepositoryImpl) Create(db *gorm.DB, token domain.RefreshToken) (domain.RefreshToken, error) {
+	err := db.Create(&token).Error
+	return token, err
+}
+
+// LockByHash loads a refresh token and locks its row until the transaction
+// ends, so the same token cannot be exchanged twice concurrently.
+func (r *RefreshTokenRepositoryImpl) LockByHash(db *gorm.DB, tokenHash string) (domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token_hash = ?", tokenHash).First(&token).Error
+	return token, err
+}
+
+func (r *RefreshTokenRepositoryImpl) FindByFamilyId(db *gorm.DB, familyId string) ([]domain.RefreshToken, error) {
+	var tokens []domain.RefreshToken
+	err := db.Where("family_id = ?", familyId).Order("id").Find(&tokens).Error
+	return tokens, err
+}
+
+// FindLiveByUserId returns the user's refresh tokens that have not expired
+// yet, whether or not they have been used or revoked.
+func (r *RefreshTokenRepositoryImpl) FindLiveByUserId(db *gorm.DB, userId int, now time.Time) ([]domain.RefreshToken, error) {
+	var tokens []domain.RefreshToken
+	err := db.Where("user_id = ? AND expires_at > ?", userId, now).Order("id").Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *RefreshTokenRepositoryImpl) MarkUsed(db *gorm.DB, id int, usedAt time.Time) error {
+	return db.Model(&domain.RefreshToken{}).Where("id = ?", id).Update("used_at", usedAt).Error
+}
+
+// Revoke revokes the given refresh tokens, leaving the revocation time of
+// tokens revoked earlier untouched.
+func (r *RefreshTokenRepositoryImpl) Revoke(db *gorm.DB, ids []int, revokedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.Model(&domain.RefreshToken{}).Where("id IN ? AND revoked_at IS NULL", ids).Update("revoked_at", revokedAt).Error
+}
+
+func (r *RefreshTokenRepositoryImpl) DeleteExpired(db *gorm.DB, before time.Time) (int64, error) {
+	result := db.Where("expires_at < ?", before).Delete(&domain.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
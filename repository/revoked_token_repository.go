@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevokedTokenRepository interface {
+	Create(db *gorm.DB, tokens []domain.RevokedToken) error
+	FindUnexpired(db *gorm.DB, now time.Time) ([]domain.RevokedToken, error)
+	DeleteExpired(db *gorm.DB, before time.Time) (int64, error)
+}
+
+type RevokedTokenRepositoryImpl struct{}
+
+func NewRevokedTokenRepository() RevokedTokenRepository {
+	return &RevokedTokenRepositoryImpl{}
+}
+
+// Create adds tokens to the denylist. Tokens already on it are skipped.
+func (r *RevokedTokenRepositoryImpl) Create(db *gorm.DB, tokens []domain.RevokedToken) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "jti"}}, DoNothing: true}).Create(&tokens).Error
+}
+
+// FindUnexpired returns the denylist entries whose tokens have not expired
+// by now. Access tokens are short-lived, so there are only ever a few.
+func (r *RevokedTokenRepositoryImpl) FindUnexpired(db *gorm.DB, now time.Time) ([]domain.RevokedToken, error) {
+	var tokens []domain.RevokedToken
+	err := db.Where("expires_at > ?", now).Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *RevokedTokenRepositoryImpl) DeleteExpired(db *gorm.DB, before time.Time) (int64, error) {
+	result := db.Where("expires_at < ?", before).Delete(&domain.RevokedToken{})
+	return result.RowsAffected, result.Error
+}
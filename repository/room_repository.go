@@ -2,22 +2,44 @@ package repository
 
 import (
 	"hotel_ip-p2/model/domain"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type RoomRepository interface {
 	Create(db *gorm.DB, room domain.Room) (domain.Room, error)
-	FindAll(db *gorm.DB) ([]domain.Room, error)
+	FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.Room, int64, error)
 	FindById(db *gorm.DB, id int) (domain.Room, error)
+	LockById(db *gorm.DB, id int) (domain.Room, error)
 	FindByRoomNumber(db *gorm.DB, roomNumber string) (domain.Room, error)
 	Update(db *gorm.DB, room domain.Room) (domain.Room, error)
 	Delete(db *gorm.DB, id int) error
 	FindByRoomTypeId(db *gorm.DB, roomTypeId int) ([]domain.Room, error)
+	FindAvailable(db *gorm.DB, checkIn time.Time, checkOut time.Time, roomTypeId int) ([]domain.Room, error)
+	CountNightsFrom(db *gorm.DB, roomId int, from time.Time) (int64, error)
+	FindDeleted(db *gorm.DB, query domain.ListQuery) ([]domain.Room, int64, error)
+	FindDeletedById(db *gorm.DB, id int) (domain.Room, error)
+	Restore(db *gorm.DB, id int) error
 }
 
 type RoomRepositoryImpl struct{}
 
+var roomListFields = listFields{
+	filters: map[string]listFilter{
+		"room_type_id": {condition: "room_type_id = ?", numeric: true},
+		"room_number":  {condition: "room_number ILIKE '%' || ? || '%'"},
+	},
+	sorts: map[string]string{
+		"id":           "id",
+		"room_number":  "room_number",
+		"room_type_id": "room_type_id",
+		"created_at":   "created_at",
+	},
+	defaultOrder: "id",
+}
+
 func NewRoomRepository() RoomRepository {
 	return &RoomRepositoryImpl{}
 }
@@ -31,10 +53,10 @@ func (r *RoomRepositoryImpl) Create(db *gorm.DB, room domain.Room) (domain.Room,
 	return room, err
 }
 
-func (r *RoomRepositoryImpl) FindAll(db *gorm.DB) ([]domain.Room, error) {
+func (r *RoomRepositoryImpl) FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.Room, int64, error) {
 	var rooms []domain.Room
-	err := db.Preload("RoomType").Find(&rooms).Error
-	return rooms, err
+	total, err := findPage(db, &domain.Room{}, roomListFields, query, &rooms, "RoomType")
+	return rooms, total, err
 }
 func (r *RoomRepositoryImpl) FindById(db *gorm.DB, id int) (domain.Room, error) {
 	var room domain.Room
@@ -42,6 +64,14 @@ func (r *RoomRepositoryImpl) FindById(db *gorm.DB, id int) (domain.Room, error)
 	return room, err
 }
 
+// LockById loads a room and locks its row until the transaction ends, so a
+// booking of the room and its deletion cannot both pass their checks.
+func (r *RoomRepositoryImpl) LockById(db *gorm.DB, id int) (domain.Room, error) {
+	var room domain.Room
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("RoomType").First(&room, id).Error
+	return room, err
+}
+
 func (r *RoomRepositoryImpl) FindByRoomNumber(db *gorm.DB, roomNumber string) (domain.Room, error) {
 	var room domain.Room
 	err := db.Where("room_number = ?", roomNumber).First(&room).Error
@@ -66,3 +96,43 @@ func (r *RoomRepositoryImpl) FindByRoomTypeId(db *gorm.DB, roomTypeId int) ([]do
 	err := db.Where("room_type_id = ?", roomTypeId).Find(&rooms).Error
 	return rooms, err
 }
+
+// FindAvailable returns rooms that have no booked night and no maintenance
+// block between checkIn (inclusive) and checkOut (exclusive), optionally
+// limited to one room type.
+func (r *RoomRepositoryImpl) FindAvailable(db *gorm.DB, checkIn time.Time, checkOut time.Time, roomTypeId int) ([]domain.Room, error) {
+	var rooms []domain.Room
+	query := db.Preload("RoomType").
+		Where("NOT EXISTS (SELECT 1 FROM book_room_nights WHERE book_room_nights.room_id = rooms.id AND book_room_nights.date >= ? AND book_room_nights.date < ?)", checkIn, checkOut).
+		Where("NOT EXISTS (SELECT 1 FROM maintenance_blocks WHERE maintenance_blocks.room_id = rooms.id AND maintenance_blocks.start_date < ? AND maintenance_blocks.end_date >= ?)", checkOut, checkIn)
+	if roomTypeId != 0 {
+		query = query.Where("room_type_id = ?", roomTypeId)
+	}
+	err := query.Order("room_type_id, room_number").Find(&rooms).Error
+	return rooms, err
+}
+
+// CountNightsFrom counts the nights booked in a room on or after from.
+func (r *RoomRepositoryImpl) CountNightsFrom(db *gorm.DB, roomId int, from time.Time) (int64, error) {
+	var count int64
+	err := db.Model(&domain.BookRoomNight{}).Where("room_id = ? AND date >= ?", roomId, from).Count(&count).Error
+	return count, err
+}
+
+// FindDeleted returns a page of deleted rooms with their room types, which
+// may have been deleted too.
+func (r *RoomRepositoryImpl) FindDeleted(db *gorm.DB, query domain.ListQuery) ([]domain.Room, int64, error) {
+	var rooms []domain.Room
+	total, err := findPage(db.Unscoped().Where("rooms.deleted_at IS NOT NULL"), &domain.Room{}, roomListFields, query, &rooms, "RoomType")
+	return rooms, total, err
+}
+
+func (r *RoomRepositoryImpl) FindDeletedById(db *gorm.DB, id int) (domain.Room, error) {
+	var room domain.Room
+	err := db.Unscoped().Preload("RoomType").Where("deleted_at IS NOT NULL").First(&room, id).Error
+	return room, err
+}
+
+func (r *RoomRepositoryImpl) Restore(db *gorm.DB, id int) error {
+	return db.Unscoped().Model(&domain.Room{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
@@ -8,15 +8,33 @@ import (
 
 type RoomTypeRepository interface {
 	Create(db *gorm.DB, roomType domain.RoomType) (domain.RoomType, error)
-	FindAll(db *gorm.DB) ([]domain.RoomType, error)
+	FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.RoomType, int64, error)
 	FindById(db *gorm.DB, id int) (domain.RoomType, error)
 	FindByName(db *gorm.DB, name string) (domain.RoomType, error)
 	Update(db *gorm.DB, roomType domain.RoomType) (domain.RoomType, error)
 	Delete(db *gorm.DB, id int) error
+	FindDeleted(db *gorm.DB, query domain.ListQuery) ([]domain.RoomType, int64, error)
+	FindDeletedById(db *gorm.DB, id int) (domain.RoomType, error)
+	Restore(db *gorm.DB, id int) error
 }
 
 type RoomTypeRepositoryImpl struct{}
 
+var roomTypeListFields = listFields{
+	filters: map[string]listFilter{
+		"name":      {condition: "name ILIKE '%' || ? || '%'"},
+		"min_price": {condition: "price >= ?", numeric: true},
+		"max_price": {condition: "price <= ?", numeric: true},
+	},
+	sorts: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"price":      "price",
+		"created_at": "created_at",
+	},
+	defaultOrder: "id",
+}
+
 func NewRoomTypeRepository() RoomTypeRepository {
 	return &RoomTypeRepositoryImpl{}
 }
@@ -26,10 +44,10 @@ func (r *RoomTypeRepositoryImpl) Create(db *gorm.DB, roomType domain.RoomType) (
 	return roomType, err
 }
 
-func (r *RoomTypeRepositoryImpl) FindAll(db *gorm.DB) ([]domain.RoomType, error) {
+func (r *RoomTypeRepositoryImpl) FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.RoomType, int64, error) {
 	var roomTypes []domain.RoomType
-	err := db.Find(&roomTypes).Error
-	return roomTypes, err
+	total, err := findPage(db, &domain.RoomType{}, roomTypeListFields, query, &roomTypes)
+	return roomTypes, total, err
 }
 func (r *RoomTypeRepositoryImpl) FindById(db *gorm.DB, id int) (domain.RoomType, error) {
 	var roomType domain.RoomType
@@ -51,3 +69,19 @@ func (r *RoomTypeRepositoryImpl) Update(db *gorm.DB, roomType domain.RoomType) (
 func (r *RoomTypeRepositoryImpl) Delete(db *gorm.DB, id int) error {
 	return db.Delete(&domain.RoomType{}, id).Error
 }
+
+func (r *RoomTypeRepositoryImpl) FindDeleted(db *gorm.DB, query domain.ListQuery) ([]domain.RoomType, int64, error) {
+	var roomTypes []domain.RoomType
+	total, err := findPage(db.Unscoped().Where("deleted_at IS NOT NULL"), &domain.RoomType{}, roomTypeListFields, query, &roomTypes)
+	return roomTypes, total, err
+}
+
+func (r *RoomTypeRepositoryImpl) FindDeletedById(db *gorm.DB, id int) (domain.RoomType, error) {
+	var roomType domain.RoomType
+	err := db.Unscoped().Where("deleted_at IS NOT NULL").First(&roomType, id).Error
+	return roomType, err
+}
+
+func (r *RoomTypeRepositoryImpl) Restore(db *gorm.DB, id int) error {
+	return db.Unscoped().Model(&domain.RoomType{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
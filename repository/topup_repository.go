@@ -9,6 +9,11 @@ import (
 type TopupRepository interface {
 	Create(db *gorm.DB, topup domain.Topup) (domain.Topup, error)
 	FindByOrderID(db *gorm.DB, orderID string) (domain.Topup, error)
+	FindById(db *gorm.DB, id int) (domain.Topup, error)
+	CountPendingByUserId(db *gorm.DB, userId int) (int64, error)
+	LockOrderID(db *gorm.DB, orderID string) error
+	Update(db *gorm.DB, topup domain.Topup) (domain.Topup, error)
+	CreateStatusHistory(db *gorm.DB, history domain.TopupStatusHistory) (domain.TopupStatusHistory, error)
 }
 
 type topupRepositoryImpl struct {
@@ -34,3 +39,52 @@ func (repository *topupRepositoryImpl) FindByOrderID(db *gorm.DB, orderID string
 	}
 	return topup, nil
 }
+
+func (repository *topupRepositoryImpl) FindById(db *gorm.DB, id int) (domain.Topup, error) {
+	var topup domain.Topup
+	err := db.First(&topup, id).Error
+	if err != nil {
+		return domain.Topup{}, err
+	}
+	return topup, nil
+}
+
+// CountPendingByUserId counts the user's topups whose payment may still
+// arrive: orders not paid yet and captures held for fraud review.
+func (repository *topupRepositoryImpl) CountPendingByUserId(db *gorm.DB, userId int) (int64, error) {
+	var count int64
+	err := db.Model(&domain.Topup{}).
+		Where("user_id = ? AND (status = ? OR (status = ? AND fraud_status = ?))", userId, domain.TopupStatusPending, domain.TopupStatusCapture, domain.FraudStatusChallenge).
+		Count(&count).Error
+	return count, err
+}
+
+// LockOrderID takes a transaction-scoped advisory lock on orderID so
+// concurrent notifications for the same order are processed one at a time.
+func (repository *topupRepositoryImpl) LockOrderID(db *gorm.DB, orderID string) error {
+	return db.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", orderID).Error
+}
+
+func (repository *topupRepositoryImpl) Update(db *gorm.DB, topup domain.Topup) (domain.Topup, error) {
+	err := db.Model(&domain.Topup{}).Where("id = ?", topup.ID).Updates(map[string]interface{}{
+		"midtrans_transaction_id": topup.MidtransTransactionID,
+		"status":                  topup.Status,
+		"payment_type":            topup.PaymentType,
+		"fraud_status":            topup.FraudStatus,
+		"refunded_amount":         topup.RefundedAmount,
+		"snap_token":              topup.SnapToken,
+		"redirect_url":            topup.RedirectURL,
+	}).Error
+	if err != nil {
+		return domain.Topup{}, err
+	}
+	return topup, nil
+}
+
+func (repository *topupRepositoryImpl) CreateStatusHistory(db *gorm.DB, history domain.TopupStatusHistory) (domain.TopupStatusHistory, error) {
+	err := db.Create(&history).Error
+	if err != nil {
+		return domain.TopupStatusHistory{}, err
+	}
+	return history, nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"hotel_ip-p2/model/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TwoFactorPolicyRepository interface {
+	FindAll(db *gorm.DB) ([]domain.TwoFactorPolicy, error)
+	FindByRole(db *gorm.DB, role string) (domain.TwoFactorPolicy, error)
+	Save(db *gorm.DB, policy domain.TwoFactorPolicy) (domain.TwoFactorPolicy, error)
+}
+
+type TwoFactorPolicyRepositoryImpl struct{}
+
+func NewTwoFactorPolicyRepository() TwoFactorPolicyRepository {
+	return &TwoFactorPolicyRepositoryImpl{}
+}
+
+func (r *TwoFactorPolicyRepositoryImpl) FindAll(db *gorm.DB) ([]domain.TwoFactorPolicy, error) {
+	var policies []domain.TwoFactorPolicy
+	err := db.Order("role ASC").Find(&policies).Error
+	return policies, err
+}
+
+func (r *TwoFactorPolicyRepositoryImpl) FindByRole(db *gorm.DB, role string) (domain.TwoFactorPolicy, error) {
+	var policy domain.TwoFactorPolicy
+	err := db.Where("role = ?", role).First(&policy).Error
+	return policy, err
+}
+
+// Save creates the policy for its role or overwrites the existing one.
+func (r *TwoFactorPolicyRepositoryImpl) Save(db *gorm.DB, policy domain.TwoFactorPolicy) (domain.TwoFactorPolicy, error) {
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "role"}},
+		DoUpdates: clause.AssignmentColumns([]string{"required", "updated_by", "updated_at"}),
+	}).Create(&policy).Error
+	return policy, err
+}
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"hotel_ip-p2/model/domain"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
@@ -11,6 +13,18 @@ type UserRepository interface {
 	FindByEmail(db *gorm.DB, email string) (domain.User, error)
 	FindById(db *gorm.DB, id int) (domain.User, error)
 	Update(db *gorm.DB, user domain.User) (domain.User, error)
+	FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.User, int64, error)
+	UpdateRole(db *gorm.DB, id int, role string) error
+	CountByRole(db *gorm.DB, role string) (int64, error)
+	LockByRole(db *gorm.DB, role string) (int64, error)
+	Delete(db *gorm.DB, id int) error
+	FindDeleted(db *gorm.DB, query domain.ListQuery) ([]domain.User, int64, error)
+	FindDeletedById(db *gorm.DB, id int) (domain.User, error)
+	Restore(db *gorm.DB, id int) error
+	MarkEmailVerified(db *gorm.DB, id int, verifiedAt time.Time) error
+	UpdatePassword(db *gorm.DB, id int, password string) error
+	LockById(db *gorm.DB, id int) (domain.User, error)
+	Anonymize(db *gorm.DB, id int, name string, email string) error
 }
 
 type userRepositoryImpl struct {
@@ -48,12 +62,110 @@ func (repository *userRepositoryImpl) FindById(db *gorm.DB, id int) (domain.User
 
 func (repository *userRepositoryImpl) Update(db *gorm.DB, user domain.User) (domain.User, error) {
 	err := db.Model(&domain.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
-		"name":    user.Name,
-		"email":   user.Email,
-		"balance": user.Balance,
+		"name":              user.Name,
+		"email":             user.Email,
+		"email_verified_at": user.EmailVerifiedAt,
 	}).Error
 	if err != nil {
 		return domain.User{}, err
 	}
 	return user, nil
 }
+
+var userListFields = listFields{
+	filters: map[string]listFilter{
+		"role":  {condition: "role = ?"},
+		"email": {condition: "email ILIKE '%' || ? || '%'"},
+	},
+	sorts: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"email":      "email",
+		"created_at": "created_at",
+	},
+	defaultOrder: "id",
+}
+
+func (repository *userRepositoryImpl) FindAll(db *gorm.DB, query domain.ListQuery) ([]domain.User, int64, error) {
+	var users []domain.User
+	total, err := findPage(db, &domain.User{}, userListFields, query, &users)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (repository *userRepositoryImpl) UpdateRole(db *gorm.DB, id int, role string) error {
+	return db.Model(&domain.User{}).Where("id = ?", id).Update("role", role).Error
+}
+
+func (repository *userRepositoryImpl) CountByRole(db *gorm.DB, role string) (int64, error) {
+	var count int64
+	err := db.Model(&domain.User{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
+// LockByRole locks the rows of every user holding role until the
+// transaction ends and returns how many there are, so concurrent role
+// changes and deletions see each other's effect on the count.
+func (repository *userRepositoryImpl) LockByRole(db *gorm.DB, role string) (int64, error) {
+	var ids []int
+	err := db.Model(&domain.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("role = ?", role).Pluck("id", &ids).Error
+	return int64(len(ids)), err
+}
+
+func (repository *userRepositoryImpl) Delete(db *gorm.DB, id int) error {
+	return db.Delete(&domain.User{}, id).Error
+}
+
+func (repository *userRepositoryImpl) FindDeleted(db *gorm.DB, query domain.ListQuery) ([]domain.User, int64, error) {
+	var users []domain.User
+	total, err := findPage(db.Unscoped().Where("deleted_at IS NOT NULL"), &domain.User{}, userListFields, query, &users)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (repository *userRepositoryImpl) FindDeletedById(db *gorm.DB, id int) (domain.User, error) {
+	var user domain.User
+	err := db.Unscoped().Where("deleted_at IS NOT NULL").First(&user, id).Error
+	if err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+func (repository *userRepositoryImpl) Restore(db *gorm.DB, id int) error {
+	return db.Unscoped().Model(&domain.User{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+func (repository *userRepositoryImpl) MarkEmailVerified(db *gorm.DB, id int, verifiedAt time.Time) error {
+	return db.Model(&domain.User{}).Where("id = ?", id).Update("email_verified_at", verifiedAt).Error
+}
+
+func (repository *userRepositoryImpl) UpdatePassword(db *gorm.DB, id int, password string) error {
+	return db.Model(&domain.User{}).Where("id = ?", id).Update("password", password).Error
+}
+
+// LockById loads a user and locks their row until the transaction ends.
+// Wallet entries update the same row, so they wait for the lock too.
+func (repository *userRepositoryImpl) LockById(db *gorm.DB, id int) (domain.User, error) {
+	var user domain.User
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, id).Error
+	if err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+// Anonymize replaces the personal data of a user with placeholders and
+// clears their password so nobody can log in as them again.
+func (repository *userRepositoryImpl) Anonymize(db *gorm.DB, id int, name string, email string) error {
+	return db.Model(&domain.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"name":              name,
+		"email":             email,
+		"password":          "",
+		"email_verified_at": nil,
+	}).Error
+}
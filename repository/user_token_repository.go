@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserTokenRepository interface {
+	Create(db *gorm.DB, token domain.UserToken) (domain.UserToken, error)
+	LockByHash(db *gorm.DB, purpose string, tokenHash string) (domain.UserToken, error)
+	MarkUsed(db *gorm.DB, id int, usedAt time.Time) error
+	InvalidateByUserId(db *gorm.DB, userId int, purpose string, at time.Time) error
+	DeleteExpired(db *gorm.DB, before time.Time) (int64, error)
+	DeleteByUserId(db *gorm.DB, userId int) error
+}
+
+type UserTokenRepositoryImpl struct{}
+
+func NewUserTokenRepository() UserTokenRepository {
+	return &UserTokenRepositoryImpl{}
+}
+
+func (r *UserTokenRepositoryImpl) Create(db *gorm.DB, token domain.UserToken) (domain.UserToken, error) {
+	err := db.Create(&token).Error
+	return token, err
+}
+
+// LockByHash loads a token issued for purpose and locks its row until the
+// transaction ends, so the same token cannot be used twice concurrently.
+func (r *UserTokenRepositoryImpl) LockByHash(db *gorm.DB, purpose string, tokenHash string) (domain.UserToken, error) {
+	var token domain.UserToken
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("purpose = ? AND token_hash = ?", purpose, tokenHash).First(&token).Error
+	return token, err
+}
+
+func (r *UserTokenRepositoryImpl) MarkUsed(db *gorm.DB, id int, usedAt time.Time) error {
+	return db.Model(&domain.UserToken{}).Where("id = ?", id).Update("used_at", usedAt).Error
+}
+
+// InvalidateByUserId marks every unused token the user holds for purpose as
+// used, so only the most recently mailed link works.
+func (r *UserTokenRepositoryImpl) InvalidateByUserId(db *gorm.DB, userId int, purpose string, at time.Time) error {
+	return db.Model(&domain.UserToken{}).Where("user_id = ? AND purpose = ? AND used_at IS NULL", userId, purpose).Update("used_at", at).Error
+}
+
+func (r *UserTokenRepositoryImpl) DeleteExpired(db *gorm.DB, before time.Time) (int64, error) {
+	result := db.Where("expires_at < ?", before).Delete(&domain.UserToken{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *UserTokenRepositoryImpl) DeleteByUserId(db *gorm.DB, userId int) error {
+	return db.Where("user_id = ?", userId).Delete(&domain.UserToken{}).Error
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"hotel_ip-p2/model/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserTOTPRepository interface {
+	FindByUserId(db *gorm.DB, userId int) (domain.UserTOTP, error)
+	LockByUserId(db *gorm.DB, userId int) (domain.UserTOTP, error)
+	Save(db *gorm.DB, totp domain.UserTOTP) (domain.UserTOTP, error)
+	Confirm(db *gorm.DB, id int, confirmedAt time.Time, step int64) error
+	UpdateLastUsedStep(db *gorm.DB, id int, step int64) error
+	DeleteByUserId(db *gorm.DB, userId int) error
+}
+
+type UserTOTPRepositoryImpl struct{}
+
+func NewUserTOTPRepository() UserTOTPRepository {
+	return &UserTOTPRepositoryImpl{}
+}
+
+func (r *UserTOTPRepositoryImpl) FindByUserId(db *gorm.DB, userId int) (domain.UserTOTP, error) {
+	var totp domain.UserTOTP
+	err := db.Where("user_id = ?", userId).First(&totp).Error
+	return totp, err
+}
+
+// LockByUserId loads the user's authenticator and locks its row until the
+// transaction ends, so the same code cannot be accepted twice concurrently.
+func (r *UserTOTPRepositoryImpl) LockByUserId(db *gorm.DB, userId int) (domain.UserTOTP, error) {
+	var totp domain.UserTOTP
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userId).First(&totp).Error
+	return totp, err
+}
+
+// Save stores a new, unconfirmed authenticator for the user, replacing one
+// left over from an enrolment that was never confirmed.
+func (r *UserTOTPRepositoryImpl) Save(db *gorm.DB, totp domain.UserTOTP) (domain.UserTOTP, error) {
+	err := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"secret":         totp.Secret,
+			"confirmed_at":   nil,
+			"last_used_step": 0,
+			"updated_at":     time.Now(),
+		}),
+	}).Create(&totp).Error
+	return totp, err
+}
+
+func (r *UserTOTPRepositoryImpl) Confirm(db *gorm.DB, id int, confirmedAt time.Time, step int64) error {
+	return db.Model(&domain.UserTOTP{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"confirmed_at":   confirmedAt,
+		"last_used_step": step,
+	}).Error
+}
+
+func (r *UserTOTPRepositoryImpl) UpdateLastUsedStep(db *gorm.DB, id int, step int64) error {
+	return db.Model(&domain.UserTOTP{}).Where("id = ?", id).Update("last_used_step", step).Error
+}
+
+func (r *UserTOTPRepositoryImpl) DeleteByUserId(db *gorm.DB, userId int) error {
+	return db.Where("user_id = ?", userId).Delete(&domain.UserTOTP{}).Error
+}
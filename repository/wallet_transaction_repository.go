@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"errors"
+	"hotel_ip-p2/model/domain"
+
+	"gorm.io/gorm"
+)
+
+type WalletTransactionRepository interface {
+	Create(db *gorm.DB, transaction domain.WalletTransaction) (domain.WalletTransaction, error)
+	Debit(db *gorm.DB, transaction domain.WalletTransaction) (domain.WalletTransaction, error)
+	FindByUserId(db *gorm.DB, userId int, pagination domain.Pagination) ([]domain.WalletTransaction, int64, error)
+	FindUnreconciled(db *gorm.DB) ([]domain.WalletReconciliation, error)
+}
+
+// ErrInsufficientBalance is returned by Debit when the entry would take the
+// balance below zero.
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+type walletTransactionRepositoryImpl struct {
+}
+
+func NewWalletTransactionRepository() WalletTransactionRepository {
+	return &walletTransactionRepositoryImpl{}
+}
+
+// Create applies the entry's amount to the user's balance and appends the
+// entry to the ledger. It must run inside the caller's transaction so both
+// writes commit together.
+func (repository *walletTransactionRepositoryImpl) Create(db *gorm.DB, transaction domain.WalletTransaction) (domain.WalletTransaction, error) {
+	var user domain.User
+	result := db.Raw("UPDATE users SET balance = balance + ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? RETURNING balance", transaction.Amount, transaction.UserID).Scan(&user)
+	if result.Error != nil {
+		return domain.WalletTransaction{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.WalletTransaction{}, gorm.ErrRecordNotFound
+	}
+
+	return repository.append(db, transaction, user.Balance)
+}
+
+// Debit is Create for entries that must not overdraw the wallet. The balance
+// check and the update are one statement, so concurrent debits cannot both
+// spend the same money.
+func (repository *walletTransactionRepositoryImpl) Debit(db *gorm.DB, transaction domain.WalletTransaction) (domain.WalletTransaction, error) {
+	var user domain.User
+	result := db.Raw("UPDATE users SET balance = balance + ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND balance + ? >= 0 RETURNING balance", transaction.Amount, transaction.UserID, transaction.Amount).Scan(&user)
+	if result.Error != nil {
+		return domain.WalletTransaction{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		err := db.Select("id").First(&domain.User{}, transaction.UserID).Error
+		if err != nil {
+			return domain.WalletTransaction{}, err
+		}
+		return domain.WalletTransaction{}, ErrInsufficientBalance
+	}
+
+	return repository.append(db, transaction, user.Balance)
+}
+
+func (repository *walletTransactionRepositoryImpl) append(db *gorm.DB, transaction domain.WalletTransaction, balanceAfter domain.Money) (domain.WalletTransaction, error) {
+	transaction.BalanceAfter = balanceAfter
+	err := db.Create(&transaction).Error
+	if err != nil {
+		return domain.WalletTransaction{}, err
+	}
+	return transaction, nil
+}
+
+func (repository *walletTransactionRepositoryImpl) FindByUserId(db *gorm.DB, userId int, pagination domain.Pagination) ([]domain.WalletTransaction, int64, error) {
+	var total int64
+	err := db.Model(&domain.WalletTransaction{}).Where("user_id = ?", userId).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var transactions []domain.WalletTransaction
+	err = db.Where("user_id = ?", userId).
+		Order("id DESC").
+		Offset(pagination.Offset()).
+		Limit(pagination.PerPage).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return transactions, total, nil
+}
+
+// FindUnreconciled lists users whose stored balance differs from the sum of
+// their ledger entries.
+func (repository *walletTransactionRepositoryImpl) FindUnreconciled(db *gorm.DB) ([]domain.WalletReconciliation, error) {
+	var reconciliations []domain.WalletReconciliation
+	err := db.Raw(`SELECT users.id AS user_id, users.balance AS balance, COALESCE(SUM(wallet_transactions.amount), 0) AS ledger_balance
+		FROM users
+		LEFT JOIN wallet_transactions ON wallet_transactions.user_id = users.id
+		GROUP BY users.id, users.balance
+		HAVING users.balance <> COALESCE(SUM(wallet_transactions.amount), 0)
+		ORDER BY users.id`).Scan(&reconciliations).Error
+	return reconciliations, err
+}
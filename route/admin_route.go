@@ -0,0 +1,44 @@
+package route
+
+import (
+	"hotel_ip-p2/controller"
+	"hotel_ip-p2/middleware"
+	"hotel_ip-p2/model/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+func AdminRoutes(e *echo.Group, userController *controller.UserController, twoFactorController *controller.TwoFactorController, roomController *controller.RoomController, roomTypeController *controller.RoomTypeController, walletController *controller.WalletController, ratePlanController *controller.RatePlanController, promoCodeController *controller.PromoCodeController, feeRuleController *controller.FeeRuleController) {
+	admin := e.Group("/admin")
+	adminOnly := middleware.RequireRole(domain.RoleAdmin)
+
+	admin.GET("/users", userController.FindAll, middleware.AuthMiddleware, adminOnly)
+	admin.GET("/users/deleted", userController.FindDeleted, middleware.AuthMiddleware, adminOnly)
+	admin.DELETE("/users/:id", userController.Delete, middleware.AuthMiddleware, adminOnly)
+	admin.POST("/users/:id/restore", userController.Restore, middleware.AuthMiddleware, adminOnly)
+	admin.POST("/users/:id/unlock", userController.Unlock, middleware.AuthMiddleware, adminOnly)
+	admin.PUT("/users/:id/role", userController.UpdateRole, middleware.AuthMiddleware, adminOnly)
+	admin.GET("/two-factor/roles", twoFactorController.FindPolicies, middleware.AuthMiddleware, adminOnly)
+	admin.PUT("/two-factor/roles/:role", twoFactorController.UpdatePolicy, middleware.AuthMiddleware, adminOnly)
+	admin.POST("/users/:id/adjustments", walletController.Adjust, middleware.AuthMiddleware, adminOnly)
+	admin.GET("/wallets/reconciliation", walletController.FindUnreconciled, middleware.AuthMiddleware, adminOnly)
+	admin.GET("/rooms/deleted", roomController.FindDeleted, middleware.AuthMiddleware, adminOnly)
+	admin.POST("/rooms/:id/restore", roomController.Restore, middleware.AuthMiddleware, adminOnly)
+	admin.GET("/room-types/deleted", roomTypeController.FindDeleted, middleware.AuthMiddleware, adminOnly)
+	admin.POST("/room-types/:id/restore", roomTypeController.Restore, middleware.AuthMiddleware, adminOnly)
+	admin.GET("/room-types/:id/rate-plans", ratePlanController.FindByRoomTypeId, middleware.AuthMiddleware, adminOnly)
+	admin.POST("/room-types/:id/rate-plans", ratePlanController.Create, middleware.AuthMiddleware, adminOnly)
+	admin.GET("/rate-plans/:id", ratePlanController.FindById, middleware.AuthMiddleware, adminOnly)
+	admin.PUT("/rate-plans/:id", ratePlanController.Update, middleware.AuthMiddleware, adminOnly)
+	admin.DELETE("/rate-plans/:id", ratePlanController.Delete, middleware.AuthMiddleware, adminOnly)
+	admin.GET("/promo-codes", promoCodeController.FindAll, middleware.AuthMiddleware, adminOnly)
+	admin.POST("/promo-codes", promoCodeController.Create, middleware.AuthMiddleware, adminOnly)
+	admin.GET("/promo-codes/:id", promoCodeController.FindById, middleware.AuthMiddleware, adminOnly)
+	admin.PUT("/promo-codes/:id", promoCodeController.Update, middleware.AuthMiddleware, adminOnly)
+	admin.DELETE("/promo-codes/:id", promoCodeController.Delete, middleware.AuthMiddleware, adminOnly)
+	admin.GET("/fee-rules", feeRuleController.FindAll, middleware.AuthMiddleware, adminOnly)
+	admin.POST("/fee-rules", feeRuleController.Create, middleware.AuthMiddleware, adminOnly)
+	admin.GET("/fee-rules/:id", feeRuleController.FindById, middleware.AuthMiddleware, adminOnly)
+	admin.PUT("/fee-rules/:id", feeRuleController.Update, middleware.AuthMiddleware, adminOnly)
+	admin.DELETE("/fee-rules/:id", feeRuleController.Delete, middleware.AuthMiddleware, adminOnly)
+}
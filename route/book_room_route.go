@@ -3,13 +3,21 @@ package route
 import (
 	"hotel_ip-p2/controller"
 	"hotel_ip-p2/middleware"
+	"hotel_ip-p2/model/domain"
 
 	"github.com/labstack/echo/v4"
 )
 
-func BookRoomRoutes(e *echo.Group, bookRoomController *controller.BookRoomController) {
+func BookRoomRoutes(e *echo.Group, bookRoomController *controller.BookRoomController, invoiceController *controller.InvoiceController) {
 	bookRooms := e.Group("/book-rooms")
+	staffOnly := middleware.RequireRole(domain.RoleAdmin, domain.RoleStaff)
 
 	bookRooms.POST("", bookRoomController.Create, middleware.AuthMiddleware)
+	bookRooms.POST("/quote", bookRoomController.Quote, middleware.AuthMiddleware)
 	bookRooms.GET("/my-bookings", bookRoomController.FindByUserId, middleware.AuthMiddleware)
+	bookRooms.DELETE("/:id", bookRoomController.Cancel, middleware.AuthMiddleware)
+	bookRooms.GET("/:id/invoice", invoiceController.BookRoomInvoice, middleware.AuthMiddleware)
+	bookRooms.POST("/:id/check-in", bookRoomController.CheckIn, middleware.AuthMiddleware, staffOnly)
+	bookRooms.POST("/:id/check-out", bookRoomController.CheckOut, middleware.AuthMiddleware, staffOnly)
+	bookRooms.POST("/:id/no-show", bookRoomController.NoShow, middleware.AuthMiddleware, staffOnly)
 }
@@ -0,0 +1,18 @@
+package route
+
+import (
+	"hotel_ip-p2/controller"
+	"hotel_ip-p2/middleware"
+	"hotel_ip-p2/model/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+func MaintenanceBlockRoutes(e *echo.Group, maintenanceBlockController *controller.MaintenanceBlockController) {
+	staffOnly := middleware.RequireRole(domain.RoleAdmin, domain.RoleStaff)
+
+	e.GET("/rooms/:id/maintenance-blocks", maintenanceBlockController.FindByRoomId, middleware.AuthMiddleware, staffOnly)
+	e.POST("/rooms/:id/maintenance-blocks", maintenanceBlockController.Create, middleware.AuthMiddleware, staffOnly)
+	e.GET("/maintenance-blocks/:id", maintenanceBlockController.FindById, middleware.AuthMiddleware, staffOnly)
+	e.DELETE("/maintenance-blocks/:id", maintenanceBlockController.Delete, middleware.AuthMiddleware, staffOnly)
+}
@@ -3,16 +3,19 @@ package route
 import (
 	"hotel_ip-p2/controller"
 	"hotel_ip-p2/middleware"
+	"hotel_ip-p2/model/domain"
 
 	"github.com/labstack/echo/v4"
 )
 
 func RoomRoutes(e *echo.Group, roomController *controller.RoomController) {
 	rooms := e.Group("/rooms")
+	staffOnly := middleware.RequireRole(domain.RoleAdmin, domain.RoleStaff)
 
-	rooms.POST("", roomController.Create, middleware.AuthMiddleware)
+	rooms.POST("", roomController.Create, middleware.AuthMiddleware, staffOnly)
 	rooms.GET("", roomController.FindAll, middleware.AuthMiddleware)
+	rooms.GET("/availability", roomController.FindAvailable, middleware.AuthMiddleware)
 	rooms.GET("/:id", roomController.FindById, middleware.AuthMiddleware)
-	rooms.PUT("/:id", roomController.Update, middleware.AuthMiddleware)
-	rooms.DELETE("/:id", roomController.Delete, middleware.AuthMiddleware)
+	rooms.PUT("/:id", roomController.Update, middleware.AuthMiddleware, staffOnly)
+	rooms.DELETE("/:id", roomController.Delete, middleware.AuthMiddleware, staffOnly)
 }
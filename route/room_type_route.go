@@ -3,16 +3,19 @@ package route
 import (
 	"hotel_ip-p2/controller"
 	"hotel_ip-p2/middleware"
+	"hotel_ip-p2/model/domain"
 
 	"github.com/labstack/echo/v4"
 )
 
 func RoomTypeRoutes(e *echo.Group, roomTypeController *controller.RoomTypeController) {
 	roomTypes := e.Group("/room-types")
+	staffOnly := middleware.RequireRole(domain.RoleAdmin, domain.RoleStaff)
 
-	roomTypes.POST("", roomTypeController.Create, middleware.AuthMiddleware)
+	roomTypes.POST("", roomTypeController.Create, middleware.AuthMiddleware, staffOnly)
 	roomTypes.GET("", roomTypeController.FindAll, middleware.AuthMiddleware)
 	roomTypes.GET("/:id", roomTypeController.FindById, middleware.AuthMiddleware)
-	roomTypes.PUT("/:id", roomTypeController.Update, middleware.AuthMiddleware)
-	roomTypes.DELETE("/:id", roomTypeController.Delete, middleware.AuthMiddleware)
+	roomTypes.GET("/:id/quote", roomTypeController.Quote, middleware.AuthMiddleware)
+	roomTypes.PUT("/:id", roomTypeController.Update, middleware.AuthMiddleware, staffOnly)
+	roomTypes.DELETE("/:id", roomTypeController.Delete, middleware.AuthMiddleware, staffOnly)
 }
@@ -7,10 +7,28 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func UserRoutes(e *echo.Group, userController *controller.UserController, topupController *controller.TopupController) {
+func UserRoutes(e *echo.Group, userController *controller.UserController, twoFactorController *controller.TwoFactorController, topupController *controller.TopupController, invoiceController *controller.InvoiceController) {
 	users := e.Group("/users")
 	users.POST("/register", userController.Register)
 	users.POST("/login", userController.Login)
+	users.POST("/login/2fa", twoFactorController.VerifyLogin)
+	users.POST("/login/2fa/enroll", twoFactorController.EnrollWithChallenge)
+	users.POST("/refresh", userController.Refresh)
+	users.POST("/logout", userController.Logout, middleware.AuthMiddleware)
+	users.POST("/logout-all", userController.LogoutAll, middleware.AuthMiddleware)
+	users.POST("/verify-email", userController.VerifyEmail)
+	users.POST("/forgot-password", userController.ForgotPassword)
+	users.POST("/reset-password", userController.ResetPassword)
 	users.GET("/me", userController.GetMe, middleware.AuthMiddleware)
+	users.PATCH("/me", userController.UpdateMe, middleware.AuthMiddleware)
+	users.DELETE("/me", userController.DeleteMe, middleware.AuthMiddleware)
+	users.PUT("/me/password", userController.ChangePassword, middleware.AuthMiddleware)
+	users.POST("/me/verification-email", userController.ResendVerificationEmail, middleware.AuthMiddleware)
+	users.POST("/me/2fa/enroll", twoFactorController.Enroll, middleware.AuthMiddleware)
+	users.POST("/me/2fa/confirm", twoFactorController.Confirm, middleware.AuthMiddleware)
+	users.POST("/me/2fa/recovery-codes", twoFactorController.RegenerateRecoveryCodes, middleware.AuthMiddleware)
+	users.DELETE("/me/2fa", twoFactorController.Disable, middleware.AuthMiddleware)
+	users.POST("/me/topups", topupController.Create, middleware.AuthMiddleware)
+	users.GET("/me/topups/:id/receipt", invoiceController.TopupReceipt, middleware.AuthMiddleware)
 	users.POST("/topup", topupController.TopupWebhook)
 }
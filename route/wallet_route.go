@@ -0,0 +1,14 @@
+package route
+
+import (
+	"hotel_ip-p2/controller"
+	"hotel_ip-p2/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func WalletRoutes(e *echo.Group, walletController *controller.WalletController) {
+	users := e.Group("/users")
+
+	users.GET("/me/transactions", walletController.FindMyTransactions, middleware.AuthMiddleware)
+}
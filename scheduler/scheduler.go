@@ -0,0 +1,171 @@
+package scheduler
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Job is background work run inside the server process at the times Next
+// returns. EveryReplica jobs keep per-process state up to date and run on
+// every replica instead of only on the leader.
+type Job struct {
+	Name         string
+	Next         func(after time.Time) time.Time
+	Run          func(ctx context.Context) error
+	EveryReplica bool
+}
+
+// Daily returns a Next function that fires once a day, at the given time
+// after midnight in after's location.
+func Daily(at time.Duration) func(after time.Time) time.Time {
+	return func(after time.Time) time.Time {
+		midnight := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, after.Location())
+		next := midnight.Add(at)
+		if !next.After(after) {
+			next = midnight.AddDate(0, 0, 1).Add(at)
+		}
+		return next
+	}
+}
+
+// Every returns a Next function that fires interval after the previous run.
+func Every(interval time.Duration) func(after time.Time) time.Time {
+	return func(after time.Time) time.Time {
+		return after.Add(interval)
+	}
+}
+
+// Scheduler runs jobs on every replica of the server, but each run only goes
+// ahead on the replica that wins the job's Postgres advisory lock, so a job
+// never runs twice at the same time.
+type Scheduler struct {
+	db     *gorm.DB
+	jobs   []Job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(db *gorm.DB) *Scheduler {
+	return &Scheduler{db: db}
+}
+
+// Add registers a job. Jobs must be added before Start.
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Stop cancels the context of running jobs and waits for them to return.
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	for {
+		next := job.Next(time.Now())
+		if !job.EveryReplica {
+			log.Printf("Job %s scheduled for %s", job.Name, next.Format(time.RFC3339))
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if job.EveryReplica {
+			s.run(ctx, job)
+		} else {
+			s.runAsLeader(ctx, job)
+		}
+	}
+}
+
+// runAsLeader runs job if no other replica is running it. The advisory lock
+// is held on a connection of its own for the whole run, so it is released
+// when the run ends or, if the process dies, when the connection drops.
+func (s *Scheduler) runAsLeader(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job %s panicked: %v", job.Name, r)
+		}
+	}()
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		log.Printf("Job %s could not get a database connection: %v", job.Name, err)
+		return
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		log.Printf("Job %s could not get a database connection: %v", job.Name, err)
+		return
+	}
+	defer conn.Close()
+
+	key := lockKey(job.Name)
+	var acquired bool
+	err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired)
+	if err != nil {
+		log.Printf("Job %s could not take its lock: %v", job.Name, err)
+		return
+	}
+	if !acquired {
+		log.Printf("Job %s is already running on another instance, skipping", job.Name)
+		return
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+
+	log.Printf("Running job %s", job.Name)
+	started := time.Now()
+	if err := job.Run(ctx); err != nil {
+		log.Printf("Job %s failed after %s: %v", job.Name, time.Since(started), err)
+		return
+	}
+	log.Printf("Job %s finished in %s", job.Name, time.Since(started))
+}
+
+// run runs job on this replica. Jobs run this way are frequent and quiet, so
+// only failures are logged.
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job %s panicked: %v", job.Name, r)
+		}
+	}()
+
+	if err := job.Run(ctx); err != nil {
+		log.Printf("Job %s failed: %v", job.Name, err)
+	}
+}
+
+// lockKey maps a job name onto the single bigint advisory lock key space,
+// which Postgres keeps apart from the two-int keys used for booking nights.
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("scheduler:" + name))
+	return int64(h.Sum64())
+}
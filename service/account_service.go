@@ -0,0 +1,598 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/helper"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type AccountService interface {
+	SendVerificationEmail(user domain.User) error
+	ResendVerificationEmail(userId int) error
+	VerifyEmail(token string) (domain.User, error)
+	ForgotPassword(email string, ip string) error
+	ResetPassword(token string, password string) error
+	UpdateProfile(userId int, name string, email string, currentPassword string) (domain.User, error)
+	ChangePassword(userId int, sessionId string, currentPassword string, newPassword string) error
+	DeleteAccount(userId int, password string) error
+	PurgeExpired(ctx context.Context) (int64, error)
+	Drain(ctx context.Context) error
+}
+
+type AccountServiceImpl struct {
+	UserRepository          repository.UserRepository
+	UserTokenRepository     repository.UserTokenRepository
+	BookRoomRepository      repository.BookRoomRepository
+	TopupRepository         repository.TopupRepository
+	LoginThrottleRepository repository.LoginThrottleRepository
+	AuditLogRepository      repository.AuditLogRepository
+	UserTOTPRepository      repository.UserTOTPRepository
+	RecoveryCodeRepository  repository.RecoveryCodeRepository
+	TokenService            TokenService
+	Mailer                  helper.Mailer
+	Policy                  domain.AccountMailPolicy
+	LoginPolicy             domain.LoginPolicy
+	DB                      *gorm.DB
+
+	// mailing tracks mails sent in the background so Drain can wait for
+	// them on shutdown.
+	mailing sync.WaitGroup
+}
+
+func NewAccountService(userRepository repository.UserRepository, userTokenRepository repository.UserTokenRepository, bookRoomRepository repository.BookRoomRepository, topupRepository repository.TopupRepository, loginThrottleRepository repository.LoginThrottleRepository, auditLogRepository repository.AuditLogRepository, userTOTPRepository repository.UserTOTPRepository, recoveryCodeRepository repository.RecoveryCodeRepository, tokenService TokenService, mailer helper.Mailer, policy domain.AccountMailPolicy, loginPolicy domain.LoginPolicy, db *gorm.DB) AccountService {
+	return &AccountServiceImpl{
+		UserRepository:          userRepository,
+		UserTokenRepository:     userTokenRepository,
+		BookRoomRepository:      bookRoomRepository,
+		TopupRepository:         topupRepository,
+		LoginThrottleRepository: loginThrottleRepository,
+		AuditLogRepository:      auditLogRepository,
+		UserTOTPRepository:      userTOTPRepository,
+		RecoveryCodeRepository:  recoveryCodeRepository,
+		TokenService:            tokenService,
+		Mailer:                  mailer,
+		Policy:                  policy,
+		LoginPolicy:             loginPolicy,
+		DB:                      db,
+	}
+}
+
+// SendVerificationEmail mails the user a link proving they own their email
+// address. Links mailed earlier stop working.
+func (s *AccountServiceImpl) SendVerificationEmail(user domain.User) error {
+	token, err := s.issue(user, domain.TokenPurposeEmailVerification, s.Policy.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.Mailer.Send(helper.Mail{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm that this is your email address by opening the link below:\n\n%s\n\nThe link expires in %s. If you did not create an account, you can ignore this email.\n",
+			user.Name, s.link("/verify-email", token), formatTTL(s.Policy.EmailVerificationTTL)),
+	})
+}
+
+func (s *AccountServiceImpl) ResendVerificationEmail(userId int) error {
+	user, err := s.UserRepository.FindById(s.DB, userId)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return exception.NewCustomError(http.StatusNotFound, "User not found")
+		}
+		return err
+	}
+
+	if user.IsEmailVerified() {
+		return exception.NewCustomError(http.StatusBadRequest, "Email address is already verified")
+	}
+
+	return s.SendVerificationEmail(user)
+}
+
+func (s *AccountServiceImpl) VerifyEmail(token string) (domain.User, error) {
+	var user domain.User
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		userToken, err := s.consume(tx, domain.TokenPurposeEmailVerification, token)
+		if err != nil {
+			return err
+		}
+
+		user, err = s.findTokenOwner(tx, userToken)
+		if err != nil {
+			return err
+		}
+
+		if user.IsEmailVerified() {
+			return nil
+		}
+
+		now := time.Now()
+		err = s.UserRepository.MarkEmailVerified(tx, user.ID, now)
+		if err != nil {
+			return err
+		}
+		user.EmailVerifiedAt = &now
+		return nil
+	})
+
+	return user, err
+}
+
+// ForgotPassword mails a password reset link to email. Requests are
+// throttled per email address and per client IP. The account is looked up
+// and the mail sent in the background, and failures there are only logged,
+// so the answer is the same whether or not someone has that email. Drain
+// waits for the mail to go out.
+func (s *AccountServiceImpl) ForgotPassword(email string, ip string) error {
+	now := time.Now()
+	throttler := s.loginThrottler()
+	throttles := passwordResetThrottleKeys(email, ip)
+
+	err := throttler.check(throttles, now)
+	if err != nil {
+		return err
+	}
+
+	err = throttler.recordFailure(throttles, 0, ip, now)
+	if err != nil {
+		return err
+	}
+
+	s.mailing.Add(1)
+	go func() {
+		defer s.mailing.Done()
+		if err := s.sendPasswordReset(email); err != nil {
+			log.Printf("Failed to send password reset email: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Drain waits for mails still being sent in the background, or for ctx to
+// end. Call it once the server stops taking requests, so a shutdown does
+// not drop them.
+func (s *AccountServiceImpl) Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.mailing.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sendPasswordReset mails a password reset link to the user with email, if
+// there is one.
+func (s *AccountServiceImpl) sendPasswordReset(email string) error {
+	user, err := s.UserRepository.FindByEmail(s.DB, email)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			log.Printf("Password reset requested for unknown email")
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.issue(user, domain.TokenPurposePasswordReset, s.Policy.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.Mailer.Send(helper.Mail{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset the password of your account. Choose a new password by opening the link below:\n\n%s\n\nThe link expires in %s. If you did not ask for this, you can ignore this email; your password stays the same.\n",
+			user.Name, s.link("/reset-password", token), formatTTL(s.Policy.PasswordResetTTL)),
+	})
+}
+
+func (s *AccountServiceImpl) loginThrottler() loginThrottler {
+	return loginThrottler{
+		LoginThrottleRepository: s.LoginThrottleRepository,
+		AuditLogRepository:      s.AuditLogRepository,
+		LoginPolicy:             s.LoginPolicy,
+		DB:                      s.DB,
+	}
+}
+
+// ResetPassword sets a new password with a token from ForgotPassword and
+// logs the user out everywhere, since whoever knew the old password may
+// still hold a session.
+func (s *AccountServiceImpl) ResetPassword(token string, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return exception.NewCustomError(http.StatusInternalServerError, "Failed to hash password")
+	}
+
+	var userId int
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		userToken, err := s.consume(tx, domain.TokenPurposePasswordReset, token)
+		if err != nil {
+			return err
+		}
+
+		user, err := s.findTokenOwner(tx, userToken)
+		if err != nil {
+			return err
+		}
+		userId = user.ID
+
+		err = s.UserRepository.UpdatePassword(tx, user.ID, string(hashedPassword))
+		if err != nil {
+			return err
+		}
+
+		return s.UserTokenRepository.InvalidateByUserId(tx, user.ID, domain.TokenPurposePasswordReset, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Password of user ID: %d was reset, ending all sessions", userId)
+	return s.TokenService.LogoutAll(userId)
+}
+
+// UpdateProfile changes the name and email address of a user, leaving empty
+// fields unchanged. Changing the email address takes the current password,
+// since whoever controls the address can reset the password. A new email
+// address has to be verified again, and the old address is told about the
+// change.
+func (s *AccountServiceImpl) UpdateProfile(userId int, name string, email string, currentPassword string) (domain.User, error) {
+	if name == "" && email == "" {
+		return domain.User{}, exception.NewCustomError(http.StatusBadRequest, "Nothing to update")
+	}
+
+	previous, err := s.UserRepository.FindById(s.DB, userId)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return domain.User{}, exception.NewCustomError(http.StatusNotFound, "User not found")
+		}
+		return domain.User{}, err
+	}
+
+	if email != "" && email != previous.Email {
+		if currentPassword == "" {
+			return domain.User{}, exception.NewCustomError(http.StatusBadRequest, "Current password is required to change the email address")
+		}
+		err = s.checkPassword(previous, currentPassword, "Current password is incorrect")
+		if err != nil {
+			return domain.User{}, err
+		}
+	}
+
+	var result domain.User
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		user := previous
+		if name != "" {
+			user.Name = name
+		}
+		if email != "" && email != user.Email {
+			_, err := s.UserRepository.FindByEmail(tx, email)
+			if err == nil {
+				return exception.NewCustomError(http.StatusConflict, "Email already exists")
+			}
+			if err != gorm.ErrRecordNotFound {
+				return err
+			}
+			user.Email = email
+			user.EmailVerifiedAt = nil
+		}
+
+		updated, err := s.UserRepository.Update(tx, user)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return exception.NewCustomError(http.StatusConflict, "Email already exists")
+		}
+		result = updated
+		return err
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if result.Email != previous.Email {
+		log.Printf("Email address of user ID: %d changed, sending verification email", userId)
+		err = s.Mailer.Send(helper.Mail{
+			To:      previous.Email,
+			Subject: "Your email address was changed",
+			Body: fmt.Sprintf("Hi %s,\n\nThe email address of your account was changed to %s. If you did not make this change, reset your password and contact us.\n",
+				previous.Name, result.Email),
+		})
+		if err != nil {
+			log.Printf("Failed to notify previous email address of user ID: %d: %v", userId, err)
+		}
+		if err := s.SendVerificationEmail(result); err != nil {
+			log.Printf("Failed to send verification email to user ID: %d: %v", userId, err)
+		}
+	}
+
+	return result, nil
+}
+
+// checkPassword confirms that the signed-in user knows their password before
+// a sensitive change. Wrong passwords count as failed logins for the account,
+// so a stolen session cannot be used to guess the password; incorrect is the
+// error message for one.
+func (s *AccountServiceImpl) checkPassword(user domain.User, password string, incorrect string) error {
+	now := time.Now()
+	throttler := s.loginThrottler()
+	throttles := []domain.LoginThrottle{{Scope: domain.LoginThrottleScopeAccount, Key: normalizeEmail(user.Email)}}
+
+	if err := throttler.check(throttles, now); err != nil {
+		return err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		if err := throttler.recordFailure(throttles, user.ID, "", now); err != nil {
+			log.Printf("Failed to record wrong password of user ID: %d: %v", user.ID, err)
+		}
+		return exception.NewCustomError(http.StatusBadRequest, incorrect)
+	}
+
+	throttler.reset(user.Email, user.ID)
+	return nil
+}
+
+// ChangePassword sets a new password for a user who knows the current one
+// and logs out every other session, keeping the one that made the change.
+func (s *AccountServiceImpl) ChangePassword(userId int, sessionId string, currentPassword string, newPassword string) error {
+	user, err := s.UserRepository.FindById(s.DB, userId)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return exception.NewCustomError(http.StatusNotFound, "User not found")
+		}
+		return err
+	}
+
+	err = s.checkPassword(user, currentPassword, "Current password is incorrect")
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return exception.NewCustomError(http.StatusInternalServerError, "Failed to hash password")
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		err := s.UserRepository.UpdatePassword(tx, userId, string(hashedPassword))
+		if err != nil {
+			return err
+		}
+		return s.UserTokenRepository.InvalidateByUserId(tx, userId, domain.TokenPurposePasswordReset, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Password of user ID: %d was changed, ending other sessions", userId)
+	return s.TokenService.LogoutOthers(userId, sessionId)
+}
+
+// DeleteAccount closes a user's account at their own request. Bookings,
+// wallet entries and invoices are kept for accounting, so the user row is
+// anonymised and soft deleted rather than removed. The email address is
+// scrubbed from login throttles and audit logs, and the two-factor secret
+// and recovery codes are deleted. Users with stays still ahead of them, money
+// left in their wallet or a topup payment still on its way cannot delete
+// their account. There is no withdrawal for guests; an admin pays out what is
+// left and debits it with a wallet adjustment.
+func (s *AccountServiceImpl) DeleteAccount(userId int, password string) error {
+	user, err := s.UserRepository.FindById(s.DB, userId)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return exception.NewCustomError(http.StatusNotFound, "User not found")
+		}
+		return err
+	}
+
+	err = s.checkPassword(user, password, "Password is incorrect")
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		// Bookings lock the user row too, so one in flight finishes before
+		// the checks below and one started later finds the account gone.
+		// Wallet entries only take the row lock when they post, so a topup
+		// that has not been paid yet is refused below instead.
+		user, err := s.UserRepository.LockById(tx, userId)
+		if err != nil {
+			return err
+		}
+
+		if user.Balance > 0 {
+			return exception.NewCustomError(http.StatusConflict, fmt.Sprintf("Your wallet still holds %s; contact us to have the balance paid out by an admin before deleting your account", user.Balance))
+		}
+
+		active, err := s.BookRoomRepository.CountActiveByUserId(tx, userId)
+		if err != nil {
+			return err
+		}
+		if active > 0 {
+			return exception.NewCustomError(http.StatusConflict, "Cannot delete an account with upcoming bookings")
+		}
+
+		pending, err := s.TopupRepository.CountPendingByUserId(tx, userId)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return exception.NewCustomError(http.StatusConflict, "Cannot delete an account while a topup payment is pending")
+		}
+
+		if user.Role == domain.RoleAdmin {
+			admins, err := s.UserRepository.LockByRole(tx, domain.RoleAdmin)
+			if err != nil {
+				return err
+			}
+			if admins <= 1 {
+				return exception.NewCustomError(http.StatusConflict, "Cannot delete the last admin")
+			}
+		}
+
+		anonymousEmail := fmt.Sprintf("deleted-user-%d@deleted.invalid", userId)
+		err = s.UserRepository.Anonymize(tx, userId, "Deleted user", anonymousEmail)
+		if err != nil {
+			return err
+		}
+
+		err = s.UserTokenRepository.DeleteByUserId(tx, userId)
+		if err != nil {
+			return err
+		}
+
+		err = s.scrubPersonalData(tx, userId, user.Email, anonymousEmail)
+		if err != nil {
+			return err
+		}
+
+		return s.UserRepository.Delete(tx, userId)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Account of user ID: %d was deleted, ending all sessions", userId)
+	return s.TokenService.LogoutAll(userId)
+}
+
+// scrubPersonalData removes what is kept about a deleted user outside the
+// user row: their login throttles, their email address in audit logs, which
+// is replaced with anonymousEmail, and their two-factor credentials.
+func (s *AccountServiceImpl) scrubPersonalData(tx *gorm.DB, userId int, email string, anonymousEmail string) error {
+	email = normalizeEmail(email)
+	throttles := []domain.LoginThrottle{
+		{Scope: domain.LoginThrottleScopeAccount, Key: email},
+		{Scope: domain.LoginThrottleScopeResetEmail, Key: email},
+		{Scope: domain.LoginThrottleScopeTwoFactor, Key: strconv.Itoa(userId)},
+	}
+	for _, throttle := range throttles {
+		err := s.LoginThrottleRepository.Delete(tx, throttle.Scope, throttle.Key)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := s.AuditLogRepository.ReplaceInDetails(tx, email, anonymousEmail)
+	if err != nil {
+		return err
+	}
+
+	err = s.UserTOTPRepository.DeleteByUserId(tx, userId)
+	if err != nil {
+		return err
+	}
+
+	return s.RecoveryCodeRepository.DeleteByUserId(tx, userId)
+}
+
+// PurgeExpired deletes mailed tokens that can no longer be used.
+func (s *AccountServiceImpl) PurgeExpired(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return s.UserTokenRepository.DeleteExpired(s.DB, time.Now())
+}
+
+// issue stores a new token for purpose, invalidating the ones mailed to the
+// user before, and returns the raw token to put in the link.
+func (s *AccountServiceImpl) issue(user domain.User, purpose string, ttl time.Duration) (string, error) {
+	token, err := helper.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := s.UserTokenRepository.InvalidateByUserId(tx, user.ID, purpose, now)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.UserTokenRepository.Create(tx, domain.UserToken{
+			UserID:    user.ID,
+			Purpose:   purpose,
+			Email:     user.Email,
+			TokenHash: hashToken(token),
+			ExpiresAt: now.Add(ttl),
+		})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// consume locks a mailed token and marks it used, refusing tokens that are
+// unknown, used or expired alike.
+func (s *AccountServiceImpl) consume(tx *gorm.DB, purpose string, token string) (domain.UserToken, error) {
+	userToken, err := s.UserTokenRepository.LockByHash(tx, purpose, hashToken(token))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return userToken, exception.NewCustomError(http.StatusBadRequest, "Invalid or expired token")
+		}
+		return userToken, err
+	}
+
+	now := time.Now()
+	if userToken.UsedAt != nil || !now.Before(userToken.ExpiresAt) {
+		return userToken, exception.NewCustomError(http.StatusBadRequest, "Invalid or expired token")
+	}
+
+	err = s.UserTokenRepository.MarkUsed(tx, userToken.ID, now)
+	return userToken, err
+}
+
+// findTokenOwner loads the user a token was mailed to, refusing the token if
+// the user has been deleted or has changed their email address since.
+func (s *AccountServiceImpl) findTokenOwner(tx *gorm.DB, userToken domain.UserToken) (domain.User, error) {
+	user, err := s.UserRepository.FindById(tx, userToken.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return user, exception.NewCustomError(http.StatusBadRequest, "Invalid or expired token")
+		}
+		return user, err
+	}
+
+	if user.Email != userToken.Email {
+		return user, exception.NewCustomError(http.StatusBadRequest, "Invalid or expired token")
+	}
+	return user, nil
+}
+
+func (s *AccountServiceImpl) link(path string, token string) string {
+	return s.Policy.AppURL + path + "?token=" + url.QueryEscape(token)
+}
+
+func formatTTL(ttl time.Duration) string {
+	if ttl%time.Hour == 0 {
+		hours := int(ttl / time.Hour)
+		if hours == 1 {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return fmt.Sprintf("%d minutes", int(ttl/time.Minute))
+}
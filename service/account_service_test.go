@@ -0,0 +1,612 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/helper"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository/mock"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var testAccountMailPolicy = domain.AccountMailPolicy{
+	AppURL:               "https://hotel.example.com",
+	EmailVerificationTTL: 48 * time.Hour,
+	PasswordResetTTL:     time.Hour,
+}
+
+type accountServiceMocks struct {
+	userRepo         *mock.UserRepositoryMock
+	userTokenRepo    *mock.UserTokenRepositoryMock
+	bookRoomRepo     *mock.BookRoomRepositoryMock
+	topupRepo        *mock.TopupRepositoryMock
+	throttleRepo     *mock.LoginThrottleRepositoryMock
+	auditLogRepo     *mock.AuditLogRepositoryMock
+	userTOTPRepo     *mock.UserTOTPRepositoryMock
+	recoveryCodeRepo *mock.RecoveryCodeRepositoryMock
+	refreshTokenRepo *mock.RefreshTokenRepositoryMock
+	revokedTokenRepo *mock.RevokedTokenRepositoryMock
+	mailer           *helper.MemoryMailer
+}
+
+func newAccountServiceForTest(db *gorm.DB) (AccountService, accountServiceMocks) {
+	mocks := accountServiceMocks{
+		userRepo:         new(mock.UserRepositoryMock),
+		userTokenRepo:    new(mock.UserTokenRepositoryMock),
+		bookRoomRepo:     new(mock.BookRoomRepositoryMock),
+		topupRepo:        new(mock.TopupRepositoryMock),
+		throttleRepo:     new(mock.LoginThrottleRepositoryMock),
+		auditLogRepo:     new(mock.AuditLogRepositoryMock),
+		userTOTPRepo:     new(mock.UserTOTPRepositoryMock),
+		recoveryCodeRepo: new(mock.RecoveryCodeRepositoryMock),
+		refreshTokenRepo: new(mock.RefreshTokenRepositoryMock),
+		revokedTokenRepo: new(mock.RevokedTokenRepositoryMock),
+		mailer:           helper.NewMemoryMailer(),
+	}
+	tokenService := NewTokenService(mocks.refreshTokenRepo, mocks.revokedTokenRepo, mocks.userRepo, testTokenIssuer, helper.NewTokenDenylist(), 30*24*time.Hour, db)
+	return NewAccountService(mocks.userRepo, mocks.userTokenRepo, mocks.bookRoomRepo, mocks.topupRepo, mocks.throttleRepo, mocks.auditLogRepo, mocks.userTOTPRepo, mocks.recoveryCodeRepo, tokenService, mocks.mailer, testAccountMailPolicy, testLoginPolicy, db), mocks
+}
+
+// mailedToken pulls the token out of the link in a mail body.
+func mailedToken(t *testing.T, body string) string {
+	start := strings.Index(body, testAccountMailPolicy.AppURL)
+	if !assert.GreaterOrEqual(t, start, 0) {
+		return ""
+	}
+	link := strings.Fields(body[start:])[0]
+	parsed, err := url.Parse(link)
+	assert.NoError(t, err)
+	return parsed.Query().Get("token")
+}
+
+func TestAccountService_SendVerificationEmail(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	user := domain.User{ID: 2, Name: "Jane Doe", Email: "jane@example.com"}
+
+	var stored domain.UserToken
+	sqlMock.ExpectBegin()
+	mocks.userTokenRepo.On("InvalidateByUserId", testifymock.Anything, 2, domain.TokenPurposeEmailVerification, testifymock.Anything).Return(nil)
+	mocks.userTokenRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(token domain.UserToken) bool {
+		stored = token
+		return true
+	})).Return(domain.UserToken{ID: 1}, nil)
+	sqlMock.ExpectCommit()
+
+	err := service.SendVerificationEmail(user)
+
+	assert.NoError(t, err)
+	sent := mocks.mailer.Sent()
+	assert.Len(t, sent, 1)
+	assert.Equal(t, "jane@example.com", sent[0].To)
+	assert.Contains(t, sent[0].Body, "https://hotel.example.com/verify-email?token=")
+
+	token := mailedToken(t, sent[0].Body)
+	assert.Equal(t, hashToken(token), stored.TokenHash)
+	assert.Equal(t, "jane@example.com", stored.Email)
+	assert.WithinDuration(t, time.Now().Add(48*time.Hour), stored.ExpiresAt, time.Minute)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAccountService_VerifyEmail_Success(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	token := domain.UserToken{ID: 1, UserID: 2, Purpose: domain.TokenPurposeEmailVerification, Email: "jane@example.com", ExpiresAt: time.Now().Add(time.Hour)}
+
+	sqlMock.ExpectBegin()
+	mocks.userTokenRepo.On("LockByHash", testifymock.Anything, domain.TokenPurposeEmailVerification, hashToken("mailed-token")).Return(token, nil)
+	mocks.userTokenRepo.On("MarkUsed", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mocks.userRepo.On("FindById", testifymock.Anything, 2).Return(domain.User{ID: 2, Email: "jane@example.com"}, nil)
+	mocks.userRepo.On("MarkEmailVerified", testifymock.Anything, 2, testifymock.Anything).Return(nil)
+	sqlMock.ExpectCommit()
+
+	user, err := service.VerifyEmail("mailed-token")
+
+	assert.NoError(t, err)
+	assert.True(t, user.IsEmailVerified())
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mocks.userRepo.AssertExpectations(t)
+}
+
+func TestAccountService_VerifyEmail_TokenUsed(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	usedAt := time.Now().Add(-time.Minute)
+	token := domain.UserToken{ID: 1, UserID: 2, Purpose: domain.TokenPurposeEmailVerification, Email: "jane@example.com", ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}
+
+	sqlMock.ExpectBegin()
+	mocks.userTokenRepo.On("LockByHash", testifymock.Anything, domain.TokenPurposeEmailVerification, hashToken("mailed-token")).Return(token, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.VerifyEmail("mailed-token")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Invalid or expired token", customErr.Message)
+	mocks.userRepo.AssertNotCalled(t, "MarkEmailVerified", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func TestAccountService_VerifyEmail_EmailChanged(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	token := domain.UserToken{ID: 1, UserID: 2, Purpose: domain.TokenPurposeEmailVerification, Email: "old@example.com", ExpiresAt: time.Now().Add(time.Hour)}
+
+	sqlMock.ExpectBegin()
+	mocks.userTokenRepo.On("LockByHash", testifymock.Anything, domain.TokenPurposeEmailVerification, hashToken("mailed-token")).Return(token, nil)
+	mocks.userTokenRepo.On("MarkUsed", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mocks.userRepo.On("FindById", testifymock.Anything, 2).Return(domain.User{ID: 2, Email: "new@example.com"}, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.VerifyEmail("mailed-token")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, customErr.Code)
+	mocks.userRepo.AssertNotCalled(t, "MarkEmailVerified", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+// expectPasswordResetCounted lets a password reset request from ip for
+// email pass the throttle and be counted.
+func expectPasswordResetCounted(sqlMock sqlmock.Sqlmock, mocks accountServiceMocks, email string, ip string) {
+	mocks.throttleRepo.On("Find", testifymock.Anything, testifymock.Anything, testifymock.Anything).Return(domain.LoginThrottle{}, gorm.ErrRecordNotFound)
+	sqlMock.ExpectBegin()
+	mocks.throttleRepo.On("Lock", testifymock.Anything, domain.LoginThrottleScopeResetEmail, email).Return(domain.LoginThrottle{ID: 1, Scope: domain.LoginThrottleScopeResetEmail, Key: email}, nil)
+	mocks.throttleRepo.On("Lock", testifymock.Anything, domain.LoginThrottleScopeResetIP, ip).Return(domain.LoginThrottle{ID: 2, Scope: domain.LoginThrottleScopeResetIP, Key: ip}, nil)
+	mocks.throttleRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(throttle domain.LoginThrottle) bool {
+		return throttle.Failures == 1 && throttle.LockedUntil == nil
+	})).Return(nil)
+	sqlMock.ExpectCommit()
+}
+
+// expectPasswordAccepted lets a correct password for email pass the login
+// throttle, which is then cleared.
+func expectPasswordAccepted(mocks accountServiceMocks, email string) {
+	mocks.throttleRepo.On("Find", testifymock.Anything, domain.LoginThrottleScopeAccount, email).Return(domain.LoginThrottle{}, gorm.ErrRecordNotFound)
+	mocks.throttleRepo.On("Delete", testifymock.Anything, domain.LoginThrottleScopeAccount, email).Return(nil)
+}
+
+func TestAccountService_ForgotPassword_Success(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	expectPasswordResetCounted(sqlMock, mocks, "jane@example.com", "203.0.113.7")
+	mocks.userRepo.On("FindByEmail", testifymock.Anything, "jane@example.com").Return(domain.User{ID: 2, Name: "Jane Doe", Email: "jane@example.com"}, nil)
+	sqlMock.ExpectBegin()
+	mocks.userTokenRepo.On("InvalidateByUserId", testifymock.Anything, 2, domain.TokenPurposePasswordReset, testifymock.Anything).Return(nil)
+	mocks.userTokenRepo.On("Create", testifymock.Anything, testifymock.Anything).Return(domain.UserToken{ID: 1}, nil)
+	sqlMock.ExpectCommit()
+
+	err := service.ForgotPassword("jane@example.com", "203.0.113.7")
+
+	assert.NoError(t, err)
+	assert.NoError(t, service.Drain(context.Background()))
+	sent := mocks.mailer.Sent()
+	assert.Len(t, sent, 1)
+	assert.Equal(t, "jane@example.com", sent[0].To)
+	assert.Contains(t, sent[0].Body, "https://hotel.example.com/reset-password?token=")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAccountService_ForgotPassword_UnknownEmail(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	expectPasswordResetCounted(sqlMock, mocks, "nobody@example.com", "203.0.113.7")
+	mocks.userRepo.On("FindByEmail", testifymock.Anything, "nobody@example.com").Return(domain.User{}, gorm.ErrRecordNotFound)
+
+	err := service.ForgotPassword("nobody@example.com", "203.0.113.7")
+
+	assert.NoError(t, err)
+	assert.NoError(t, service.Drain(context.Background()))
+	mocks.userRepo.AssertExpectations(t)
+	assert.Empty(t, mocks.mailer.Sent())
+	mocks.userTokenRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+}
+
+func TestAccountService_ForgotPassword_LookupFails(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	expectPasswordResetCounted(sqlMock, mocks, "jane@example.com", "203.0.113.7")
+	mocks.userRepo.On("FindByEmail", testifymock.Anything, "jane@example.com").Return(domain.User{}, errors.New("connection refused"))
+
+	err := service.ForgotPassword("jane@example.com", "203.0.113.7")
+
+	assert.NoError(t, err)
+	assert.NoError(t, service.Drain(context.Background()))
+	mocks.userRepo.AssertExpectations(t)
+	assert.Empty(t, mocks.mailer.Sent())
+}
+
+func TestAccountService_Drain_WaitsForMail(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	release := make(chan time.Time)
+	expectPasswordResetCounted(sqlMock, mocks, "nobody@example.com", "203.0.113.7")
+	mocks.userRepo.On("FindByEmail", testifymock.Anything, "nobody@example.com").Return(domain.User{}, gorm.ErrRecordNotFound).WaitUntil(release)
+
+	err := service.ForgotPassword("nobody@example.com", "203.0.113.7")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, service.Drain(ctx), context.DeadlineExceeded)
+
+	close(release)
+	assert.NoError(t, service.Drain(context.Background()))
+	mocks.userRepo.AssertExpectations(t)
+}
+
+func TestAccountService_ForgotPassword_Throttled(t *testing.T) {
+	service, mocks := newAccountServiceForTest(&gorm.DB{})
+
+	lockedUntil := time.Now().Add(30 * time.Second)
+	mocks.throttleRepo.On("Find", &gorm.DB{}, domain.LoginThrottleScopeResetEmail, "jane@example.com").Return(domain.LoginThrottle{Scope: domain.LoginThrottleScopeResetEmail, Key: "jane@example.com", LockedUntil: &lockedUntil}, nil)
+
+	err := service.ForgotPassword("Jane@Example.com ", "203.0.113.7")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusTooManyRequests, customErr.Code)
+	assert.Contains(t, customErr.Message, "too many password reset requests")
+	mocks.throttleRepo.AssertNotCalled(t, "Lock", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+	mocks.userRepo.AssertNotCalled(t, "FindByEmail", testifymock.Anything, testifymock.Anything)
+}
+
+func TestAccountService_ResetPassword_Success(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	token := domain.UserToken{ID: 1, UserID: 2, Purpose: domain.TokenPurposePasswordReset, Email: "jane@example.com", ExpiresAt: time.Now().Add(time.Hour)}
+	session := domain.RefreshToken{ID: 7, UserID: 2, FamilyID: "session-1", AccessTokenJTI: "jti-7", AccessExpiresAt: time.Now().Add(10 * time.Minute), ExpiresAt: time.Now().Add(time.Hour)}
+
+	var newPassword string
+	sqlMock.ExpectBegin()
+	mocks.userTokenRepo.On("LockByHash", testifymock.Anything, domain.TokenPurposePasswordReset, hashToken("reset-token")).Return(token, nil)
+	mocks.userTokenRepo.On("MarkUsed", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mocks.userRepo.On("FindById", testifymock.Anything, 2).Return(domain.User{ID: 2, Email: "jane@example.com"}, nil)
+	mocks.userRepo.On("UpdatePassword", testifymock.Anything, 2, testifymock.MatchedBy(func(password string) bool {
+		newPassword = password
+		return true
+	})).Return(nil)
+	mocks.userTokenRepo.On("InvalidateByUserId", testifymock.Anything, 2, domain.TokenPurposePasswordReset, testifymock.Anything).Return(nil)
+	sqlMock.ExpectCommit()
+	sqlMock.ExpectBegin()
+	mocks.refreshTokenRepo.On("FindLiveByUserId", testifymock.Anything, 2, testifymock.Anything).Return([]domain.RefreshToken{session}, nil)
+	mocks.refreshTokenRepo.On("Revoke", testifymock.Anything, []int{7}, testifymock.Anything).Return(nil)
+	mocks.revokedTokenRepo.On("Create", testifymock.Anything, testifymock.Anything).Return(nil)
+	sqlMock.ExpectCommit()
+
+	err := service.ResetPassword("reset-token", "new-secret")
+
+	assert.NoError(t, err)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(newPassword), []byte("new-secret")))
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mocks.refreshTokenRepo.AssertExpectations(t)
+}
+
+func TestAccountService_ResetPassword_Expired(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	token := domain.UserToken{ID: 1, UserID: 2, Purpose: domain.TokenPurposePasswordReset, Email: "jane@example.com", ExpiresAt: time.Now().Add(-time.Minute)}
+
+	sqlMock.ExpectBegin()
+	mocks.userTokenRepo.On("LockByHash", testifymock.Anything, domain.TokenPurposePasswordReset, hashToken("reset-token")).Return(token, nil)
+	sqlMock.ExpectRollback()
+
+	err := service.ResetPassword("reset-token", "new-secret")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Invalid or expired token", customErr.Message)
+	mocks.userRepo.AssertNotCalled(t, "UpdatePassword", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+	mocks.refreshTokenRepo.AssertNotCalled(t, "FindLiveByUserId", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func hashTestPassword(t *testing.T, password string) string {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	assert.NoError(t, err)
+	return string(hashed)
+}
+
+func TestAccountService_UpdateProfile_EmailChange(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	user := domain.User{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Password: hashTestPassword(t, "secret"), EmailVerifiedAt: &verifiedAt}
+
+	mocks.userRepo.On("FindById", testifymock.Anything, 2).Return(user, nil)
+	expectPasswordAccepted(mocks, "jane@example.com")
+	sqlMock.ExpectBegin()
+	mocks.userRepo.On("FindByEmail", testifymock.Anything, "jane@new.example.com").Return(domain.User{}, gorm.ErrRecordNotFound)
+	mocks.userRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(u domain.User) bool {
+		return u.Email == "jane@new.example.com" && u.EmailVerifiedAt == nil
+	})).Return(domain.User{ID: 2, Name: "Jane Doe", Email: "jane@new.example.com"}, nil)
+	sqlMock.ExpectCommit()
+	sqlMock.ExpectBegin()
+	mocks.userTokenRepo.On("InvalidateByUserId", testifymock.Anything, 2, domain.TokenPurposeEmailVerification, testifymock.Anything).Return(nil)
+	mocks.userTokenRepo.On("Create", testifymock.Anything, testifymock.Anything).Return(domain.UserToken{ID: 1}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.UpdateProfile(2, "", "jane@new.example.com", "secret")
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsEmailVerified())
+	sent := mocks.mailer.Sent()
+	assert.Len(t, sent, 2)
+	assert.Equal(t, "jane@example.com", sent[0].To)
+	assert.Equal(t, "jane@new.example.com", sent[1].To)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAccountService_UpdateProfile_EmailChangeNeedsPassword(t *testing.T) {
+	service, mocks := newAccountServiceForTest(&gorm.DB{})
+
+	mocks.userRepo.On("FindById", &gorm.DB{}, 2).Return(domain.User{ID: 2, Email: "jane@example.com", Password: hashTestPassword(t, "secret")}, nil)
+
+	_, err := service.UpdateProfile(2, "", "attacker@example.com", "")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Current password is required to change the email address", customErr.Message)
+	mocks.userRepo.AssertNotCalled(t, "Update", testifymock.Anything, testifymock.Anything)
+	assert.Empty(t, mocks.mailer.Sent())
+}
+
+func TestAccountService_UpdateProfile_EmailChangeWrongPassword(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	mocks.userRepo.On("FindById", testifymock.Anything, 2).Return(domain.User{ID: 2, Email: "jane@example.com", Password: hashTestPassword(t, "secret")}, nil)
+	mocks.throttleRepo.On("Find", testifymock.Anything, domain.LoginThrottleScopeAccount, "jane@example.com").Return(domain.LoginThrottle{}, gorm.ErrRecordNotFound)
+	sqlMock.ExpectBegin()
+	mocks.throttleRepo.On("Lock", testifymock.Anything, domain.LoginThrottleScopeAccount, "jane@example.com").Return(domain.LoginThrottle{ID: 1, Scope: domain.LoginThrottleScopeAccount, Key: "jane@example.com"}, nil)
+	mocks.throttleRepo.On("Update", testifymock.Anything, testifymock.Anything).Return(nil)
+	sqlMock.ExpectCommit()
+
+	_, err := service.UpdateProfile(2, "", "attacker@example.com", "guess")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Current password is incorrect", customErr.Message)
+	mocks.throttleRepo.AssertExpectations(t)
+	mocks.userRepo.AssertNotCalled(t, "Update", testifymock.Anything, testifymock.Anything)
+}
+
+func TestAccountService_UpdateProfile_NameNeedsNoPassword(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	mocks.userRepo.On("FindById", testifymock.Anything, 2).Return(domain.User{ID: 2, Name: "Jane Doe", Email: "jane@example.com"}, nil)
+	sqlMock.ExpectBegin()
+	mocks.userRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(u domain.User) bool {
+		return u.Name == "Jane Smith" && u.Email == "jane@example.com"
+	})).Return(domain.User{ID: 2, Name: "Jane Smith", Email: "jane@example.com"}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.UpdateProfile(2, "Jane Smith", "", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane Smith", result.Name)
+	mocks.throttleRepo.AssertNotCalled(t, "Find", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+	assert.Empty(t, mocks.mailer.Sent())
+}
+
+func TestAccountService_UpdateProfile_EmailTaken(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	mocks.userRepo.On("FindById", testifymock.Anything, 2).Return(domain.User{ID: 2, Email: "jane@example.com", Password: hashTestPassword(t, "secret")}, nil)
+	expectPasswordAccepted(mocks, "jane@example.com")
+	sqlMock.ExpectBegin()
+	mocks.userRepo.On("FindByEmail", testifymock.Anything, "john@example.com").Return(domain.User{ID: 3, Email: "john@example.com"}, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.UpdateProfile(2, "", "john@example.com", "secret")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusConflict, customErr.Code)
+	mocks.userRepo.AssertNotCalled(t, "Update", testifymock.Anything, testifymock.Anything)
+	assert.Empty(t, mocks.mailer.Sent())
+}
+
+func TestAccountService_ChangePassword_KeepsCurrentSession(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	user := domain.User{ID: 2, Email: "jane@example.com", Password: hashTestPassword(t, "old-secret")}
+	current := domain.RefreshToken{ID: 7, UserID: 2, FamilyID: "session-1", AccessTokenJTI: "jti-7", AccessExpiresAt: time.Now().Add(10 * time.Minute), ExpiresAt: time.Now().Add(time.Hour)}
+	other := domain.RefreshToken{ID: 8, UserID: 2, FamilyID: "session-2", AccessTokenJTI: "jti-8", AccessExpiresAt: time.Now().Add(10 * time.Minute), ExpiresAt: time.Now().Add(time.Hour)}
+
+	mocks.userRepo.On("FindById", testifymock.Anything, 2).Return(user, nil)
+	expectPasswordAccepted(mocks, "jane@example.com")
+	sqlMock.ExpectBegin()
+	mocks.userRepo.On("UpdatePassword", testifymock.Anything, 2, testifymock.Anything).Return(nil)
+	mocks.userTokenRepo.On("InvalidateByUserId", testifymock.Anything, 2, domain.TokenPurposePasswordReset, testifymock.Anything).Return(nil)
+	sqlMock.ExpectCommit()
+	sqlMock.ExpectBegin()
+	mocks.refreshTokenRepo.On("FindLiveByUserId", testifymock.Anything, 2, testifymock.Anything).Return([]domain.RefreshToken{current, other}, nil)
+	mocks.refreshTokenRepo.On("Revoke", testifymock.Anything, []int{8}, testifymock.Anything).Return(nil)
+	mocks.revokedTokenRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(tokens []domain.RevokedToken) bool {
+		return len(tokens) == 1 && tokens[0].JTI == "jti-8"
+	})).Return(nil)
+	sqlMock.ExpectCommit()
+
+	err := service.ChangePassword(2, "session-1", "old-secret", "new-secret")
+
+	assert.NoError(t, err)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mocks.refreshTokenRepo.AssertExpectations(t)
+}
+
+func TestAccountService_ChangePassword_WrongCurrentPassword(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	user := domain.User{ID: 2, Email: "jane@example.com", Password: hashTestPassword(t, "old-secret")}
+	mocks.userRepo.On("FindById", testifymock.Anything, 2).Return(user, nil)
+	mocks.throttleRepo.On("Find", testifymock.Anything, domain.LoginThrottleScopeAccount, "jane@example.com").Return(domain.LoginThrottle{}, gorm.ErrRecordNotFound)
+	sqlMock.ExpectBegin()
+	mocks.throttleRepo.On("Lock", testifymock.Anything, domain.LoginThrottleScopeAccount, "jane@example.com").Return(domain.LoginThrottle{ID: 1, Scope: domain.LoginThrottleScopeAccount, Key: "jane@example.com"}, nil)
+	mocks.throttleRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(throttle domain.LoginThrottle) bool {
+		return throttle.Key == "jane@example.com" && throttle.Failures == 1
+	})).Return(nil)
+	sqlMock.ExpectCommit()
+
+	err := service.ChangePassword(2, "session-1", "guess", "new-secret")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Current password is incorrect", customErr.Message)
+	mocks.throttleRepo.AssertExpectations(t)
+	mocks.userRepo.AssertNotCalled(t, "UpdatePassword", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func TestAccountService_ChangePassword_Throttled(t *testing.T) {
+	service, mocks := newAccountServiceForTest(&gorm.DB{})
+
+	user := domain.User{ID: 2, Email: "Jane@Example.com", Password: hashTestPassword(t, "old-secret")}
+	lockedUntil := time.Now().Add(30 * time.Second)
+	mocks.userRepo.On("FindById", &gorm.DB{}, 2).Return(user, nil)
+	mocks.throttleRepo.On("Find", &gorm.DB{}, domain.LoginThrottleScopeAccount, "jane@example.com").Return(domain.LoginThrottle{Scope: domain.LoginThrottleScopeAccount, Key: "jane@example.com", LockedUntil: &lockedUntil}, nil)
+
+	err := service.ChangePassword(2, "session-1", "old-secret", "new-secret")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusTooManyRequests, customErr.Code)
+	mocks.userRepo.AssertNotCalled(t, "UpdatePassword", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func TestAccountService_DeleteAccount_Success(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	user := domain.User{ID: 2, Name: "Jane Doe", Email: "Jane@Example.com", Role: domain.RoleGuest, Password: hashTestPassword(t, "secret")}
+
+	mocks.userRepo.On("FindById", testifymock.Anything, 2).Return(user, nil)
+	expectPasswordAccepted(mocks, "jane@example.com")
+	sqlMock.ExpectBegin()
+	mocks.userRepo.On("LockById", testifymock.Anything, 2).Return(user, nil)
+	mocks.bookRoomRepo.On("CountActiveByUserId", testifymock.Anything, 2).Return(int64(0), nil)
+	mocks.topupRepo.On("CountPendingByUserId", testifymock.Anything, 2).Return(int64(0), nil)
+	mocks.userRepo.On("Anonymize", testifymock.Anything, 2, "Deleted user", "deleted-user-2@deleted.invalid").Return(nil)
+	mocks.userTokenRepo.On("DeleteByUserId", testifymock.Anything, 2).Return(nil)
+	mocks.throttleRepo.On("Delete", testifymock.Anything, domain.LoginThrottleScopeAccount, "jane@example.com").Return(nil)
+	mocks.throttleRepo.On("Delete", testifymock.Anything, domain.LoginThrottleScopeResetEmail, "jane@example.com").Return(nil)
+	mocks.throttleRepo.On("Delete", testifymock.Anything, domain.LoginThrottleScopeTwoFactor, "2").Return(nil)
+	mocks.auditLogRepo.On("ReplaceInDetails", testifymock.Anything, "jane@example.com", "deleted-user-2@deleted.invalid").Return(nil)
+	mocks.userTOTPRepo.On("DeleteByUserId", testifymock.Anything, 2).Return(nil)
+	mocks.recoveryCodeRepo.On("DeleteByUserId", testifymock.Anything, 2).Return(nil)
+	mocks.userRepo.On("Delete", testifymock.Anything, 2).Return(nil)
+	sqlMock.ExpectCommit()
+	sqlMock.ExpectBegin()
+	mocks.refreshTokenRepo.On("FindLiveByUserId", testifymock.Anything, 2, testifymock.Anything).Return([]domain.RefreshToken{}, nil)
+	mocks.refreshTokenRepo.On("Revoke", testifymock.Anything, testifymock.Anything, testifymock.Anything).Return(nil)
+	mocks.revokedTokenRepo.On("Create", testifymock.Anything, testifymock.Anything).Return(nil)
+	sqlMock.ExpectCommit()
+
+	err := service.DeleteAccount(2, "secret")
+
+	assert.NoError(t, err)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mocks.userRepo.AssertExpectations(t)
+	mocks.throttleRepo.AssertExpectations(t)
+	mocks.auditLogRepo.AssertExpectations(t)
+	mocks.userTOTPRepo.AssertExpectations(t)
+	mocks.recoveryCodeRepo.AssertExpectations(t)
+}
+
+func TestAccountService_DeleteAccount_PositiveBalance(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	user := domain.User{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Role: domain.RoleGuest, Balance: domain.NewMoney(50000), Password: hashTestPassword(t, "secret")}
+
+	mocks.userRepo.On("FindById", testifymock.Anything, 2).Return(user, nil)
+	expectPasswordAccepted(mocks, "jane@example.com")
+	sqlMock.ExpectBegin()
+	mocks.userRepo.On("LockById", testifymock.Anything, 2).Return(user, nil)
+	sqlMock.ExpectRollback()
+
+	err := service.DeleteAccount(2, "secret")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusConflict, customErr.Code)
+	assert.Contains(t, customErr.Message, "paid out by an admin")
+	mocks.userRepo.AssertNotCalled(t, "Anonymize", testifymock.Anything, testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func TestAccountService_DeleteAccount_UpcomingBookings(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	user := domain.User{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Role: domain.RoleGuest, Password: hashTestPassword(t, "secret")}
+
+	mocks.userRepo.On("FindById", testifymock.Anything, 2).Return(user, nil)
+	expectPasswordAccepted(mocks, "jane@example.com")
+	sqlMock.ExpectBegin()
+	mocks.userRepo.On("LockById", testifymock.Anything, 2).Return(user, nil)
+	mocks.bookRoomRepo.On("CountActiveByUserId", testifymock.Anything, 2).Return(int64(1), nil)
+	sqlMock.ExpectRollback()
+
+	err := service.DeleteAccount(2, "secret")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Cannot delete an account with upcoming bookings", customErr.Message)
+	mocks.userRepo.AssertNotCalled(t, "Delete", testifymock.Anything, testifymock.Anything)
+}
+
+func TestAccountService_DeleteAccount_PendingTopup(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newAccountServiceForTest(db)
+
+	user := domain.User{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Role: domain.RoleGuest, Password: hashTestPassword(t, "secret")}
+
+	mocks.userRepo.On("FindById", testifymock.Anything, 2).Return(user, nil)
+	expectPasswordAccepted(mocks, "jane@example.com")
+	sqlMock.ExpectBegin()
+	mocks.userRepo.On("LockById", testifymock.Anything, 2).Return(user, nil)
+	mocks.bookRoomRepo.On("CountActiveByUserId", testifymock.Anything, 2).Return(int64(0), nil)
+	mocks.topupRepo.On("CountPendingByUserId", testifymock.Anything, 2).Return(int64(1), nil)
+	sqlMock.ExpectRollback()
+
+	err := service.DeleteAccount(2, "secret")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Cannot delete an account while a topup payment is pending", customErr.Message)
+	mocks.userRepo.AssertNotCalled(t, "Anonymize", testifymock.Anything, testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// These tests run BookRoomService against a real Postgres. Set
+// TEST_DATABASE_URL to a postgres:// URL to enable them; each test builds the
+// schema from migrations/complete_ddl.sql in a throwaway Postgres schema.
+
+func setupConcurrencyDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	config := &gorm.Config{TranslateError: true, Logger: logger.Discard}
+	admin, err := gorm.Open(postgres.Open(dsn), config)
+	require.NoError(t, err)
+
+	schema := fmt.Sprintf("booking_concurrency_%d", time.Now().UnixNano())
+	require.NoError(t, admin.Exec("CREATE SCHEMA "+schema).Error)
+
+	scoped, err := url.Parse(dsn)
+	require.NoError(t, err)
+	query := scoped.Query()
+	query.Set("search_path", schema)
+	scoped.RawQuery = query.Encode()
+
+	db, err := gorm.Open(postgres.Open(scoped.String()), config)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	ddl, err := os.ReadFile("../migrations/complete_ddl.sql")
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(string(ddl)).Error)
+
+	return db
+}
+
+func newConcurrencyBookRoomService(db *gorm.DB) BookRoomService {
+	return NewBookRoomService(
+		repository.NewBookRoomRepository(),
+		repository.NewRoomRepository(),
+		repository.NewMaintenanceBlockRepository(),
+		repository.NewRatePlanRepository(),
+		repository.NewPromoCodeRepository(),
+		repository.NewFeeRuleRepository(),
+		repository.NewUserRepository(),
+		repository.NewWalletTransactionRepository(),
+		testRefundPolicy,
+		testQuoteSigner,
+		db,
+	)
+}
+
+func seedRooms(t *testing.T, db *gorm.DB, count int, price domain.Money) []domain.Room {
+	roomType, err := repository.NewRoomTypeRepository().Create(db, domain.RoomType{Name: "Deluxe", Price: price})
+	require.NoError(t, err)
+
+	var rooms []domain.Room
+	for i := 0; i < count; i++ {
+		room, err := repository.NewRoomRepository().Create(db, domain.Room{RoomTypeID: roomType.ID, RoomNumber: fmt.Sprintf("%d", 101+i)})
+		require.NoError(t, err)
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
+func seedUser(t *testing.T, db *gorm.DB, email string, balance domain.Money) domain.User {
+	user, err := repository.NewUserRepository().Register(db, domain.User{Name: email, Email: email, Password: "x", Role: domain.RoleGuest, EmailVerifiedAt: &verifiedAt})
+	require.NoError(t, err)
+
+	_, err = repository.NewWalletTransactionRepository().Create(db, domain.WalletTransaction{
+		UserID:        user.ID,
+		Type:          domain.WalletTransactionAdjustment,
+		Amount:        balance,
+		ContraAccount: domain.LedgerAccountAdjustments,
+		Description:   "Opening balance",
+	})
+	require.NoError(t, err)
+	return user
+}
+
+func TestBookRoomService_Concurrent_SameRoomOverlappingStays(t *testing.T) {
+	db := setupConcurrencyDB(t)
+	service := newConcurrencyBookRoomService(db)
+
+	room := seedRooms(t, db, 1, domain.NewMoney(500000))[0]
+	base := startOfDay(time.Now().AddDate(0, 0, 7))
+
+	const guests = 20
+	var users []domain.User
+	for i := 0; i < guests; i++ {
+		users = append(users, seedUser(t, db, fmt.Sprintf("guest%d@example.com", i), domain.NewMoney(5000000)))
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, guests)
+	for i := 0; i < guests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Every stay covers base+1, half of them starting a night earlier.
+			checkIn := base.AddDate(0, 0, i%2)
+			_, errs[i] = service.Create(domain.BookRoom{
+				RoomID:   room.ID,
+				UserID:   users[i].ID,
+				CheckIn:  checkIn,
+				CheckOut: checkIn.AddDate(0, 0, 2),
+			}, domain.BookingOptions{})
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		var customErr *exception.CustomError
+		require.True(t, errors.As(err, &customErr), "unexpected error: %v", err)
+		assert.Equal(t, http.StatusConflict, customErr.Code)
+	}
+	assert.Equal(t, 1, succeeded)
+
+	var nights int64
+	require.NoError(t, db.Model(&domain.BookRoomNight{}).Where("room_id = ?", room.ID).Count(&nights).Error)
+	assert.Equal(t, int64(2), nights)
+}
+
+func TestBookRoomService_Concurrent_SameUserBalance(t *testing.T) {
+	db := setupConcurrencyDB(t)
+	service := newConcurrencyBookRoomService(db)
+
+	const attempts = 10
+	rooms := seedRooms(t, db, attempts, domain.NewMoney(500000))
+	user := seedUser(t, db, "guest@example.com", domain.NewMoney(1500000))
+	checkIn := startOfDay(time.Now().AddDate(0, 0, 7))
+
+	var wg sync.WaitGroup
+	errs := make([]error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = service.Create(domain.BookRoom{
+				RoomID:   rooms[i].ID,
+				UserID:   user.ID,
+				CheckIn:  checkIn,
+				CheckOut: checkIn.AddDate(0, 0, 1),
+			}, domain.BookingOptions{})
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		var customErr *exception.CustomError
+		require.True(t, errors.As(err, &customErr), "unexpected error: %v", err)
+		assert.Equal(t, "Insufficient balance", customErr.Message)
+	}
+	assert.Equal(t, 3, succeeded)
+
+	stored, err := repository.NewUserRepository().FindById(db, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Money(0), stored.Balance)
+
+	unreconciled, err := repository.NewWalletTransactionRepository().FindUnreconciled(db)
+	require.NoError(t, err)
+	assert.Empty(t, unreconciled)
+}
@@ -1,76 +1,478 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"hotel_ip-p2/exception"
+	"hotel_ip-p2/helper"
 	"hotel_ip-p2/model/domain"
 	"hotel_ip-p2/repository"
+	"log"
 	"net/http"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type BookRoomService interface {
-	Create(bookRoom domain.BookRoom) (domain.BookRoom, error)
-	FindByUserId(userId int) ([]domain.BookRoom, error)
+	Create(bookRoom domain.BookRoom, options domain.BookingOptions) (domain.BookRoom, error)
+	Quote(bookRoom domain.BookRoom, options domain.BookingOptions) (domain.BookingQuote, error)
+	FindByUserId(userId int, query domain.CursorQuery) ([]domain.BookRoom, *domain.Cursor, error)
+	Cancel(id int, userId int) (domain.BookRoom, error)
+	CheckIn(id int, staffId int) (domain.BookRoom, error)
+	CheckOut(id int, staffId int) (domain.BookRoom, error)
+	MarkNoShow(id int, staffId int) (domain.BookRoom, error)
+	SweepNoShows(ctx context.Context) (int, error)
 }
 
 type BookRoomServiceImpl struct {
-	BookRoomRepository repository.BookRoomRepository
-	RoomRepository     repository.RoomRepository
-	UserRepository     repository.UserRepository
-	DB                 *gorm.DB
+	BookRoomRepository          repository.BookRoomRepository
+	RoomRepository              repository.RoomRepository
+	MaintenanceBlockRepository  repository.MaintenanceBlockRepository
+	RatePlanRepository          repository.RatePlanRepository
+	PromoCodeRepository         repository.PromoCodeRepository
+	FeeRuleRepository           repository.FeeRuleRepository
+	UserRepository              repository.UserRepository
+	WalletTransactionRepository repository.WalletTransactionRepository
+	RefundPolicy                domain.RefundPolicy
+	QuoteTokenSigner            helper.QuoteTokenSigner
+	DB                          *gorm.DB
 }
 
-func NewBookRoomService(bookRoomRepository repository.BookRoomRepository, roomRepository repository.RoomRepository, userRepository repository.UserRepository, db *gorm.DB) BookRoomService {
+func NewBookRoomService(bookRoomRepository repository.BookRoomRepository, roomRepository repository.RoomRepository, maintenanceBlockRepository repository.MaintenanceBlockRepository, ratePlanRepository repository.RatePlanRepository, promoCodeRepository repository.PromoCodeRepository, feeRuleRepository repository.FeeRuleRepository, userRepository repository.UserRepository, walletTransactionRepository repository.WalletTransactionRepository, refundPolicy domain.RefundPolicy, quoteTokenSigner helper.QuoteTokenSigner, db *gorm.DB) BookRoomService {
 	return &BookRoomServiceImpl{
-		BookRoomRepository: bookRoomRepository,
-		RoomRepository:     roomRepository,
-		UserRepository:     userRepository,
-		DB:                 db,
+		BookRoomRepository:          bookRoomRepository,
+		RoomRepository:              roomRepository,
+		MaintenanceBlockRepository:  maintenanceBlockRepository,
+		RatePlanRepository:          ratePlanRepository,
+		PromoCodeRepository:         promoCodeRepository,
+		FeeRuleRepository:           feeRuleRepository,
+		UserRepository:              userRepository,
+		WalletTransactionRepository: walletTransactionRepository,
+		RefundPolicy:                refundPolicy,
+		QuoteTokenSigner:            quoteTokenSigner,
+		DB:                          db,
 	}
 }
 
-func (s *BookRoomServiceImpl) Create(bookRoom domain.BookRoom) (domain.BookRoom, error) {
+func (s *BookRoomServiceImpl) Create(bookRoom domain.BookRoom, options domain.BookingOptions) (domain.BookRoom, error) {
+	promoCode := domain.NormalizePromoCode(options.PromoCode)
+
+	var pinnedQuote *domain.BookingQuote
+	if options.QuoteToken != "" {
+		quote, err := s.verifyQuote(bookRoom, promoCode, options.QuoteToken)
+		if err != nil {
+			return domain.BookRoom{}, err
+		}
+		pinnedQuote = &quote
+		promoCode = quote.PromoCode
+	}
+
 	var result domain.BookRoom
 
 	err := s.DB.Transaction(func(tx *gorm.DB) error {
-		room, err := s.RoomRepository.FindById(tx, bookRoom.RoomID)
+		room, user, nightDates, err := s.findStay(tx, bookRoom, true)
 		if err != nil {
-			if err == gorm.ErrRecordNotFound {
-				return exception.NewCustomError(http.StatusNotFound, "Room not found")
+			return err
+		}
+		if !user.IsEmailVerified() {
+			return exception.NewCustomError(http.StatusForbidden, "Verify your email address before booking")
+		}
+
+		// Concurrent bookings of the same nights wait here, so the check
+		// below sees any booking that committed first.
+		err = s.BookRoomRepository.LockNights(tx, bookRoom.RoomID, nightDates)
+		if err != nil {
+			return err
+		}
+
+		err = s.checkNightsFree(tx, bookRoom)
+		if err != nil {
+			return err
+		}
+
+		ratePlans, err := s.RatePlanRepository.FindByRoomTypeIdAndDateRange(tx, room.RoomTypeID, bookRoom.CheckIn, bookRoom.CheckOut)
+		if err != nil {
+			return err
+		}
+
+		quote := domain.BookingQuote{LineItems: priceStay(room, nightDates, ratePlans)}
+
+		// Holding the promo code's row lock until commit makes concurrent
+		// bookings with the same code count its redemptions one at a time.
+		var promo domain.PromoCode
+		if promoCode != "" {
+			promo, err = s.PromoCodeRepository.LockByCode(tx, promoCode)
+			if err != nil {
+				return promoCodeNotFound(err)
+			}
+			err = s.checkPromoCode(tx, promo, user.ID, room.RoomTypeID, len(nightDates))
+			if err != nil {
+				return err
+			}
+			quote.LineItems = append(quote.LineItems, promoLineItem(promo, quote.Subtotal()))
+		}
+
+		if pinnedQuote != nil {
+			quote.LineItems = withKnownRatePlans(pinnedQuote.LineItems, ratePlans)
+		} else {
+			quote.LineItems, err = s.chargeFees(tx, quote.LineItems, len(nightDates))
+			if err != nil {
+				return err
+			}
+		}
+
+		bookRoom.Status = domain.BookRoomStatusConfirmed
+		bookRoom.Nights = nil
+		for _, item := range quote.LineItems {
+			if item.Type != domain.LineItemRoomNight {
+				continue
+			}
+			bookRoom.Nights = append(bookRoom.Nights, domain.BookRoomNight{
+				RoomID:     bookRoom.RoomID,
+				Date:       *item.Date,
+				Price:      item.Amount,
+				RatePlanID: item.RatePlanID,
+			})
+		}
+		bookRoom.LineItems = nil
+		for _, item := range quote.LineItems {
+			bookRoom.LineItems = append(bookRoom.LineItems, domain.BookRoomLineItem{LineItem: item})
+		}
+		bookRoom.Price = quote.Total()
+
+		if user.Balance < bookRoom.Price {
+			return exception.NewCustomError(http.StatusBadRequest, "Insufficient balance")
+		}
+
+		result, err = s.BookRoomRepository.Create(tx, bookRoom)
+		if err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return exception.NewCustomError(http.StatusConflict, "Room is already booked for the selected dates")
 			}
 			return err
 		}
 
-		user, err := s.UserRepository.FindById(tx, bookRoom.UserID)
+		if promo.ID != 0 {
+			_, err = s.PromoCodeRepository.CreateRedemption(tx, domain.PromoRedemption{
+				PromoCodeID: promo.ID,
+				UserID:      user.ID,
+				BookRoomID:  result.ID,
+				Discount:    quote.Discount(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		// A promo code can make a stay free, leaving nothing to debit.
+		if result.Price == 0 {
+			return nil
+		}
+
+		// The balance read above may be stale; the debit re-checks it
+		// atomically against concurrent bookings by the same user.
+		_, err = s.WalletTransactionRepository.Debit(tx, domain.WalletTransaction{
+			UserID:        user.ID,
+			Type:          domain.WalletTransactionBookingDebit,
+			Amount:        -result.Price,
+			ContraAccount: domain.LedgerAccountRoomRevenue,
+			ReferenceType: domain.ReferenceTypeBookRoom,
+			ReferenceID:   &result.ID,
+			Description:   fmt.Sprintf("Booking #%d for room %s", result.ID, room.RoomNumber),
+		})
 		if err != nil {
-			if err == gorm.ErrRecordNotFound {
-				return exception.NewCustomError(http.StatusNotFound, "User not found")
+			if errors.Is(err, repository.ErrInsufficientBalance) {
+				return exception.NewCustomError(http.StatusBadRequest, "Insufficient balance")
 			}
 			return err
 		}
 
-		existingBooking, err := s.BookRoomRepository.FindByRoomIdAndDate(tx, bookRoom.RoomID, bookRoom.Date)
-		if err != nil && err != gorm.ErrRecordNotFound {
+		return nil
+	})
+
+	return result, err
+}
+
+// Quote prices a stay the way Create would without booking it, and signs the
+// result so Create can honour the price until the quote expires.
+func (s *BookRoomServiceImpl) Quote(bookRoom domain.BookRoom, options domain.BookingOptions) (domain.BookingQuote, error) {
+	room, user, nightDates, err := s.findStay(s.DB, bookRoom, false)
+	if err != nil {
+		return domain.BookingQuote{}, err
+	}
+
+	err = s.checkNightsFree(s.DB, bookRoom)
+	if err != nil {
+		return domain.BookingQuote{}, err
+	}
+
+	ratePlans, err := s.RatePlanRepository.FindByRoomTypeIdAndDateRange(s.DB, room.RoomTypeID, bookRoom.CheckIn, bookRoom.CheckOut)
+	if err != nil {
+		return domain.BookingQuote{}, err
+	}
+
+	quote := domain.BookingQuote{
+		UserID:    bookRoom.UserID,
+		RoomID:    bookRoom.RoomID,
+		CheckIn:   bookRoom.CheckIn,
+		CheckOut:  bookRoom.CheckOut,
+		LineItems: priceStay(room, nightDates, ratePlans),
+		Balance:   user.Balance,
+	}
+
+	if code := domain.NormalizePromoCode(options.PromoCode); code != "" {
+		promo, err := s.PromoCodeRepository.FindByCode(s.DB, code)
+		if err != nil {
+			return domain.BookingQuote{}, promoCodeNotFound(err)
+		}
+		err = s.checkPromoCode(s.DB, promo, user.ID, room.RoomTypeID, len(nightDates))
+		if err != nil {
+			return domain.BookingQuote{}, err
+		}
+		quote.PromoCode = promo.Code
+		quote.LineItems = append(quote.LineItems, promoLineItem(promo, quote.Subtotal()))
+	}
+
+	quote.LineItems, err = s.chargeFees(s.DB, quote.LineItems, len(nightDates))
+	if err != nil {
+		return domain.BookingQuote{}, err
+	}
+
+	quote.Token, quote.ExpiresAt, err = s.QuoteTokenSigner.Sign(quote)
+	if err != nil {
+		return domain.BookingQuote{}, err
+	}
+
+	return quote, nil
+}
+
+// findStay loads the room and guest of a booking and the nights it covers.
+// With lock set the room and user rows stay locked until the transaction
+// ends, so neither can be deleted while the stay is being booked. A deleted
+// user is not found.
+func (s *BookRoomServiceImpl) findStay(db *gorm.DB, bookRoom domain.BookRoom, lock bool) (domain.Room, domain.User, []time.Time, error) {
+	findRoom, findUser := s.RoomRepository.FindById, s.UserRepository.FindById
+	if lock {
+		findRoom, findUser = s.RoomRepository.LockById, s.UserRepository.LockById
+	}
+
+	room, err := findRoom(db, bookRoom.RoomID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return room, domain.User{}, nil, exception.NewCustomError(http.StatusNotFound, "Room not found")
+		}
+		return room, domain.User{}, nil, err
+	}
+
+	user, err := findUser(db, bookRoom.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return room, user, nil, exception.NewCustomError(http.StatusNotFound, "User not found")
+		}
+		return room, user, nil, err
+	}
+
+	nightDates := bookRoom.NightDates()
+	if len(nightDates) == 0 {
+		return room, user, nil, exception.NewCustomError(http.StatusBadRequest, "Check-out date must be after check-in date")
+	}
+
+	now := time.Now()
+	if stayDay(bookRoom.CheckIn, now).Before(stayDay(now, now)) {
+		return room, user, nil, exception.NewCustomError(http.StatusBadRequest, "Check-in date must be today or in the future")
+	}
+
+	return room, user, nightDates, nil
+}
+
+func (s *BookRoomServiceImpl) checkNightsFree(db *gorm.DB, bookRoom domain.BookRoom) error {
+	bookedNights, err := s.BookRoomRepository.FindNightsByRoomIdAndDateRange(db, bookRoom.RoomID, bookRoom.CheckIn, bookRoom.CheckOut)
+	if err != nil {
+		return err
+	}
+	if len(bookedNights) > 0 {
+		return exception.NewCustomError(http.StatusConflict, fmt.Sprintf("Room is already booked for %s", bookedNights[0].Date.Format("2006-01-02")))
+	}
+	return checkOutOfService(db, s.MaintenanceBlockRepository, bookRoom.RoomID, bookRoom.CheckIn, bookRoom.CheckOut)
+}
+
+// verifyQuote checks that a quote token is genuine, unexpired and issued for
+// exactly this booking. promoCode may be left empty to use the quote's code.
+func (s *BookRoomServiceImpl) verifyQuote(bookRoom domain.BookRoom, promoCode string, token string) (domain.BookingQuote, error) {
+	quote, err := s.QuoteTokenSigner.Verify(token)
+	if err != nil {
+		return quote, exception.NewCustomError(http.StatusBadRequest, "Quote is invalid or has expired")
+	}
+
+	if quote.UserID != bookRoom.UserID || quote.RoomID != bookRoom.RoomID ||
+		quote.CheckIn.Format("2006-01-02") != bookRoom.CheckIn.Format("2006-01-02") ||
+		quote.CheckOut.Format("2006-01-02") != bookRoom.CheckOut.Format("2006-01-02") ||
+		(promoCode != "" && promoCode != quote.PromoCode) {
+		return quote, exception.NewCustomError(http.StatusBadRequest, "Quote does not match this booking")
+	}
+
+	return quote, nil
+}
+
+// checkPromoCode makes sure a promo code can be used now by userId for a
+// stay of the given number of nights in roomTypeId.
+func (s *BookRoomServiceImpl) checkPromoCode(db *gorm.DB, promo domain.PromoCode, userId int, roomTypeId int, nights int) error {
+	now := time.Now()
+	if !promo.Active {
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid promo code")
+	}
+	if promo.ValidFrom != nil && now.Before(*promo.ValidFrom) {
+		return exception.NewCustomError(http.StatusBadRequest, "Promo code is not valid yet")
+	}
+	if promo.ValidUntil != nil && now.After(*promo.ValidUntil) {
+		return exception.NewCustomError(http.StatusBadRequest, "Promo code has expired")
+	}
+	if nights < promo.MinNights {
+		return exception.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Promo code requires a stay of at least %d nights", promo.MinNights))
+	}
+	if !promo.AppliesToRoomType(roomTypeId) {
+		return exception.NewCustomError(http.StatusBadRequest, "Promo code does not apply to this room type")
+	}
+
+	if promo.MaxRedemptions > 0 {
+		redemptions, err := s.PromoCodeRepository.CountActiveRedemptions(db, promo.ID, 0)
+		if err != nil {
 			return err
 		}
-		if existingBooking.ID != 0 {
-			return exception.NewCustomError(http.StatusBadRequest, "Room is already booked for this date")
+		if redemptions >= int64(promo.MaxRedemptions) {
+			return exception.NewCustomError(http.StatusBadRequest, "Promo code has been fully redeemed")
 		}
+	}
 
-		if user.Balance < room.RoomType.Price {
-			return exception.NewCustomError(http.StatusBadRequest, "Insufficient balance")
+	if promo.MaxRedemptionsPerUser > 0 {
+		redemptions, err := s.PromoCodeRepository.CountActiveRedemptions(db, promo.ID, userId)
+		if err != nil {
+			return err
 		}
+		if redemptions >= int64(promo.MaxRedemptionsPerUser) {
+			return exception.NewCustomError(http.StatusBadRequest, "You have already used this promo code the maximum number of times")
+		}
+	}
 
-		bookRoom.Price = room.RoomType.Price
+	return nil
+}
 
-		user.Balance = user.Balance - room.RoomType.Price
-		_, err = s.UserRepository.Update(tx, user)
+func promoCodeNotFound(err error) error {
+	if err == gorm.ErrRecordNotFound {
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid promo code")
+	}
+	return err
+}
+
+func promoLineItem(promo domain.PromoCode, subtotal domain.Money) domain.LineItem {
+	return domain.LineItem{
+		Type:        domain.LineItemDiscount,
+		Description: fmt.Sprintf("Promo code %s", promo.Code),
+		Amount:      -promo.Discount(subtotal),
+	}
+}
+
+// chargeFees adds the active taxes and service charges to the line items
+// of a stay, charging them on the room nights after discounts.
+func (s *BookRoomServiceImpl) chargeFees(db *gorm.DB, items domain.LineItems, nights int) (domain.LineItems, error) {
+	feeRules, err := s.FeeRuleRepository.FindActive(db)
+	if err != nil {
+		return items, err
+	}
+	return append(items, domain.FeeLineItems(feeRules, items.Subtotal()-items.Discount(), nights)...), nil
+}
+
+// priceStay returns a line item for every night of a stay in room.
+func priceStay(room domain.Room, nightDates []time.Time, ratePlans []domain.RatePlan) domain.LineItems {
+	var items domain.LineItems
+	for _, night := range domain.PriceNights(room.RoomType.Price, ratePlans, nightDates) {
+		item := domain.LineItem{
+			Type:        domain.LineItemRoomNight,
+			Description: fmt.Sprintf("Room %s, night of %s", room.RoomNumber, night.Date.Format("2006-01-02")),
+			Date:        &night.Date,
+			Amount:      night.Price,
+		}
+		if night.RatePlan != nil {
+			item.Description += fmt.Sprintf(" (%s)", night.RatePlan.Name)
+			item.RatePlanID = &night.RatePlan.ID
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// withKnownRatePlans drops references to rate plans deleted since a quote
+// was issued; the quoted amounts still stand.
+func withKnownRatePlans(items domain.LineItems, ratePlans []domain.RatePlan) domain.LineItems {
+	known := make(map[int]bool)
+	for _, ratePlan := range ratePlans {
+		known[ratePlan.ID] = true
+	}
+
+	result := make(domain.LineItems, len(items))
+	for i, item := range items {
+		if item.RatePlanID != nil && !known[*item.RatePlanID] {
+			item.RatePlanID = nil
+		}
+		result[i] = item
+	}
+	return result
+}
+
+func (s *BookRoomServiceImpl) FindByUserId(userId int, query domain.CursorQuery) ([]domain.BookRoom, *domain.Cursor, error) {
+	bookRooms, next, err := s.BookRoomRepository.FindByUserId(s.DB, userId, query)
+	if errors.Is(err, repository.ErrInvalidListQuery) {
+		return nil, nil, exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+	return bookRooms, next, err
+}
+
+func (s *BookRoomServiceImpl) Cancel(id int, userId int) (domain.BookRoom, error) {
+	var result domain.BookRoom
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		bookRoom, err := s.BookRoomRepository.LockById(tx, id)
 		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exception.NewCustomError(http.StatusNotFound, "Booking not found")
+			}
 			return err
 		}
 
-		result, err = s.BookRoomRepository.Create(tx, bookRoom)
+		if bookRoom.UserID != userId {
+			return exception.NewCustomError(http.StatusNotFound, "Booking not found")
+		}
+
+		if bookRoom.Status == domain.BookRoomStatusCancelled {
+			return exception.NewCustomError(http.StatusBadRequest, "Booking is already cancelled")
+		}
+
+		now := time.Now()
+		if stayDay(bookRoom.CheckIn, now).Before(stayDay(now, now)) {
+			return exception.NewCustomError(http.StatusBadRequest, "Cannot cancel a stay that has already started")
+		}
+
+		err = s.changeStatus(tx, &bookRoom, domain.BookRoomStatusCancelled, &userId)
+		if err != nil {
+			return err
+		}
+
+		refundAmount := s.RefundPolicy.RefundAmount(bookRoom.Price, bookRoom.CheckIn, now)
+		err = s.refund(tx, &bookRoom, refundAmount, fmt.Sprintf("Refund for cancelled booking #%d", bookRoom.ID))
+		if err != nil {
+			return err
+		}
+
+		err = s.BookRoomRepository.DeleteNightsByBookRoomId(tx, bookRoom.ID)
+		if err != nil {
+			return err
+		}
+
+		bookRoom.CancelledAt = &now
+
+		result, err = s.BookRoomRepository.Update(tx, bookRoom)
 		if err != nil {
 			return err
 		}
@@ -81,6 +483,160 @@ func (s *BookRoomServiceImpl) Create(bookRoom domain.BookRoom) (domain.BookRoom,
 	return result, err
 }
 
-func (s *BookRoomServiceImpl) FindByUserId(userId int) ([]domain.BookRoom, error) {
-	return s.BookRoomRepository.FindByUserId(s.DB, userId)
+func (s *BookRoomServiceImpl) CheckIn(id int, staffId int) (domain.BookRoom, error) {
+	return s.transition(id, &staffId, domain.BookRoomStatusCheckedIn, func(tx *gorm.DB, bookRoom *domain.BookRoom, now time.Time) error {
+		today := stayDay(now, now)
+		if today.Before(stayDay(bookRoom.CheckIn, now)) {
+			return exception.NewCustomError(http.StatusBadRequest, "Guests cannot check in before the arrival date")
+		}
+		if !today.Before(stayDay(bookRoom.CheckOut, now)) {
+			return exception.NewCustomError(http.StatusBadRequest, "Cannot check in after the stay has ended")
+		}
+
+		bookRoom.CheckedInAt = &now
+		return nil
+	})
+}
+
+// CheckOut closes a stay. A guest who leaves early no longer holds the
+// nights from the departure day on, so they are released for new bookings.
+func (s *BookRoomServiceImpl) CheckOut(id int, staffId int) (domain.BookRoom, error) {
+	return s.transition(id, &staffId, domain.BookRoomStatusCheckedOut, func(tx *gorm.DB, bookRoom *domain.BookRoom, now time.Time) error {
+		bookRoom.CheckedOutAt = &now
+		return s.BookRoomRepository.DeleteNightsFromDate(tx, bookRoom.ID, stayDay(now, now))
+	})
+}
+
+func (s *BookRoomServiceImpl) MarkNoShow(id int, staffId int) (domain.BookRoom, error) {
+	return s.markNoShow(id, &staffId)
+}
+
+// SweepNoShows marks every confirmed booking whose arrival date has passed as
+// a no-show. Bookings that fail are logged and left for the next sweep, and
+// running the sweep twice is harmless because each booking is re-checked
+// under its row lock.
+func (s *BookRoomServiceImpl) SweepNoShows(ctx context.Context) (int, error) {
+	now := time.Now()
+	ids, err := s.BookRoomRepository.FindIdsAwaitingArrival(s.DB, stayDay(now, now))
+	if err != nil {
+		return 0, err
+	}
+
+	marked := 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return marked, err
+		}
+
+		bookRoom, err := s.markNoShow(id, nil)
+		if err != nil {
+			log.Printf("Failed to mark booking ID: %d as no-show: %v", id, err)
+			continue
+		}
+
+		log.Printf("Marked booking ID: %d as no-show, refunded: %s", bookRoom.ID, bookRoom.RefundAmount)
+		marked++
+	}
+
+	return marked, nil
+}
+
+// markNoShow closes a booking whose guest never arrived. The guest gets the
+// no-show share of the price back and the nights are released so the room
+// can be sold again.
+func (s *BookRoomServiceImpl) markNoShow(id int, changedBy *int) (domain.BookRoom, error) {
+	return s.transition(id, changedBy, domain.BookRoomStatusNoShow, func(tx *gorm.DB, bookRoom *domain.BookRoom, now time.Time) error {
+		if stayDay(now, now).Before(stayDay(bookRoom.CheckIn, now)) {
+			return exception.NewCustomError(http.StatusBadRequest, "Cannot mark a booking as no-show before the arrival date")
+		}
+
+		refundAmount := s.RefundPolicy.NoShowRefundAmount(bookRoom.Price)
+		err := s.refund(tx, bookRoom, refundAmount, fmt.Sprintf("Refund for no-show booking #%d", bookRoom.ID))
+		if err != nil {
+			return err
+		}
+
+		return s.BookRoomRepository.DeleteNightsByBookRoomId(tx, bookRoom.ID)
+	})
+}
+
+// refund credits amount of a booking back to its guest's wallet and records
+// it on the booking.
+func (s *BookRoomServiceImpl) refund(tx *gorm.DB, bookRoom *domain.BookRoom, amount domain.Money, description string) error {
+	if amount > 0 {
+		_, err := s.WalletTransactionRepository.Create(tx, domain.WalletTransaction{
+			UserID:        bookRoom.UserID,
+			Type:          domain.WalletTransactionRefundCredit,
+			Amount:        amount,
+			ContraAccount: domain.LedgerAccountRoomRevenue,
+			ReferenceType: domain.ReferenceTypeBookRoom,
+			ReferenceID:   &bookRoom.ID,
+			Description:   description,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	bookRoom.RefundAmount = amount
+	return nil
+}
+
+// transition moves a booking to status on behalf of changedBy, or of the
+// no-show job when nil. apply checks the timing of the change and stamps the
+// booking before it is saved.
+func (s *BookRoomServiceImpl) transition(id int, changedBy *int, status string, apply func(tx *gorm.DB, bookRoom *domain.BookRoom, now time.Time) error) (domain.BookRoom, error) {
+	var result domain.BookRoom
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		bookRoom, err := s.BookRoomRepository.LockById(tx, id)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exception.NewCustomError(http.StatusNotFound, "Booking not found")
+			}
+			return err
+		}
+
+		err = s.changeStatus(tx, &bookRoom, status, changedBy)
+		if err != nil {
+			return err
+		}
+
+		err = apply(tx, &bookRoom, time.Now())
+		if err != nil {
+			return err
+		}
+
+		result, err = s.BookRoomRepository.Update(tx, bookRoom)
+		return err
+	})
+
+	return result, err
+}
+
+// changeStatus sets the booking's new status and records who changed it,
+// rejecting moves the booking status machine does not allow.
+func (s *BookRoomServiceImpl) changeStatus(tx *gorm.DB, bookRoom *domain.BookRoom, status string, changedBy *int) error {
+	if !domain.CanTransitionBookRoom(bookRoom.Status, status) {
+		return exception.NewCustomError(http.StatusConflict, fmt.Sprintf("Cannot change booking status from %s to %s", bookRoom.Status, status))
+	}
+
+	_, err := s.BookRoomRepository.CreateStatusHistory(tx, domain.BookRoomStatusHistory{
+		BookRoomID: bookRoom.ID,
+		FromStatus: bookRoom.Status,
+		ToStatus:   status,
+		ChangedBy:  changedBy,
+	})
+	if err != nil {
+		return err
+	}
+
+	bookRoom.Status = status
+	return nil
+}
+
+// stayDay returns the calendar day of t in now's location, the unit check-in
+// and check-out dates are kept in.
+func stayDay(t time.Time, now time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location())
 }
@@ -1,20 +1,30 @@
 package service
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"hotel_ip-p2/exception"
+	"hotel_ip-p2/helper"
 	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository"
 	"hotel_ip-p2/repository/mock"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	testifymock "github.com/stretchr/testify/mock"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// verifiedAt marks test users as having verified their email address, which
+// booking and topping up require.
+var verifiedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
 func setupMockDB() (*gorm.DB, sqlmock.Sqlmock, error) {
 	var (
 		db  *sql.DB
@@ -32,19 +42,32 @@ func setupMockDB() (*gorm.DB, sqlmock.Sqlmock, error) {
 	return gormDB, mock, err
 }
 
+var testRefundPolicy = domain.RefundPolicy{
+	FullRefundHours:      48,
+	PartialRefundPercent: domain.NewPercent(50),
+}
+
+var testQuoteSigner = helper.NewQuoteTokenSigner("test-secret", 15*time.Minute)
+
 func TestBookRoomService_Create_Success(t *testing.T) {
 	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
 	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
 
 	db, sqlMock, _ := setupMockDB()
-	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, mockUserRepo, db)
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
 
-	bookingDate := time.Now().AddDate(0, 0, 1)
+	checkIn := time.Now().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+	checkOut := checkIn.AddDate(0, 0, 1)
 	bookRoom := domain.BookRoom{
-		RoomID: 1,
-		UserID: 1,
-		Date:   bookingDate,
+		RoomID:   1,
+		UserID:   1,
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
 	}
 
 	room := domain.Room{
@@ -54,70 +77,266 @@ func TestBookRoomService_Create_Success(t *testing.T) {
 		RoomType: domain.RoomType{
 			ID:    1,
 			Name:  "Deluxe",
-			Price: 500000,
+			Price: domain.NewMoney(500000),
 		},
 	}
 
 	user := domain.User{
-		ID:      1,
-		Name:    "John Doe",
-		Email:   "john@example.com",
-		Balance: 600000,
-	}
-
-	updatedUser := domain.User{
-		ID:      1,
-		Name:    "John Doe",
-		Email:   "john@example.com",
-		Balance: 100000,
+		ID:              1,
+		Name:            "John Doe",
+		Email:           "john@example.com",
+		Balance:         domain.NewMoney(600000),
+		EmailVerifiedAt: &verifiedAt,
 	}
 
 	expectedBooking := domain.BookRoom{
-		ID:     1,
-		RoomID: 1,
-		UserID: 1,
-		Date:   bookingDate,
-		Price:  500000,
+		ID:       1,
+		RoomID:   1,
+		UserID:   1,
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
+		Price:    domain.NewMoney(500000),
 	}
 
 	// Mock transaction
 	sqlMock.ExpectBegin()
-	mockRoomRepo.On("FindById", testifymock.Anything, 1).Return(room, nil)
-	mockUserRepo.On("FindById", testifymock.Anything, 1).Return(user, nil)
-	mockBookRoomRepo.On("FindByRoomIdAndDate", testifymock.Anything, 1, bookingDate).Return(domain.BookRoom{}, gorm.ErrRecordNotFound)
-	mockUserRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(u domain.User) bool {
-		return u.ID == 1 && u.Balance == 100000
-	})).Return(updatedUser, nil)
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(room, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(user, nil)
+	mockBookRoomRepo.On("LockNights", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.RatePlan{}, nil)
+	mockFeeRuleRepo.On("FindActive", testifymock.Anything).Return([]domain.FeeRule{}, nil)
 	mockBookRoomRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(b domain.BookRoom) bool {
-		return b.RoomID == 1 && b.UserID == 1 && b.Price == 500000
+		return b.RoomID == 1 && b.UserID == 1 && b.Price == domain.NewMoney(500000) && len(b.Nights) == 1
 	})).Return(expectedBooking, nil)
+	mockWalletRepo.On("Debit", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.UserID == 1 && w.Type == domain.WalletTransactionBookingDebit && w.Amount == domain.NewMoney(-500000) && *w.ReferenceID == 1
+	})).Return(domain.WalletTransaction{ID: 1, BalanceAfter: domain.NewMoney(100000)}, nil)
 	sqlMock.ExpectCommit()
 
-	result, err := service.Create(bookRoom)
+	result, err := service.Create(bookRoom, domain.BookingOptions{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedBooking.ID, result.ID)
-	assert.Equal(t, float64(500000), result.Price)
+	assert.Equal(t, domain.NewMoney(500000), result.Price)
+	mockWalletRepo.AssertExpectations(t)
+}
+
+func TestBookRoomService_Create_MultiNightStay(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := time.Now().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+	checkOut := checkIn.AddDate(0, 0, 4)
+	bookRoom := domain.BookRoom{
+		RoomID:   1,
+		UserID:   1,
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
+	}
+
+	room := domain.Room{
+		ID:         1,
+		RoomTypeID: 1,
+		RoomNumber: "101",
+		RoomType: domain.RoomType{
+			ID:    1,
+			Name:  "Deluxe",
+			Price: domain.NewMoney(500000),
+		},
+	}
+
+	user := domain.User{
+		ID:              1,
+		Name:            "John Doe",
+		Email:           "john@example.com",
+		Balance:         domain.NewMoney(2500000),
+		EmailVerifiedAt: &verifiedAt,
+	}
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(room, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(user, nil)
+	mockBookRoomRepo.On("LockNights", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.RatePlan{}, nil)
+	mockFeeRuleRepo.On("FindActive", testifymock.Anything).Return([]domain.FeeRule{}, nil)
+	mockBookRoomRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(b domain.BookRoom) bool {
+		if len(b.Nights) != 4 || b.Price != domain.NewMoney(2000000) {
+			return false
+		}
+		for i, night := range b.Nights {
+			if !night.Date.Equal(checkIn.AddDate(0, 0, i)) || night.RoomID != 1 || night.Price != domain.NewMoney(500000) {
+				return false
+			}
+		}
+		return true
+	})).Return(domain.BookRoom{ID: 1, RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut, Price: domain.NewMoney(2000000)}, nil)
+	mockWalletRepo.On("Debit", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.Amount == domain.NewMoney(-2000000)
+	})).Return(domain.WalletTransaction{ID: 1, BalanceAfter: domain.NewMoney(500000)}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.Create(bookRoom, domain.BookingOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(2000000), result.Price)
+	mockBookRoomRepo.AssertExpectations(t)
+	mockWalletRepo.AssertExpectations(t)
+}
+
+func TestBookRoomService_Create_RatePlanPricing(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	// Thursday to Sunday: a weekend plan covers Friday and Saturday, and a
+	// higher priority holiday plan overrides it on Saturday.
+	checkIn := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	for checkIn.Weekday() != time.Thursday {
+		checkIn = checkIn.AddDate(0, 0, 1)
+	}
+	checkOut := checkIn.AddDate(0, 0, 3)
+	saturday := checkIn.AddDate(0, 0, 2)
+
+	room := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}}
+	user := domain.User{ID: 1, Balance: domain.NewMoney(5000000), EmailVerifiedAt: &verifiedAt}
+	ratePlans := []domain.RatePlan{
+		{ID: 1, RoomTypeID: 1, Name: "Weekend", Weekdays: domain.NewWeekdays(time.Friday, time.Saturday), Price: domain.NewMoney(650000)},
+		{ID: 2, RoomTypeID: 1, Name: "Holiday", StartDate: &saturday, EndDate: &saturday, Price: domain.NewMoney(900000), Priority: 10},
+	}
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(room, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(user, nil)
+	mockBookRoomRepo.On("LockNights", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return(ratePlans, nil)
+	mockFeeRuleRepo.On("FindActive", testifymock.Anything).Return([]domain.FeeRule{}, nil)
+	mockBookRoomRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(b domain.BookRoom) bool {
+		if len(b.Nights) != 3 || b.Price != domain.NewMoney(2050000) {
+			return false
+		}
+		return b.Nights[0].Price == domain.NewMoney(500000) && b.Nights[0].RatePlanID == nil &&
+			b.Nights[1].Price == domain.NewMoney(650000) && *b.Nights[1].RatePlanID == 1 &&
+			b.Nights[2].Price == domain.NewMoney(900000) && *b.Nights[2].RatePlanID == 2
+	})).Return(domain.BookRoom{ID: 1, RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut, Price: domain.NewMoney(2050000)}, nil)
+	mockWalletRepo.On("Debit", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.Amount == domain.NewMoney(-2050000)
+	})).Return(domain.WalletTransaction{ID: 1, BalanceAfter: domain.NewMoney(2950000)}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.Create(domain.BookRoom{RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut}, domain.BookingOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(2050000), result.Price)
+	mockBookRoomRepo.AssertExpectations(t)
+	mockWalletRepo.AssertExpectations(t)
+}
+
+func TestBookRoomService_Create_InvalidStayRange(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := time.Now().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+	bookRoom := domain.BookRoom{
+		RoomID:   1,
+		UserID:   1,
+		CheckIn:  checkIn,
+		CheckOut: checkIn,
+	}
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(domain.Room{ID: 1}, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(domain.User{ID: 1, EmailVerifiedAt: &verifiedAt}, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.Create(bookRoom, domain.BookingOptions{})
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Check-out date must be after check-in date", customErr.Message)
+	mockBookRoomRepo.AssertNotCalled(t, "Create")
+}
+
+func TestBookRoomService_Create_CheckInInPast(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	now := time.Now()
+	yesterday := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC)
+	bookRoom := domain.BookRoom{
+		RoomID:   1,
+		UserID:   1,
+		CheckIn:  yesterday,
+		CheckOut: yesterday.AddDate(0, 0, 2),
+	}
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(domain.Room{ID: 1}, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(domain.User{ID: 1, EmailVerifiedAt: &verifiedAt}, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.Create(bookRoom, domain.BookingOptions{})
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Check-in date must be today or in the future", customErr.Message)
+	mockBookRoomRepo.AssertNotCalled(t, "Create")
 }
 
 func TestBookRoomService_Create_RoomNotFound(t *testing.T) {
 	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
 	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
 	db, sqlMock, _ := setupMockDB()
-	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, mockUserRepo, db)
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
 
 	bookRoom := domain.BookRoom{
-		RoomID: 999,
-		UserID: 1,
-		Date:   time.Now(),
+		RoomID:   999,
+		UserID:   1,
+		CheckIn:  time.Now(),
+		CheckOut: time.Now().AddDate(0, 0, 1),
 	}
 
 	sqlMock.ExpectBegin()
-	mockRoomRepo.On("FindById", testifymock.Anything, 999).Return(domain.Room{}, gorm.ErrRecordNotFound)
+	mockRoomRepo.On("LockById", testifymock.Anything, 999).Return(domain.Room{}, gorm.ErrRecordNotFound)
 	sqlMock.ExpectRollback()
 
-	_, err := service.Create(bookRoom)
+	_, err := service.Create(bookRoom, domain.BookingOptions{})
 
 	assert.Error(t, err)
 	customErr, ok := err.(*exception.CustomError)
@@ -128,14 +347,19 @@ func TestBookRoomService_Create_RoomNotFound(t *testing.T) {
 func TestBookRoomService_Create_UserNotFound(t *testing.T) {
 	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
 	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
 	db, sqlMock, _ := setupMockDB()
-	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, mockUserRepo, db)
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
 
 	bookRoom := domain.BookRoom{
-		RoomID: 1,
-		UserID: 999,
-		Date:   time.Now(),
+		RoomID:   1,
+		UserID:   999,
+		CheckIn:  time.Now(),
+		CheckOut: time.Now().AddDate(0, 0, 1),
 	}
 
 	room := domain.Room{
@@ -145,16 +369,16 @@ func TestBookRoomService_Create_UserNotFound(t *testing.T) {
 		RoomType: domain.RoomType{
 			ID:    1,
 			Name:  "Deluxe",
-			Price: 500000,
+			Price: domain.NewMoney(500000),
 		},
 	}
 
 	sqlMock.ExpectBegin()
-	mockRoomRepo.On("FindById", testifymock.Anything, 1).Return(room, nil)
-	mockUserRepo.On("FindById", testifymock.Anything, 999).Return(domain.User{}, gorm.ErrRecordNotFound)
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(room, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 999).Return(domain.User{}, gorm.ErrRecordNotFound)
 	sqlMock.ExpectRollback()
 
-	_, err := service.Create(bookRoom)
+	_, err := service.Create(bookRoom, domain.BookingOptions{})
 
 	assert.Error(t, err)
 	customErr, ok := err.(*exception.CustomError)
@@ -162,18 +386,57 @@ func TestBookRoomService_Create_UserNotFound(t *testing.T) {
 	assert.Equal(t, "User not found", customErr.Message)
 }
 
+func TestBookRoomService_Create_EmailNotVerified(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	bookRoom := domain.BookRoom{
+		RoomID:   1,
+		UserID:   1,
+		CheckIn:  time.Now(),
+		CheckOut: time.Now().AddDate(0, 0, 1),
+	}
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(domain.Room{ID: 1, RoomTypeID: 1}, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(domain.User{ID: 1, Balance: domain.NewMoney(1000000)}, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.Create(bookRoom, domain.BookingOptions{})
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusForbidden, customErr.Code)
+	mockBookRoomRepo.AssertNotCalled(t, "LockNights", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
 func TestBookRoomService_Create_RoomAlreadyBooked(t *testing.T) {
 	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
 	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
 	db, sqlMock, _ := setupMockDB()
-	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, mockUserRepo, db)
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
 
-	bookingDate := time.Now().AddDate(0, 0, 1)
+	checkIn := time.Now().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+	checkOut := checkIn.AddDate(0, 0, 1)
 	bookRoom := domain.BookRoom{
-		RoomID: 1,
-		UserID: 1,
-		Date:   bookingDate,
+		RoomID:   1,
+		UserID:   1,
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
 	}
 
 	room := domain.Room{
@@ -183,51 +446,56 @@ func TestBookRoomService_Create_RoomAlreadyBooked(t *testing.T) {
 		RoomType: domain.RoomType{
 			ID:    1,
 			Name:  "Deluxe",
-			Price: 500000,
+			Price: domain.NewMoney(500000),
 		},
 	}
 
 	user := domain.User{
-		ID:      1,
-		Name:    "John Doe",
-		Email:   "john@example.com",
-		Balance: 600000,
+		ID:              1,
+		Name:            "John Doe",
+		Email:           "john@example.com",
+		Balance:         domain.NewMoney(600000),
+		EmailVerifiedAt: &verifiedAt,
 	}
 
-	existingBooking := domain.BookRoom{
-		ID:     1,
-		RoomID: 1,
-		UserID: 2,
-		Date:   bookingDate,
-		Price:  500000,
+	bookedNights := []domain.BookRoomNight{
+		{ID: 1, BookRoomID: 1, RoomID: 1, Date: checkIn, Price: domain.NewMoney(500000)},
 	}
 
 	sqlMock.ExpectBegin()
-	mockRoomRepo.On("FindById", testifymock.Anything, 1).Return(room, nil)
-	mockUserRepo.On("FindById", testifymock.Anything, 1).Return(user, nil)
-	mockBookRoomRepo.On("FindByRoomIdAndDate", testifymock.Anything, 1, bookingDate).Return(existingBooking, nil)
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(room, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(user, nil)
+	mockBookRoomRepo.On("LockNights", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return(bookedNights, nil)
 	sqlMock.ExpectRollback()
 
-	_, err := service.Create(bookRoom)
+	_, err := service.Create(bookRoom, domain.BookingOptions{})
 
 	assert.Error(t, err)
 	customErr, ok := err.(*exception.CustomError)
 	assert.True(t, ok)
-	assert.Equal(t, "Room is already booked for this date", customErr.Message)
+	assert.Equal(t, http.StatusConflict, customErr.Code)
+	assert.Equal(t, "Room is already booked for "+checkIn.Format("2006-01-02"), customErr.Message)
 }
 
 func TestBookRoomService_Create_InsufficientBalance(t *testing.T) {
 	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
 	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
 	db, sqlMock, _ := setupMockDB()
-	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, mockUserRepo, db)
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
 
-	bookingDate := time.Now().AddDate(0, 0, 1)
+	checkIn := time.Now().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+	checkOut := checkIn.AddDate(0, 0, 1)
 	bookRoom := domain.BookRoom{
-		RoomID: 1,
-		UserID: 1,
-		Date:   bookingDate,
+		RoomID:   1,
+		UserID:   1,
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
 	}
 
 	room := domain.Room{
@@ -237,48 +505,993 @@ func TestBookRoomService_Create_InsufficientBalance(t *testing.T) {
 		RoomType: domain.RoomType{
 			ID:    1,
 			Name:  "Deluxe",
-			Price: 500000,
+			Price: domain.NewMoney(500000),
 		},
 	}
 
 	user := domain.User{
-		ID:      1,
-		Name:    "John Doe",
-		Email:   "john@example.com",
-		Balance: 100000, // Insufficient balance
+		ID:              1,
+		Name:            "John Doe",
+		Email:           "john@example.com",
+		Balance:         domain.NewMoney(100000), // Insufficient balance
+		EmailVerifiedAt: &verifiedAt,
+	}
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(room, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(user, nil)
+	mockBookRoomRepo.On("LockNights", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.RatePlan{}, nil)
+	mockFeeRuleRepo.On("FindActive", testifymock.Anything).Return([]domain.FeeRule{}, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.Create(bookRoom, domain.BookingOptions{})
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Insufficient balance", customErr.Message)
+}
+
+func TestBookRoomService_Create_BalanceSpentConcurrently(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := time.Now().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+	checkOut := checkIn.AddDate(0, 0, 1)
+	bookRoom := domain.BookRoom{
+		RoomID:   1,
+		UserID:   1,
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
 	}
 
+	room := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}}
+
+	// The balance read is enough, but another booking spends it before
+	// the debit runs.
+	user := domain.User{ID: 1, Name: "John Doe", Email: "john@example.com", Balance: domain.NewMoney(600000), EmailVerifiedAt: &verifiedAt}
+
 	sqlMock.ExpectBegin()
-	mockRoomRepo.On("FindById", testifymock.Anything, 1).Return(room, nil)
-	mockUserRepo.On("FindById", testifymock.Anything, 1).Return(user, nil)
-	mockBookRoomRepo.On("FindByRoomIdAndDate", testifymock.Anything, 1, bookingDate).Return(domain.BookRoom{}, gorm.ErrRecordNotFound)
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(room, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(user, nil)
+	mockBookRoomRepo.On("LockNights", testifymock.Anything, 1, []time.Time{checkIn}).Return(nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.RatePlan{}, nil)
+	mockFeeRuleRepo.On("FindActive", testifymock.Anything).Return([]domain.FeeRule{}, nil)
+	mockBookRoomRepo.On("Create", testifymock.Anything, testifymock.Anything).Return(domain.BookRoom{ID: 1, RoomID: 1, UserID: 1, Price: domain.NewMoney(500000)}, nil)
+	mockWalletRepo.On("Debit", testifymock.Anything, testifymock.Anything).Return(domain.WalletTransaction{}, repository.ErrInsufficientBalance)
 	sqlMock.ExpectRollback()
 
-	_, err := service.Create(bookRoom)
+	_, err := service.Create(bookRoom, domain.BookingOptions{})
 
 	assert.Error(t, err)
 	customErr, ok := err.(*exception.CustomError)
 	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, customErr.Code)
 	assert.Equal(t, "Insufficient balance", customErr.Message)
+	mockBookRoomRepo.AssertExpectations(t)
+}
+
+func TestBookRoomService_Create_DuplicateNight(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := time.Now().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+	checkOut := checkIn.AddDate(0, 0, 1)
+	bookRoom := domain.BookRoom{
+		RoomID:   1,
+		UserID:   1,
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
+	}
+
+	room := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}}
+	user := domain.User{ID: 1, Name: "John Doe", Email: "john@example.com", Balance: domain.NewMoney(600000), EmailVerifiedAt: &verifiedAt}
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(room, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(user, nil)
+	mockBookRoomRepo.On("LockNights", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.RatePlan{}, nil)
+	mockFeeRuleRepo.On("FindActive", testifymock.Anything).Return([]domain.FeeRule{}, nil)
+	mockBookRoomRepo.On("Create", testifymock.Anything, testifymock.Anything).Return(domain.BookRoom{}, gorm.ErrDuplicatedKey)
+	sqlMock.ExpectRollback()
+
+	_, err := service.Create(bookRoom, domain.BookingOptions{})
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusConflict, customErr.Code)
+	mockWalletRepo.AssertNotCalled(t, "Debit", testifymock.Anything, testifymock.Anything)
 }
 
 func TestBookRoomService_FindByUserId_Success(t *testing.T) {
 	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
 	mockUserRepo := new(mock.UserRepositoryMock)
-	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, mockUserRepo, &gorm.DB{})
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, &gorm.DB{})
 
 	expectedBookings := []domain.BookRoom{
-		{ID: 1, RoomID: 1, UserID: 1, Date: time.Now(), Price: 500000},
-		{ID: 2, RoomID: 2, UserID: 1, Date: time.Now().AddDate(0, 0, 1), Price: 500000},
+		{ID: 1, RoomID: 1, UserID: 1, CheckIn: time.Now(), CheckOut: time.Now().AddDate(0, 0, 2), Price: domain.NewMoney(1000000)},
+		{ID: 2, RoomID: 2, UserID: 1, CheckIn: time.Now().AddDate(0, 0, 3), CheckOut: time.Now().AddDate(0, 0, 4), Price: domain.NewMoney(500000)},
 	}
 
-	mockBookRoomRepo.On("FindByUserId", &gorm.DB{}, 1).Return(expectedBookings, nil)
+	query := domain.CursorQuery{PerPage: 2}
+	next := &domain.Cursor{Key: "2026-01-01", ID: 2}
 
-	result, err := service.FindByUserId(1)
+	mockBookRoomRepo.On("FindByUserId", &gorm.DB{}, 1, query).Return(expectedBookings, next, nil)
+
+	result, cursor, err := service.FindByUserId(1, query)
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
 	assert.Equal(t, expectedBookings[0].ID, result[0].ID)
+	assert.Equal(t, next, cursor)
+	mockBookRoomRepo.AssertExpectations(t)
+}
+
+func TestBookRoomService_FindByUserId_InvalidFilter(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, &gorm.DB{})
+
+	query := domain.CursorQuery{PerPage: 20, Filters: map[string]string{"price": "1"}}
+
+	mockBookRoomRepo.On("FindByUserId", &gorm.DB{}, 1, query).Return([]domain.BookRoom(nil), nil, fmt.Errorf("%w: unknown filter \"price\"", repository.ErrInvalidListQuery))
+
+	_, _, err := service.FindByUserId(1, query)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, customErr.Code)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// noMaintenance returns a maintenance block repository with every room in
+// service.
+func noMaintenance() *mock.MaintenanceBlockRepositoryMock {
+	maintenanceBlockRepo := new(mock.MaintenanceBlockRepositoryMock)
+	maintenanceBlockRepo.On("FindByRoomIdAndDateRange", testifymock.Anything, testifymock.Anything, testifymock.Anything, testifymock.Anything).Return([]domain.MaintenanceBlock(nil), nil)
+	return maintenanceBlockRepo
+}
+
+func TestBookRoomService_Cancel_FullRefund(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := startOfDay(time.Now()).AddDate(0, 0, 5)
+	booking := domain.BookRoom{
+		ID:       1,
+		RoomID:   1,
+		UserID:   1,
+		CheckIn:  checkIn,
+		CheckOut: checkIn.AddDate(0, 0, 2),
+		Price:    domain.NewMoney(1000000),
+		Status:   domain.BookRoomStatusConfirmed,
+	}
+
+	sqlMock.ExpectBegin()
+	mockBookRoomRepo.On("LockById", testifymock.Anything, 1).Return(booking, nil)
+	mockBookRoomRepo.On("CreateStatusHistory", testifymock.Anything, domain.BookRoomStatusHistory{BookRoomID: 1, FromStatus: domain.BookRoomStatusConfirmed, ToStatus: domain.BookRoomStatusCancelled, ChangedBy: intPtr(1)}).Return(domain.BookRoomStatusHistory{}, nil)
+	mockWalletRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.UserID == 1 && w.Type == domain.WalletTransactionRefundCredit && w.Amount == domain.NewMoney(1000000)
+	})).Return(domain.WalletTransaction{ID: 2, BalanceAfter: domain.NewMoney(1050000)}, nil)
+	mockBookRoomRepo.On("DeleteNightsByBookRoomId", testifymock.Anything, 1).Return(nil)
+	mockBookRoomRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(b domain.BookRoom) bool {
+		return b.Status == domain.BookRoomStatusCancelled && b.RefundAmount == domain.NewMoney(1000000) && b.CancelledAt != nil
+	})).Return(domain.BookRoom{ID: 1, Status: domain.BookRoomStatusCancelled, RefundAmount: domain.NewMoney(1000000)}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.Cancel(1, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.BookRoomStatusCancelled, result.Status)
+	assert.Equal(t, domain.NewMoney(1000000), result.RefundAmount)
+	mockWalletRepo.AssertExpectations(t)
+	mockBookRoomRepo.AssertExpectations(t)
+}
+
+func TestBookRoomService_Cancel_PartialRefund(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := startOfDay(time.Now()).AddDate(0, 0, 1)
+	booking := domain.BookRoom{
+		ID:       1,
+		RoomID:   1,
+		UserID:   1,
+		CheckIn:  checkIn,
+		CheckOut: checkIn.AddDate(0, 0, 1),
+		Price:    domain.NewMoney(500000),
+		Status:   domain.BookRoomStatusConfirmed,
+	}
+
+	sqlMock.ExpectBegin()
+	mockBookRoomRepo.On("LockById", testifymock.Anything, 1).Return(booking, nil)
+	mockBookRoomRepo.On("CreateStatusHistory", testifymock.Anything, domain.BookRoomStatusHistory{BookRoomID: 1, FromStatus: domain.BookRoomStatusConfirmed, ToStatus: domain.BookRoomStatusCancelled, ChangedBy: intPtr(1)}).Return(domain.BookRoomStatusHistory{}, nil)
+	mockWalletRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.Type == domain.WalletTransactionRefundCredit && w.Amount == domain.NewMoney(250000)
+	})).Return(domain.WalletTransaction{ID: 2, BalanceAfter: domain.NewMoney(250000)}, nil)
+	mockBookRoomRepo.On("DeleteNightsByBookRoomId", testifymock.Anything, 1).Return(nil)
+	mockBookRoomRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(b domain.BookRoom) bool {
+		return b.Status == domain.BookRoomStatusCancelled && b.RefundAmount == domain.NewMoney(250000)
+	})).Return(domain.BookRoom{ID: 1, Status: domain.BookRoomStatusCancelled, RefundAmount: domain.NewMoney(250000)}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.Cancel(1, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(250000), result.RefundAmount)
+	mockWalletRepo.AssertExpectations(t)
+	mockBookRoomRepo.AssertExpectations(t)
+}
+
+func TestBookRoomService_Cancel_SameDayNoRefund(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := startOfDay(time.Now())
+	booking := domain.BookRoom{
+		ID:       1,
+		RoomID:   1,
+		UserID:   1,
+		CheckIn:  checkIn,
+		CheckOut: checkIn.AddDate(0, 0, 1),
+		Price:    domain.NewMoney(500000),
+		Status:   domain.BookRoomStatusConfirmed,
+	}
+
+	sqlMock.ExpectBegin()
+	mockBookRoomRepo.On("LockById", testifymock.Anything, 1).Return(booking, nil)
+	mockBookRoomRepo.On("CreateStatusHistory", testifymock.Anything, domain.BookRoomStatusHistory{BookRoomID: 1, FromStatus: domain.BookRoomStatusConfirmed, ToStatus: domain.BookRoomStatusCancelled, ChangedBy: intPtr(1)}).Return(domain.BookRoomStatusHistory{}, nil)
+	mockBookRoomRepo.On("DeleteNightsByBookRoomId", testifymock.Anything, 1).Return(nil)
+	mockBookRoomRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(b domain.BookRoom) bool {
+		return b.Status == domain.BookRoomStatusCancelled && b.RefundAmount == domain.NewMoney(0)
+	})).Return(domain.BookRoom{ID: 1, Status: domain.BookRoomStatusCancelled}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.Cancel(1, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(0), result.RefundAmount)
+	mockWalletRepo.AssertNotCalled(t, "Create")
+}
+
+func TestBookRoomService_Cancel_AlreadyCancelled(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	booking := domain.BookRoom{
+		ID:     1,
+		UserID: 1,
+		Status: domain.BookRoomStatusCancelled,
+	}
+
+	sqlMock.ExpectBegin()
+	mockBookRoomRepo.On("LockById", testifymock.Anything, 1).Return(booking, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.Cancel(1, 1)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Booking is already cancelled", customErr.Message)
+}
+
+func TestBookRoomService_Cancel_NotOwner(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	booking := domain.BookRoom{
+		ID:     1,
+		UserID: 2,
+		Status: domain.BookRoomStatusConfirmed,
+	}
+
+	sqlMock.ExpectBegin()
+	mockBookRoomRepo.On("LockById", testifymock.Anything, 1).Return(booking, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.Cancel(1, 1)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Booking not found", customErr.Message)
+	mockBookRoomRepo.AssertNotCalled(t, "Update")
+}
+
+func TestBookRoomService_Quote_Success(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, &gorm.DB{})
+
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 2)
+	room := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}}
+	weekend := domain.RatePlan{ID: 4, RoomTypeID: 1, Name: "Weekend", Weekdays: domain.NewWeekdays(time.Friday), Price: domain.NewMoney(650000)}
+
+	mockRoomRepo.On("FindById", &gorm.DB{}, 1).Return(room, nil)
+	mockUserRepo.On("FindById", &gorm.DB{}, 1).Return(domain.User{ID: 1, Balance: domain.NewMoney(1000000), EmailVerifiedAt: &verifiedAt}, nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", &gorm.DB{}, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", &gorm.DB{}, 1, checkIn, checkOut).Return([]domain.RatePlan{weekend}, nil)
+	mockFeeRuleRepo.On("FindActive", &gorm.DB{}).Return([]domain.FeeRule{}, nil)
+
+	result, err := service.Quote(domain.BookRoom{RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut}, domain.BookingOptions{})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.LineItems, 2)
+	assert.Equal(t, "Room 101, night of 2030-01-11 (Weekend)", result.LineItems[1].Description)
+	assert.Equal(t, domain.NewMoney(1150000), result.Total())
+	assert.False(t, result.BalanceCovers())
+	assert.NotEmpty(t, result.Token)
+	assert.True(t, result.ExpiresAt.After(time.Now()))
+
+	signed, err := testQuoteSigner.Verify(result.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, result.LineItems, signed.LineItems)
+	mockBookRoomRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+}
+
+func TestBookRoomService_Quote_AlreadyBooked(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, &gorm.DB{})
+
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 1)
+
+	mockRoomRepo.On("FindById", &gorm.DB{}, 1).Return(domain.Room{ID: 1, RoomTypeID: 1}, nil)
+	mockUserRepo.On("FindById", &gorm.DB{}, 1).Return(domain.User{ID: 1, EmailVerifiedAt: &verifiedAt}, nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", &gorm.DB{}, 1, checkIn, checkOut).Return([]domain.BookRoomNight{{RoomID: 1, Date: checkIn}}, nil)
+
+	_, err := service.Quote(domain.BookRoom{RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut}, domain.BookingOptions{})
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusConflict, customErr.Code)
+}
+
+func TestBookRoomService_Quote_UnderMaintenance(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockMaintenanceBlockRepo := new(mock.MaintenanceBlockRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, mockMaintenanceBlockRepo, new(mock.RatePlanRepositoryMock), new(mock.PromoCodeRepositoryMock), new(mock.FeeRuleRepositoryMock), mockUserRepo, new(mock.WalletTransactionRepositoryMock), testRefundPolicy, testQuoteSigner, &gorm.DB{})
+
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 3)
+	block := domain.MaintenanceBlock{ID: 4, RoomID: 1, StartDate: checkIn.AddDate(0, 0, 1), EndDate: checkIn.AddDate(0, 0, 10), Reason: "Bathroom renovation"}
+
+	mockRoomRepo.On("FindById", &gorm.DB{}, 1).Return(domain.Room{ID: 1, RoomTypeID: 1}, nil)
+	mockUserRepo.On("FindById", &gorm.DB{}, 1).Return(domain.User{ID: 1, EmailVerifiedAt: &verifiedAt}, nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", &gorm.DB{}, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockMaintenanceBlockRepo.On("FindByRoomIdAndDateRange", &gorm.DB{}, 1, checkIn, checkOut).Return([]domain.MaintenanceBlock{block}, nil)
+
+	_, err := service.Quote(domain.BookRoom{RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut}, domain.BookingOptions{})
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusConflict, customErr.Code)
+	assert.Equal(t, "Room is closed for maintenance on 2030-01-11", customErr.Message)
+}
+
+func TestBookRoomService_Create_WithQuoteToken(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 1)
+	ratePlanID := 9
+	token, _, err := testQuoteSigner.Sign(domain.BookingQuote{
+		UserID:   1,
+		RoomID:   1,
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
+		LineItems: []domain.LineItem{
+			{Type: domain.LineItemRoomNight, Description: "Room 101, night of 2030-01-10 (Promo)", Date: &checkIn, RatePlanID: &ratePlanID, Amount: domain.NewMoney(450000)},
+		},
+	})
+	assert.NoError(t, err)
+
+	room := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}}
+
+	// The promo rate plan was deleted after the quote; the quoted price stands.
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(room, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(domain.User{ID: 1, Balance: domain.NewMoney(1000000), EmailVerifiedAt: &verifiedAt}, nil)
+	mockBookRoomRepo.On("LockNights", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.RatePlan{}, nil)
+	mockFeeRuleRepo.On("FindActive", testifymock.Anything).Return([]domain.FeeRule{}, nil)
+	mockBookRoomRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(b domain.BookRoom) bool {
+		return b.Price == domain.NewMoney(450000) && len(b.Nights) == 1 && b.Nights[0].Price == domain.NewMoney(450000) && b.Nights[0].RatePlanID == nil
+	})).Return(domain.BookRoom{ID: 1, RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut, Price: domain.NewMoney(450000)}, nil)
+	mockWalletRepo.On("Debit", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.Amount == domain.NewMoney(-450000)
+	})).Return(domain.WalletTransaction{ID: 1, BalanceAfter: domain.NewMoney(550000)}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.Create(domain.BookRoom{RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut}, domain.BookingOptions{QuoteToken: token})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(450000), result.Price)
+	mockBookRoomRepo.AssertExpectations(t)
+	mockWalletRepo.AssertExpectations(t)
+}
+
+func TestBookRoomService_Create_QuoteTokenRejected(t *testing.T) {
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 1)
+	quote := domain.BookingQuote{UserID: 2, RoomID: 1, CheckIn: checkIn, CheckOut: checkOut}
+
+	otherUserToken, _, err := testQuoteSigner.Sign(quote)
+	assert.NoError(t, err)
+	expiredToken, _, err := helper.NewQuoteTokenSigner("test-secret", -time.Minute).Sign(quote)
+	assert.NoError(t, err)
+	forgedToken, _, err := helper.NewQuoteTokenSigner("other-secret", 15*time.Minute).Sign(quote)
+	assert.NoError(t, err)
+	loginToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, helper.JWTClaims{
+		UserID:           1,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	}).SignedString([]byte("test-secret"))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		token   string
+		message string
+	}{
+		{"issued to another user", otherUserToken, "Quote does not match this booking"},
+		{"expired", expiredToken, "Quote is invalid or has expired"},
+		{"forged", forgedToken, "Quote is invalid or has expired"},
+		{"login token", loginToken, "Quote is invalid or has expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+			mockRoomRepo := new(mock.RoomRepositoryMock)
+			mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+			mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+			mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+			mockUserRepo := new(mock.UserRepositoryMock)
+			mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+			service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, &gorm.DB{})
+
+			_, err := service.Create(domain.BookRoom{RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut}, domain.BookingOptions{QuoteToken: tt.token})
+
+			assert.Error(t, err)
+			customErr, ok := err.(*exception.CustomError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, customErr.Code)
+			assert.Equal(t, tt.message, customErr.Message)
+			mockRoomRepo.AssertNotCalled(t, "FindById", testifymock.Anything, 1)
+		})
+	}
+}
+
+func TestBookRoomService_Create_WithPromoCode(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 2)
+	room := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}}
+	promo := domain.PromoCode{ID: 3, Code: "SUMMER10", DiscountType: domain.PromoDiscountPercent, PercentOff: domain.NewPercent(10), MinNights: 2, MaxRedemptions: 100, MaxRedemptionsPerUser: 1, Active: true, RoomTypes: []domain.RoomType{{ID: 1}}}
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(room, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(domain.User{ID: 1, Balance: domain.NewMoney(1000000), EmailVerifiedAt: &verifiedAt}, nil)
+	mockBookRoomRepo.On("LockNights", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.RatePlan{}, nil)
+	mockFeeRuleRepo.On("FindActive", testifymock.Anything).Return([]domain.FeeRule{}, nil)
+	mockPromoCodeRepo.On("LockByCode", testifymock.Anything, "SUMMER10").Return(promo, nil)
+	mockPromoCodeRepo.On("CountActiveRedemptions", testifymock.Anything, 3, 0).Return(int64(99), nil)
+	mockPromoCodeRepo.On("CountActiveRedemptions", testifymock.Anything, 3, 1).Return(int64(0), nil)
+	mockBookRoomRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(b domain.BookRoom) bool {
+		return b.Price == domain.NewMoney(900000) && len(b.Nights) == 2 && b.Nights[0].Price == domain.NewMoney(500000)
+	})).Return(domain.BookRoom{ID: 7, RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut, Price: domain.NewMoney(900000)}, nil)
+	mockPromoCodeRepo.On("CreateRedemption", testifymock.Anything, domain.PromoRedemption{PromoCodeID: 3, UserID: 1, BookRoomID: 7, Discount: domain.NewMoney(100000)}).Return(domain.PromoRedemption{ID: 1}, nil)
+	mockWalletRepo.On("Debit", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.Amount == domain.NewMoney(-900000)
+	})).Return(domain.WalletTransaction{ID: 1, BalanceAfter: domain.NewMoney(100000)}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.Create(domain.BookRoom{RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut}, domain.BookingOptions{PromoCode: " summer10 "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(900000), result.Price)
+	mockPromoCodeRepo.AssertExpectations(t)
+	mockWalletRepo.AssertExpectations(t)
+}
+
+func TestBookRoomService_Create_PromoCodeFullyRedeemed(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 1)
+	room := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}}
+	promo := domain.PromoCode{ID: 3, Code: "FLASH", DiscountType: domain.PromoDiscountFixed, AmountOff: domain.NewMoney(50000), MaxRedemptions: 20, Active: true}
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(room, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(domain.User{ID: 1, Balance: domain.NewMoney(1000000), EmailVerifiedAt: &verifiedAt}, nil)
+	mockBookRoomRepo.On("LockNights", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.RatePlan{}, nil)
+	mockFeeRuleRepo.On("FindActive", testifymock.Anything).Return([]domain.FeeRule{}, nil)
+	mockPromoCodeRepo.On("LockByCode", testifymock.Anything, "FLASH").Return(promo, nil)
+	mockPromoCodeRepo.On("CountActiveRedemptions", testifymock.Anything, 3, 0).Return(int64(20), nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.Create(domain.BookRoom{RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut}, domain.BookingOptions{PromoCode: "flash"})
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Promo code has been fully redeemed", customErr.Message)
+	mockBookRoomRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+	mockWalletRepo.AssertNotCalled(t, "Debit", testifymock.Anything, testifymock.Anything)
+}
+
+func TestBookRoomService_Create_FreeStaySkipsDebit(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 1)
+	room := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}}
+	promo := domain.PromoCode{ID: 5, Code: "COMP", DiscountType: domain.PromoDiscountFixed, AmountOff: domain.NewMoney(1000000), Active: true}
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(room, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(domain.User{ID: 1, EmailVerifiedAt: &verifiedAt}, nil)
+	mockBookRoomRepo.On("LockNights", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.RatePlan{}, nil)
+	mockFeeRuleRepo.On("FindActive", testifymock.Anything).Return([]domain.FeeRule{}, nil)
+	mockPromoCodeRepo.On("LockByCode", testifymock.Anything, "COMP").Return(promo, nil)
+	mockBookRoomRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(b domain.BookRoom) bool {
+		return b.Price == 0 && len(b.Nights) == 1
+	})).Return(domain.BookRoom{ID: 8, RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut, Price: 0}, nil)
+	mockPromoCodeRepo.On("CreateRedemption", testifymock.Anything, domain.PromoRedemption{PromoCodeID: 5, UserID: 1, BookRoomID: 8, Discount: domain.NewMoney(500000)}).Return(domain.PromoRedemption{ID: 2}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.Create(domain.BookRoom{RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut}, domain.BookingOptions{PromoCode: "COMP"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Money(0), result.Price)
+	mockWalletRepo.AssertNotCalled(t, "Debit", testifymock.Anything, testifymock.Anything)
+}
+
+func TestBookRoomService_Quote_WithPromoCode(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, &gorm.DB{})
+
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 1)
+	room := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}}
+	promo := domain.PromoCode{ID: 3, Code: "FLASH", DiscountType: domain.PromoDiscountFixed, AmountOff: domain.NewMoney(50000), Active: true}
+
+	mockRoomRepo.On("FindById", &gorm.DB{}, 1).Return(room, nil)
+	mockUserRepo.On("FindById", &gorm.DB{}, 1).Return(domain.User{ID: 1, Balance: domain.NewMoney(450000), EmailVerifiedAt: &verifiedAt}, nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", &gorm.DB{}, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", &gorm.DB{}, 1, checkIn, checkOut).Return([]domain.RatePlan{}, nil)
+	mockFeeRuleRepo.On("FindActive", &gorm.DB{}).Return([]domain.FeeRule{}, nil)
+	mockPromoCodeRepo.On("FindByCode", &gorm.DB{}, "FLASH").Return(promo, nil)
+
+	result, err := service.Quote(domain.BookRoom{RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut}, domain.BookingOptions{PromoCode: "flash"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "FLASH", result.PromoCode)
+	assert.Equal(t, domain.NewMoney(500000), result.Subtotal())
+	assert.Equal(t, domain.NewMoney(50000), result.Discount())
+	assert.Equal(t, domain.NewMoney(450000), result.Total())
+	assert.True(t, result.BalanceCovers())
+
+	signed, err := testQuoteSigner.Verify(result.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, "FLASH", signed.PromoCode)
+	mockPromoCodeRepo.AssertNotCalled(t, "LockByCode", testifymock.Anything, testifymock.Anything)
+}
+
+func TestBookRoomService_Quote_PromoCodeRoomTypeRestricted(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, &gorm.DB{})
+
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 1)
+	room := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}}
+	promo := domain.PromoCode{ID: 3, Code: "SUITES", DiscountType: domain.PromoDiscountPercent, PercentOff: domain.NewPercent(20), Active: true, RoomTypes: []domain.RoomType{{ID: 2}}}
+
+	mockRoomRepo.On("FindById", &gorm.DB{}, 1).Return(room, nil)
+	mockUserRepo.On("FindById", &gorm.DB{}, 1).Return(domain.User{ID: 1, EmailVerifiedAt: &verifiedAt}, nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", &gorm.DB{}, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", &gorm.DB{}, 1, checkIn, checkOut).Return([]domain.RatePlan{}, nil)
+	mockFeeRuleRepo.On("FindActive", &gorm.DB{}).Return([]domain.FeeRule{}, nil)
+	mockPromoCodeRepo.On("FindByCode", &gorm.DB{}, "SUITES").Return(promo, nil)
+
+	_, err := service.Quote(domain.BookRoom{RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut}, domain.BookingOptions{PromoCode: "suites"})
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Promo code does not apply to this room type", customErr.Message)
+}
+
+func TestBookRoomService_Create_WithFees(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 2)
+	room := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}}
+	feeRules := []domain.FeeRule{
+		{ID: 1, Name: "Service charge", Type: domain.LineItemServiceCharge, Calculation: domain.FeeCalculationPercent, Rate: domain.NewPercent(10), Basis: domain.FeeBasisPerStay, Active: true},
+		{ID: 2, Name: "Hotel tax", Type: domain.LineItemTax, Calculation: domain.FeeCalculationPercent, Rate: domain.NewPercent(10), Basis: domain.FeeBasisPerStay, Active: true},
+		{ID: 3, Name: "City levy", Type: domain.LineItemTax, Calculation: domain.FeeCalculationFixed, Amount: domain.NewMoney(10000), Basis: domain.FeeBasisPerNight, Active: true},
+	}
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(room, nil)
+	mockUserRepo.On("LockById", testifymock.Anything, 1).Return(domain.User{ID: 1, Balance: domain.NewMoney(2000000), EmailVerifiedAt: &verifiedAt}, nil)
+	mockBookRoomRepo.On("LockNights", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", testifymock.Anything, 1, checkIn, checkOut).Return([]domain.RatePlan{}, nil)
+	mockFeeRuleRepo.On("FindActive", testifymock.Anything).Return(feeRules, nil)
+	mockBookRoomRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(b domain.BookRoom) bool {
+		breakdown := b.Breakdown()
+		return b.Price == domain.NewMoney(1220000) && len(b.Nights) == 2 && len(b.LineItems) == 5 &&
+			breakdown.ServiceCharge() == domain.NewMoney(100000) && breakdown.Tax() == domain.NewMoney(120000)
+	})).Return(domain.BookRoom{ID: 1, RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut, Price: domain.NewMoney(1220000)}, nil)
+	mockWalletRepo.On("Debit", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.Amount == domain.NewMoney(-1220000)
+	})).Return(domain.WalletTransaction{ID: 1, BalanceAfter: domain.NewMoney(780000)}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.Create(domain.BookRoom{RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut}, domain.BookingOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(1220000), result.Price)
 	mockBookRoomRepo.AssertExpectations(t)
+	mockWalletRepo.AssertExpectations(t)
+}
+
+func TestBookRoomService_Quote_InclusiveTax(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	service := NewBookRoomService(mockBookRoomRepo, mockRoomRepo, noMaintenance(), mockRatePlanRepo, mockPromoCodeRepo, mockFeeRuleRepo, mockUserRepo, mockWalletRepo, testRefundPolicy, testQuoteSigner, &gorm.DB{})
+
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 1)
+	room := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}}
+	promo := domain.PromoCode{ID: 3, Code: "FLASH", DiscountType: domain.PromoDiscountFixed, AmountOff: domain.NewMoney(60000), Active: true}
+	feeRules := []domain.FeeRule{
+		{ID: 1, Name: "VAT", Type: domain.LineItemTax, Calculation: domain.FeeCalculationPercent, Rate: domain.NewPercent(10), Basis: domain.FeeBasisPerStay, Inclusive: true, Active: true},
+	}
+
+	mockRoomRepo.On("FindById", &gorm.DB{}, 1).Return(room, nil)
+	mockUserRepo.On("FindById", &gorm.DB{}, 1).Return(domain.User{ID: 1, Balance: domain.NewMoney(500000), EmailVerifiedAt: &verifiedAt}, nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", &gorm.DB{}, 1, checkIn, checkOut).Return([]domain.BookRoomNight{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", &gorm.DB{}, 1, checkIn, checkOut).Return([]domain.RatePlan{}, nil)
+	mockPromoCodeRepo.On("FindByCode", &gorm.DB{}, "FLASH").Return(promo, nil)
+	mockFeeRuleRepo.On("FindActive", &gorm.DB{}).Return(feeRules, nil)
+
+	result, err := service.Quote(domain.BookRoom{RoomID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkOut}, domain.BookingOptions{PromoCode: "FLASH"})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.LineItems, 3)
+	assert.Equal(t, domain.NewMoney(440000), result.Total())
+	assert.Equal(t, domain.NewMoney(40000), result.Tax())
+	assert.Equal(t, domain.NewMoney(400000), result.Net())
+	assert.True(t, result.LineItems[2].Inclusive)
+
+	signed, err := testQuoteSigner.Verify(result.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, result.Total(), signed.Total())
+	assert.Equal(t, result.Tax(), signed.Tax())
+}
+
+func TestBookRoomService_CheckIn_Success(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, new(mock.RoomRepositoryMock), noMaintenance(), new(mock.RatePlanRepositoryMock), new(mock.PromoCodeRepositoryMock), new(mock.FeeRuleRepositoryMock), new(mock.UserRepositoryMock), new(mock.WalletTransactionRepositoryMock), testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := startOfDay(time.Now())
+	booking := domain.BookRoom{ID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2), Status: domain.BookRoomStatusConfirmed}
+
+	sqlMock.ExpectBegin()
+	mockBookRoomRepo.On("LockById", testifymock.Anything, 1).Return(booking, nil)
+	mockBookRoomRepo.On("CreateStatusHistory", testifymock.Anything, domain.BookRoomStatusHistory{BookRoomID: 1, FromStatus: domain.BookRoomStatusConfirmed, ToStatus: domain.BookRoomStatusCheckedIn, ChangedBy: intPtr(9)}).Return(domain.BookRoomStatusHistory{}, nil)
+	mockBookRoomRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(b domain.BookRoom) bool {
+		return b.Status == domain.BookRoomStatusCheckedIn && b.CheckedInAt != nil
+	})).Return(domain.BookRoom{ID: 1, Status: domain.BookRoomStatusCheckedIn}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.CheckIn(1, 9)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.BookRoomStatusCheckedIn, result.Status)
+	mockBookRoomRepo.AssertExpectations(t)
+}
+
+func TestBookRoomService_CheckIn_BeforeArrival(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, new(mock.RoomRepositoryMock), noMaintenance(), new(mock.RatePlanRepositoryMock), new(mock.PromoCodeRepositoryMock), new(mock.FeeRuleRepositoryMock), new(mock.UserRepositoryMock), new(mock.WalletTransactionRepositoryMock), testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := startOfDay(time.Now()).AddDate(0, 0, 1)
+	booking := domain.BookRoom{ID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2), Status: domain.BookRoomStatusConfirmed}
+
+	sqlMock.ExpectBegin()
+	mockBookRoomRepo.On("LockById", testifymock.Anything, 1).Return(booking, nil)
+	mockBookRoomRepo.On("CreateStatusHistory", testifymock.Anything, testifymock.Anything).Return(domain.BookRoomStatusHistory{}, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.CheckIn(1, 9)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Guests cannot check in before the arrival date", customErr.Message)
+	mockBookRoomRepo.AssertNotCalled(t, "Update", testifymock.Anything, testifymock.Anything)
+}
+
+func TestBookRoomService_Transition_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		change func(BookRoomService) (domain.BookRoom, error)
+	}{
+		{"check out before check in", domain.BookRoomStatusConfirmed, func(s BookRoomService) (domain.BookRoom, error) { return s.CheckOut(1, 9) }},
+		{"check in twice", domain.BookRoomStatusCheckedIn, func(s BookRoomService) (domain.BookRoom, error) { return s.CheckIn(1, 9) }},
+		{"no-show after check in", domain.BookRoomStatusCheckedIn, func(s BookRoomService) (domain.BookRoom, error) { return s.MarkNoShow(1, 9) }},
+		{"check in cancelled booking", domain.BookRoomStatusCancelled, func(s BookRoomService) (domain.BookRoom, error) { return s.CheckIn(1, 9) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+			db, sqlMock, _ := setupMockDB()
+			service := NewBookRoomService(mockBookRoomRepo, new(mock.RoomRepositoryMock), noMaintenance(), new(mock.RatePlanRepositoryMock), new(mock.PromoCodeRepositoryMock), new(mock.FeeRuleRepositoryMock), new(mock.UserRepositoryMock), new(mock.WalletTransactionRepositoryMock), testRefundPolicy, testQuoteSigner, db)
+
+			checkIn := startOfDay(time.Now())
+			sqlMock.ExpectBegin()
+			mockBookRoomRepo.On("LockById", testifymock.Anything, 1).Return(domain.BookRoom{ID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 1), Status: tt.status}, nil)
+			sqlMock.ExpectRollback()
+
+			_, err := tt.change(service)
+
+			assert.Error(t, err)
+			customErr, ok := err.(*exception.CustomError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusConflict, customErr.Code)
+			mockBookRoomRepo.AssertNotCalled(t, "CreateStatusHistory", testifymock.Anything, testifymock.Anything)
+		})
+	}
+}
+
+func TestBookRoomService_CheckOut_EarlyDepartureReleasesNights(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, new(mock.RoomRepositoryMock), noMaintenance(), new(mock.RatePlanRepositoryMock), new(mock.PromoCodeRepositoryMock), new(mock.FeeRuleRepositoryMock), new(mock.UserRepositoryMock), new(mock.WalletTransactionRepositoryMock), testRefundPolicy, testQuoteSigner, db)
+
+	today := startOfDay(time.Now())
+	booking := domain.BookRoom{ID: 1, UserID: 1, CheckIn: today.AddDate(0, 0, -1), CheckOut: today.AddDate(0, 0, 3), Status: domain.BookRoomStatusCheckedIn}
+
+	sqlMock.ExpectBegin()
+	mockBookRoomRepo.On("LockById", testifymock.Anything, 1).Return(booking, nil)
+	mockBookRoomRepo.On("CreateStatusHistory", testifymock.Anything, domain.BookRoomStatusHistory{BookRoomID: 1, FromStatus: domain.BookRoomStatusCheckedIn, ToStatus: domain.BookRoomStatusCheckedOut, ChangedBy: intPtr(9)}).Return(domain.BookRoomStatusHistory{}, nil)
+	mockBookRoomRepo.On("DeleteNightsFromDate", testifymock.Anything, 1, today).Return(nil)
+	mockBookRoomRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(b domain.BookRoom) bool {
+		return b.Status == domain.BookRoomStatusCheckedOut && b.CheckedOutAt != nil
+	})).Return(domain.BookRoom{ID: 1, Status: domain.BookRoomStatusCheckedOut}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.CheckOut(1, 9)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.BookRoomStatusCheckedOut, result.Status)
+	mockBookRoomRepo.AssertExpectations(t)
+}
+
+func TestBookRoomService_MarkNoShow_ReleasesNights(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewBookRoomService(mockBookRoomRepo, new(mock.RoomRepositoryMock), noMaintenance(), new(mock.RatePlanRepositoryMock), new(mock.PromoCodeRepositoryMock), new(mock.FeeRuleRepositoryMock), new(mock.UserRepositoryMock), mockWalletRepo, testRefundPolicy, testQuoteSigner, db)
+
+	checkIn := startOfDay(time.Now()).AddDate(0, 0, -1)
+	booking := domain.BookRoom{ID: 1, UserID: 1, CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 3), Price: domain.NewMoney(1500000), Status: domain.BookRoomStatusConfirmed}
+
+	sqlMock.ExpectBegin()
+	mockBookRoomRepo.On("LockById", testifymock.Anything, 1).Return(booking, nil)
+	mockBookRoomRepo.On("CreateStatusHistory", testifymock.Anything, domain.BookRoomStatusHistory{BookRoomID: 1, FromStatus: domain.BookRoomStatusConfirmed, ToStatus: domain.BookRoomStatusNoShow, ChangedBy: intPtr(9)}).Return(domain.BookRoomStatusHistory{}, nil)
+	mockBookRoomRepo.On("DeleteNightsByBookRoomId", testifymock.Anything, 1).Return(nil)
+	mockBookRoomRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(b domain.BookRoom) bool {
+		return b.Status == domain.BookRoomStatusNoShow && b.RefundAmount == 0
+	})).Return(domain.BookRoom{ID: 1, Status: domain.BookRoomStatusNoShow}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.MarkNoShow(1, 9)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.BookRoomStatusNoShow, result.Status)
+	mockBookRoomRepo.AssertExpectations(t)
+	mockWalletRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+}
+
+func TestBookRoomService_SweepNoShows(t *testing.T) {
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	policy := domain.RefundPolicy{FullRefundHours: 48, PartialRefundPercent: domain.NewPercent(50), NoShowRefundPercent: domain.NewPercent(20)}
+	service := NewBookRoomService(mockBookRoomRepo, new(mock.RoomRepositoryMock), noMaintenance(), new(mock.RatePlanRepositoryMock), new(mock.PromoCodeRepositoryMock), new(mock.FeeRuleRepositoryMock), new(mock.UserRepositoryMock), mockWalletRepo, policy, testQuoteSigner, db)
+
+	today := startOfDay(time.Now())
+	missed := domain.BookRoom{ID: 1, UserID: 1, CheckIn: today.AddDate(0, 0, -1), CheckOut: today.AddDate(0, 0, 1), Price: domain.NewMoney(1000000), Status: domain.BookRoomStatusConfirmed}
+	// Checked in by the front desk after the sweep listed it.
+	arrived := domain.BookRoom{ID: 2, UserID: 3, CheckIn: today.AddDate(0, 0, -1), CheckOut: today.AddDate(0, 0, 1), Price: domain.NewMoney(1000000), Status: domain.BookRoomStatusCheckedIn}
+
+	mockBookRoomRepo.On("FindIdsAwaitingArrival", db, today).Return([]int{1, 2}, nil)
+	sqlMock.ExpectBegin()
+	mockBookRoomRepo.On("LockById", testifymock.Anything, 1).Return(missed, nil)
+	mockBookRoomRepo.On("CreateStatusHistory", testifymock.Anything, domain.BookRoomStatusHistory{BookRoomID: 1, FromStatus: domain.BookRoomStatusConfirmed, ToStatus: domain.BookRoomStatusNoShow}).Return(domain.BookRoomStatusHistory{}, nil)
+	mockWalletRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.UserID == 1 && w.Type == domain.WalletTransactionRefundCredit && w.Amount == domain.NewMoney(200000)
+	})).Return(domain.WalletTransaction{ID: 5}, nil)
+	mockBookRoomRepo.On("DeleteNightsByBookRoomId", testifymock.Anything, 1).Return(nil)
+	mockBookRoomRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(b domain.BookRoom) bool {
+		return b.ID == 1 && b.Status == domain.BookRoomStatusNoShow && b.RefundAmount == domain.NewMoney(200000)
+	})).Return(domain.BookRoom{ID: 1, Status: domain.BookRoomStatusNoShow, RefundAmount: domain.NewMoney(200000)}, nil)
+	sqlMock.ExpectCommit()
+	sqlMock.ExpectBegin()
+	mockBookRoomRepo.On("LockById", testifymock.Anything, 2).Return(arrived, nil)
+	sqlMock.ExpectRollback()
+
+	marked, err := service.SweepNoShows(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, marked)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mockWalletRepo.AssertExpectations(t)
+	mockBookRoomRepo.AssertNumberOfCalls(t, "Update", 1)
 }
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type FeeRuleService interface {
+	Create(feeRule domain.FeeRule) (domain.FeeRule, error)
+	FindAll(query domain.ListQuery) ([]domain.FeeRule, int64, error)
+	FindById(id int) (domain.FeeRule, error)
+	Update(feeRule domain.FeeRule) (domain.FeeRule, error)
+	Delete(id int) error
+}
+
+type FeeRuleServiceImpl struct {
+	FeeRuleRepository repository.FeeRuleRepository
+	DB                *gorm.DB
+}
+
+func NewFeeRuleService(feeRuleRepository repository.FeeRuleRepository, db *gorm.DB) FeeRuleService {
+	return &FeeRuleServiceImpl{
+		FeeRuleRepository: feeRuleRepository,
+		DB:                db,
+	}
+}
+
+func (s *FeeRuleServiceImpl) Create(feeRule domain.FeeRule) (domain.FeeRule, error) {
+	if err := validateFeeRule(feeRule); err != nil {
+		return feeRule, err
+	}
+
+	return s.FeeRuleRepository.Create(s.DB, feeRule)
+}
+
+func (s *FeeRuleServiceImpl) FindAll(query domain.ListQuery) ([]domain.FeeRule, int64, error) {
+	feeRules, total, err := s.FeeRuleRepository.FindAll(s.DB, query)
+	if errors.Is(err, repository.ErrInvalidListQuery) {
+		return nil, 0, exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+	return feeRules, total, err
+}
+
+func (s *FeeRuleServiceImpl) FindById(id int) (domain.FeeRule, error) {
+	feeRule, err := s.FeeRuleRepository.FindById(s.DB, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return feeRule, exception.NewCustomError(http.StatusNotFound, "Fee rule not found")
+		}
+		return feeRule, err
+	}
+	return feeRule, nil
+}
+
+// Update changes a rule for bookings made from now on. Bookings already made
+// keep the line items they were charged.
+func (s *FeeRuleServiceImpl) Update(feeRule domain.FeeRule) (domain.FeeRule, error) {
+	existingFeeRule, err := s.FeeRuleRepository.FindById(s.DB, feeRule.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return feeRule, exception.NewCustomError(http.StatusNotFound, "Fee rule not found")
+		}
+		return feeRule, err
+	}
+
+	if err := validateFeeRule(feeRule); err != nil {
+		return feeRule, err
+	}
+
+	feeRule.CreatedAt = existingFeeRule.CreatedAt
+
+	return s.FeeRuleRepository.Update(s.DB, feeRule)
+}
+
+func (s *FeeRuleServiceImpl) Delete(id int) error {
+	_, err := s.FeeRuleRepository.FindById(s.DB, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return exception.NewCustomError(http.StatusNotFound, "Fee rule not found")
+		}
+		return err
+	}
+
+	return s.FeeRuleRepository.Delete(s.DB, id)
+}
+
+func validateFeeRule(feeRule domain.FeeRule) error {
+	switch feeRule.Type {
+	case domain.LineItemTax, domain.LineItemServiceCharge:
+	default:
+		return exception.NewCustomError(http.StatusBadRequest, "Type must be tax or service_charge")
+	}
+
+	switch feeRule.Basis {
+	case domain.FeeBasisPerNight, domain.FeeBasisPerStay:
+	default:
+		return exception.NewCustomError(http.StatusBadRequest, "Basis must be per_night or per_stay")
+	}
+
+	switch feeRule.Calculation {
+	case domain.FeeCalculationPercent:
+		if feeRule.Rate <= 0 || feeRule.Rate > domain.NewPercent(100) {
+			return exception.NewCustomError(http.StatusBadRequest, "Rate must be greater than 0 and at most 100")
+		}
+		if feeRule.Amount != 0 {
+			return exception.NewCustomError(http.StatusBadRequest, "Amount only applies to fixed fees")
+		}
+		if feeRule.Basis == domain.FeeBasisPerNight {
+			return exception.NewCustomError(http.StatusBadRequest, "Percent fees are charged on the whole stay, use per_stay")
+		}
+	case domain.FeeCalculationFixed:
+		if feeRule.Amount <= 0 {
+			return exception.NewCustomError(http.StatusBadRequest, "Amount must be greater than 0")
+		}
+		if feeRule.Rate != 0 {
+			return exception.NewCustomError(http.StatusBadRequest, "Rate only applies to percent fees")
+		}
+	default:
+		return exception.NewCustomError(http.StatusBadRequest, "Calculation must be percent or fixed")
+	}
+
+	return nil
+}
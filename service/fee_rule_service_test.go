@@ -0,0 +1,93 @@
+package service
+
+import (
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestFeeRuleService_Create_Success(t *testing.T) {
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	service := NewFeeRuleService(mockFeeRuleRepo, &gorm.DB{})
+
+	feeRule := domain.FeeRule{
+		Name:        "City levy",
+		Type:        domain.LineItemTax,
+		Calculation: domain.FeeCalculationFixed,
+		Amount:      domain.NewMoney(10000),
+		Basis:       domain.FeeBasisPerNight,
+		Active:      true,
+	}
+	created := feeRule
+	created.ID = 1
+
+	mockFeeRuleRepo.On("Create", &gorm.DB{}, feeRule).Return(created, nil)
+
+	result, err := service.Create(feeRule)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.ID)
+	mockFeeRuleRepo.AssertExpectations(t)
+}
+
+func TestFeeRuleService_Create_InvalidRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		feeRule domain.FeeRule
+		message string
+	}{
+		{
+			name:    "percent charged per night",
+			feeRule: domain.FeeRule{Name: "Tax", Type: domain.LineItemTax, Calculation: domain.FeeCalculationPercent, Rate: domain.NewPercent(10), Basis: domain.FeeBasisPerNight},
+			message: "Percent fees are charged on the whole stay, use per_stay",
+		},
+		{
+			name:    "percent over 100",
+			feeRule: domain.FeeRule{Name: "Tax", Type: domain.LineItemTax, Calculation: domain.FeeCalculationPercent, Rate: domain.NewPercent(120), Basis: domain.FeeBasisPerStay},
+			message: "Rate must be greater than 0 and at most 100",
+		},
+		{
+			name:    "fixed without amount",
+			feeRule: domain.FeeRule{Name: "Levy", Type: domain.LineItemTax, Calculation: domain.FeeCalculationFixed, Basis: domain.FeeBasisPerStay},
+			message: "Amount must be greater than 0",
+		},
+		{
+			name:    "unknown type",
+			feeRule: domain.FeeRule{Name: "Tip", Type: domain.LineItemDiscount, Calculation: domain.FeeCalculationFixed, Amount: domain.NewMoney(1000), Basis: domain.FeeBasisPerStay},
+			message: "Type must be tax or service_charge",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+			service := NewFeeRuleService(mockFeeRuleRepo, &gorm.DB{})
+
+			_, err := service.Create(tt.feeRule)
+
+			assert.Error(t, err)
+			customErr, ok := err.(*exception.CustomError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.message, customErr.Message)
+			mockFeeRuleRepo.AssertNotCalled(t, "Create", &gorm.DB{}, tt.feeRule)
+		})
+	}
+}
+
+func TestFeeRuleService_Update_NotFound(t *testing.T) {
+	mockFeeRuleRepo := new(mock.FeeRuleRepositoryMock)
+	service := NewFeeRuleService(mockFeeRuleRepo, &gorm.DB{})
+
+	mockFeeRuleRepo.On("FindById", &gorm.DB{}, 9).Return(domain.FeeRule{}, gorm.ErrRecordNotFound)
+
+	_, err := service.Update(domain.FeeRule{ID: 9})
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Fee rule not found", customErr.Message)
+}
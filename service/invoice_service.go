@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InvoiceService issues invoices for bookings and receipts for topups. A
+// userId of 0 fetches any guest's document, for admins and front desk staff.
+type InvoiceService interface {
+	BookRoomInvoice(bookRoomId int, userId int) (domain.InvoiceDocument, error)
+	TopupReceipt(topupId int, userId int) (domain.InvoiceDocument, error)
+}
+
+type InvoiceServiceImpl struct {
+	InvoiceRepository  repository.InvoiceRepository
+	BookRoomRepository repository.BookRoomRepository
+	TopupRepository    repository.TopupRepository
+	UserRepository     repository.UserRepository
+	Hotel              domain.HotelDetails
+	DB                 *gorm.DB
+}
+
+func NewInvoiceService(invoiceRepository repository.InvoiceRepository, bookRoomRepository repository.BookRoomRepository, topupRepository repository.TopupRepository, userRepository repository.UserRepository, hotel domain.HotelDetails, db *gorm.DB) InvoiceService {
+	return &InvoiceServiceImpl{
+		InvoiceRepository:  invoiceRepository,
+		BookRoomRepository: bookRoomRepository,
+		TopupRepository:    topupRepository,
+		UserRepository:     userRepository,
+		Hotel:              hotel,
+		DB:                 db,
+	}
+}
+
+func (s *InvoiceServiceImpl) BookRoomInvoice(bookRoomId int, userId int) (domain.InvoiceDocument, error) {
+	bookRoom, err := s.BookRoomRepository.FindById(s.DB, bookRoomId)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return domain.InvoiceDocument{}, exception.NewCustomError(http.StatusNotFound, "Booking not found")
+		}
+		return domain.InvoiceDocument{}, err
+	}
+
+	if userId != 0 && bookRoom.UserID != userId {
+		return domain.InvoiceDocument{}, exception.NewCustomError(http.StatusNotFound, "Booking not found")
+	}
+
+	invoice, err := s.issue(domain.InvoiceSubjectBookRoom, bookRoom.ID, bookRoom.UserID, domain.InvoiceSeriesBookRoom)
+	if err != nil {
+		return domain.InvoiceDocument{}, err
+	}
+
+	return domain.NewBookRoomInvoice(invoice, s.Hotel, bookRoom), nil
+}
+
+func (s *InvoiceServiceImpl) TopupReceipt(topupId int, userId int) (domain.InvoiceDocument, error) {
+	topup, err := s.TopupRepository.FindById(s.DB, topupId)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return domain.InvoiceDocument{}, exception.NewCustomError(http.StatusNotFound, "Topup not found")
+		}
+		return domain.InvoiceDocument{}, err
+	}
+
+	if userId != 0 && topup.UserID != userId {
+		return domain.InvoiceDocument{}, exception.NewCustomError(http.StatusNotFound, "Topup not found")
+	}
+
+	// A topup that was paid and later reversed still had its payment
+	// received, so it keeps its receipt.
+	if !domain.IsPaidTopupStatus(topup.Status, topup.FraudStatus) && topup.RefundedAmount == 0 {
+		return domain.InvoiceDocument{}, exception.NewCustomError(http.StatusBadRequest, "Receipts are only available for paid topups")
+	}
+
+	user, err := s.UserRepository.FindById(s.DB, topup.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return domain.InvoiceDocument{}, exception.NewCustomError(http.StatusNotFound, "User not found")
+		}
+		return domain.InvoiceDocument{}, err
+	}
+
+	invoice, err := s.issue(domain.InvoiceSubjectTopup, topup.ID, topup.UserID, domain.InvoiceSeriesTopup)
+	if err != nil {
+		return domain.InvoiceDocument{}, err
+	}
+
+	return domain.NewTopupReceipt(invoice, s.Hotel, topup, user), nil
+}
+
+// issue returns the invoice of a subject, numbering it from series the first
+// time it is asked for.
+func (s *InvoiceServiceImpl) issue(subjectType string, subjectId int, userId int, series string) (domain.Invoice, error) {
+	invoice, err := s.InvoiceRepository.FindBySubject(s.DB, subjectType, subjectId)
+	if err == nil {
+		return invoice, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return invoice, err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		issuedAt := time.Now()
+		sequence, err := s.InvoiceRepository.NextNumber(tx, series, issuedAt.Year())
+		if err != nil {
+			return err
+		}
+
+		invoice, err = s.InvoiceRepository.Create(tx, domain.Invoice{
+			Number:      domain.FormatInvoiceNumber(series, issuedAt.Year(), sequence),
+			SubjectType: subjectType,
+			SubjectID:   subjectId,
+			UserID:      userId,
+			IssuedAt:    issuedAt,
+		})
+		return err
+	})
+
+	// A concurrent request issued the invoice first. Rolling back handed our
+	// number back, so the sequence stays gap-free.
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return s.InvoiceRepository.FindBySubject(s.DB, subjectType, subjectId)
+	}
+
+	return invoice, err
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"fmt"
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var testHotel = domain.HotelDetails{Name: "Hotel IP", Address: "Jl. Sudirman 1, Jakarta"}
+
+func invoiceTestBooking() domain.BookRoom {
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	return domain.BookRoom{
+		ID:       7,
+		RoomID:   1,
+		UserID:   1,
+		CheckIn:  checkIn,
+		CheckOut: checkIn.AddDate(0, 0, 1),
+		Price:    domain.NewMoney(550000),
+		Status:   domain.BookRoomStatusConfirmed,
+		Room:     domain.Room{ID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1, Name: "Deluxe"}},
+		User:     domain.User{ID: 1, Name: "Ana", Email: "ana@example.com"},
+		LineItems: []domain.BookRoomLineItem{
+			{BookRoomID: 7, LineItem: domain.LineItem{Type: domain.LineItemRoomNight, Description: "Room 101, night of 2030-01-10", Date: &checkIn, Amount: domain.NewMoney(500000)}},
+			{BookRoomID: 7, LineItem: domain.LineItem{Type: domain.LineItemTax, Description: "Hotel tax (10%)", Amount: domain.NewMoney(50000)}},
+		},
+	}
+}
+
+func TestInvoiceService_BookRoomInvoice_IssuesNumber(t *testing.T) {
+	mockInvoiceRepo := new(mock.InvoiceRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewInvoiceService(mockInvoiceRepo, mockBookRoomRepo, mockTopupRepo, mockUserRepo, testHotel, db)
+
+	year := time.Now().Year()
+	number := fmt.Sprintf("INV-%d-000042", year)
+
+	mockBookRoomRepo.On("FindById", db, 7).Return(invoiceTestBooking(), nil)
+	mockInvoiceRepo.On("FindBySubject", db, domain.InvoiceSubjectBookRoom, 7).Return(domain.Invoice{}, gorm.ErrRecordNotFound)
+	sqlMock.ExpectBegin()
+	mockInvoiceRepo.On("NextNumber", testifymock.Anything, domain.InvoiceSeriesBookRoom, year).Return(int64(42), nil)
+	mockInvoiceRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(i domain.Invoice) bool {
+		return i.Number == number && i.SubjectType == domain.InvoiceSubjectBookRoom && i.SubjectID == 7 && i.UserID == 1
+	})).Return(domain.Invoice{ID: 1, Number: number, SubjectType: domain.InvoiceSubjectBookRoom, SubjectID: 7, UserID: 1}, nil)
+	sqlMock.ExpectCommit()
+
+	document, err := service.BookRoomInvoice(7, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, number, document.Invoice.Number)
+	assert.Equal(t, "Ana", document.GuestName)
+	assert.Equal(t, testHotel, document.Hotel)
+	assert.Len(t, document.Lines, 2)
+	assert.Equal(t, domain.InvoiceLine{Description: "Total paid", Amount: domain.NewMoney(550000)}, document.Totals[len(document.Totals)-1])
+	mockInvoiceRepo.AssertExpectations(t)
+}
+
+func TestInvoiceService_BookRoomInvoice_KeepsIssuedNumber(t *testing.T) {
+	mockInvoiceRepo := new(mock.InvoiceRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	service := NewInvoiceService(mockInvoiceRepo, mockBookRoomRepo, mockTopupRepo, mockUserRepo, testHotel, &gorm.DB{})
+
+	issued := domain.Invoice{ID: 3, Number: "INV-2029-000005", SubjectType: domain.InvoiceSubjectBookRoom, SubjectID: 7, UserID: 1}
+
+	mockBookRoomRepo.On("FindById", &gorm.DB{}, 7).Return(invoiceTestBooking(), nil)
+	mockInvoiceRepo.On("FindBySubject", &gorm.DB{}, domain.InvoiceSubjectBookRoom, 7).Return(issued, nil)
+
+	document, err := service.BookRoomInvoice(7, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "INV-2029-000005", document.Invoice.Number)
+	mockInvoiceRepo.AssertNotCalled(t, "NextNumber", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func TestInvoiceService_BookRoomInvoice_ConcurrentIssue(t *testing.T) {
+	mockInvoiceRepo := new(mock.InvoiceRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewInvoiceService(mockInvoiceRepo, mockBookRoomRepo, mockTopupRepo, mockUserRepo, testHotel, db)
+
+	issued := domain.Invoice{ID: 3, Number: "INV-2030-000005", SubjectType: domain.InvoiceSubjectBookRoom, SubjectID: 7, UserID: 1}
+
+	mockBookRoomRepo.On("FindById", db, 7).Return(invoiceTestBooking(), nil)
+	mockInvoiceRepo.On("FindBySubject", db, domain.InvoiceSubjectBookRoom, 7).Return(domain.Invoice{}, gorm.ErrRecordNotFound).Once()
+	sqlMock.ExpectBegin()
+	mockInvoiceRepo.On("NextNumber", testifymock.Anything, domain.InvoiceSeriesBookRoom, time.Now().Year()).Return(int64(6), nil)
+	mockInvoiceRepo.On("Create", testifymock.Anything, testifymock.Anything).Return(domain.Invoice{}, gorm.ErrDuplicatedKey)
+	sqlMock.ExpectRollback()
+	mockInvoiceRepo.On("FindBySubject", db, domain.InvoiceSubjectBookRoom, 7).Return(issued, nil).Once()
+
+	document, err := service.BookRoomInvoice(7, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "INV-2030-000005", document.Invoice.Number)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestInvoiceService_BookRoomInvoice_OtherUser(t *testing.T) {
+	mockInvoiceRepo := new(mock.InvoiceRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	service := NewInvoiceService(mockInvoiceRepo, mockBookRoomRepo, mockTopupRepo, mockUserRepo, testHotel, &gorm.DB{})
+
+	mockBookRoomRepo.On("FindById", &gorm.DB{}, 7).Return(invoiceTestBooking(), nil)
+
+	_, err := service.BookRoomInvoice(7, 2)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, 404, customErr.Code)
+	assert.Equal(t, "Booking not found", customErr.Message)
+	mockInvoiceRepo.AssertNotCalled(t, "FindBySubject", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func TestInvoiceService_TopupReceipt_Success(t *testing.T) {
+	mockInvoiceRepo := new(mock.InvoiceRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	service := NewInvoiceService(mockInvoiceRepo, mockBookRoomRepo, mockTopupRepo, mockUserRepo, testHotel, &gorm.DB{})
+
+	topup := domain.Topup{ID: 4, UserID: 1, MidtransOrderID: "TOPUP-1-abc", Amount: domain.NewMoney(100000), Status: domain.TopupStatusSettlement, PaymentType: "bank_transfer"}
+	issued := domain.Invoice{ID: 9, Number: "RCT-2030-000001", SubjectType: domain.InvoiceSubjectTopup, SubjectID: 4, UserID: 1}
+
+	mockTopupRepo.On("FindById", &gorm.DB{}, 4).Return(topup, nil)
+	mockUserRepo.On("FindById", &gorm.DB{}, 1).Return(domain.User{ID: 1, Name: "Ana", Email: "ana@example.com"}, nil)
+	mockInvoiceRepo.On("FindBySubject", &gorm.DB{}, domain.InvoiceSubjectTopup, 4).Return(issued, nil)
+
+	document, err := service.TopupReceipt(4, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Receipt", document.Title)
+	assert.Equal(t, "RCT-2030-000001", document.Invoice.Number)
+	assert.Contains(t, document.Details, domain.InvoiceField{Label: "Payment method", Value: "Midtrans (bank_transfer)"})
+	assert.Equal(t, domain.NewMoney(100000), document.Totals[len(document.Totals)-1].Amount)
+}
+
+func TestInvoiceService_TopupReceipt_NotPaid(t *testing.T) {
+	mockInvoiceRepo := new(mock.InvoiceRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	service := NewInvoiceService(mockInvoiceRepo, mockBookRoomRepo, mockTopupRepo, mockUserRepo, testHotel, &gorm.DB{})
+
+	mockTopupRepo.On("FindById", &gorm.DB{}, 4).Return(domain.Topup{ID: 4, UserID: 1, Amount: domain.NewMoney(100000), Status: domain.TopupStatusPending}, nil)
+
+	_, err := service.TopupReceipt(4, 1)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Receipts are only available for paid topups", customErr.Message)
+	mockInvoiceRepo.AssertNotCalled(t, "NextNumber", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"fmt"
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// loginThrottler counts failed logins per email and per client IP. Both the
+// password step and the two-factor step of a login count against it, and
+// password reset requests are counted the same way under their own scopes.
+type loginThrottler struct {
+	LoginThrottleRepository repository.LoginThrottleRepository
+	AuditLogRepository      repository.AuditLogRepository
+	LoginPolicy             domain.LoginPolicy
+	DB                      *gorm.DB
+}
+
+// loginThrottleKeys returns the account throttle of email first and, when
+// the client IP is known, the throttle of ip.
+func loginThrottleKeys(email string, ip string) []domain.LoginThrottle {
+	throttles := []domain.LoginThrottle{{Scope: domain.LoginThrottleScopeAccount, Key: normalizeEmail(email)}}
+	if ip != "" {
+		throttles = append(throttles, domain.LoginThrottle{Scope: domain.LoginThrottleScopeIP, Key: ip})
+	}
+	return throttles
+}
+
+// passwordResetThrottleKeys returns the reset throttle of email first and,
+// when the client IP is known, the reset throttle of ip.
+func passwordResetThrottleKeys(email string, ip string) []domain.LoginThrottle {
+	throttles := []domain.LoginThrottle{{Scope: domain.LoginThrottleScopeResetEmail, Key: normalizeEmail(email)}}
+	if ip != "" {
+		throttles = append(throttles, domain.LoginThrottle{Scope: domain.LoginThrottleScopeResetIP, Key: ip})
+	}
+	return throttles
+}
+
+// throttledAttempts names what a throttle of scope counts.
+func throttledAttempts(scope string) string {
+	if domain.IsPasswordResetThrottleScope(scope) {
+		return "password reset requests"
+	}
+	return "failed login attempts"
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// check returns a 429 error while any of throttles holds logins back.
+func (t loginThrottler) check(throttles []domain.LoginThrottle, now time.Time) error {
+	for _, throttle := range throttles {
+		current, err := t.LoginThrottleRepository.Find(t.DB, throttle.Scope, throttle.Key)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				continue
+			}
+			return err
+		}
+		if current.LockedUntil != nil && now.Before(*current.LockedUntil) {
+			retryAfter := int(math.Ceil(current.LockedUntil.Sub(now).Seconds()))
+			return exception.NewCustomError(http.StatusTooManyRequests, fmt.Sprintf("too many %s, try again in %d seconds", throttledAttempts(throttle.Scope), retryAfter))
+		}
+	}
+	return nil
+}
+
+// recordFailure counts a failed login against each throttle and audits
+// every lockout it causes. userId is 0 when the email is not registered.
+func (t loginThrottler) recordFailure(throttles []domain.LoginThrottle, userId int, ip string, now time.Time) error {
+	return t.DB.Transaction(func(tx *gorm.DB) error {
+		for _, key := range throttles {
+			throttle, err := t.LoginThrottleRepository.Lock(tx, key.Scope, key.Key)
+			if err != nil {
+				return err
+			}
+
+			lockedOut := t.LoginPolicy.RecordFailure(&throttle, now)
+			err = t.LoginThrottleRepository.Update(tx, throttle)
+			if err != nil {
+				return err
+			}
+			if !lockedOut {
+				continue
+			}
+
+			log.Printf("Locked out logins for %s %s until %s", throttle.Scope, throttle.Key, throttle.LockedUntil.Format(time.RFC3339))
+			auditLog := domain.AuditLog{
+				Action:    domain.AuditActionLoginLockout,
+				IPAddress: ip,
+				Details:   fmt.Sprintf("%s %s locked out until %s after %d %s", throttle.Scope, throttle.Key, throttle.LockedUntil.Format(time.RFC3339), t.LoginPolicy.LockoutFailures(throttle.Scope), throttledAttempts(throttle.Scope)),
+			}
+			if !domain.IsIPLoginThrottleScope(throttle.Scope) && userId != 0 {
+				auditLog.UserID = &userId
+			}
+			_, err = t.AuditLogRepository.Create(tx, auditLog)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// reset clears the failed logins of email after a successful login.
+func (t loginThrottler) reset(email string, userId int) {
+	err := t.LoginThrottleRepository.Delete(t.DB, domain.LoginThrottleScopeAccount, normalizeEmail(email))
+	if err != nil {
+		log.Printf("Failed to reset failed logins of user ID: %d: %v", userId, err)
+	}
+}
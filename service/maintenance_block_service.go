@@ -0,0 +1,217 @@
+package service
+
+import (
+	"fmt"
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MaintenanceBlockService interface {
+	Create(block domain.MaintenanceBlock, plan domain.RelocationPlan) (domain.MaintenanceBlock, error)
+	FindByRoomId(roomId int) ([]domain.MaintenanceBlock, error)
+	FindById(id int) (domain.MaintenanceBlock, error)
+	Delete(id int) error
+}
+
+type MaintenanceBlockServiceImpl struct {
+	MaintenanceBlockRepository repository.MaintenanceBlockRepository
+	RoomRepository             repository.RoomRepository
+	BookRoomRepository         repository.BookRoomRepository
+	DB                         *gorm.DB
+}
+
+func NewMaintenanceBlockService(maintenanceBlockRepository repository.MaintenanceBlockRepository, roomRepository repository.RoomRepository, bookRoomRepository repository.BookRoomRepository, db *gorm.DB) MaintenanceBlockService {
+	return &MaintenanceBlockServiceImpl{
+		MaintenanceBlockRepository: maintenanceBlockRepository,
+		RoomRepository:             roomRepository,
+		BookRoomRepository:         bookRoomRepository,
+		DB:                         db,
+	}
+}
+
+// Create takes a room out of service. The room row is locked the way a
+// booking locks it before taking its nights, so a booking cannot slip in while
+// the block is checked, however long the block runs. Bookings it overlaps
+// refuse the block unless plan forces it and moves each of them to another
+// room.
+func (s *MaintenanceBlockServiceImpl) Create(block domain.MaintenanceBlock, plan domain.RelocationPlan) (domain.MaintenanceBlock, error) {
+	if block.EndDate.Before(block.StartDate) {
+		return block, exception.NewCustomError(http.StatusBadRequest, "End date must be on or after start date")
+	}
+
+	now := time.Now()
+	if stayDay(block.StartDate, now).Before(stayDay(now, now)) {
+		return block, exception.NewCustomError(http.StatusBadRequest, "Start date must be today or in the future")
+	}
+
+	var result domain.MaintenanceBlock
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		_, err := s.RoomRepository.LockById(tx, block.RoomID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exception.NewCustomError(http.StatusNotFound, "Room not found")
+			}
+			return err
+		}
+
+		nights, err := s.BookRoomRepository.FindNightsByRoomIdAndDateRange(tx, block.RoomID, block.StartDate, block.EndDate.AddDate(0, 0, 1))
+		if err != nil {
+			return err
+		}
+
+		var overlapping []int
+		isOverlapping := map[int]bool{}
+		for _, night := range nights {
+			if !isOverlapping[night.BookRoomID] {
+				isOverlapping[night.BookRoomID] = true
+				overlapping = append(overlapping, night.BookRoomID)
+			}
+		}
+
+		if len(overlapping) > 0 && !plan.Force {
+			return exception.NewCustomError(http.StatusConflict, fmt.Sprintf("Room is booked during the maintenance period by bookings %s; relocate them to force the block", joinBookingIds(overlapping)))
+		}
+
+		for _, relocation := range plan.Relocations {
+			if !isOverlapping[relocation.BookRoomID] {
+				return exception.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Booking #%d does not overlap the maintenance period", relocation.BookRoomID))
+			}
+		}
+
+		for _, bookRoomId := range overlapping {
+			roomId, ok := plan.RoomFor(bookRoomId)
+			if !ok {
+				return exception.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Booking #%d needs a room to be relocated to", bookRoomId))
+			}
+
+			err = s.relocate(tx, bookRoomId, roomId, block.RoomID)
+			if err != nil {
+				return err
+			}
+			block.Relocations = append(block.Relocations, domain.Relocation{BookRoomID: bookRoomId, RoomID: roomId})
+		}
+
+		result, err = s.MaintenanceBlockRepository.Create(tx, block)
+		if err != nil {
+			return err
+		}
+		result.Relocations = block.Relocations
+
+		return nil
+	})
+
+	return result, err
+}
+
+// relocate moves a booking out of the room going under maintenance into a
+// room that is free and in service for the whole stay. The target room is
+// locked too, so a block or booking on it waits for the move.
+func (s *MaintenanceBlockServiceImpl) relocate(tx *gorm.DB, bookRoomId int, roomId int, blockedRoomId int) error {
+	if roomId == blockedRoomId {
+		return exception.NewCustomError(http.StatusBadRequest, fmt.Sprintf("Booking #%d must be relocated to a different room", bookRoomId))
+	}
+
+	_, err := s.RoomRepository.LockById(tx, roomId)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return exception.NewCustomError(http.StatusNotFound, "Room not found")
+		}
+		return err
+	}
+
+	bookRoom, err := s.BookRoomRepository.LockById(tx, bookRoomId)
+	if err != nil {
+		return err
+	}
+
+	err = s.BookRoomRepository.LockNights(tx, roomId, bookRoom.NightDates())
+	if err != nil {
+		return err
+	}
+
+	bookedNights, err := s.BookRoomRepository.FindNightsByRoomIdAndDateRange(tx, roomId, bookRoom.CheckIn, bookRoom.CheckOut)
+	if err != nil {
+		return err
+	}
+	if len(bookedNights) > 0 {
+		return exception.NewCustomError(http.StatusConflict, fmt.Sprintf("Cannot relocate booking #%d, the room is already booked for %s", bookRoomId, bookedNights[0].Date.Format("2006-01-02")))
+	}
+
+	err = checkOutOfService(tx, s.MaintenanceBlockRepository, roomId, bookRoom.CheckIn, bookRoom.CheckOut)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Relocating booking ID: %d from room ID: %d to room ID: %d for maintenance", bookRoomId, blockedRoomId, roomId)
+	return s.BookRoomRepository.UpdateRoom(tx, bookRoomId, roomId)
+}
+
+func (s *MaintenanceBlockServiceImpl) FindByRoomId(roomId int) ([]domain.MaintenanceBlock, error) {
+	_, err := s.RoomRepository.FindById(s.DB, roomId)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewCustomError(http.StatusNotFound, "Room not found")
+		}
+		return nil, err
+	}
+
+	return s.MaintenanceBlockRepository.FindByRoomId(s.DB, roomId)
+}
+
+func (s *MaintenanceBlockServiceImpl) FindById(id int) (domain.MaintenanceBlock, error) {
+	block, err := s.MaintenanceBlockRepository.FindById(s.DB, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return block, exception.NewCustomError(http.StatusNotFound, "Maintenance block not found")
+		}
+		return block, err
+	}
+	return block, nil
+}
+
+// Delete puts the room back in service. Bookings relocated for the block
+// stay in the rooms they were moved to.
+func (s *MaintenanceBlockServiceImpl) Delete(id int) error {
+	_, err := s.MaintenanceBlockRepository.FindById(s.DB, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return exception.NewCustomError(http.StatusNotFound, "Maintenance block not found")
+		}
+		return err
+	}
+
+	return s.MaintenanceBlockRepository.Delete(s.DB, id)
+}
+
+// checkOutOfService rejects a stay on a room that is under maintenance for
+// any of the nights from checkIn up to but not including checkOut.
+func checkOutOfService(db *gorm.DB, maintenanceBlockRepository repository.MaintenanceBlockRepository, roomId int, checkIn time.Time, checkOut time.Time) error {
+	blocks, err := maintenanceBlockRepository.FindByRoomIdAndDateRange(db, roomId, checkIn, checkOut)
+	if err != nil {
+		return err
+	}
+	if len(blocks) > 0 {
+		closed := blocks[0].StartDate
+		if closed.Before(checkIn) {
+			closed = checkIn
+		}
+		return exception.NewCustomError(http.StatusConflict, fmt.Sprintf("Room is closed for maintenance on %s", closed.Format("2006-01-02")))
+	}
+	return nil
+}
+
+func joinBookingIds(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("#%d", id)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository/mock"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func maintenanceTestBlock() domain.MaintenanceBlock {
+	start := startOfDay(time.Now()).AddDate(0, 0, 10)
+	return domain.MaintenanceBlock{RoomID: 1, StartDate: start, EndDate: start.AddDate(0, 0, 2), Reason: "Repainting", CreatedBy: 9}
+}
+
+func TestMaintenanceBlockService_Create_Success(t *testing.T) {
+	mockMaintenanceBlockRepo := new(mock.MaintenanceBlockRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewMaintenanceBlockService(mockMaintenanceBlockRepo, mockRoomRepo, mockBookRoomRepo, db)
+
+	block := maintenanceTestBlock()
+	created := block
+	created.ID = 1
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(domain.Room{ID: 1}, nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, block.StartDate, block.EndDate.AddDate(0, 0, 1)).Return([]domain.BookRoomNight{}, nil)
+	mockMaintenanceBlockRepo.On("Create", testifymock.Anything, block).Return(created, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.Create(block, domain.RelocationPlan{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.ID)
+	assert.Empty(t, result.Relocations)
+	mockMaintenanceBlockRepo.AssertExpectations(t)
+	mockRoomRepo.AssertExpectations(t)
+	mockBookRoomRepo.AssertNotCalled(t, "LockNights", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func TestMaintenanceBlockService_Create_OverlapsBookings(t *testing.T) {
+	mockMaintenanceBlockRepo := new(mock.MaintenanceBlockRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewMaintenanceBlockService(mockMaintenanceBlockRepo, mockRoomRepo, mockBookRoomRepo, db)
+
+	block := maintenanceTestBlock()
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(domain.Room{ID: 1}, nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, block.StartDate, block.EndDate.AddDate(0, 0, 1)).Return([]domain.BookRoomNight{
+		{BookRoomID: 5, RoomID: 1, Date: block.StartDate},
+		{BookRoomID: 5, RoomID: 1, Date: block.StartDate.AddDate(0, 0, 1)},
+		{BookRoomID: 8, RoomID: 1, Date: block.EndDate},
+	}, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.Create(block, domain.RelocationPlan{Relocations: []domain.Relocation{{BookRoomID: 5, RoomID: 2}}})
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusConflict, customErr.Code)
+	assert.Contains(t, customErr.Message, "#5, #8")
+	mockBookRoomRepo.AssertNotCalled(t, "UpdateRoom", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+	mockMaintenanceBlockRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+}
+
+func TestMaintenanceBlockService_Create_ForcedWithRelocation(t *testing.T) {
+	mockMaintenanceBlockRepo := new(mock.MaintenanceBlockRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewMaintenanceBlockService(mockMaintenanceBlockRepo, mockRoomRepo, mockBookRoomRepo, db)
+
+	block := maintenanceTestBlock()
+	booking := domain.BookRoom{ID: 5, RoomID: 1, UserID: 3, CheckIn: block.StartDate.AddDate(0, 0, -1), CheckOut: block.StartDate.AddDate(0, 0, 1), Status: domain.BookRoomStatusConfirmed}
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(domain.Room{ID: 1}, nil)
+	mockRoomRepo.On("LockById", testifymock.Anything, 2).Return(domain.Room{ID: 2}, nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, block.StartDate, block.EndDate.AddDate(0, 0, 1)).Return([]domain.BookRoomNight{{BookRoomID: 5, RoomID: 1, Date: block.StartDate}}, nil)
+	mockBookRoomRepo.On("LockById", testifymock.Anything, 5).Return(booking, nil)
+	mockBookRoomRepo.On("LockNights", testifymock.Anything, 2, booking.NightDates()).Return(nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 2, booking.CheckIn, booking.CheckOut).Return([]domain.BookRoomNight{}, nil)
+	mockMaintenanceBlockRepo.On("FindByRoomIdAndDateRange", testifymock.Anything, 2, booking.CheckIn, booking.CheckOut).Return([]domain.MaintenanceBlock{}, nil)
+	mockBookRoomRepo.On("UpdateRoom", testifymock.Anything, 5, 2).Return(nil)
+	mockMaintenanceBlockRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(b domain.MaintenanceBlock) bool {
+		return b.RoomID == 1 && b.Reason == "Repainting"
+	})).Return(domain.MaintenanceBlock{ID: 1, RoomID: 1}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.Create(block, domain.RelocationPlan{Force: true, Relocations: []domain.Relocation{{BookRoomID: 5, RoomID: 2}}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Relocation{{BookRoomID: 5, RoomID: 2}}, result.Relocations)
+	mockBookRoomRepo.AssertExpectations(t)
+}
+
+func TestMaintenanceBlockService_Create_ForcedWithoutRelocation(t *testing.T) {
+	mockMaintenanceBlockRepo := new(mock.MaintenanceBlockRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewMaintenanceBlockService(mockMaintenanceBlockRepo, mockRoomRepo, mockBookRoomRepo, db)
+
+	block := maintenanceTestBlock()
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(domain.Room{ID: 1}, nil)
+	mockBookRoomRepo.On("FindNightsByRoomIdAndDateRange", testifymock.Anything, 1, block.StartDate, block.EndDate.AddDate(0, 0, 1)).Return([]domain.BookRoomNight{{BookRoomID: 5, RoomID: 1, Date: block.StartDate}}, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.Create(block, domain.RelocationPlan{Force: true})
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Booking #5 needs a room to be relocated to", customErr.Message)
+}
+
+func TestMaintenanceBlockService_Create_EndBeforeStart(t *testing.T) {
+	mockMaintenanceBlockRepo := new(mock.MaintenanceBlockRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	db, _, _ := setupMockDB()
+	service := NewMaintenanceBlockService(mockMaintenanceBlockRepo, mockRoomRepo, mockBookRoomRepo, db)
+
+	block := maintenanceTestBlock()
+	block.EndDate = block.StartDate.AddDate(0, 0, -1)
+
+	_, err := service.Create(block, domain.RelocationPlan{})
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "End date must be on or after start date", customErr.Message)
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"errors"
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type PromoCodeService interface {
+	Create(promoCode domain.PromoCode) (domain.PromoCode, error)
+	FindAll(query domain.ListQuery) ([]domain.PromoCode, int64, error)
+	FindById(id int) (domain.PromoCode, error)
+	Update(promoCode domain.PromoCode) (domain.PromoCode, error)
+	Delete(id int) error
+}
+
+type PromoCodeServiceImpl struct {
+	PromoCodeRepository repository.PromoCodeRepository
+	RoomTypeRepository  repository.RoomTypeRepository
+	DB                  *gorm.DB
+}
+
+func NewPromoCodeService(promoCodeRepository repository.PromoCodeRepository, roomTypeRepository repository.RoomTypeRepository, db *gorm.DB) PromoCodeService {
+	return &PromoCodeServiceImpl{
+		PromoCodeRepository: promoCodeRepository,
+		RoomTypeRepository:  roomTypeRepository,
+		DB:                  db,
+	}
+}
+
+func (s *PromoCodeServiceImpl) Create(promoCode domain.PromoCode) (domain.PromoCode, error) {
+	promoCode.Code = domain.NormalizePromoCode(promoCode.Code)
+
+	existingPromoCode, err := s.PromoCodeRepository.FindByCode(s.DB, promoCode.Code)
+	if err == nil && existingPromoCode.ID != 0 {
+		return promoCode, exception.NewCustomError(http.StatusBadRequest, "Promo code already exists")
+	}
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return promoCode, err
+	}
+
+	if err := s.validate(promoCode); err != nil {
+		return promoCode, err
+	}
+
+	return s.PromoCodeRepository.Create(s.DB, promoCode)
+}
+
+func (s *PromoCodeServiceImpl) FindAll(query domain.ListQuery) ([]domain.PromoCode, int64, error) {
+	promoCodes, total, err := s.PromoCodeRepository.FindAll(s.DB, query)
+	if errors.Is(err, repository.ErrInvalidListQuery) {
+		return nil, 0, exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+	return promoCodes, total, err
+}
+
+func (s *PromoCodeServiceImpl) FindById(id int) (domain.PromoCode, error) {
+	promoCode, err := s.PromoCodeRepository.FindById(s.DB, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return promoCode, exception.NewCustomError(http.StatusNotFound, "Promo code not found")
+		}
+		return promoCode, err
+	}
+	return promoCode, nil
+}
+
+func (s *PromoCodeServiceImpl) Update(promoCode domain.PromoCode) (domain.PromoCode, error) {
+	existingPromoCode, err := s.PromoCodeRepository.FindById(s.DB, promoCode.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return promoCode, exception.NewCustomError(http.StatusNotFound, "Promo code not found")
+		}
+		return promoCode, err
+	}
+
+	promoCode.Code = domain.NormalizePromoCode(promoCode.Code)
+
+	sameCode, err := s.PromoCodeRepository.FindByCode(s.DB, promoCode.Code)
+	if err == nil && sameCode.ID != 0 && sameCode.ID != promoCode.ID {
+		return promoCode, exception.NewCustomError(http.StatusBadRequest, "Promo code already exists")
+	}
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return promoCode, err
+	}
+
+	if err := s.validate(promoCode); err != nil {
+		return promoCode, err
+	}
+
+	promoCode.CreatedAt = existingPromoCode.CreatedAt
+
+	return s.PromoCodeRepository.Update(s.DB, promoCode)
+}
+
+// Delete removes a promo code that has never been redeemed. Codes that have
+// been used are kept for the record and can be deactivated instead.
+func (s *PromoCodeServiceImpl) Delete(id int) error {
+	_, err := s.PromoCodeRepository.FindById(s.DB, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return exception.NewCustomError(http.StatusNotFound, "Promo code not found")
+		}
+		return err
+	}
+
+	redemptions, err := s.PromoCodeRepository.CountRedemptions(s.DB, id)
+	if err != nil {
+		return err
+	}
+
+	if redemptions > 0 {
+		return exception.NewCustomError(http.StatusBadRequest, "Cannot delete promo code that has been redeemed, deactivate it instead")
+	}
+
+	return s.PromoCodeRepository.Delete(s.DB, id)
+}
+
+func (s *PromoCodeServiceImpl) validate(promoCode domain.PromoCode) error {
+	switch promoCode.DiscountType {
+	case domain.PromoDiscountPercent:
+		if promoCode.PercentOff <= 0 || promoCode.PercentOff > domain.NewPercent(100) {
+			return exception.NewCustomError(http.StatusBadRequest, "Percent off must be greater than 0 and at most 100")
+		}
+		if promoCode.AmountOff != 0 {
+			return exception.NewCustomError(http.StatusBadRequest, "Amount off only applies to fixed discounts")
+		}
+	case domain.PromoDiscountFixed:
+		if promoCode.AmountOff <= 0 {
+			return exception.NewCustomError(http.StatusBadRequest, "Amount off must be greater than 0")
+		}
+		if promoCode.PercentOff != 0 {
+			return exception.NewCustomError(http.StatusBadRequest, "Percent off only applies to percent discounts")
+		}
+	default:
+		return exception.NewCustomError(http.StatusBadRequest, "Discount type must be percent or fixed")
+	}
+
+	if promoCode.ValidFrom != nil && promoCode.ValidUntil != nil && !promoCode.ValidUntil.After(*promoCode.ValidFrom) {
+		return exception.NewCustomError(http.StatusBadRequest, "Valid until must be after valid from")
+	}
+
+	for _, roomType := range promoCode.RoomTypes {
+		_, err := s.RoomTypeRepository.FindById(s.DB, roomType.ID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exception.NewCustomError(http.StatusNotFound, "Room type not found")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestPromoCodeService_Create_Success(t *testing.T) {
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	service := NewPromoCodeService(mockPromoCodeRepo, mockRoomTypeRepo, &gorm.DB{})
+
+	promoCode := domain.PromoCode{
+		Code:         " summer10 ",
+		DiscountType: domain.PromoDiscountPercent,
+		PercentOff:   domain.NewPercent(10),
+		Active:       true,
+		RoomTypes:    []domain.RoomType{{ID: 1}},
+	}
+
+	mockPromoCodeRepo.On("FindByCode", &gorm.DB{}, "SUMMER10").Return(domain.PromoCode{}, gorm.ErrRecordNotFound)
+	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 1).Return(domain.RoomType{ID: 1, Name: "Deluxe"}, nil)
+	mockPromoCodeRepo.On("Create", &gorm.DB{}, testifymock.MatchedBy(func(p domain.PromoCode) bool {
+		return p.Code == "SUMMER10"
+	})).Return(domain.PromoCode{ID: 1, Code: "SUMMER10", DiscountType: domain.PromoDiscountPercent, PercentOff: domain.NewPercent(10), Active: true}, nil)
+
+	result, err := service.Create(promoCode)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SUMMER10", result.Code)
+	mockPromoCodeRepo.AssertExpectations(t)
+}
+
+func TestPromoCodeService_Create_DuplicateCode(t *testing.T) {
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	service := NewPromoCodeService(mockPromoCodeRepo, mockRoomTypeRepo, &gorm.DB{})
+
+	promoCode := domain.PromoCode{Code: "SUMMER10", DiscountType: domain.PromoDiscountPercent, PercentOff: domain.NewPercent(10)}
+
+	mockPromoCodeRepo.On("FindByCode", &gorm.DB{}, "SUMMER10").Return(domain.PromoCode{ID: 1, Code: "SUMMER10"}, nil)
+
+	_, err := service.Create(promoCode)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Promo code already exists", customErr.Message)
+	mockPromoCodeRepo.AssertNotCalled(t, "Create", &gorm.DB{}, promoCode)
+}
+
+func TestPromoCodeService_Create_InvalidDiscount(t *testing.T) {
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	service := NewPromoCodeService(mockPromoCodeRepo, mockRoomTypeRepo, &gorm.DB{})
+
+	promoCode := domain.PromoCode{Code: "HALF", DiscountType: domain.PromoDiscountFixed, PercentOff: domain.NewPercent(50)}
+
+	mockPromoCodeRepo.On("FindByCode", &gorm.DB{}, "HALF").Return(domain.PromoCode{}, gorm.ErrRecordNotFound)
+
+	_, err := service.Create(promoCode)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Amount off must be greater than 0", customErr.Message)
+}
+
+func TestPromoCodeService_Create_InvalidWindow(t *testing.T) {
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	service := NewPromoCodeService(mockPromoCodeRepo, mockRoomTypeRepo, &gorm.DB{})
+
+	validFrom := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	validUntil := validFrom.AddDate(0, 0, -1)
+	promoCode := domain.PromoCode{Code: "JUNE", DiscountType: domain.PromoDiscountFixed, AmountOff: domain.NewMoney(50000), ValidFrom: &validFrom, ValidUntil: &validUntil}
+
+	mockPromoCodeRepo.On("FindByCode", &gorm.DB{}, "JUNE").Return(domain.PromoCode{}, gorm.ErrRecordNotFound)
+
+	_, err := service.Create(promoCode)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Valid until must be after valid from", customErr.Message)
+}
+
+func TestPromoCodeService_Delete_Redeemed(t *testing.T) {
+	mockPromoCodeRepo := new(mock.PromoCodeRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	service := NewPromoCodeService(mockPromoCodeRepo, mockRoomTypeRepo, &gorm.DB{})
+
+	mockPromoCodeRepo.On("FindById", &gorm.DB{}, 1).Return(domain.PromoCode{ID: 1, Code: "SUMMER10"}, nil)
+	mockPromoCodeRepo.On("CountRedemptions", &gorm.DB{}, 1).Return(int64(4), nil)
+
+	err := service.Delete(1)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Cannot delete promo code that has been redeemed, deactivate it instead", customErr.Message)
+	mockPromoCodeRepo.AssertNotCalled(t, "Delete", &gorm.DB{}, 1)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type RatePlanService interface {
+	Create(ratePlan domain.RatePlan) (domain.RatePlan, error)
+	FindByRoomTypeId(roomTypeId int) ([]domain.RatePlan, error)
+	FindById(id int) (domain.RatePlan, error)
+	Update(ratePlan domain.RatePlan) (domain.RatePlan, error)
+	Delete(id int) error
+}
+
+type RatePlanServiceImpl struct {
+	RatePlanRepository repository.RatePlanRepository
+	RoomTypeRepository repository.RoomTypeRepository
+	DB                 *gorm.DB
+}
+
+func NewRatePlanService(ratePlanRepository repository.RatePlanRepository, roomTypeRepository repository.RoomTypeRepository, db *gorm.DB) RatePlanService {
+	return &RatePlanServiceImpl{
+		RatePlanRepository: ratePlanRepository,
+		RoomTypeRepository: roomTypeRepository,
+		DB:                 db,
+	}
+}
+
+func (s *RatePlanServiceImpl) Create(ratePlan domain.RatePlan) (domain.RatePlan, error) {
+	_, err := s.RoomTypeRepository.FindById(s.DB, ratePlan.RoomTypeID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ratePlan, exception.NewCustomError(http.StatusNotFound, "Room type not found")
+		}
+		return ratePlan, err
+	}
+
+	if err := validateRatePlanDates(ratePlan); err != nil {
+		return ratePlan, err
+	}
+
+	return s.RatePlanRepository.Create(s.DB, ratePlan)
+}
+
+func (s *RatePlanServiceImpl) FindByRoomTypeId(roomTypeId int) ([]domain.RatePlan, error) {
+	_, err := s.RoomTypeRepository.FindById(s.DB, roomTypeId)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewCustomError(http.StatusNotFound, "Room type not found")
+		}
+		return nil, err
+	}
+
+	return s.RatePlanRepository.FindByRoomTypeId(s.DB, roomTypeId)
+}
+
+func (s *RatePlanServiceImpl) FindById(id int) (domain.RatePlan, error) {
+	ratePlan, err := s.RatePlanRepository.FindById(s.DB, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ratePlan, exception.NewCustomError(http.StatusNotFound, "Rate plan not found")
+		}
+		return ratePlan, err
+	}
+	return ratePlan, nil
+}
+
+// Update replaces the plan's terms. A plan stays attached to the room type
+// it was created for.
+func (s *RatePlanServiceImpl) Update(ratePlan domain.RatePlan) (domain.RatePlan, error) {
+	existingRatePlan, err := s.RatePlanRepository.FindById(s.DB, ratePlan.ID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ratePlan, exception.NewCustomError(http.StatusNotFound, "Rate plan not found")
+		}
+		return ratePlan, err
+	}
+
+	if err := validateRatePlanDates(ratePlan); err != nil {
+		return ratePlan, err
+	}
+
+	ratePlan.RoomTypeID = existingRatePlan.RoomTypeID
+	ratePlan.CreatedAt = existingRatePlan.CreatedAt
+
+	return s.RatePlanRepository.Update(s.DB, ratePlan)
+}
+
+func (s *RatePlanServiceImpl) Delete(id int) error {
+	_, err := s.RatePlanRepository.FindById(s.DB, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return exception.NewCustomError(http.StatusNotFound, "Rate plan not found")
+		}
+		return err
+	}
+
+	return s.RatePlanRepository.Delete(s.DB, id)
+}
+
+func validateRatePlanDates(ratePlan domain.RatePlan) error {
+	if ratePlan.StartDate != nil && ratePlan.EndDate != nil && ratePlan.EndDate.Before(*ratePlan.StartDate) {
+		return exception.NewCustomError(http.StatusBadRequest, "End date must be on or after start date")
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestRatePlanService_Create_Success(t *testing.T) {
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	service := NewRatePlanService(mockRatePlanRepo, mockRoomTypeRepo, &gorm.DB{})
+
+	ratePlan := domain.RatePlan{
+		RoomTypeID: 1,
+		Name:       "Weekend",
+		Weekdays:   domain.NewWeekdays(time.Friday, time.Saturday),
+		Price:      domain.NewMoney(650000),
+	}
+	expectedRatePlan := ratePlan
+	expectedRatePlan.ID = 1
+
+	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 1).Return(domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}, nil)
+	mockRatePlanRepo.On("Create", &gorm.DB{}, ratePlan).Return(expectedRatePlan, nil)
+
+	result, err := service.Create(ratePlan)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.ID)
+	assert.Equal(t, []time.Weekday{time.Friday, time.Saturday}, result.Weekdays.Days())
+	mockRatePlanRepo.AssertExpectations(t)
+}
+
+func TestRatePlanService_Create_RoomTypeNotFound(t *testing.T) {
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	service := NewRatePlanService(mockRatePlanRepo, mockRoomTypeRepo, &gorm.DB{})
+
+	ratePlan := domain.RatePlan{RoomTypeID: 999, Name: "Weekend", Price: domain.NewMoney(650000)}
+
+	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 999).Return(domain.RoomType{}, gorm.ErrRecordNotFound)
+
+	_, err := service.Create(ratePlan)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Room type not found", customErr.Message)
+	mockRatePlanRepo.AssertNotCalled(t, "Create", &gorm.DB{}, ratePlan)
+}
+
+func TestRatePlanService_Create_EndBeforeStart(t *testing.T) {
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	service := NewRatePlanService(mockRatePlanRepo, mockRoomTypeRepo, &gorm.DB{})
+
+	startDate := time.Date(2030, 12, 31, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	ratePlan := domain.RatePlan{RoomTypeID: 1, Name: "Year end", StartDate: &startDate, EndDate: &endDate, Price: domain.NewMoney(900000)}
+
+	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 1).Return(domain.RoomType{ID: 1}, nil)
+
+	_, err := service.Create(ratePlan)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "End date must be on or after start date", customErr.Message)
+	mockRatePlanRepo.AssertNotCalled(t, "Create", &gorm.DB{}, ratePlan)
+}
+
+func TestRatePlanService_Update_KeepsRoomType(t *testing.T) {
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	service := NewRatePlanService(mockRatePlanRepo, mockRoomTypeRepo, &gorm.DB{})
+
+	existing := domain.RatePlan{ID: 1, RoomTypeID: 3, Name: "Weekend", Price: domain.NewMoney(650000)}
+
+	mockRatePlanRepo.On("FindById", &gorm.DB{}, 1).Return(existing, nil)
+	mockRatePlanRepo.On("Update", &gorm.DB{}, testifymock.MatchedBy(func(r domain.RatePlan) bool {
+		return r.ID == 1 && r.RoomTypeID == 3 && r.Price == domain.NewMoney(700000)
+	})).Return(domain.RatePlan{ID: 1, RoomTypeID: 3, Name: "Weekend", Price: domain.NewMoney(700000)}, nil)
+
+	result, err := service.Update(domain.RatePlan{ID: 1, Name: "Weekend", Price: domain.NewMoney(700000)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.RoomTypeID)
+	assert.Equal(t, domain.NewMoney(700000), result.Price)
+	mockRatePlanRepo.AssertExpectations(t)
+}
+
+func TestRatePlanService_Delete_NotFound(t *testing.T) {
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	service := NewRatePlanService(mockRatePlanRepo, mockRoomTypeRepo, &gorm.DB{})
+
+	mockRatePlanRepo.On("FindById", &gorm.DB{}, 999).Return(domain.RatePlan{}, gorm.ErrRecordNotFound)
+
+	err := service.Delete(999)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Rate plan not found", customErr.Message)
+	mockRatePlanRepo.AssertNotCalled(t, "Delete", &gorm.DB{}, 999)
+}
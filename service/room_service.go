@@ -1,32 +1,39 @@
 package service
 
 import (
+	"errors"
 	"hotel_ip-p2/exception"
 	"hotel_ip-p2/model/domain"
 	"hotel_ip-p2/repository"
 	"net/http"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type RoomService interface {
 	Create(room domain.Room) (domain.Room, error)
-	FindAll() ([]domain.Room, error)
+	FindAll(query domain.ListQuery) ([]domain.Room, int64, error)
 	FindById(id int) (domain.Room, error)
 	Update(room domain.Room) (domain.Room, error)
 	Delete(id int) error
+	FindAvailable(checkIn time.Time, checkOut time.Time, roomTypeId int) ([]domain.RoomAvailability, error)
+	FindDeleted(query domain.ListQuery) ([]domain.Room, int64, error)
+	Restore(id int) (domain.Room, error)
 }
 
 type RoomServiceImpl struct {
 	RoomRepository     repository.RoomRepository
 	RoomTypeRepository repository.RoomTypeRepository
+	RatePlanRepository repository.RatePlanRepository
 	DB                 *gorm.DB
 }
 
-func NewRoomService(roomRepository repository.RoomRepository, roomTypeRepository repository.RoomTypeRepository, db *gorm.DB) RoomService {
+func NewRoomService(roomRepository repository.RoomRepository, roomTypeRepository repository.RoomTypeRepository, ratePlanRepository repository.RatePlanRepository, db *gorm.DB) RoomService {
 	return &RoomServiceImpl{
 		RoomRepository:     roomRepository,
 		RoomTypeRepository: roomTypeRepository,
+		RatePlanRepository: ratePlanRepository,
 		DB:                 db,
 	}
 }
@@ -52,8 +59,12 @@ func (s *RoomServiceImpl) Create(room domain.Room) (domain.Room, error) {
 	return s.RoomRepository.Create(s.DB, room)
 }
 
-func (s *RoomServiceImpl) FindAll() ([]domain.Room, error) {
-	return s.RoomRepository.FindAll(s.DB)
+func (s *RoomServiceImpl) FindAll(query domain.ListQuery) ([]domain.Room, int64, error) {
+	rooms, total, err := s.RoomRepository.FindAll(s.DB, query)
+	if errors.Is(err, repository.ErrInvalidListQuery) {
+		return nil, 0, exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+	return rooms, total, err
 }
 
 func (s *RoomServiceImpl) FindById(id int) (domain.Room, error) {
@@ -95,14 +106,112 @@ func (s *RoomServiceImpl) Update(room domain.Room) (domain.Room, error) {
 	return s.RoomRepository.Update(s.DB, room)
 }
 
+// Delete archives a room. Past bookings keep referring to it, but a room
+// still booked for tonight or later cannot be deleted. The room row stays
+// locked until the room is gone, so a booking made meanwhile either commits
+// before the check or finds the room deleted.
 func (s *RoomServiceImpl) Delete(id int) error {
-	_, err := s.RoomRepository.FindById(s.DB, id)
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		_, err := s.RoomRepository.LockById(tx, id)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exception.NewCustomError(http.StatusNotFound, "Room not found")
+			}
+			return err
+		}
+
+		now := time.Now()
+		upcoming, err := s.RoomRepository.CountNightsFrom(tx, id, stayDay(now, now))
+		if err != nil {
+			return err
+		}
+		if upcoming > 0 {
+			return exception.NewCustomError(http.StatusConflict, "Cannot delete a room with upcoming bookings")
+		}
+
+		return s.RoomRepository.Delete(tx, id)
+	})
+}
+
+func (s *RoomServiceImpl) FindDeleted(query domain.ListQuery) ([]domain.Room, int64, error) {
+	rooms, total, err := s.RoomRepository.FindDeleted(s.DB, query)
+	if errors.Is(err, repository.ErrInvalidListQuery) {
+		return nil, 0, exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+	return rooms, total, err
+}
+
+// Restore puts a deleted room back in use, provided its room type is not
+// deleted and no other room has taken its number in the meantime.
+func (s *RoomServiceImpl) Restore(id int) (domain.Room, error) {
+	room, err := s.RoomRepository.FindDeletedById(s.DB, id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return exception.NewCustomError(http.StatusNotFound, "Room not found")
+			return room, exception.NewCustomError(http.StatusNotFound, "Deleted room not found")
+		}
+		return room, err
+	}
+
+	if room.RoomType.DeletedAt.Valid {
+		return room, exception.NewCustomError(http.StatusBadRequest, "Restore room type "+room.RoomType.Name+" before its rooms")
+	}
+
+	existingRoom, err := s.RoomRepository.FindByRoomNumber(s.DB, room.RoomNumber)
+	if err == nil && existingRoom.ID != 0 {
+		return room, exception.NewCustomError(http.StatusConflict, "Room number already exists")
+	}
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return room, err
+	}
+
+	err = s.RoomRepository.Restore(s.DB, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return room, exception.NewCustomError(http.StatusConflict, "Room number already exists")
+		}
+		return room, err
+	}
+
+	return s.RoomRepository.FindById(s.DB, id)
+}
+
+func (s *RoomServiceImpl) FindAvailable(checkIn time.Time, checkOut time.Time, roomTypeId int) ([]domain.RoomAvailability, error) {
+	if roomTypeId != 0 {
+		_, err := s.RoomTypeRepository.FindById(s.DB, roomTypeId)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, exception.NewCustomError(http.StatusNotFound, "Room type not found")
+			}
+			return nil, err
+		}
+	}
+
+	rooms, err := s.RoomRepository.FindAvailable(s.DB, checkIn, checkOut, roomTypeId)
+	if err != nil {
+		return nil, err
+	}
+
+	var availabilities []domain.RoomAvailability
+	indexByRoomType := make(map[int]int)
+	for _, room := range rooms {
+		index, ok := indexByRoomType[room.RoomTypeID]
+		if !ok {
+			ratePlans, err := s.RatePlanRepository.FindByRoomTypeIdAndDateRange(s.DB, room.RoomTypeID, checkIn, checkOut)
+			if err != nil {
+				return nil, err
+			}
+			quote := domain.QuoteStay(room.RoomType, ratePlans, checkIn, checkOut)
+
+			index = len(availabilities)
+			indexByRoomType[room.RoomTypeID] = index
+			availabilities = append(availabilities, domain.RoomAvailability{
+				RoomType:   room.RoomType,
+				Nights:     len(quote.Nights),
+				TotalPrice: quote.Total,
+			})
 		}
-		return err
+		availabilities[index].Rooms = append(availabilities[index].Rooms, room)
 	}
 
-	return s.RoomRepository.Delete(s.DB, id)
+	return availabilities, nil
 }
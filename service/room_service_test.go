@@ -5,15 +5,18 @@ import (
 	"hotel_ip-p2/model/domain"
 	"hotel_ip-p2/repository/mock"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 )
 
 func TestRoomService_Create_Success(t *testing.T) {
 	mockRoomRepo := new(mock.RoomRepositoryMock)
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
-	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, &gorm.DB{})
 
 	room := domain.Room{
 		RoomTypeID: 1,
@@ -23,7 +26,7 @@ func TestRoomService_Create_Success(t *testing.T) {
 	roomType := domain.RoomType{
 		ID:    1,
 		Name:  "Deluxe",
-		Price: 500000,
+		Price: domain.NewMoney(500000),
 	}
 
 	expectedRoom := domain.Room{
@@ -48,7 +51,8 @@ func TestRoomService_Create_Success(t *testing.T) {
 func TestRoomService_Create_RoomTypeNotFound(t *testing.T) {
 	mockRoomRepo := new(mock.RoomRepositoryMock)
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
-	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, &gorm.DB{})
 
 	room := domain.Room{
 		RoomTypeID: 999,
@@ -69,14 +73,15 @@ func TestRoomService_Create_RoomTypeNotFound(t *testing.T) {
 func TestRoomService_Create_DuplicateRoomNumber(t *testing.T) {
 	mockRoomRepo := new(mock.RoomRepositoryMock)
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
-	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, &gorm.DB{})
 
 	room := domain.Room{
 		RoomTypeID: 1,
 		RoomNumber: "101",
 	}
 
-	roomType := domain.RoomType{ID: 1, Name: "Deluxe", Price: 500000}
+	roomType := domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}
 	existingRoom := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101"}
 
 	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 1).Return(roomType, nil)
@@ -95,26 +100,35 @@ func TestRoomService_Create_DuplicateRoomNumber(t *testing.T) {
 func TestRoomService_FindAll_Success(t *testing.T) {
 	mockRoomRepo := new(mock.RoomRepositoryMock)
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
-	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, &gorm.DB{})
 
 	expectedRooms := []domain.Room{
 		{ID: 1, RoomTypeID: 1, RoomNumber: "101"},
 		{ID: 2, RoomTypeID: 1, RoomNumber: "102"},
 	}
 
-	mockRoomRepo.On("FindAll", &gorm.DB{}).Return(expectedRooms, nil)
+	query := domain.ListQuery{
+		Pagination: domain.NewPagination(1, 20),
+		Sort:       []domain.SortField{{Field: "room_number", Desc: true}},
+		Filters:    map[string]string{"room_type_id": "1"},
+	}
+
+	mockRoomRepo.On("FindAll", &gorm.DB{}, query).Return(expectedRooms, int64(2), nil)
 
-	result, err := service.FindAll()
+	result, total, err := service.FindAll(query)
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
+	assert.Equal(t, int64(2), total)
 	mockRoomRepo.AssertExpectations(t)
 }
 
 func TestRoomService_FindById_Success(t *testing.T) {
 	mockRoomRepo := new(mock.RoomRepositoryMock)
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
-	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, &gorm.DB{})
 
 	expectedRoom := domain.Room{
 		ID:         1,
@@ -134,7 +148,8 @@ func TestRoomService_FindById_Success(t *testing.T) {
 func TestRoomService_FindById_NotFound(t *testing.T) {
 	mockRoomRepo := new(mock.RoomRepositoryMock)
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
-	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, &gorm.DB{})
 
 	mockRoomRepo.On("FindById", &gorm.DB{}, 999).Return(domain.Room{}, gorm.ErrRecordNotFound)
 
@@ -150,7 +165,8 @@ func TestRoomService_FindById_NotFound(t *testing.T) {
 func TestRoomService_Update_Success(t *testing.T) {
 	mockRoomRepo := new(mock.RoomRepositoryMock)
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
-	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, &gorm.DB{})
 
 	room := domain.Room{
 		ID:         1,
@@ -159,7 +175,7 @@ func TestRoomService_Update_Success(t *testing.T) {
 	}
 
 	existingRoom := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101"}
-	roomType := domain.RoomType{ID: 1, Name: "Deluxe", Price: 500000}
+	roomType := domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}
 
 	mockRoomRepo.On("FindById", &gorm.DB{}, 1).Return(existingRoom, nil)
 	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 1).Return(roomType, nil)
@@ -177,7 +193,8 @@ func TestRoomService_Update_Success(t *testing.T) {
 func TestRoomService_Update_NotFound(t *testing.T) {
 	mockRoomRepo := new(mock.RoomRepositoryMock)
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
-	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, &gorm.DB{})
 
 	room := domain.Room{
 		ID:         999,
@@ -199,25 +216,122 @@ func TestRoomService_Update_NotFound(t *testing.T) {
 func TestRoomService_Delete_Success(t *testing.T) {
 	mockRoomRepo := new(mock.RoomRepositoryMock)
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
-	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, db)
 
 	existingRoom := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101"}
 
-	mockRoomRepo.On("FindById", &gorm.DB{}, 1).Return(existingRoom, nil)
-	mockRoomRepo.On("Delete", &gorm.DB{}, 1).Return(nil)
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(existingRoom, nil)
+	mockRoomRepo.On("CountNightsFrom", testifymock.Anything, 1, testifymock.Anything).Return(int64(0), nil)
+	mockRoomRepo.On("Delete", testifymock.Anything, 1).Return(nil)
+	sqlMock.ExpectCommit()
+
+	err := service.Delete(1)
+
+	assert.NoError(t, err)
+	mockRoomRepo.AssertExpectations(t)
+}
+
+func TestRoomService_Delete_UpcomingBookings(t *testing.T) {
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, db)
+
+	existingRoom := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101"}
+
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 1).Return(existingRoom, nil)
+	mockRoomRepo.On("CountNightsFrom", testifymock.Anything, 1, startOfDay(time.Now())).Return(int64(2), nil)
+	sqlMock.ExpectRollback()
 
 	err := service.Delete(1)
 
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, 409, customErr.Code)
+	assert.Equal(t, "Cannot delete a room with upcoming bookings", customErr.Message)
+	mockRoomRepo.AssertNotCalled(t, "Delete", testifymock.Anything, testifymock.Anything)
+}
+
+func TestRoomService_Restore_Success(t *testing.T) {
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, &gorm.DB{})
+
+	roomType := domain.RoomType{ID: 1, Name: "Deluxe"}
+	deletedRoom := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: roomType, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}
+	restoredRoom := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: roomType}
+
+	mockRoomRepo.On("FindDeletedById", &gorm.DB{}, 1).Return(deletedRoom, nil)
+	mockRoomRepo.On("FindByRoomNumber", &gorm.DB{}, "101").Return(domain.Room{}, gorm.ErrRecordNotFound)
+	mockRoomRepo.On("Restore", &gorm.DB{}, 1).Return(nil)
+	mockRoomRepo.On("FindById", &gorm.DB{}, 1).Return(restoredRoom, nil)
+
+	result, err := service.Restore(1)
+
 	assert.NoError(t, err)
+	assert.Equal(t, restoredRoom, result)
 	mockRoomRepo.AssertExpectations(t)
 }
 
+func TestRoomService_Restore_RoomNumberTaken(t *testing.T) {
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, &gorm.DB{})
+
+	deletedRoom := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1}, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}
+
+	mockRoomRepo.On("FindDeletedById", &gorm.DB{}, 1).Return(deletedRoom, nil)
+	mockRoomRepo.On("FindByRoomNumber", &gorm.DB{}, "101").Return(domain.Room{ID: 7, RoomNumber: "101"}, nil)
+
+	_, err := service.Restore(1)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, 409, customErr.Code)
+	assert.Equal(t, "Room number already exists", customErr.Message)
+	mockRoomRepo.AssertNotCalled(t, "Restore", testifymock.Anything, testifymock.Anything)
+}
+
+func TestRoomService_Restore_RoomTypeDeleted(t *testing.T) {
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, &gorm.DB{})
+
+	deleted := gorm.DeletedAt{Time: time.Now(), Valid: true}
+	deletedRoom := domain.Room{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: domain.RoomType{ID: 1, Name: "Deluxe", DeletedAt: deleted}, DeletedAt: deleted}
+
+	mockRoomRepo.On("FindDeletedById", &gorm.DB{}, 1).Return(deletedRoom, nil)
+
+	_, err := service.Restore(1)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, 400, customErr.Code)
+	assert.Equal(t, "Restore room type Deluxe before its rooms", customErr.Message)
+	mockRoomRepo.AssertNotCalled(t, "Restore", testifymock.Anything, testifymock.Anything)
+}
+
 func TestRoomService_Delete_NotFound(t *testing.T) {
 	mockRoomRepo := new(mock.RoomRepositoryMock)
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
-	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, db)
 
-	mockRoomRepo.On("FindById", &gorm.DB{}, 999).Return(domain.Room{}, gorm.ErrRecordNotFound)
+	sqlMock.ExpectBegin()
+	mockRoomRepo.On("LockById", testifymock.Anything, 999).Return(domain.Room{}, gorm.ErrRecordNotFound)
+	sqlMock.ExpectRollback()
 
 	err := service.Delete(999)
 
@@ -227,3 +341,64 @@ func TestRoomService_Delete_NotFound(t *testing.T) {
 	assert.Equal(t, "Room not found", customErr.Message)
 	mockRoomRepo.AssertExpectations(t)
 }
+
+func TestRoomService_FindAvailable_GroupsByRoomType(t *testing.T) {
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, &gorm.DB{})
+
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 3)
+
+	deluxe := domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}
+	suite := domain.RoomType{ID: 2, Name: "Suite", Price: domain.NewMoney(1000000)}
+
+	rooms := []domain.Room{
+		{ID: 1, RoomTypeID: 1, RoomNumber: "101", RoomType: deluxe},
+		{ID: 2, RoomTypeID: 1, RoomNumber: "102", RoomType: deluxe},
+		{ID: 3, RoomTypeID: 2, RoomNumber: "201", RoomType: suite},
+	}
+
+	// Thursday to Sunday, with the suite charging more on Friday and Saturday.
+	weekend := domain.RatePlan{ID: 1, RoomTypeID: 2, Name: "Weekend", Weekdays: domain.NewWeekdays(time.Friday, time.Saturday), Price: domain.NewMoney(1200000)}
+
+	mockRoomRepo.On("FindAvailable", &gorm.DB{}, checkIn, checkOut, 0).Return(rooms, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", &gorm.DB{}, 1, checkIn, checkOut).Return([]domain.RatePlan{}, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", &gorm.DB{}, 2, checkIn, checkOut).Return([]domain.RatePlan{weekend}, nil)
+
+	result, err := service.FindAvailable(checkIn, checkOut, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "Deluxe", result[0].RoomType.Name)
+	assert.Len(t, result[0].Rooms, 2)
+	assert.Equal(t, 3, result[0].Nights)
+	assert.Equal(t, domain.NewMoney(1500000), result[0].TotalPrice)
+	assert.Equal(t, "Suite", result[1].RoomType.Name)
+	assert.Len(t, result[1].Rooms, 1)
+	assert.Equal(t, domain.NewMoney(3400000), result[1].TotalPrice)
+	mockRoomRepo.AssertExpectations(t)
+	mockRatePlanRepo.AssertExpectations(t)
+	mockRoomTypeRepo.AssertNotCalled(t, "FindById")
+}
+
+func TestRoomService_FindAvailable_RoomTypeNotFound(t *testing.T) {
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomService(mockRoomRepo, mockRoomTypeRepo, mockRatePlanRepo, &gorm.DB{})
+
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 1)
+
+	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 999).Return(domain.RoomType{}, gorm.ErrRecordNotFound)
+
+	_, err := service.FindAvailable(checkIn, checkOut, 999)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Room type not found", customErr.Message)
+	mockRoomRepo.AssertNotCalled(t, "FindAvailable")
+}
@@ -1,32 +1,39 @@
 package service
 
 import (
+	"errors"
 	"hotel_ip-p2/exception"
 	"hotel_ip-p2/model/domain"
 	"hotel_ip-p2/repository"
 	"net/http"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type RoomTypeService interface {
 	Create(roomType domain.RoomType) (domain.RoomType, error)
-	FindAll() ([]domain.RoomType, error)
+	FindAll(query domain.ListQuery) ([]domain.RoomType, int64, error)
 	FindById(id int) (domain.RoomType, error)
 	Update(roomType domain.RoomType) (domain.RoomType, error)
 	Delete(id int) error
+	Quote(id int, checkIn time.Time, checkOut time.Time) (domain.RateQuote, error)
+	FindDeleted(query domain.ListQuery) ([]domain.RoomType, int64, error)
+	Restore(id int) (domain.RoomType, error)
 }
 
 type RoomTypeServiceImpl struct {
 	RoomTypeRepository repository.RoomTypeRepository
 	RoomRepository     repository.RoomRepository
+	RatePlanRepository repository.RatePlanRepository
 	DB                 *gorm.DB
 }
 
-func NewRoomTypeService(roomTypeRepository repository.RoomTypeRepository, roomRepository repository.RoomRepository, db *gorm.DB) RoomTypeService {
+func NewRoomTypeService(roomTypeRepository repository.RoomTypeRepository, roomRepository repository.RoomRepository, ratePlanRepository repository.RatePlanRepository, db *gorm.DB) RoomTypeService {
 	return &RoomTypeServiceImpl{
 		RoomTypeRepository: roomTypeRepository,
 		RoomRepository:     roomRepository,
+		RatePlanRepository: ratePlanRepository,
 		DB:                 db,
 	}
 }
@@ -43,8 +50,12 @@ func (s *RoomTypeServiceImpl) Create(roomType domain.RoomType) (domain.RoomType,
 	return s.RoomTypeRepository.Create(s.DB, roomType)
 }
 
-func (s *RoomTypeServiceImpl) FindAll() ([]domain.RoomType, error) {
-	return s.RoomTypeRepository.FindAll(s.DB)
+func (s *RoomTypeServiceImpl) FindAll(query domain.ListQuery) ([]domain.RoomType, int64, error) {
+	roomTypes, total, err := s.RoomTypeRepository.FindAll(s.DB, query)
+	if errors.Is(err, repository.ErrInvalidListQuery) {
+		return nil, 0, exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+	return roomTypes, total, err
 }
 
 func (s *RoomTypeServiceImpl) FindById(id int) (domain.RoomType, error) {
@@ -98,3 +109,61 @@ func (s *RoomTypeServiceImpl) Delete(id int) error {
 
 	return s.RoomTypeRepository.Delete(s.DB, id)
 }
+
+func (s *RoomTypeServiceImpl) FindDeleted(query domain.ListQuery) ([]domain.RoomType, int64, error) {
+	roomTypes, total, err := s.RoomTypeRepository.FindDeleted(s.DB, query)
+	if errors.Is(err, repository.ErrInvalidListQuery) {
+		return nil, 0, exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+	return roomTypes, total, err
+}
+
+// Restore puts a deleted room type back in use unless another room type has
+// taken its name in the meantime. Its rooms stay deleted until restored one
+// by one.
+func (s *RoomTypeServiceImpl) Restore(id int) (domain.RoomType, error) {
+	roomType, err := s.RoomTypeRepository.FindDeletedById(s.DB, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return roomType, exception.NewCustomError(http.StatusNotFound, "Deleted room type not found")
+		}
+		return roomType, err
+	}
+
+	existingRoomType, err := s.RoomTypeRepository.FindByName(s.DB, roomType.Name)
+	if err == nil && existingRoomType.ID != 0 {
+		return roomType, exception.NewCustomError(http.StatusConflict, "Room type name already exists")
+	}
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return roomType, err
+	}
+
+	err = s.RoomTypeRepository.Restore(s.DB, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return roomType, exception.NewCustomError(http.StatusConflict, "Room type name already exists")
+		}
+		return roomType, err
+	}
+
+	roomType.DeletedAt = gorm.DeletedAt{}
+	return roomType, nil
+}
+
+// Quote prices a stay in a room type night by night from its rate plans.
+func (s *RoomTypeServiceImpl) Quote(id int, checkIn time.Time, checkOut time.Time) (domain.RateQuote, error) {
+	roomType, err := s.RoomTypeRepository.FindById(s.DB, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return domain.RateQuote{}, exception.NewCustomError(http.StatusNotFound, "Room type not found")
+		}
+		return domain.RateQuote{}, err
+	}
+
+	ratePlans, err := s.RatePlanRepository.FindByRoomTypeIdAndDateRange(s.DB, id, checkIn, checkOut)
+	if err != nil {
+		return domain.RateQuote{}, err
+	}
+
+	return domain.QuoteStay(roomType, ratePlans, checkIn, checkOut), nil
+}
@@ -5,6 +5,7 @@ import (
 	"hotel_ip-p2/model/domain"
 	"hotel_ip-p2/repository/mock"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
@@ -13,17 +14,18 @@ import (
 func TestRoomTypeService_Create_Success(t *testing.T) {
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
-	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, mockRatePlanRepo, &gorm.DB{})
 
 	roomType := domain.RoomType{
 		Name:  "Deluxe",
-		Price: 500000,
+		Price: domain.NewMoney(500000),
 	}
 
 	expectedRoomType := domain.RoomType{
 		ID:    1,
 		Name:  "Deluxe",
-		Price: 500000,
+		Price: domain.NewMoney(500000),
 	}
 
 	mockRoomTypeRepo.On("FindByName", &gorm.DB{}, "Deluxe").Return(domain.RoomType{}, gorm.ErrRecordNotFound)
@@ -41,17 +43,18 @@ func TestRoomTypeService_Create_Success(t *testing.T) {
 func TestRoomTypeService_Create_DuplicateName(t *testing.T) {
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
-	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, mockRatePlanRepo, &gorm.DB{})
 
 	roomType := domain.RoomType{
 		Name:  "Deluxe",
-		Price: 500000,
+		Price: domain.NewMoney(500000),
 	}
 
 	existingRoomType := domain.RoomType{
 		ID:    1,
 		Name:  "Deluxe",
-		Price: 450000,
+		Price: domain.NewMoney(450000),
 	}
 
 	mockRoomTypeRepo.On("FindByName", &gorm.DB{}, "Deluxe").Return(existingRoomType, nil)
@@ -68,19 +71,23 @@ func TestRoomTypeService_Create_DuplicateName(t *testing.T) {
 func TestRoomTypeService_FindAll_Success(t *testing.T) {
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
-	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, mockRatePlanRepo, &gorm.DB{})
 
 	expectedRoomTypes := []domain.RoomType{
-		{ID: 1, Name: "Standard", Price: 300000},
-		{ID: 2, Name: "Deluxe", Price: 500000},
+		{ID: 1, Name: "Standard", Price: domain.NewMoney(300000)},
+		{ID: 2, Name: "Deluxe", Price: domain.NewMoney(500000)},
 	}
 
-	mockRoomTypeRepo.On("FindAll", &gorm.DB{}).Return(expectedRoomTypes, nil)
+	query := domain.ListQuery{Pagination: domain.NewPagination(2, 2)}
 
-	result, err := service.FindAll()
+	mockRoomTypeRepo.On("FindAll", &gorm.DB{}, query).Return(expectedRoomTypes, int64(4), nil)
+
+	result, total, err := service.FindAll(query)
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
+	assert.Equal(t, int64(4), total)
 	assert.Equal(t, expectedRoomTypes[0].Name, result[0].Name)
 	mockRoomTypeRepo.AssertExpectations(t)
 }
@@ -88,12 +95,13 @@ func TestRoomTypeService_FindAll_Success(t *testing.T) {
 func TestRoomTypeService_FindById_Success(t *testing.T) {
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
-	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, mockRatePlanRepo, &gorm.DB{})
 
 	expectedRoomType := domain.RoomType{
 		ID:    1,
 		Name:  "Deluxe",
-		Price: 500000,
+		Price: domain.NewMoney(500000),
 	}
 
 	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 1).Return(expectedRoomType, nil)
@@ -109,7 +117,8 @@ func TestRoomTypeService_FindById_Success(t *testing.T) {
 func TestRoomTypeService_FindById_NotFound(t *testing.T) {
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
-	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, mockRatePlanRepo, &gorm.DB{})
 
 	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 999).Return(domain.RoomType{}, gorm.ErrRecordNotFound)
 
@@ -125,18 +134,19 @@ func TestRoomTypeService_FindById_NotFound(t *testing.T) {
 func TestRoomTypeService_Update_Success(t *testing.T) {
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
-	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, mockRatePlanRepo, &gorm.DB{})
 
 	roomType := domain.RoomType{
 		ID:    1,
 		Name:  "Deluxe Updated",
-		Price: 550000,
+		Price: domain.NewMoney(550000),
 	}
 
 	existingRoomType := domain.RoomType{
 		ID:    1,
 		Name:  "Deluxe",
-		Price: 500000,
+		Price: domain.NewMoney(500000),
 	}
 
 	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 1).Return(existingRoomType, nil)
@@ -154,12 +164,13 @@ func TestRoomTypeService_Update_Success(t *testing.T) {
 func TestRoomTypeService_Update_NotFound(t *testing.T) {
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
-	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, mockRatePlanRepo, &gorm.DB{})
 
 	roomType := domain.RoomType{
 		ID:    999,
 		Name:  "Deluxe",
-		Price: 500000,
+		Price: domain.NewMoney(500000),
 	}
 
 	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 999).Return(domain.RoomType{}, gorm.ErrRecordNotFound)
@@ -176,12 +187,13 @@ func TestRoomTypeService_Update_NotFound(t *testing.T) {
 func TestRoomTypeService_Delete_Success(t *testing.T) {
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
-	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, mockRatePlanRepo, &gorm.DB{})
 
 	existingRoomType := domain.RoomType{
 		ID:    1,
 		Name:  "Deluxe",
-		Price: 500000,
+		Price: domain.NewMoney(500000),
 	}
 
 	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 1).Return(existingRoomType, nil)
@@ -198,12 +210,13 @@ func TestRoomTypeService_Delete_Success(t *testing.T) {
 func TestRoomTypeService_Delete_HasRooms(t *testing.T) {
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
-	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, mockRatePlanRepo, &gorm.DB{})
 
 	existingRoomType := domain.RoomType{
 		ID:    1,
 		Name:  "Deluxe",
-		Price: 500000,
+		Price: domain.NewMoney(500000),
 	}
 
 	rooms := []domain.Room{
@@ -226,7 +239,8 @@ func TestRoomTypeService_Delete_HasRooms(t *testing.T) {
 func TestRoomTypeService_Delete_NotFound(t *testing.T) {
 	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
 	mockRoomRepo := new(mock.RoomRepositoryMock)
-	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, &gorm.DB{})
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, mockRatePlanRepo, &gorm.DB{})
 
 	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 999).Return(domain.RoomType{}, gorm.ErrRecordNotFound)
 
@@ -238,3 +252,90 @@ func TestRoomTypeService_Delete_NotFound(t *testing.T) {
 	assert.Equal(t, "Room type not found", customErr.Message)
 	mockRoomTypeRepo.AssertExpectations(t)
 }
+
+func TestRoomTypeService_Restore_Success(t *testing.T) {
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, mockRatePlanRepo, &gorm.DB{})
+
+	deletedRoomType := domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000), DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}
+
+	mockRoomTypeRepo.On("FindDeletedById", &gorm.DB{}, 1).Return(deletedRoomType, nil)
+	mockRoomTypeRepo.On("FindByName", &gorm.DB{}, "Deluxe").Return(domain.RoomType{}, gorm.ErrRecordNotFound)
+	mockRoomTypeRepo.On("Restore", &gorm.DB{}, 1).Return(nil)
+
+	result, err := service.Restore(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}, result)
+	mockRoomTypeRepo.AssertExpectations(t)
+}
+
+func TestRoomTypeService_Restore_NotDeleted(t *testing.T) {
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, mockRatePlanRepo, &gorm.DB{})
+
+	mockRoomTypeRepo.On("FindDeletedById", &gorm.DB{}, 1).Return(domain.RoomType{}, gorm.ErrRecordNotFound)
+
+	_, err := service.Restore(1)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, 404, customErr.Code)
+	assert.Equal(t, "Deleted room type not found", customErr.Message)
+}
+
+func TestRoomTypeService_Quote_Success(t *testing.T) {
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, mockRatePlanRepo, &gorm.DB{})
+
+	// Thursday 2030-01-10 to Sunday, with high season starting on Saturday.
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 3)
+	highSeasonStart := checkIn.AddDate(0, 0, 2)
+
+	roomType := domain.RoomType{ID: 1, Name: "Deluxe", Price: domain.NewMoney(500000)}
+	ratePlans := []domain.RatePlan{
+		{ID: 2, RoomTypeID: 1, Name: "High season", StartDate: &highSeasonStart, Price: domain.NewMoney(800000), Priority: 5},
+		{ID: 1, RoomTypeID: 1, Name: "Weekend", Weekdays: domain.NewWeekdays(time.Friday, time.Saturday), Price: domain.NewMoney(600000)},
+	}
+
+	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 1).Return(roomType, nil)
+	mockRatePlanRepo.On("FindByRoomTypeIdAndDateRange", &gorm.DB{}, 1, checkIn, checkOut).Return(ratePlans, nil)
+
+	result, err := service.Quote(1, checkIn, checkOut)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Nights, 3)
+	assert.Equal(t, domain.NewMoney(500000), result.Nights[0].Price)
+	assert.Nil(t, result.Nights[0].RatePlan)
+	assert.Equal(t, "Weekend", result.Nights[1].RatePlan.Name)
+	assert.Equal(t, "High season", result.Nights[2].RatePlan.Name)
+	assert.Equal(t, domain.NewMoney(1900000), result.Total)
+	mockRatePlanRepo.AssertExpectations(t)
+}
+
+func TestRoomTypeService_Quote_NotFound(t *testing.T) {
+	mockRoomTypeRepo := new(mock.RoomTypeRepositoryMock)
+	mockRoomRepo := new(mock.RoomRepositoryMock)
+	mockRatePlanRepo := new(mock.RatePlanRepositoryMock)
+	service := NewRoomTypeService(mockRoomTypeRepo, mockRoomRepo, mockRatePlanRepo, &gorm.DB{})
+
+	checkIn := time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	mockRoomTypeRepo.On("FindById", &gorm.DB{}, 999).Return(domain.RoomType{}, gorm.ErrRecordNotFound)
+
+	_, err := service.Quote(999, checkIn, checkIn.AddDate(0, 0, 1))
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Room type not found", customErr.Message)
+	mockRatePlanRepo.AssertNotCalled(t, "FindByRoomTypeIdAndDateRange", &gorm.DB{}, 999, checkIn, checkIn.AddDate(0, 0, 1))
+}
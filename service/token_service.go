@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/helper"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository"
+	"log"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TokenService interface {
+	Issue(user domain.User) (domain.TokenPair, error)
+	Refresh(refreshToken string) (domain.TokenPair, error)
+	Logout(userId int, sessionId string) error
+	LogoutAll(userId int) error
+	LogoutOthers(userId int, sessionId string) error
+	SyncDenylist(ctx context.Context) error
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type TokenServiceImpl struct {
+	RefreshTokenRepository repository.RefreshTokenRepository
+	RevokedTokenRepository repository.RevokedTokenRepository
+	UserRepository         repository.UserRepository
+	TokenIssuer            helper.TokenIssuer
+	Denylist               *helper.TokenDenylist
+	RefreshTTL             time.Duration
+	DB                     *gorm.DB
+}
+
+func NewTokenService(refreshTokenRepository repository.RefreshTokenRepository, revokedTokenRepository repository.RevokedTokenRepository, userRepository repository.UserRepository, tokenIssuer helper.TokenIssuer, denylist *helper.TokenDenylist, refreshTTL time.Duration, db *gorm.DB) TokenService {
+	return &TokenServiceImpl{
+		RefreshTokenRepository: refreshTokenRepository,
+		RevokedTokenRepository: revokedTokenRepository,
+		UserRepository:         userRepository,
+		TokenIssuer:            tokenIssuer,
+		Denylist:               denylist,
+		RefreshTTL:             refreshTTL,
+		DB:                     db,
+	}
+}
+
+// Issue starts a new session for a user who has just logged in.
+func (s *TokenServiceImpl) Issue(user domain.User) (domain.TokenPair, error) {
+	familyId, err := helper.RandomToken(16)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+	return s.issue(s.DB, user, familyId, time.Now())
+}
+
+func (s *TokenServiceImpl) issue(db *gorm.DB, user domain.User, familyId string, now time.Time) (domain.TokenPair, error) {
+	accessToken, err := s.TokenIssuer.Issue(user.ID, user.Role, familyId)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	refreshToken, err := helper.RandomToken(32)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	expiresAt := now.Add(s.RefreshTTL)
+	_, err = s.RefreshTokenRepository.Create(db, domain.RefreshToken{
+		UserID:          user.ID,
+		FamilyID:        familyId,
+		TokenHash:       hashToken(refreshToken),
+		AccessTokenJTI:  accessToken.JTI,
+		AccessExpiresAt: accessToken.ExpiresAt,
+		ExpiresAt:       expiresAt,
+	})
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	return domain.TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: expiresAt,
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair in the same
+// session. Each refresh token can be exchanged once. Presenting one a second
+// time means someone else holds a copy, so the whole session is revoked.
+func (s *TokenServiceImpl) Refresh(refreshToken string) (domain.TokenPair, error) {
+	var pair domain.TokenPair
+	var revoked []domain.RevokedToken
+	reused := false
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		token, err := s.RefreshTokenRepository.LockByHash(tx, hashToken(refreshToken))
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exception.NewCustomError(http.StatusUnauthorized, "Invalid refresh token")
+			}
+			return err
+		}
+
+		now := time.Now()
+		if token.UsedAt != nil {
+			log.Printf("Refresh token ID: %d of user ID: %d was reused, revoking session", token.ID, token.UserID)
+			reused = true
+			tokens, err := s.RefreshTokenRepository.FindByFamilyId(tx, token.FamilyID)
+			if err != nil {
+				return err
+			}
+			revoked, err = s.revoke(tx, tokens, now)
+			return err
+		}
+
+		if token.RevokedAt != nil || !now.Before(token.ExpiresAt) {
+			return exception.NewCustomError(http.StatusUnauthorized, "Refresh token has expired or been revoked")
+		}
+
+		user, err := s.UserRepository.FindById(tx, token.UserID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exception.NewCustomError(http.StatusUnauthorized, "Invalid refresh token")
+			}
+			return err
+		}
+
+		err = s.RefreshTokenRepository.MarkUsed(tx, token.ID, now)
+		if err != nil {
+			return err
+		}
+
+		pair, err = s.issue(tx, user, token.FamilyID, now)
+		return err
+	})
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	s.deny(revoked)
+	if reused {
+		return domain.TokenPair{}, exception.NewCustomError(http.StatusUnauthorized, "Refresh token was already used; the session has been revoked")
+	}
+	return pair, nil
+}
+
+// Logout ends one session of a user: its refresh tokens stop working and its
+// access tokens are denylisted.
+func (s *TokenServiceImpl) Logout(userId int, sessionId string) error {
+	var revoked []domain.RevokedToken
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		tokens, err := s.RefreshTokenRepository.FindByFamilyId(tx, sessionId)
+		if err != nil {
+			return err
+		}
+
+		var owned []domain.RefreshToken
+		for _, token := range tokens {
+			if token.UserID == userId {
+				owned = append(owned, token)
+			}
+		}
+
+		revoked, err = s.revoke(tx, owned, time.Now())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deny(revoked)
+	return nil
+}
+
+// LogoutAll ends every session of a user.
+func (s *TokenServiceImpl) LogoutAll(userId int) error {
+	var revoked []domain.RevokedToken
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		tokens, err := s.RefreshTokenRepository.FindLiveByUserId(tx, userId, now)
+		if err != nil {
+			return err
+		}
+
+		revoked, err = s.revoke(tx, tokens, now)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deny(revoked)
+	return nil
+}
+
+// LogoutOthers ends every session of a user except sessionId, the one the
+// request came from.
+func (s *TokenServiceImpl) LogoutOthers(userId int, sessionId string) error {
+	var revoked []domain.RevokedToken
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		tokens, err := s.RefreshTokenRepository.FindLiveByUserId(tx, userId, now)
+		if err != nil {
+			return err
+		}
+
+		var others []domain.RefreshToken
+		for _, token := range tokens {
+			if token.FamilyID != sessionId {
+				others = append(others, token)
+			}
+		}
+
+		revoked, err = s.revoke(tx, others, now)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deny(revoked)
+	return nil
+}
+
+// revoke revokes refresh tokens and denylists the access tokens issued with
+// them that are still valid. It returns the new denylist entries.
+func (s *TokenServiceImpl) revoke(tx *gorm.DB, tokens []domain.RefreshToken, now time.Time) ([]domain.RevokedToken, error) {
+	var ids []int
+	var revoked []domain.RevokedToken
+	for _, token := range tokens {
+		ids = append(ids, token.ID)
+		if token.AccessExpiresAt.After(now) {
+			revoked = append(revoked, domain.RevokedToken{
+				JTI:       token.AccessTokenJTI,
+				UserID:    token.UserID,
+				ExpiresAt: token.AccessExpiresAt,
+			})
+		}
+	}
+
+	err := s.RefreshTokenRepository.Revoke(tx, ids, now)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.RevokedTokenRepository.Create(tx, revoked)
+	if err != nil {
+		return nil, err
+	}
+	return revoked, nil
+}
+
+// deny adds committed denylist entries to this instance's denylist straight
+// away. Other instances pick them up on their next SyncDenylist.
+func (s *TokenServiceImpl) deny(revoked []domain.RevokedToken) {
+	for _, token := range revoked {
+		s.Denylist.Add(token.JTI, token.ExpiresAt)
+	}
+}
+
+// SyncDenylist loads the access tokens revoked by any instance into this
+// instance's denylist and forgets the ones that have expired.
+func (s *TokenServiceImpl) SyncDenylist(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	revoked, err := s.RevokedTokenRepository.FindUnexpired(s.DB, now)
+	if err != nil {
+		return err
+	}
+
+	s.deny(revoked)
+	s.Denylist.Prune(now)
+	return nil
+}
+
+// PurgeExpired deletes refresh tokens and denylist entries that can no
+// longer match a valid token.
+func (s *TokenServiceImpl) PurgeExpired(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	refreshTokens, err := s.RefreshTokenRepository.DeleteExpired(s.DB, now)
+	if err != nil {
+		return 0, err
+	}
+
+	revokedTokens, err := s.RevokedTokenRepository.DeleteExpired(s.DB, now)
+	if err != nil {
+		return refreshTokens, err
+	}
+
+	return refreshTokens + revokedTokens, nil
+}
+
+// hashToken returns the hash under which a refresh or mailed token is
+// stored, so a leaked database does not leak usable tokens.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/helper"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var testTokenIssuer = helper.NewTokenIssuer("test-secret", 15*time.Minute)
+
+func TestTokenService_Refresh_RotatesToken(t *testing.T) {
+	mockRefreshTokenRepo := new(mock.RefreshTokenRepositoryMock)
+	mockRevokedTokenRepo := new(mock.RevokedTokenRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewTokenService(mockRefreshTokenRepo, mockRevokedTokenRepo, mockUserRepo, testTokenIssuer, helper.NewTokenDenylist(), 30*24*time.Hour, db)
+
+	token := domain.RefreshToken{ID: 1, UserID: 2, FamilyID: "session-1", ExpiresAt: time.Now().Add(time.Hour)}
+	user := domain.User{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Role: domain.RoleGuest}
+
+	sqlMock.ExpectBegin()
+	mockRefreshTokenRepo.On("LockByHash", testifymock.Anything, hashToken("old-token")).Return(token, nil)
+	mockUserRepo.On("FindById", testifymock.Anything, 2).Return(user, nil)
+	mockRefreshTokenRepo.On("MarkUsed", testifymock.Anything, 1, testifymock.Anything).Return(nil)
+	mockRefreshTokenRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(r domain.RefreshToken) bool {
+		return r.UserID == 2 && r.FamilyID == "session-1" && r.TokenHash != hashToken("old-token") && r.AccessTokenJTI != ""
+	})).Return(domain.RefreshToken{ID: 3}, nil)
+	sqlMock.ExpectCommit()
+
+	pair, err := service.Refresh("old-token")
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, "old-token", pair.RefreshToken)
+	assert.NotEmpty(t, pair.AccessToken.Token)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mockRefreshTokenRepo.AssertExpectations(t)
+}
+
+func TestTokenService_Refresh_ReuseRevokesSession(t *testing.T) {
+	mockRefreshTokenRepo := new(mock.RefreshTokenRepositoryMock)
+	mockRevokedTokenRepo := new(mock.RevokedTokenRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	denylist := helper.NewTokenDenylist()
+	service := NewTokenService(mockRefreshTokenRepo, mockRevokedTokenRepo, mockUserRepo, testTokenIssuer, denylist, 30*24*time.Hour, db)
+
+	now := time.Now()
+	usedAt := now.Add(-time.Hour)
+	reused := domain.RefreshToken{ID: 1, UserID: 2, FamilyID: "session-1", AccessTokenJTI: "jti-1", AccessExpiresAt: now.Add(-45 * time.Minute), ExpiresAt: now.Add(time.Hour), UsedAt: &usedAt}
+	latest := domain.RefreshToken{ID: 2, UserID: 2, FamilyID: "session-1", AccessTokenJTI: "jti-2", AccessExpiresAt: now.Add(10 * time.Minute), ExpiresAt: now.Add(time.Hour)}
+
+	sqlMock.ExpectBegin()
+	mockRefreshTokenRepo.On("LockByHash", testifymock.Anything, hashToken("stolen-token")).Return(reused, nil)
+	mockRefreshTokenRepo.On("FindByFamilyId", testifymock.Anything, "session-1").Return([]domain.RefreshToken{reused, latest}, nil)
+	mockRefreshTokenRepo.On("Revoke", testifymock.Anything, []int{1, 2}, testifymock.Anything).Return(nil)
+	mockRevokedTokenRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(tokens []domain.RevokedToken) bool {
+		return len(tokens) == 1 && tokens[0].JTI == "jti-2" && tokens[0].UserID == 2
+	})).Return(nil)
+	sqlMock.ExpectCommit()
+
+	_, err := service.Refresh("stolen-token")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, 401, customErr.Code)
+	assert.True(t, denylist.Contains("jti-2"))
+	assert.False(t, denylist.Contains("jti-1"))
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mockRefreshTokenRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+}
+
+func TestTokenService_Refresh_Revoked(t *testing.T) {
+	mockRefreshTokenRepo := new(mock.RefreshTokenRepositoryMock)
+	mockRevokedTokenRepo := new(mock.RevokedTokenRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewTokenService(mockRefreshTokenRepo, mockRevokedTokenRepo, mockUserRepo, testTokenIssuer, helper.NewTokenDenylist(), 30*24*time.Hour, db)
+
+	revokedAt := time.Now().Add(-time.Minute)
+	token := domain.RefreshToken{ID: 1, UserID: 2, FamilyID: "session-1", ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}
+
+	sqlMock.ExpectBegin()
+	mockRefreshTokenRepo.On("LockByHash", testifymock.Anything, hashToken("old-token")).Return(token, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.Refresh("old-token")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "Refresh token has expired or been revoked", customErr.Message)
+	mockRefreshTokenRepo.AssertNotCalled(t, "MarkUsed", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func TestTokenService_Logout_RevokesOwnSession(t *testing.T) {
+	mockRefreshTokenRepo := new(mock.RefreshTokenRepositoryMock)
+	mockRevokedTokenRepo := new(mock.RevokedTokenRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	denylist := helper.NewTokenDenylist()
+	service := NewTokenService(mockRefreshTokenRepo, mockRevokedTokenRepo, mockUserRepo, testTokenIssuer, denylist, 30*24*time.Hour, db)
+
+	token := domain.RefreshToken{ID: 4, UserID: 2, FamilyID: "session-1", AccessTokenJTI: "jti-4", AccessExpiresAt: time.Now().Add(10 * time.Minute), ExpiresAt: time.Now().Add(time.Hour)}
+
+	sqlMock.ExpectBegin()
+	mockRefreshTokenRepo.On("FindByFamilyId", testifymock.Anything, "session-1").Return([]domain.RefreshToken{token}, nil)
+	mockRefreshTokenRepo.On("Revoke", testifymock.Anything, []int{4}, testifymock.Anything).Return(nil)
+	mockRevokedTokenRepo.On("Create", testifymock.Anything, testifymock.Anything).Return(nil)
+	sqlMock.ExpectCommit()
+
+	err := service.Logout(2, "session-1")
+
+	assert.NoError(t, err)
+	assert.True(t, denylist.Contains("jti-4"))
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestTokenService_SyncDenylist(t *testing.T) {
+	mockRefreshTokenRepo := new(mock.RefreshTokenRepositoryMock)
+	mockRevokedTokenRepo := new(mock.RevokedTokenRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	denylist := helper.NewTokenDenylist()
+	service := NewTokenService(mockRefreshTokenRepo, mockRevokedTokenRepo, mockUserRepo, testTokenIssuer, denylist, 30*24*time.Hour, &gorm.DB{})
+
+	denylist.Add("expired", time.Now().Add(-time.Minute))
+	mockRevokedTokenRepo.On("FindUnexpired", &gorm.DB{}, testifymock.Anything).Return([]domain.RevokedToken{
+		{ID: 1, JTI: "revoked-elsewhere", UserID: 3, ExpiresAt: time.Now().Add(5 * time.Minute)},
+	}, nil)
+
+	err := service.SyncDenylist(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, denylist.Contains("revoked-elsewhere"))
+	assert.False(t, denylist.Contains("expired"))
+}
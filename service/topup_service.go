@@ -1,68 +1,218 @@
 package service
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"hotel_ip-p2/exception"
+	"hotel_ip-p2/helper"
 	"hotel_ip-p2/model/domain"
 	"hotel_ip-p2/repository"
+	"log"
 	"net/http"
-	"strconv"
-	"strings"
 
 	"gorm.io/gorm"
 )
 
+var minTopupAmount = domain.NewMoney(10000)
+
 type TopupService interface {
-	ProcessWebhook(topup domain.Topup) (domain.Topup, error)
+	Create(userID int, amount domain.Money) (domain.Topup, error)
+	ProcessWebhook(notification domain.Topup, payload string) (domain.Topup, error)
 }
 
 type topupServiceImpl struct {
-	TopupRepository repository.TopupRepository
-	UserRepository  repository.UserRepository
-	DB              *gorm.DB
+	TopupRepository             repository.TopupRepository
+	UserRepository              repository.UserRepository
+	WalletTransactionRepository repository.WalletTransactionRepository
+	SnapClient                  helper.SnapClient
+	DB                          *gorm.DB
 }
 
-func NewTopupService(topupRepository repository.TopupRepository, userRepository repository.UserRepository, db *gorm.DB) TopupService {
+func NewTopupService(topupRepository repository.TopupRepository, userRepository repository.UserRepository, walletTransactionRepository repository.WalletTransactionRepository, snapClient helper.SnapClient, db *gorm.DB) TopupService {
 	return &topupServiceImpl{
-		TopupRepository: topupRepository,
-		UserRepository:  userRepository,
-		DB:              db,
+		TopupRepository:             topupRepository,
+		UserRepository:              userRepository,
+		WalletTransactionRepository: walletTransactionRepository,
+		SnapClient:                  snapClient,
+		DB:                          db,
 	}
 }
 
-func (service *topupServiceImpl) ProcessWebhook(topup domain.Topup) (domain.Topup, error) {
-	if topup.Status != "settlement" {
-		return domain.Topup{}, nil
+// Create records a pending topup under a server-generated order id and opens
+// a Snap payment page for it. The balance is only credited once Midtrans
+// reports the order as settled through the webhook.
+func (service *topupServiceImpl) Create(userID int, amount domain.Money) (domain.Topup, error) {
+	if amount < minTopupAmount {
+		return domain.Topup{}, exception.NewCustomError(http.StatusBadRequest, fmt.Sprintf("amount must be at least %s", minTopupAmount))
+	}
+	if !amount.IsWhole() {
+		return domain.Topup{}, exception.NewCustomError(http.StatusBadRequest, "amount must be a whole number")
+	}
+
+	user, err := service.UserRepository.FindById(service.DB, userID)
+	if err != nil {
+		return domain.Topup{}, exception.NewCustomError(http.StatusNotFound, "user not found")
+	}
+	if !user.IsEmailVerified() {
+		return domain.Topup{}, exception.NewCustomError(http.StatusForbidden, "verify your email address before topping up")
+	}
+
+	orderID, err := generateOrderID(userID)
+	if err != nil {
+		return domain.Topup{}, exception.NewCustomError(http.StatusInternalServerError, "failed to generate order id")
+	}
+
+	topup, err := service.TopupRepository.Create(service.DB, domain.Topup{
+		UserID:          userID,
+		MidtransOrderID: orderID,
+		Amount:          amount,
+		Status:          domain.TopupStatusPending,
+	})
+	if err != nil {
+		return domain.Topup{}, exception.NewCustomError(http.StatusInternalServerError, "failed to create topup record")
 	}
 
-	parts := strings.Split(topup.MidtransOrderID, "-")
-	if len(parts) != 3 {
-		return domain.Topup{}, exception.NewCustomError(http.StatusBadRequest, "invalid order id format")
+	snap, err := service.SnapClient.CreateTransaction(helper.SnapTransaction{
+		OrderID:       orderID,
+		GrossAmount:   amount.Units(),
+		CustomerName:  user.Name,
+		CustomerEmail: user.Email,
+	})
+	if err != nil {
+		log.Printf("Snap transaction for order %s failed: %v", orderID, err)
+		topup.Status = domain.TopupStatusFailed
+		if _, updateErr := service.TopupRepository.Update(service.DB, topup); updateErr != nil {
+			log.Printf("Failed to mark topup %s as failed: %v", orderID, updateErr)
+		}
+		return domain.Topup{}, exception.NewCustomError(http.StatusBadGateway, "failed to create payment")
 	}
 
-	userID, err := strconv.Atoi(parts[1])
+	topup.SnapToken = snap.Token
+	topup.RedirectURL = snap.RedirectURL
+	topup, err = service.TopupRepository.Update(service.DB, topup)
 	if err != nil {
-		return domain.Topup{}, exception.NewCustomError(http.StatusBadRequest, "invalid user id in order id")
+		return domain.Topup{}, exception.NewCustomError(http.StatusInternalServerError, "failed to update topup record")
 	}
 
-	topup.UserID = userID
+	return topup, nil
+}
+
+// ProcessWebhook moves the topup named by the notification to the reported
+// status. Entering a paid status credits the wallet; cancelling, refunding or
+// charging back a paid topup posts a reversal for the part not yet reversed.
+// A capture challenged by fraud detection is recorded but not credited until
+// it is accepted or settles. Each accepted change is stored in the status
+// history with its payload.
+func (service *topupServiceImpl) ProcessWebhook(notification domain.Topup, payload string) (domain.Topup, error) {
+	if !domain.IsKnownTopupStatus(notification.Status) {
+		return domain.Topup{}, nil
+	}
 
 	var result domain.Topup
 
-	err = service.DB.Transaction(func(tx *gorm.DB) error {
-		result, err = service.TopupRepository.Create(tx, topup)
+	err := service.DB.Transaction(func(tx *gorm.DB) error {
+		err := service.TopupRepository.LockOrderID(tx, notification.MidtransOrderID)
 		if err != nil {
-			return exception.NewCustomError(http.StatusBadRequest, "failed to create topup record")
+			return err
 		}
 
-		user, err := service.UserRepository.FindById(tx, userID)
+		existing, err := service.TopupRepository.FindByOrderID(tx, notification.MidtransOrderID)
 		if err != nil {
-			return exception.NewCustomError(http.StatusNotFound, "user not found")
+			if err == gorm.ErrRecordNotFound {
+				return exception.NewCustomError(http.StatusNotFound, "topup order not found")
+			}
+			return err
 		}
 
-		user.Balance += topup.Amount
-		_, err = service.UserRepository.Update(tx, user)
+		if isDuplicateTopupNotification(existing, notification) {
+			// Midtrans retries notifications; a status we already hold has
+			// been applied, so hand back the current record.
+			result = existing
+			return nil
+		}
+
+		if !domain.CanTransitionTopup(existing, notification) {
+			return exception.NewCustomError(http.StatusConflict, fmt.Sprintf("cannot change topup status from %s to %s", existing.Status, notification.Status))
+		}
+
+		wasPaid := domain.IsPaidTopupStatus(existing.Status, existing.FraudStatus)
+		var credit, reversal domain.Money
+
+		switch {
+		case !wasPaid && domain.IsPaidTopupStatus(notification.Status, notification.FraudStatus):
+			if existing.Amount != notification.Amount {
+				return exception.NewCustomError(http.StatusBadRequest, "gross amount does not match topup order")
+			}
+			credit = existing.Amount
+		case wasPaid && domain.IsPartialReversalTopupStatus(notification.Status):
+			if notification.RefundedAmount <= existing.RefundedAmount || notification.RefundedAmount > existing.Amount {
+				return exception.NewCustomError(http.StatusBadRequest, "invalid refund amount")
+			}
+			reversal = notification.RefundedAmount - existing.RefundedAmount
+			existing.RefundedAmount = notification.RefundedAmount
+		case wasPaid:
+			reversal = existing.Amount - existing.RefundedAmount
+			existing.RefundedAmount = existing.Amount
+		}
+
+		history := domain.TopupStatusHistory{
+			TopupID:    existing.ID,
+			FromStatus: existing.Status,
+			ToStatus:   notification.Status,
+			Payload:    payload,
+		}
+
+		if notification.MidtransTransactionID != "" {
+			existing.MidtransTransactionID = notification.MidtransTransactionID
+		}
+		if notification.PaymentType != "" {
+			existing.PaymentType = notification.PaymentType
+		}
+		if notification.FraudStatus != "" {
+			existing.FraudStatus = notification.FraudStatus
+		}
+		existing.Status = notification.Status
+		result, err = service.TopupRepository.Update(tx, existing)
+		if err != nil {
+			return exception.NewCustomError(http.StatusInternalServerError, "failed to update topup record")
+		}
+
+		_, err = service.TopupRepository.CreateStatusHistory(tx, history)
 		if err != nil {
-			return exception.NewCustomError(http.StatusInternalServerError, "failed to update balance")
+			return exception.NewCustomError(http.StatusInternalServerError, "failed to record topup status")
+		}
+
+		if credit > 0 {
+			_, err = service.WalletTransactionRepository.Create(tx, domain.WalletTransaction{
+				UserID:        result.UserID,
+				Type:          domain.WalletTransactionTopupCredit,
+				Amount:        credit,
+				ContraAccount: domain.LedgerAccountPaymentGateway,
+				ReferenceType: domain.ReferenceTypeTopup,
+				ReferenceID:   &result.ID,
+				Description:   "Topup " + result.MidtransOrderID,
+			})
+			if err != nil {
+				return exception.NewCustomError(http.StatusInternalServerError, "failed to update balance")
+			}
+		}
+
+		if reversal > 0 {
+			// A reversal may take the balance below zero when the credited
+			// money was already spent; the debt is settled by later topups.
+			_, err = service.WalletTransactionRepository.Create(tx, domain.WalletTransaction{
+				UserID:        result.UserID,
+				Type:          domain.WalletTransactionTopupReversal,
+				Amount:        -reversal,
+				ContraAccount: domain.LedgerAccountPaymentGateway,
+				ReferenceType: domain.ReferenceTypeTopup,
+				ReferenceID:   &result.ID,
+				Description:   fmt.Sprintf("Topup %s %s", result.MidtransOrderID, result.Status),
+			})
+			if err != nil {
+				return exception.NewCustomError(http.StatusInternalServerError, "failed to update balance")
+			}
 		}
 
 		return nil
@@ -74,3 +224,28 @@ func (service *topupServiceImpl) ProcessWebhook(topup domain.Topup) (domain.Topu
 
 	return result, nil
 }
+
+// isDuplicateTopupNotification reports whether notification repeats the
+// status the topup already holds. A capture only repeats with the same fraud
+// status, and partial reversals only repeat when they do not raise the
+// refunded total.
+func isDuplicateTopupNotification(existing domain.Topup, notification domain.Topup) bool {
+	if existing.Status != notification.Status {
+		return false
+	}
+	if notification.Status == domain.TopupStatusCapture {
+		return existing.FraudStatus == notification.FraudStatus
+	}
+	if domain.IsPartialReversalTopupStatus(notification.Status) {
+		return notification.RefundedAmount <= existing.RefundedAmount
+	}
+	return true
+}
+
+func generateOrderID(userID int) (string, error) {
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("TOPUP-%d-%s", userID, hex.EncodeToString(suffix)), nil
+}
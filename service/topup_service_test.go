@@ -2,9 +2,14 @@ package service
 
 import (
 	"database/sql"
+	"encoding/json"
 	"hotel_ip-p2/exception"
+	"hotel_ip-p2/helper"
 	"hotel_ip-p2/model/domain"
 	"hotel_ip-p2/repository/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -31,31 +36,160 @@ func setupTopupMockDB() (*gorm.DB, sqlmock.Sqlmock, error) {
 	return gormDB, mockSQL, err
 }
 
+const testWebhookPayload = `{"order_id":"TOPUP-1-123456"}`
+
+func newFakeSnapServer(t *testing.T, status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverKey, _, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "server-key", serverKey)
+
+		var payload map[string]map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, float64(100000), payload["transaction_details"]["gross_amount"])
+		assert.Equal(t, "john@example.com", payload["customer_details"]["email"])
+
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestTopupService_Create_Success(t *testing.T) {
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	snapServer := newFakeSnapServer(t, http.StatusCreated, `{"token":"snap-token","redirect_url":"https://app.sandbox.midtrans.com/snap/v4/redirection/snap-token"}`)
+	defer snapServer.Close()
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, helper.NewSnapClient(snapServer.URL, "server-key"), &gorm.DB{})
+
+	user := domain.User{ID: 1, Name: "John Doe", Email: "john@example.com", EmailVerifiedAt: &verifiedAt}
+
+	mockUserRepo.On("FindById", testifymock.Anything, 1).Return(user, nil)
+	mockTopupRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(t domain.Topup) bool {
+		return t.UserID == 1 && t.Amount == domain.NewMoney(100000) && t.Status == domain.TopupStatusPending && strings.HasPrefix(t.MidtransOrderID, "TOPUP-1-")
+	})).Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-abc", Amount: domain.NewMoney(100000), Status: domain.TopupStatusPending}, nil)
+	mockTopupRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(t domain.Topup) bool {
+		return t.ID == 1 && t.SnapToken == "snap-token" && t.Status == domain.TopupStatusPending
+	})).Return(domain.Topup{
+		ID:              1,
+		UserID:          1,
+		MidtransOrderID: "TOPUP-1-abc",
+		Amount:          domain.NewMoney(100000),
+		Status:          domain.TopupStatusPending,
+		SnapToken:       "snap-token",
+		RedirectURL:     "https://app.sandbox.midtrans.com/snap/v4/redirection/snap-token",
+	}, nil)
+
+	result, err := service.Create(1, domain.NewMoney(100000))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "snap-token", result.SnapToken)
+	assert.Equal(t, "https://app.sandbox.midtrans.com/snap/v4/redirection/snap-token", result.RedirectURL)
+	assert.Equal(t, domain.TopupStatusPending, result.Status)
+	mockTopupRepo.AssertExpectations(t)
+	mockWalletRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+}
+
+func TestTopupService_Create_SnapFailure(t *testing.T) {
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	snapServer := newFakeSnapServer(t, http.StatusUnauthorized, `{"error_messages":["Access denied due to unauthorized transaction"]}`)
+	defer snapServer.Close()
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, helper.NewSnapClient(snapServer.URL, "server-key"), &gorm.DB{})
+
+	user := domain.User{ID: 1, Name: "John Doe", Email: "john@example.com", EmailVerifiedAt: &verifiedAt}
+
+	mockUserRepo.On("FindById", testifymock.Anything, 1).Return(user, nil)
+	mockTopupRepo.On("Create", testifymock.Anything, testifymock.Anything).Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-abc", Amount: domain.NewMoney(100000), Status: domain.TopupStatusPending}, nil)
+	mockTopupRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(t domain.Topup) bool {
+		return t.ID == 1 && t.Status == domain.TopupStatusFailed
+	})).Return(domain.Topup{}, nil)
+
+	_, err := service.Create(1, domain.NewMoney(100000))
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadGateway, customErr.Code)
+	mockTopupRepo.AssertExpectations(t)
+}
+
+func TestTopupService_Create_UserNotFound(t *testing.T) {
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, helper.NewSnapClient("http://127.0.0.1:0", "server-key"), &gorm.DB{})
+
+	mockUserRepo.On("FindById", testifymock.Anything, 99).Return(domain.User{}, gorm.ErrRecordNotFound)
+
+	_, err := service.Create(99, domain.NewMoney(100000))
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "user not found", customErr.Message)
+	mockTopupRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+}
+
+func TestTopupService_Create_EmailNotVerified(t *testing.T) {
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, helper.NewSnapClient("http://127.0.0.1:0", "server-key"), &gorm.DB{})
+
+	mockUserRepo.On("FindById", testifymock.Anything, 1).Return(domain.User{ID: 1, Name: "John Doe", Email: "john@example.com"}, nil)
+
+	_, err := service.Create(1, domain.NewMoney(100000))
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusForbidden, customErr.Code)
+	mockTopupRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+}
+
+func TestTopupService_Create_InvalidAmount(t *testing.T) {
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, nil, &gorm.DB{})
+
+	for _, amount := range []string{"9999.99", "10000.50"} {
+		money, err := domain.ParseMoney(amount)
+		assert.NoError(t, err)
+
+		_, err = service.Create(1, money)
+
+		assert.Error(t, err)
+		customErr, ok := err.(*exception.CustomError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, customErr.Code)
+	}
+	mockUserRepo.AssertNotCalled(t, "FindById", testifymock.Anything, testifymock.Anything)
+	mockTopupRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+}
+
 func TestTopupService_ProcessWebhook_Success(t *testing.T) {
 	mockTopupRepo := new(mock.TopupRepositoryMock)
 	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
 	db, sqlMock, _ := setupTopupMockDB()
-	service := NewTopupService(mockTopupRepo, mockUserRepo, db)
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, nil, db)
 
 	topup := domain.Topup{
 		MidtransTransactionID: "TRX-123",
 		MidtransOrderID:       "TOPUP-1-123456",
-		Amount:                100000,
+		Amount:                domain.NewMoney(100000),
 		Status:                "settlement",
 	}
 
-	user := domain.User{
-		ID:      1,
-		Name:    "John Doe",
-		Email:   "john@example.com",
-		Balance: 50000,
-	}
-
-	updatedUser := domain.User{
-		ID:      1,
-		Name:    "John Doe",
-		Email:   "john@example.com",
-		Balance: 150000,
+	pendingTopup := domain.Topup{
+		ID:              1,
+		UserID:          1,
+		MidtransOrderID: "TOPUP-1-123456",
+		Amount:          domain.NewMoney(100000),
+		Status:          domain.TopupStatusPending,
 	}
 
 	expectedTopup := domain.Topup{
@@ -63,84 +197,402 @@ func TestTopupService_ProcessWebhook_Success(t *testing.T) {
 		UserID:                1,
 		MidtransTransactionID: "TRX-123",
 		MidtransOrderID:       "TOPUP-1-123456",
-		Amount:                100000,
+		Amount:                domain.NewMoney(100000),
 		Status:                "settlement",
 	}
 
 	// Mock the transaction behavior
 	sqlMock.ExpectBegin()
-	mockTopupRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(t domain.Topup) bool {
-		return t.UserID == 1 && t.Amount == 100000
-	})).Return(expectedTopup, nil)
-	mockUserRepo.On("FindById", testifymock.Anything, 1).Return(user, nil)
-	mockUserRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(u domain.User) bool {
-		return u.ID == 1 && u.Balance == 150000
-	})).Return(updatedUser, nil)
+	mockTopupRepo.On("LockOrderID", testifymock.Anything, "TOPUP-1-123456").Return(nil)
+	mockTopupRepo.On("FindByOrderID", testifymock.Anything, "TOPUP-1-123456").Return(pendingTopup, nil)
+	mockTopupRepo.On("Update", testifymock.Anything, expectedTopup).Return(expectedTopup, nil)
+	mockTopupRepo.On("CreateStatusHistory", testifymock.Anything, domain.TopupStatusHistory{
+		TopupID:    1,
+		FromStatus: domain.TopupStatusPending,
+		ToStatus:   domain.TopupStatusSettlement,
+		Payload:    testWebhookPayload,
+	}).Return(domain.TopupStatusHistory{ID: 1}, nil)
+	mockWalletRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.UserID == 1 && w.Type == domain.WalletTransactionTopupCredit && w.Amount == domain.NewMoney(100000) && *w.ReferenceID == 1
+	})).Return(domain.WalletTransaction{ID: 1, BalanceAfter: domain.NewMoney(150000)}, nil)
 	sqlMock.ExpectCommit()
 
-	result, err := service.ProcessWebhook(topup)
+	result, err := service.ProcessWebhook(topup, testWebhookPayload)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedTopup.ID, result.ID)
 	assert.Equal(t, 1, result.UserID)
+	mockTopupRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+	mockWalletRepo.AssertExpectations(t)
 }
 
-func TestTopupService_ProcessWebhook_NotSettlement(t *testing.T) {
+func TestTopupService_ProcessWebhook_CaptureAccepted(t *testing.T) {
 	mockTopupRepo := new(mock.TopupRepositoryMock)
 	mockUserRepo := new(mock.UserRepositoryMock)
-	service := NewTopupService(mockTopupRepo, mockUserRepo, &gorm.DB{})
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupTopupMockDB()
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, nil, db)
 
 	topup := domain.Topup{
 		MidtransTransactionID: "TRX-123",
 		MidtransOrderID:       "TOPUP-1-123456",
-		Amount:                100000,
-		Status:                "pending",
+		Amount:                domain.NewMoney(100000),
+		Status:                domain.TopupStatusCapture,
+		FraudStatus:           domain.FraudStatusAccept,
+		PaymentType:           "credit_card",
 	}
 
-	result, err := service.ProcessWebhook(topup)
+	sqlMock.ExpectBegin()
+	mockTopupRepo.On("LockOrderID", testifymock.Anything, "TOPUP-1-123456").Return(nil)
+	mockTopupRepo.On("FindByOrderID", testifymock.Anything, "TOPUP-1-123456").Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), Status: domain.TopupStatusPending}, nil)
+	mockTopupRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(t domain.Topup) bool {
+		return t.Status == domain.TopupStatusCapture && t.FraudStatus == domain.FraudStatusAccept
+	})).Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), Status: domain.TopupStatusCapture, FraudStatus: domain.FraudStatusAccept}, nil)
+	mockTopupRepo.On("CreateStatusHistory", testifymock.Anything, testifymock.Anything).Return(domain.TopupStatusHistory{ID: 1}, nil)
+	mockWalletRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.UserID == 1 && w.Type == domain.WalletTransactionTopupCredit && w.Amount == domain.NewMoney(100000)
+	})).Return(domain.WalletTransaction{ID: 1}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.ProcessWebhook(topup, testWebhookPayload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.FraudStatusAccept, result.FraudStatus)
+	mockWalletRepo.AssertExpectations(t)
+}
+
+func TestTopupService_ProcessWebhook_CaptureChallenged(t *testing.T) {
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupTopupMockDB()
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, nil, db)
+
+	topup := domain.Topup{
+		MidtransTransactionID: "TRX-123",
+		MidtransOrderID:       "TOPUP-1-123456",
+		Amount:                domain.NewMoney(100000),
+		Status:                domain.TopupStatusCapture,
+		FraudStatus:           domain.FraudStatusChallenge,
+		PaymentType:           "credit_card",
+	}
+
+	sqlMock.ExpectBegin()
+	mockTopupRepo.On("LockOrderID", testifymock.Anything, "TOPUP-1-123456").Return(nil)
+	mockTopupRepo.On("FindByOrderID", testifymock.Anything, "TOPUP-1-123456").Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), Status: domain.TopupStatusPending}, nil)
+	mockTopupRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(t domain.Topup) bool {
+		return t.Status == domain.TopupStatusCapture && t.FraudStatus == domain.FraudStatusChallenge
+	})).Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), Status: domain.TopupStatusCapture, FraudStatus: domain.FraudStatusChallenge}, nil)
+	mockTopupRepo.On("CreateStatusHistory", testifymock.Anything, testifymock.Anything).Return(domain.TopupStatusHistory{ID: 1}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.ProcessWebhook(topup, testWebhookPayload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.FraudStatusChallenge, result.FraudStatus)
+	mockWalletRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+}
+
+func TestTopupService_ProcessWebhook_ChallengedCaptureSettles(t *testing.T) {
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupTopupMockDB()
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, nil, db)
+
+	topup := domain.Topup{
+		MidtransTransactionID: "TRX-123",
+		MidtransOrderID:       "TOPUP-1-123456",
+		Amount:                domain.NewMoney(100000),
+		Status:                domain.TopupStatusSettlement,
+		FraudStatus:           domain.FraudStatusAccept,
+	}
+
+	sqlMock.ExpectBegin()
+	mockTopupRepo.On("LockOrderID", testifymock.Anything, "TOPUP-1-123456").Return(nil)
+	mockTopupRepo.On("FindByOrderID", testifymock.Anything, "TOPUP-1-123456").Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), Status: domain.TopupStatusCapture, FraudStatus: domain.FraudStatusChallenge}, nil)
+	mockTopupRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(t domain.Topup) bool {
+		return t.Status == domain.TopupStatusSettlement
+	})).Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), Status: domain.TopupStatusSettlement, FraudStatus: domain.FraudStatusAccept}, nil)
+	mockTopupRepo.On("CreateStatusHistory", testifymock.Anything, testifymock.Anything).Return(domain.TopupStatusHistory{ID: 2}, nil)
+	mockWalletRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.Type == domain.WalletTransactionTopupCredit && w.Amount == domain.NewMoney(100000)
+	})).Return(domain.WalletTransaction{ID: 1}, nil)
+	sqlMock.ExpectCommit()
+
+	_, err := service.ProcessWebhook(topup, testWebhookPayload)
+
+	assert.NoError(t, err)
+	mockWalletRepo.AssertExpectations(t)
+}
+
+func TestTopupService_ProcessWebhook_ChallengedCaptureDenied(t *testing.T) {
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupTopupMockDB()
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, nil, db)
+
+	topup := domain.Topup{
+		MidtransTransactionID: "TRX-123",
+		MidtransOrderID:       "TOPUP-1-123456",
+		Amount:                domain.NewMoney(100000),
+		Status:                domain.TopupStatusDeny,
+		FraudStatus:           domain.FraudStatusDeny,
+	}
+
+	sqlMock.ExpectBegin()
+	mockTopupRepo.On("LockOrderID", testifymock.Anything, "TOPUP-1-123456").Return(nil)
+	mockTopupRepo.On("FindByOrderID", testifymock.Anything, "TOPUP-1-123456").Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), Status: domain.TopupStatusCapture, FraudStatus: domain.FraudStatusChallenge}, nil)
+	mockTopupRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(t domain.Topup) bool {
+		return t.Status == domain.TopupStatusDeny
+	})).Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), Status: domain.TopupStatusDeny, FraudStatus: domain.FraudStatusDeny}, nil)
+	mockTopupRepo.On("CreateStatusHistory", testifymock.Anything, testifymock.Anything).Return(domain.TopupStatusHistory{ID: 2}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.ProcessWebhook(topup, testWebhookPayload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.TopupStatusDeny, result.Status)
+	mockWalletRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+}
+
+func TestTopupService_ProcessWebhook_UnknownStatus(t *testing.T) {
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, nil, &gorm.DB{})
+
+	topup := domain.Topup{
+		MidtransTransactionID: "TRX-123",
+		MidtransOrderID:       "TOPUP-1-123456",
+		Amount:                domain.NewMoney(100000),
+		Status:                "authorize",
+	}
+
+	result, err := service.ProcessWebhook(topup, testWebhookPayload)
 
 	assert.NoError(t, err)
 	assert.Equal(t, domain.Topup{}, result)
-	mockTopupRepo.AssertNotCalled(t, "Create")
+	mockTopupRepo.AssertNotCalled(t, "FindByOrderID", testifymock.Anything, testifymock.Anything)
 	mockUserRepo.AssertNotCalled(t, "FindById")
 }
 
-func TestTopupService_ProcessWebhook_InvalidOrderIDFormat(t *testing.T) {
+func TestTopupService_ProcessWebhook_OrderNotFound(t *testing.T) {
 	mockTopupRepo := new(mock.TopupRepositoryMock)
 	mockUserRepo := new(mock.UserRepositoryMock)
-	service := NewTopupService(mockTopupRepo, mockUserRepo, &gorm.DB{})
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupTopupMockDB()
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, nil, db)
 
 	topup := domain.Topup{
 		MidtransTransactionID: "TRX-123",
-		MidtransOrderID:       "INVALID",
-		Amount:                100000,
+		MidtransOrderID:       "1-fabricated-order",
+		Amount:                domain.NewMoney(100000),
 		Status:                "settlement",
 	}
 
-	_, err := service.ProcessWebhook(topup)
+	sqlMock.ExpectBegin()
+	mockTopupRepo.On("LockOrderID", testifymock.Anything, "1-fabricated-order").Return(nil)
+	mockTopupRepo.On("FindByOrderID", testifymock.Anything, "1-fabricated-order").Return(domain.Topup{}, gorm.ErrRecordNotFound)
+	sqlMock.ExpectRollback()
+
+	_, err := service.ProcessWebhook(topup, testWebhookPayload)
 
 	assert.Error(t, err)
 	customErr, ok := err.(*exception.CustomError)
 	assert.True(t, ok)
-	assert.Equal(t, "invalid order id format", customErr.Message)
+	assert.Equal(t, "topup order not found", customErr.Message)
+	mockWalletRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
 }
 
-func TestTopupService_ProcessWebhook_InvalidUserIDInOrderID(t *testing.T) {
+func TestTopupService_ProcessWebhook_AmountMismatch(t *testing.T) {
 	mockTopupRepo := new(mock.TopupRepositoryMock)
 	mockUserRepo := new(mock.UserRepositoryMock)
-	service := NewTopupService(mockTopupRepo, mockUserRepo, &gorm.DB{})
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupTopupMockDB()
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, nil, db)
 
 	topup := domain.Topup{
 		MidtransTransactionID: "TRX-123",
-		MidtransOrderID:       "TOPUP-ABC-123456",
-		Amount:                100000,
+		MidtransOrderID:       "TOPUP-1-123456",
+		Amount:                domain.NewMoney(500000),
 		Status:                "settlement",
 	}
 
-	_, err := service.ProcessWebhook(topup)
+	sqlMock.ExpectBegin()
+	mockTopupRepo.On("LockOrderID", testifymock.Anything, "TOPUP-1-123456").Return(nil)
+	mockTopupRepo.On("FindByOrderID", testifymock.Anything, "TOPUP-1-123456").Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), Status: domain.TopupStatusPending}, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.ProcessWebhook(topup, testWebhookPayload)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "gross amount does not match topup order", customErr.Message)
+	mockTopupRepo.AssertNotCalled(t, "Update", testifymock.Anything, testifymock.Anything)
+	mockWalletRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+}
+
+func TestTopupService_ProcessWebhook_DuplicateSettlement(t *testing.T) {
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupTopupMockDB()
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, nil, db)
+
+	topup := domain.Topup{
+		MidtransTransactionID: "TRX-123",
+		MidtransOrderID:       "TOPUP-1-123456",
+		Amount:                domain.NewMoney(100000),
+		Status:                "settlement",
+	}
+
+	existingTopup := domain.Topup{
+		ID:                    1,
+		UserID:                1,
+		MidtransTransactionID: "TRX-123",
+		MidtransOrderID:       "TOPUP-1-123456",
+		Amount:                domain.NewMoney(100000),
+		Status:                "settlement",
+	}
+
+	sqlMock.ExpectBegin()
+	mockTopupRepo.On("LockOrderID", testifymock.Anything, "TOPUP-1-123456").Return(nil)
+	mockTopupRepo.On("FindByOrderID", testifymock.Anything, "TOPUP-1-123456").Return(existingTopup, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.ProcessWebhook(topup, testWebhookPayload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, existingTopup, result)
+	mockTopupRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+	mockWalletRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+	mockTopupRepo.AssertExpectations(t)
+}
+
+func TestTopupService_ProcessWebhook_Expire(t *testing.T) {
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupTopupMockDB()
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, nil, db)
+
+	topup := domain.Topup{
+		MidtransOrderID: "TOPUP-1-123456",
+		Amount:          domain.NewMoney(100000),
+		Status:          domain.TopupStatusExpire,
+	}
+
+	sqlMock.ExpectBegin()
+	mockTopupRepo.On("LockOrderID", testifymock.Anything, "TOPUP-1-123456").Return(nil)
+	mockTopupRepo.On("FindByOrderID", testifymock.Anything, "TOPUP-1-123456").Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), Status: domain.TopupStatusPending}, nil)
+	mockTopupRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(t domain.Topup) bool {
+		return t.Status == domain.TopupStatusExpire
+	})).Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), Status: domain.TopupStatusExpire}, nil)
+	mockTopupRepo.On("CreateStatusHistory", testifymock.Anything, testifymock.MatchedBy(func(h domain.TopupStatusHistory) bool {
+		return h.FromStatus == domain.TopupStatusPending && h.ToStatus == domain.TopupStatusExpire
+	})).Return(domain.TopupStatusHistory{ID: 1}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.ProcessWebhook(topup, testWebhookPayload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.TopupStatusExpire, result.Status)
+	mockWalletRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+	mockTopupRepo.AssertExpectations(t)
+}
+
+func TestTopupService_ProcessWebhook_RefundAfterSettlement(t *testing.T) {
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupTopupMockDB()
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, nil, db)
+
+	topup := domain.Topup{
+		MidtransOrderID: "TOPUP-1-123456",
+		Amount:          domain.NewMoney(100000),
+		Status:          domain.TopupStatusRefund,
+	}
+
+	refundedTopup := domain.Topup{ID: 1, UserID: 1, MidtransTransactionID: "TRX-123", MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), RefundedAmount: domain.NewMoney(100000), Status: domain.TopupStatusRefund}
+
+	sqlMock.ExpectBegin()
+	mockTopupRepo.On("LockOrderID", testifymock.Anything, "TOPUP-1-123456").Return(nil)
+	mockTopupRepo.On("FindByOrderID", testifymock.Anything, "TOPUP-1-123456").Return(domain.Topup{ID: 1, UserID: 1, MidtransTransactionID: "TRX-123", MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), RefundedAmount: domain.NewMoney(30000), Status: domain.TopupStatusPartialRefund}, nil)
+	mockTopupRepo.On("Update", testifymock.Anything, refundedTopup).Return(refundedTopup, nil)
+	mockTopupRepo.On("CreateStatusHistory", testifymock.Anything, testifymock.Anything).Return(domain.TopupStatusHistory{ID: 2}, nil)
+	mockWalletRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.Type == domain.WalletTransactionTopupReversal && w.Amount == domain.NewMoney(-70000) && *w.ReferenceID == 1
+	})).Return(domain.WalletTransaction{ID: 3, BalanceAfter: domain.NewMoney(-20000)}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.ProcessWebhook(topup, testWebhookPayload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, refundedTopup, result)
+	mockWalletRepo.AssertExpectations(t)
+}
+
+func TestTopupService_ProcessWebhook_PartialRefund(t *testing.T) {
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupTopupMockDB()
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, nil, db)
+
+	topup := domain.Topup{
+		MidtransOrderID: "TOPUP-1-123456",
+		Amount:          domain.NewMoney(100000),
+		RefundedAmount:  domain.NewMoney(30000),
+		Status:          domain.TopupStatusPartialRefund,
+	}
+
+	sqlMock.ExpectBegin()
+	mockTopupRepo.On("LockOrderID", testifymock.Anything, "TOPUP-1-123456").Return(nil)
+	mockTopupRepo.On("FindByOrderID", testifymock.Anything, "TOPUP-1-123456").Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), Status: domain.TopupStatusSettlement}, nil)
+	mockTopupRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(t domain.Topup) bool {
+		return t.Status == domain.TopupStatusPartialRefund && t.RefundedAmount == domain.NewMoney(30000)
+	})).Return(domain.Topup{ID: 1, UserID: 1, Amount: domain.NewMoney(100000), RefundedAmount: domain.NewMoney(30000), Status: domain.TopupStatusPartialRefund}, nil)
+	mockTopupRepo.On("CreateStatusHistory", testifymock.Anything, testifymock.Anything).Return(domain.TopupStatusHistory{ID: 2}, nil)
+	mockWalletRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.Type == domain.WalletTransactionTopupReversal && w.Amount == domain.NewMoney(-30000)
+	})).Return(domain.WalletTransaction{ID: 2, BalanceAfter: domain.NewMoney(70000)}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.ProcessWebhook(topup, testWebhookPayload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(30000), result.RefundedAmount)
+	mockWalletRepo.AssertExpectations(t)
+}
+
+func TestTopupService_ProcessWebhook_IllegalTransition(t *testing.T) {
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	db, sqlMock, _ := setupTopupMockDB()
+	service := NewTopupService(mockTopupRepo, mockUserRepo, mockWalletRepo, nil, db)
+
+	topup := domain.Topup{
+		MidtransOrderID: "TOPUP-1-123456",
+		Amount:          domain.NewMoney(100000),
+		Status:          domain.TopupStatusSettlement,
+	}
+
+	sqlMock.ExpectBegin()
+	mockTopupRepo.On("LockOrderID", testifymock.Anything, "TOPUP-1-123456").Return(nil)
+	mockTopupRepo.On("FindByOrderID", testifymock.Anything, "TOPUP-1-123456").Return(domain.Topup{ID: 1, UserID: 1, MidtransOrderID: "TOPUP-1-123456", Amount: domain.NewMoney(100000), Status: domain.TopupStatusExpire}, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.ProcessWebhook(topup, testWebhookPayload)
 
 	assert.Error(t, err)
 	customErr, ok := err.(*exception.CustomError)
 	assert.True(t, ok)
-	assert.Equal(t, "invalid user id in order id", customErr.Message)
+	assert.Equal(t, http.StatusConflict, customErr.Code)
+	mockTopupRepo.AssertNotCalled(t, "Update", testifymock.Anything, testifymock.Anything)
+	mockWalletRepo.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
 }
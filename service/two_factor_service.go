@@ -0,0 +1,558 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/helper"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// recoveryCodeCount is how many recovery codes a user gets at a time.
+const recoveryCodeCount = 10
+
+var errInvalidTwoFactorCode = errors.New("invalid two-factor code")
+
+type TwoFactorService interface {
+	Challenge(user domain.User) (*domain.LoginChallenge, error)
+	VerifyLogin(challengeToken string, code string, ip string) (domain.User, []string, error)
+	EnrollWithChallenge(challengeToken string) (domain.TOTPEnrollment, error)
+	Enroll(userId int) (domain.TOTPEnrollment, error)
+	Confirm(userId int, code string) ([]string, error)
+	RegenerateRecoveryCodes(userId int, code string) ([]string, error)
+	Disable(userId int, code string) error
+	FindPolicies() ([]domain.TwoFactorPolicy, error)
+	UpdatePolicy(actorId int, role string, required bool) (domain.TwoFactorPolicy, error)
+}
+
+type TwoFactorServiceImpl struct {
+	UserRepository            repository.UserRepository
+	UserTOTPRepository        repository.UserTOTPRepository
+	RecoveryCodeRepository    repository.RecoveryCodeRepository
+	TwoFactorPolicyRepository repository.TwoFactorPolicyRepository
+	LoginThrottleRepository   repository.LoginThrottleRepository
+	AuditLogRepository        repository.AuditLogRepository
+	LoginPolicy               domain.LoginPolicy
+	ChallengeSigner           helper.LoginChallengeSigner
+	SecretBox                 helper.SecretBox
+	Issuer                    string
+	DB                        *gorm.DB
+}
+
+func NewTwoFactorService(userRepository repository.UserRepository, userTOTPRepository repository.UserTOTPRepository, recoveryCodeRepository repository.RecoveryCodeRepository, twoFactorPolicyRepository repository.TwoFactorPolicyRepository, loginThrottleRepository repository.LoginThrottleRepository, auditLogRepository repository.AuditLogRepository, loginPolicy domain.LoginPolicy, challengeSigner helper.LoginChallengeSigner, secretBox helper.SecretBox, issuer string, db *gorm.DB) TwoFactorService {
+	return &TwoFactorServiceImpl{
+		UserRepository:            userRepository,
+		UserTOTPRepository:        userTOTPRepository,
+		RecoveryCodeRepository:    recoveryCodeRepository,
+		TwoFactorPolicyRepository: twoFactorPolicyRepository,
+		LoginThrottleRepository:   loginThrottleRepository,
+		AuditLogRepository:        auditLogRepository,
+		LoginPolicy:               loginPolicy,
+		ChallengeSigner:           challengeSigner,
+		SecretBox:                 secretBox,
+		Issuer:                    issuer,
+		DB:                        db,
+	}
+}
+
+// Challenge decides whether a user whose password was just checked needs a
+// second factor before they get a session. It returns nil when they do not.
+// Users with a confirmed authenticator must enter a code from it; users
+// whose role requires two-factor authentication but who have none yet must
+// enrol first.
+func (s *TwoFactorServiceImpl) Challenge(user domain.User) (*domain.LoginChallenge, error) {
+	totp, err := s.UserTOTPRepository.FindByUserId(s.DB, user.ID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	enroll := false
+	if err != nil || !totp.IsConfirmed() {
+		required, err := s.isRequired(user.Role)
+		if err != nil {
+			return nil, err
+		}
+		if !required {
+			return nil, nil
+		}
+		enroll = true
+	}
+
+	challenge, err := s.ChallengeSigner.Sign(user.ID, enroll)
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// VerifyLogin completes a login started with a challenge token. The code is
+// a TOTP code or, once enrolled, one of the user's recovery codes. When the
+// challenge was for enrolment the code confirms the new authenticator and
+// the user's first recovery codes are returned. Wrong codes count as failed
+// logins for the user and the client IP.
+func (s *TwoFactorServiceImpl) VerifyLogin(challengeToken string, code string, ip string) (domain.User, []string, error) {
+	challenge, user, err := s.verifyChallenge(challengeToken)
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+
+	now := time.Now()
+	throttler := s.loginThrottler()
+	throttles := []domain.LoginThrottle{{Scope: domain.LoginThrottleScopeTwoFactor, Key: strconv.Itoa(user.ID)}}
+	if ip != "" {
+		throttles = append(throttles, domain.LoginThrottle{Scope: domain.LoginThrottleScopeIP, Key: ip})
+	}
+	if err := throttler.check(throttles, now); err != nil {
+		return domain.User{}, nil, err
+	}
+
+	var recoveryCodes []string
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		totp, err := s.UserTOTPRepository.LockByUserId(tx, user.ID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound && challenge.EnrollmentRequired {
+				return exception.NewCustomError(http.StatusBadRequest, "Two-factor enrolment has not been started")
+			}
+			if err == gorm.ErrRecordNotFound {
+				return exception.NewCustomError(http.StatusUnauthorized, "Invalid or expired login challenge")
+			}
+			return err
+		}
+
+		if totp.IsConfirmed() {
+			return s.verifyCode(tx, totp, code, now)
+		}
+		if !challenge.EnrollmentRequired {
+			return exception.NewCustomError(http.StatusUnauthorized, "Invalid or expired login challenge")
+		}
+
+		recoveryCodes, err = s.confirm(tx, totp, code, now)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, errInvalidTwoFactorCode) {
+			if err := throttler.recordFailure(throttles, user.ID, ip, now); err != nil {
+				log.Printf("Failed to record failed two-factor login: %v", err)
+			}
+			return domain.User{}, nil, exception.NewCustomError(http.StatusUnauthorized, "Invalid two-factor code")
+		}
+		return domain.User{}, nil, err
+	}
+
+	err = s.LoginThrottleRepository.Delete(s.DB, domain.LoginThrottleScopeTwoFactor, strconv.Itoa(user.ID))
+	if err != nil {
+		log.Printf("Failed to reset wrong two-factor codes of user ID: %d: %v", user.ID, err)
+	}
+
+	return user, recoveryCodes, nil
+}
+
+// EnrollWithChallenge starts enrolment for a user who must set up
+// two-factor authentication before they can log in.
+func (s *TwoFactorServiceImpl) EnrollWithChallenge(challengeToken string) (domain.TOTPEnrollment, error) {
+	challenge, user, err := s.verifyChallenge(challengeToken)
+	if err != nil {
+		return domain.TOTPEnrollment{}, err
+	}
+	if !challenge.EnrollmentRequired {
+		return domain.TOTPEnrollment{}, exception.NewCustomError(http.StatusBadRequest, "Login challenge is not for enrolment")
+	}
+
+	return s.enroll(user)
+}
+
+// Enroll starts enrolment for a logged in user. Two-factor authentication
+// is not enforced until Confirm is called with a first code.
+func (s *TwoFactorServiceImpl) Enroll(userId int) (domain.TOTPEnrollment, error) {
+	user, err := s.findUser(userId)
+	if err != nil {
+		return domain.TOTPEnrollment{}, err
+	}
+
+	return s.enroll(user)
+}
+
+// Confirm turns on two-factor authentication once the user proves their
+// authenticator works, and returns their recovery codes. The codes are not
+// stored in a readable form and cannot be shown again.
+func (s *TwoFactorServiceImpl) Confirm(userId int, code string) ([]string, error) {
+	var recoveryCodes []string
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		totp, err := s.UserTOTPRepository.LockByUserId(tx, userId)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exception.NewCustomError(http.StatusBadRequest, "Two-factor enrolment has not been started")
+			}
+			return err
+		}
+		if totp.IsConfirmed() {
+			return exception.NewCustomError(http.StatusConflict, "Two-factor authentication is already enabled")
+		}
+
+		recoveryCodes, err = s.confirm(tx, totp, code, time.Now())
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, errInvalidTwoFactorCode) {
+			return nil, exception.NewCustomError(http.StatusBadRequest, "Invalid two-factor code")
+		}
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes, used or not,
+// with new ones.
+func (s *TwoFactorServiceImpl) RegenerateRecoveryCodes(userId int, code string) ([]string, error) {
+	var recoveryCodes []string
+	err := s.withCode(userId, code, func(tx *gorm.DB, totp domain.UserTOTP) error {
+		var err error
+		recoveryCodes, err = s.replaceRecoveryCodes(tx, userId)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.AuditLogRepository.Create(tx, domain.AuditLog{
+			Action:  domain.AuditActionRecoveryCodesRegenerated,
+			UserID:  &userId,
+			ActorID: &userId,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// Disable turns off two-factor authentication, unless the user's role
+// requires it.
+func (s *TwoFactorServiceImpl) Disable(userId int, code string) error {
+	user, err := s.findUser(userId)
+	if err != nil {
+		return err
+	}
+
+	required, err := s.isRequired(user.Role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return exception.NewCustomError(http.StatusForbidden, fmt.Sprintf("Two-factor authentication is required for the %s role", user.Role))
+	}
+
+	return s.withCode(userId, code, func(tx *gorm.DB, totp domain.UserTOTP) error {
+		err := s.UserTOTPRepository.DeleteByUserId(tx, userId)
+		if err != nil {
+			return err
+		}
+
+		err = s.RecoveryCodeRepository.DeleteByUserId(tx, userId)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.AuditLogRepository.Create(tx, domain.AuditLog{
+			Action:  domain.AuditActionTwoFactorDisabled,
+			UserID:  &userId,
+			ActorID: &userId,
+		})
+		return err
+	})
+}
+
+// FindPolicies returns the policy of every role, including roles no admin
+// has set one for yet.
+func (s *TwoFactorServiceImpl) FindPolicies() ([]domain.TwoFactorPolicy, error) {
+	saved, err := s.TwoFactorPolicyRepository.FindAll(s.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	byRole := make(map[string]domain.TwoFactorPolicy, len(saved))
+	for _, policy := range saved {
+		byRole[policy.Role] = policy
+	}
+
+	roles := []string{domain.RoleAdmin, domain.RoleStaff, domain.RoleGuest}
+	policies := make([]domain.TwoFactorPolicy, len(roles))
+	for i, role := range roles {
+		policy, ok := byRole[role]
+		if !ok {
+			policy = domain.TwoFactorPolicy{Role: role}
+		}
+		policies[i] = policy
+	}
+	return policies, nil
+}
+
+// UpdatePolicy sets whether users of role must use two-factor
+// authentication. Users who are already logged in keep their sessions;
+// those without an authenticator have to enrol the next time they log in.
+func (s *TwoFactorServiceImpl) UpdatePolicy(actorId int, role string, required bool) (domain.TwoFactorPolicy, error) {
+	if !domain.IsValidRole(role) {
+		return domain.TwoFactorPolicy{}, exception.NewCustomError(http.StatusBadRequest, "Invalid role")
+	}
+
+	var result domain.TwoFactorPolicy
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		result, err = s.TwoFactorPolicyRepository.Save(tx, domain.TwoFactorPolicy{
+			Role:      role,
+			Required:  required,
+			UpdatedBy: &actorId,
+			UpdatedAt: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = s.AuditLogRepository.Create(tx, domain.AuditLog{
+			Action:  domain.AuditActionTwoFactorPolicyUpdated,
+			ActorID: &actorId,
+			Details: fmt.Sprintf("two-factor authentication required for role %s: %t", role, required),
+		})
+		return err
+	})
+	if err != nil {
+		return domain.TwoFactorPolicy{}, err
+	}
+
+	return result, nil
+}
+
+func (s *TwoFactorServiceImpl) isRequired(role string) (bool, error) {
+	policy, err := s.TwoFactorPolicyRepository.FindByRole(s.DB, role)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return policy.Required, nil
+}
+
+func (s *TwoFactorServiceImpl) findUser(userId int) (domain.User, error) {
+	user, err := s.UserRepository.FindById(s.DB, userId)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return domain.User{}, exception.NewCustomError(http.StatusNotFound, "User not found")
+		}
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+// verifyChallenge checks a challenge token and loads the user it was issued
+// to. A user deleted since gets the same error as an expired token.
+func (s *TwoFactorServiceImpl) verifyChallenge(challengeToken string) (domain.LoginChallenge, domain.User, error) {
+	challenge, err := s.ChallengeSigner.Verify(challengeToken)
+	if err != nil {
+		return domain.LoginChallenge{}, domain.User{}, exception.NewCustomError(http.StatusUnauthorized, "Invalid or expired login challenge")
+	}
+
+	user, err := s.UserRepository.FindById(s.DB, challenge.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return domain.LoginChallenge{}, domain.User{}, exception.NewCustomError(http.StatusUnauthorized, "Invalid or expired login challenge")
+		}
+		return domain.LoginChallenge{}, domain.User{}, err
+	}
+	return challenge, user, nil
+}
+
+// enroll gives the user a new authenticator secret, replacing one from an
+// enrolment they never confirmed.
+func (s *TwoFactorServiceImpl) enroll(user domain.User) (domain.TOTPEnrollment, error) {
+	existing, err := s.UserTOTPRepository.FindByUserId(s.DB, user.ID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return domain.TOTPEnrollment{}, err
+	}
+	if err == nil && existing.IsConfirmed() {
+		return domain.TOTPEnrollment{}, exception.NewCustomError(http.StatusConflict, "Two-factor authentication is already enabled")
+	}
+
+	secret, err := helper.NewTOTPSecret()
+	if err != nil {
+		return domain.TOTPEnrollment{}, err
+	}
+	sealed, err := s.SecretBox.Seal(secret)
+	if err != nil {
+		return domain.TOTPEnrollment{}, err
+	}
+
+	_, err = s.UserTOTPRepository.Save(s.DB, domain.UserTOTP{UserID: user.ID, Secret: sealed})
+	if err != nil {
+		return domain.TOTPEnrollment{}, err
+	}
+
+	return domain.TOTPEnrollment{
+		Secret: secret,
+		URI:    helper.TOTPURI(s.Issuer, user.Email, secret),
+	}, nil
+}
+
+// confirm checks the first code from a new authenticator, turns two-factor
+// authentication on and issues the user's recovery codes.
+func (s *TwoFactorServiceImpl) confirm(tx *gorm.DB, totp domain.UserTOTP, code string, now time.Time) ([]string, error) {
+	secret, err := s.SecretBox.Open(totp.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	step, ok := helper.ValidateTOTP(secret, strings.TrimSpace(code), now, totp.LastUsedStep)
+	if !ok {
+		return nil, errInvalidTwoFactorCode
+	}
+
+	err = s.UserTOTPRepository.Confirm(tx, totp.ID, now, step)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := s.replaceRecoveryCodes(tx, totp.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.AuditLogRepository.Create(tx, domain.AuditLog{
+		Action:  domain.AuditActionTwoFactorEnabled,
+		UserID:  &totp.UserID,
+		ActorID: &totp.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// verifyCode accepts a TOTP code newer than the last one used, or an unused
+// recovery code, and uses it up.
+func (s *TwoFactorServiceImpl) verifyCode(tx *gorm.DB, totp domain.UserTOTP, code string, now time.Time) error {
+	secret, err := s.SecretBox.Open(totp.Secret)
+	if err != nil {
+		return err
+	}
+
+	code = strings.TrimSpace(code)
+	if step, ok := helper.ValidateTOTP(secret, code, now, totp.LastUsedStep); ok {
+		return s.UserTOTPRepository.UpdateLastUsedStep(tx, totp.ID, step)
+	}
+
+	recoveryCode, err := s.RecoveryCodeRepository.LockUnusedByHash(tx, totp.UserID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errInvalidTwoFactorCode
+		}
+		return err
+	}
+
+	err = s.RecoveryCodeRepository.MarkUsed(tx, recoveryCode.ID, now)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.AuditLogRepository.Create(tx, domain.AuditLog{
+		Action: domain.AuditActionRecoveryCodeUsed,
+		UserID: &totp.UserID,
+	})
+	return err
+}
+
+// withCode runs fn for a user with two-factor authentication enabled once
+// code checks out. Wrong codes count against the user like wrong codes at
+// login, so a stolen session cannot be used to guess them.
+func (s *TwoFactorServiceImpl) withCode(userId int, code string, fn func(tx *gorm.DB, totp domain.UserTOTP) error) error {
+	now := time.Now()
+	throttler := s.loginThrottler()
+	throttles := []domain.LoginThrottle{{Scope: domain.LoginThrottleScopeTwoFactor, Key: strconv.Itoa(userId)}}
+	if err := throttler.check(throttles, now); err != nil {
+		return err
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		totp, err := s.UserTOTPRepository.LockByUserId(tx, userId)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		if err != nil || !totp.IsConfirmed() {
+			return exception.NewCustomError(http.StatusBadRequest, "Two-factor authentication is not enabled")
+		}
+
+		err = s.verifyCode(tx, totp, code, now)
+		if err != nil {
+			return err
+		}
+		return fn(tx, totp)
+	})
+	if errors.Is(err, errInvalidTwoFactorCode) {
+		if err := throttler.recordFailure(throttles, userId, "", now); err != nil {
+			log.Printf("Failed to record wrong two-factor code: %v", err)
+		}
+		return exception.NewCustomError(http.StatusBadRequest, "Invalid two-factor code")
+	}
+	return err
+}
+
+// replaceRecoveryCodes issues the user a new set of recovery codes and
+// stores only their hashes.
+func (s *TwoFactorServiceImpl) replaceRecoveryCodes(tx *gorm.DB, userId int) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = hashToken(normalizeRecoveryCode(code))
+	}
+
+	err := s.RecoveryCodeRepository.ReplaceByUserId(tx, userId, hashes)
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *TwoFactorServiceImpl) loginThrottler() loginThrottler {
+	return loginThrottler{
+		LoginThrottleRepository: s.LoginThrottleRepository,
+		AuditLogRepository:      s.AuditLogRepository,
+		LoginPolicy:             s.LoginPolicy,
+		DB:                      s.DB,
+	}
+}
+
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// newRecoveryCode returns a random code such as "k3m7q-7xw2p", 50 bits
+// strong.
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := recoveryCodeEncoding.EncodeToString(b)[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeRecoveryCode lets users type recovery codes in either case and
+// with or without the dash.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/helper"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository/mock"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type twoFactorServiceMocks struct {
+	userRepo          *mock.UserRepositoryMock
+	userTOTPRepo      *mock.UserTOTPRepositoryMock
+	recoveryCodeRepo  *mock.RecoveryCodeRepositoryMock
+	policyRepo        *mock.TwoFactorPolicyRepositoryMock
+	loginThrottleRepo *mock.LoginThrottleRepositoryMock
+	auditLogRepo      *mock.AuditLogRepositoryMock
+	signer            helper.LoginChallengeSigner
+	secretBox         helper.SecretBox
+}
+
+func newTwoFactorServiceForTest(t *testing.T, db *gorm.DB) (TwoFactorService, twoFactorServiceMocks) {
+	secretBox, err := helper.NewSecretBox("test-secret")
+	assert.NoError(t, err)
+
+	mocks := twoFactorServiceMocks{
+		userRepo:          new(mock.UserRepositoryMock),
+		userTOTPRepo:      new(mock.UserTOTPRepositoryMock),
+		recoveryCodeRepo:  new(mock.RecoveryCodeRepositoryMock),
+		policyRepo:        new(mock.TwoFactorPolicyRepositoryMock),
+		loginThrottleRepo: new(mock.LoginThrottleRepositoryMock),
+		auditLogRepo:      new(mock.AuditLogRepositoryMock),
+		signer:            helper.NewLoginChallengeSigner("test-secret", 5*time.Minute),
+		secretBox:         secretBox,
+	}
+	return NewTwoFactorService(mocks.userRepo, mocks.userTOTPRepo, mocks.recoveryCodeRepo, mocks.policyRepo, mocks.loginThrottleRepo, mocks.auditLogRepo, testLoginPolicy, mocks.signer, mocks.secretBox, "Hotel", db), mocks
+}
+
+// sealedTOTPSecret returns a new TOTP secret and its encrypted form.
+func sealedTOTPSecret(t *testing.T, secretBox helper.SecretBox) (string, string) {
+	secret, err := helper.NewTOTPSecret()
+	assert.NoError(t, err)
+	sealed, err := secretBox.Seal(secret)
+	assert.NoError(t, err)
+	return secret, sealed
+}
+
+func currentTOTPCode(t *testing.T, secret string) string {
+	code, err := helper.TOTPCode(secret, helper.TOTPStep(time.Now()))
+	assert.NoError(t, err)
+	return code
+}
+
+func TestTwoFactorService_Challenge_NotRequired(t *testing.T) {
+	service, mocks := newTwoFactorServiceForTest(t, &gorm.DB{})
+
+	mocks.userTOTPRepo.On("FindByUserId", testifymock.Anything, 5).Return(domain.UserTOTP{}, gorm.ErrRecordNotFound)
+	mocks.policyRepo.On("FindByRole", testifymock.Anything, domain.RoleGuest).Return(domain.TwoFactorPolicy{}, gorm.ErrRecordNotFound)
+
+	challenge, err := service.Challenge(domain.User{ID: 5, Role: domain.RoleGuest})
+
+	assert.NoError(t, err)
+	assert.Nil(t, challenge)
+}
+
+func TestTwoFactorService_Challenge_RequiredRoleMustEnroll(t *testing.T) {
+	service, mocks := newTwoFactorServiceForTest(t, &gorm.DB{})
+
+	mocks.userTOTPRepo.On("FindByUserId", testifymock.Anything, 5).Return(domain.UserTOTP{}, gorm.ErrRecordNotFound)
+	mocks.policyRepo.On("FindByRole", testifymock.Anything, domain.RoleStaff).Return(domain.TwoFactorPolicy{Role: domain.RoleStaff, Required: true}, nil)
+
+	challenge, err := service.Challenge(domain.User{ID: 5, Role: domain.RoleStaff})
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, challenge) {
+		assert.True(t, challenge.EnrollmentRequired)
+		verified, err := mocks.signer.Verify(challenge.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, verified.UserID)
+	}
+}
+
+func TestTwoFactorService_VerifyLogin_Success(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newTwoFactorServiceForTest(t, db)
+
+	secret, sealed := sealedTOTPSecret(t, mocks.secretBox)
+	challenge, err := mocks.signer.Sign(5, false)
+	assert.NoError(t, err)
+
+	mocks.userRepo.On("FindById", testifymock.Anything, 5).Return(domain.User{ID: 5, Role: domain.RoleStaff}, nil)
+	mocks.loginThrottleRepo.On("Find", testifymock.Anything, domain.LoginThrottleScopeTwoFactor, "5").Return(domain.LoginThrottle{}, gorm.ErrRecordNotFound)
+	mocks.loginThrottleRepo.On("Find", testifymock.Anything, domain.LoginThrottleScopeIP, "10.0.0.1").Return(domain.LoginThrottle{}, gorm.ErrRecordNotFound)
+	sqlMock.ExpectBegin()
+	mocks.userTOTPRepo.On("LockByUserId", testifymock.Anything, 5).Return(domain.UserTOTP{ID: 1, UserID: 5, Secret: sealed, ConfirmedAt: &verifiedAt}, nil)
+	mocks.userTOTPRepo.On("UpdateLastUsedStep", testifymock.Anything, 1, testifymock.AnythingOfType("int64")).Return(nil)
+	sqlMock.ExpectCommit()
+	mocks.loginThrottleRepo.On("Delete", testifymock.Anything, domain.LoginThrottleScopeTwoFactor, "5").Return(nil)
+
+	user, recoveryCodes, err := service.VerifyLogin(challenge.Token, currentTOTPCode(t, secret), "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, user.ID)
+	assert.Nil(t, recoveryCodes)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mocks.userTOTPRepo.AssertExpectations(t)
+	mocks.loginThrottleRepo.AssertExpectations(t)
+}
+
+func TestTwoFactorService_VerifyLogin_ReplayedCode(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newTwoFactorServiceForTest(t, db)
+
+	secret, sealed := sealedTOTPSecret(t, mocks.secretBox)
+	code := currentTOTPCode(t, secret)
+	challenge, err := mocks.signer.Sign(5, false)
+	assert.NoError(t, err)
+
+	mocks.userRepo.On("FindById", testifymock.Anything, 5).Return(domain.User{ID: 5, Role: domain.RoleStaff}, nil)
+	mocks.loginThrottleRepo.On("Find", testifymock.Anything, domain.LoginThrottleScopeTwoFactor, "5").Return(domain.LoginThrottle{}, gorm.ErrRecordNotFound)
+	sqlMock.ExpectBegin()
+	mocks.userTOTPRepo.On("LockByUserId", testifymock.Anything, 5).Return(domain.UserTOTP{ID: 1, UserID: 5, Secret: sealed, ConfirmedAt: &verifiedAt, LastUsedStep: helper.TOTPStep(time.Now()) + 1}, nil)
+	mocks.recoveryCodeRepo.On("LockUnusedByHash", testifymock.Anything, 5, hashToken(code)).Return(domain.RecoveryCode{}, gorm.ErrRecordNotFound)
+	sqlMock.ExpectRollback()
+	sqlMock.ExpectBegin()
+	mocks.loginThrottleRepo.On("Lock", testifymock.Anything, domain.LoginThrottleScopeTwoFactor, "5").Return(domain.LoginThrottle{ID: 1, Scope: domain.LoginThrottleScopeTwoFactor, Key: "5"}, nil)
+	mocks.loginThrottleRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(throttle domain.LoginThrottle) bool {
+		return throttle.Failures == 1
+	})).Return(nil)
+	sqlMock.ExpectCommit()
+
+	_, _, err = service.VerifyLogin(challenge.Token, code, "")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnauthorized, customErr.Code)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mocks.userTOTPRepo.AssertNotCalled(t, "UpdateLastUsedStep", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+	mocks.loginThrottleRepo.AssertExpectations(t)
+}
+
+func TestTwoFactorService_VerifyLogin_RecoveryCode(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newTwoFactorServiceForTest(t, db)
+
+	_, sealed := sealedTOTPSecret(t, mocks.secretBox)
+	challenge, err := mocks.signer.Sign(5, false)
+	assert.NoError(t, err)
+
+	mocks.userRepo.On("FindById", testifymock.Anything, 5).Return(domain.User{ID: 5, Role: domain.RoleStaff}, nil)
+	mocks.loginThrottleRepo.On("Find", testifymock.Anything, domain.LoginThrottleScopeTwoFactor, "5").Return(domain.LoginThrottle{}, gorm.ErrRecordNotFound)
+	sqlMock.ExpectBegin()
+	mocks.userTOTPRepo.On("LockByUserId", testifymock.Anything, 5).Return(domain.UserTOTP{ID: 1, UserID: 5, Secret: sealed, ConfirmedAt: &verifiedAt}, nil)
+	mocks.recoveryCodeRepo.On("LockUnusedByHash", testifymock.Anything, 5, hashToken("abcde23456")).Return(domain.RecoveryCode{ID: 3, UserID: 5}, nil)
+	mocks.recoveryCodeRepo.On("MarkUsed", testifymock.Anything, 3, testifymock.Anything).Return(nil)
+	mocks.auditLogRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(auditLog domain.AuditLog) bool {
+		return auditLog.Action == domain.AuditActionRecoveryCodeUsed && *auditLog.UserID == 5
+	})).Return(domain.AuditLog{ID: 1}, nil)
+	sqlMock.ExpectCommit()
+	mocks.loginThrottleRepo.On("Delete", testifymock.Anything, domain.LoginThrottleScopeTwoFactor, "5").Return(nil)
+
+	user, _, err := service.VerifyLogin(challenge.Token, " ABCDE-23456 ", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, user.ID)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mocks.recoveryCodeRepo.AssertExpectations(t)
+	mocks.auditLogRepo.AssertExpectations(t)
+}
+
+func TestTwoFactorService_VerifyLogin_EnrollmentIssuesRecoveryCodes(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newTwoFactorServiceForTest(t, db)
+
+	secret, sealed := sealedTOTPSecret(t, mocks.secretBox)
+	challenge, err := mocks.signer.Sign(5, true)
+	assert.NoError(t, err)
+
+	var storedHashes []string
+	mocks.userRepo.On("FindById", testifymock.Anything, 5).Return(domain.User{ID: 5, Role: domain.RoleStaff}, nil)
+	mocks.loginThrottleRepo.On("Find", testifymock.Anything, domain.LoginThrottleScopeTwoFactor, "5").Return(domain.LoginThrottle{}, gorm.ErrRecordNotFound)
+	sqlMock.ExpectBegin()
+	mocks.userTOTPRepo.On("LockByUserId", testifymock.Anything, 5).Return(domain.UserTOTP{ID: 1, UserID: 5, Secret: sealed}, nil)
+	mocks.userTOTPRepo.On("Confirm", testifymock.Anything, 1, testifymock.Anything, testifymock.AnythingOfType("int64")).Return(nil)
+	mocks.recoveryCodeRepo.On("ReplaceByUserId", testifymock.Anything, 5, testifymock.MatchedBy(func(hashes []string) bool {
+		storedHashes = hashes
+		return true
+	})).Return(nil)
+	mocks.auditLogRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(auditLog domain.AuditLog) bool {
+		return auditLog.Action == domain.AuditActionTwoFactorEnabled && *auditLog.UserID == 5
+	})).Return(domain.AuditLog{ID: 1}, nil)
+	sqlMock.ExpectCommit()
+	mocks.loginThrottleRepo.On("Delete", testifymock.Anything, domain.LoginThrottleScopeTwoFactor, "5").Return(nil)
+
+	_, recoveryCodes, err := service.VerifyLogin(challenge.Token, currentTOTPCode(t, secret), "")
+
+	assert.NoError(t, err)
+	assert.Len(t, recoveryCodes, recoveryCodeCount)
+	assert.Len(t, storedHashes, recoveryCodeCount)
+	for i, code := range recoveryCodes {
+		assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, code)
+		assert.Equal(t, hashToken(normalizeRecoveryCode(code)), storedHashes[i])
+	}
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mocks.userTOTPRepo.AssertExpectations(t)
+}
+
+func TestTwoFactorService_VerifyLogin_InvalidChallenge(t *testing.T) {
+	service, _ := newTwoFactorServiceForTest(t, &gorm.DB{})
+
+	accessToken, err := helper.NewTokenIssuer("test-secret", time.Minute).Issue(5, domain.RoleStaff, "session")
+	assert.NoError(t, err)
+
+	_, _, err = service.VerifyLogin(accessToken.Token, "123456", "")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnauthorized, customErr.Code)
+}
+
+func TestTwoFactorService_Disable_RequiredRole(t *testing.T) {
+	service, mocks := newTwoFactorServiceForTest(t, &gorm.DB{})
+
+	mocks.userRepo.On("FindById", testifymock.Anything, 5).Return(domain.User{ID: 5, Role: domain.RoleAdmin}, nil)
+	mocks.policyRepo.On("FindByRole", testifymock.Anything, domain.RoleAdmin).Return(domain.TwoFactorPolicy{Role: domain.RoleAdmin, Required: true}, nil)
+
+	err := service.Disable(5, "123456")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusForbidden, customErr.Code)
+	mocks.userTOTPRepo.AssertNotCalled(t, "DeleteByUserId", testifymock.Anything, testifymock.Anything)
+}
+
+func TestTwoFactorService_UpdatePolicy_Success(t *testing.T) {
+	db, sqlMock, _ := setupMockDB()
+	service, mocks := newTwoFactorServiceForTest(t, db)
+
+	sqlMock.ExpectBegin()
+	mocks.policyRepo.On("Save", testifymock.Anything, testifymock.MatchedBy(func(policy domain.TwoFactorPolicy) bool {
+		return policy.Role == domain.RoleStaff && policy.Required && *policy.UpdatedBy == 1
+	})).Return(domain.TwoFactorPolicy{Role: domain.RoleStaff, Required: true}, nil)
+	mocks.auditLogRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(auditLog domain.AuditLog) bool {
+		return auditLog.Action == domain.AuditActionTwoFactorPolicyUpdated && *auditLog.ActorID == 1
+	})).Return(domain.AuditLog{ID: 1}, nil)
+	sqlMock.ExpectCommit()
+
+	policy, err := service.UpdatePolicy(1, domain.RoleStaff, true)
+
+	assert.NoError(t, err)
+	assert.True(t, policy.Required)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mocks.auditLogRepo.AssertExpectations(t)
+}
+
+func TestTwoFactorService_UpdatePolicy_InvalidRole(t *testing.T) {
+	service, _ := newTwoFactorServiceForTest(t, &gorm.DB{})
+
+	_, err := service.UpdatePolicy(1, "owner", true)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, customErr.Code)
+}
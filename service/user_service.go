@@ -1,10 +1,16 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"hotel_ip-p2/exception"
 	"hotel_ip-p2/model/domain"
 	"hotel_ip-p2/repository"
+	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -12,27 +18,53 @@ import (
 
 type UserService interface {
 	Register(user domain.User) (domain.User, error)
-	Login(email, password string) (domain.User, error)
+	Login(email, password, ip string) (domain.User, error)
+	Unlock(actorId int, id int) (domain.User, error)
+	PurgeLoginThrottles(ctx context.Context) (int64, error)
 	GetById(id int) (domain.User, error)
+	FindAll(query domain.ListQuery) ([]domain.User, int64, error)
+	UpdateRole(id int, role string) (domain.User, error)
+	BootstrapAdmin(email string) (domain.User, error)
+	Delete(id int) error
+	FindDeleted(query domain.ListQuery) ([]domain.User, int64, error)
+	Restore(id int) (domain.User, error)
 }
 
 type userServiceImpl struct {
-	UserRepository repository.UserRepository
-	DB             *gorm.DB
+	UserRepository          repository.UserRepository
+	BookRoomRepository      repository.BookRoomRepository
+	TopupRepository         repository.TopupRepository
+	LoginThrottleRepository repository.LoginThrottleRepository
+	AuditLogRepository      repository.AuditLogRepository
+	TokenService            TokenService
+	LoginPolicy             domain.LoginPolicy
+	DB                      *gorm.DB
 }
 
-func NewUserService(userRepository repository.UserRepository, db *gorm.DB) UserService {
+func NewUserService(userRepository repository.UserRepository, bookRoomRepository repository.BookRoomRepository, topupRepository repository.TopupRepository, loginThrottleRepository repository.LoginThrottleRepository, auditLogRepository repository.AuditLogRepository, tokenService TokenService, loginPolicy domain.LoginPolicy, db *gorm.DB) UserService {
 	return &userServiceImpl{
-		UserRepository: userRepository,
-		DB:             db,
+		UserRepository:          userRepository,
+		BookRoomRepository:      bookRoomRepository,
+		TopupRepository:         topupRepository,
+		LoginThrottleRepository: loginThrottleRepository,
+		AuditLogRepository:      auditLogRepository,
+		TokenService:            tokenService,
+		LoginPolicy:             loginPolicy,
+		DB:                      db,
 	}
 }
+
+// dummyPasswordHash is checked against when a login names an unknown email,
+// so such a login takes as long as one with a wrong password.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("not a real password"), bcrypt.DefaultCost)
+
 func (service *userServiceImpl) Register(user domain.User) (domain.User, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return domain.User{}, exception.NewCustomError(http.StatusInternalServerError, "failed to hash password")
 	}
 	user.Password = string(hashedPassword)
+	user.Role = domain.RoleGuest
 
 	result, err := service.UserRepository.Register(service.DB, user)
 	if err != nil {
@@ -41,20 +73,91 @@ func (service *userServiceImpl) Register(user domain.User) (domain.User, error)
 	return result, nil
 }
 
-func (service *userServiceImpl) Login(email, password string) (domain.User, error) {
+// Login checks a user's credentials. Unknown emails and wrong passwords get
+// the same error so logins cannot be used to find out who has an account.
+// Failed logins are counted per email and per client IP; past a few of them
+// further attempts are held back for longer and longer, and then locked out.
+func (service *userServiceImpl) Login(email, password, ip string) (domain.User, error) {
+	now := time.Now()
+	throttler := service.loginThrottler()
+	throttles := loginThrottleKeys(email, ip)
+
+	if err := throttler.check(throttles, now); err != nil {
+		return domain.User{}, err
+	}
+
 	user, err := service.UserRepository.FindByEmail(service.DB, email)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return domain.User{}, err
+	}
+
+	passwordHash := []byte(user.Password)
+	if err != nil {
+		passwordHash = dummyPasswordHash
+	}
+
+	if bcrypt.CompareHashAndPassword(passwordHash, []byte(password)) != nil || err != nil {
+		if err := throttler.recordFailure(throttles, user.ID, ip, now); err != nil {
+			log.Printf("Failed to record failed login: %v", err)
+		}
+		return domain.User{}, exception.NewCustomError(http.StatusUnauthorized, "invalid email or password")
+	}
+
+	throttler.reset(email, user.ID)
+	return user, nil
+}
+
+func (service *userServiceImpl) loginThrottler() loginThrottler {
+	return loginThrottler{
+		LoginThrottleRepository: service.LoginThrottleRepository,
+		AuditLogRepository:      service.AuditLogRepository,
+		LoginPolicy:             service.LoginPolicy,
+		DB:                      service.DB,
+	}
+}
+
+// Unlock clears the failed logins and wrong two-factor codes of a user so
+// they can log in again straight away.
+func (service *userServiceImpl) Unlock(actorId int, id int) (domain.User, error) {
+	user, err := service.UserRepository.FindById(service.DB, id)
 	if err != nil {
 		return domain.User{}, exception.NewCustomError(http.StatusNotFound, "user not found")
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	err = service.DB.Transaction(func(tx *gorm.DB) error {
+		err := service.LoginThrottleRepository.Delete(tx, domain.LoginThrottleScopeAccount, normalizeEmail(user.Email))
+		if err != nil {
+			return err
+		}
+
+		err = service.LoginThrottleRepository.Delete(tx, domain.LoginThrottleScopeTwoFactor, strconv.Itoa(user.ID))
+		if err != nil {
+			return err
+		}
+
+		_, err = service.AuditLogRepository.Create(tx, domain.AuditLog{
+			Action:  domain.AuditActionLoginUnlock,
+			UserID:  &user.ID,
+			ActorID: &actorId,
+			Details: fmt.Sprintf("account %s unlocked", normalizeEmail(user.Email)),
+		})
+		return err
+	})
 	if err != nil {
-		return domain.User{}, exception.NewCustomError(http.StatusUnauthorized, "invalid credentials")
+		return domain.User{}, err
 	}
 
 	return user, nil
 }
 
+// PurgeLoginThrottles deletes the failed login counts that have expired.
+func (service *userServiceImpl) PurgeLoginThrottles(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return service.LoginThrottleRepository.DeleteStale(service.DB, time.Now().Add(-service.LoginPolicy.LockoutDuration))
+}
+
 func (service *userServiceImpl) GetById(id int) (domain.User, error) {
 	user, err := service.UserRepository.FindById(service.DB, id)
 	if err != nil {
@@ -62,3 +165,177 @@ func (service *userServiceImpl) GetById(id int) (domain.User, error) {
 	}
 	return user, nil
 }
+
+func (service *userServiceImpl) FindAll(query domain.ListQuery) ([]domain.User, int64, error) {
+	users, total, err := service.UserRepository.FindAll(service.DB, query)
+	if errors.Is(err, repository.ErrInvalidListQuery) {
+		return nil, 0, exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+	return users, total, err
+}
+
+// UpdateRole changes a user's role and ends all of their sessions. The role
+// is carried in access tokens, so without that a demoted user would keep
+// their old permissions until their token expired.
+func (service *userServiceImpl) UpdateRole(id int, role string) (domain.User, error) {
+	if !domain.IsValidRole(role) {
+		return domain.User{}, exception.NewCustomError(http.StatusBadRequest, "invalid role")
+	}
+
+	var result domain.User
+
+	err := service.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := service.UserRepository.FindById(tx, id)
+		if err != nil {
+			return exception.NewCustomError(http.StatusNotFound, "user not found")
+		}
+
+		if user.Role == domain.RoleAdmin && role != domain.RoleAdmin {
+			admins, err := service.UserRepository.LockByRole(tx, domain.RoleAdmin)
+			if err != nil {
+				return err
+			}
+			if admins <= 1 {
+				return exception.NewCustomError(http.StatusBadRequest, "cannot demote the last admin")
+			}
+		}
+
+		err = service.UserRepository.UpdateRole(tx, id, role)
+		if err != nil {
+			return err
+		}
+
+		user.Role = role
+		result = user
+		return nil
+	})
+
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	log.Printf("Role of user ID: %d changed to %s, ending all sessions", id, role)
+	err = service.TokenService.LogoutAll(id)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return result, nil
+}
+
+// BootstrapAdmin promotes the user registered with email to admin when no
+// admin exists yet. It does nothing once any admin is present, so it is safe
+// to run on every start-up.
+func (service *userServiceImpl) BootstrapAdmin(email string) (domain.User, error) {
+	admins, err := service.UserRepository.CountByRole(service.DB, domain.RoleAdmin)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if admins > 0 {
+		return domain.User{}, nil
+	}
+
+	user, err := service.UserRepository.FindByEmail(service.DB, email)
+	if err != nil {
+		return domain.User{}, exception.NewCustomError(http.StatusNotFound, "user not found")
+	}
+
+	err = service.UserRepository.UpdateRole(service.DB, user.ID, domain.RoleAdmin)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	user.Role = domain.RoleAdmin
+	return user, nil
+}
+
+// Delete archives a user and ends all of their sessions. Their bookings and
+// wallet history stay in place, but they can no longer log in. Like deleting
+// one's own account, it is refused while the user still has money in their
+// wallet, upcoming bookings or a pending topup, and the last admin cannot be
+// deleted.
+func (service *userServiceImpl) Delete(id int) error {
+	err := service.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := service.UserRepository.LockById(tx, id)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return exception.NewCustomError(http.StatusNotFound, "user not found")
+			}
+			return err
+		}
+
+		if user.Balance > 0 {
+			return exception.NewCustomError(http.StatusConflict, fmt.Sprintf("cannot delete a user whose wallet still holds %s", user.Balance))
+		}
+
+		active, err := service.BookRoomRepository.CountActiveByUserId(tx, id)
+		if err != nil {
+			return err
+		}
+		if active > 0 {
+			return exception.NewCustomError(http.StatusConflict, "cannot delete a user with upcoming bookings")
+		}
+
+		pending, err := service.TopupRepository.CountPendingByUserId(tx, id)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return exception.NewCustomError(http.StatusConflict, "cannot delete a user with a pending topup")
+		}
+
+		if user.Role == domain.RoleAdmin {
+			admins, err := service.UserRepository.LockByRole(tx, domain.RoleAdmin)
+			if err != nil {
+				return err
+			}
+			if admins <= 1 {
+				return exception.NewCustomError(http.StatusBadRequest, "cannot delete the last admin")
+			}
+		}
+
+		return service.UserRepository.Delete(tx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Deleted user ID: %d, ending all sessions", id)
+	return service.TokenService.LogoutAll(id)
+}
+
+func (service *userServiceImpl) FindDeleted(query domain.ListQuery) ([]domain.User, int64, error) {
+	users, total, err := service.UserRepository.FindDeleted(service.DB, query)
+	if errors.Is(err, repository.ErrInvalidListQuery) {
+		return nil, 0, exception.NewCustomError(http.StatusBadRequest, err.Error())
+	}
+	return users, total, err
+}
+
+// Restore lets a deleted user log in again unless someone else has
+// registered with their email in the meantime.
+func (service *userServiceImpl) Restore(id int) (domain.User, error) {
+	user, err := service.UserRepository.FindDeletedById(service.DB, id)
+	if err != nil {
+		return domain.User{}, exception.NewCustomError(http.StatusNotFound, "user not found")
+	}
+
+	_, err = service.UserRepository.FindByEmail(service.DB, user.Email)
+	if err == nil {
+		return domain.User{}, exception.NewCustomError(http.StatusConflict, "email already exists")
+	}
+	if err != gorm.ErrRecordNotFound {
+		return domain.User{}, err
+	}
+
+	err = service.UserRepository.Restore(service.DB, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return domain.User{}, exception.NewCustomError(http.StatusConflict, "email already exists")
+		}
+		return domain.User{}, err
+	}
+
+	user.DeletedAt = gorm.DeletedAt{}
+	return user, nil
+}
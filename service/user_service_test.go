@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"hotel_ip-p2/exception"
+	"hotel_ip-p2/helper"
 	"hotel_ip-p2/model/domain"
 	"hotel_ip-p2/repository/mock"
 	"testing"
@@ -16,7 +17,7 @@ import (
 
 func TestUserService_Register_Success(t *testing.T) {
 	mockRepo := new(mock.UserRepositoryMock)
-	service := NewUserService(mockRepo, &gorm.DB{})
+	service := NewUserService(mockRepo, nil, nil, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, &gorm.DB{})
 
 	user := domain.User{
 		Name:     "John Doe",
@@ -34,7 +35,7 @@ func TestUserService_Register_Success(t *testing.T) {
 	}
 
 	mockRepo.On("Register", &gorm.DB{}, testifymock.MatchedBy(func(u domain.User) bool {
-		return u.Name == "John Doe" && u.Email == "john@example.com"
+		return u.Name == "John Doe" && u.Email == "john@example.com" && u.Role == domain.RoleGuest
 	})).Return(expectedUser, nil)
 
 	result, err := service.Register(user)
@@ -48,7 +49,7 @@ func TestUserService_Register_Success(t *testing.T) {
 
 func TestUserService_Register_EmailAlreadyExists(t *testing.T) {
 	mockRepo := new(mock.UserRepositoryMock)
-	service := NewUserService(mockRepo, &gorm.DB{})
+	service := NewUserService(mockRepo, nil, nil, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, &gorm.DB{})
 
 	user := domain.User{
 		Name:     "John Doe",
@@ -70,9 +71,18 @@ func TestUserService_Register_EmailAlreadyExists(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+var testLoginPolicy = domain.LoginPolicy{
+	FreeAttempts:           3,
+	MaxBackoff:             5 * time.Minute,
+	AccountLockoutFailures: 10,
+	IPLockoutFailures:      50,
+	LockoutDuration:        15 * time.Minute,
+}
+
 func TestUserService_Login_Success(t *testing.T) {
 	mockRepo := new(mock.UserRepositoryMock)
-	service := NewUserService(mockRepo, &gorm.DB{})
+	mockThrottleRepo := new(mock.LoginThrottleRepositoryMock)
+	service := NewUserService(mockRepo, nil, nil, mockThrottleRepo, new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, &gorm.DB{})
 
 	password := "password123"
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -85,35 +95,52 @@ func TestUserService_Login_Success(t *testing.T) {
 		Balance:  0,
 	}
 
+	mockThrottleRepo.On("Find", &gorm.DB{}, domain.LoginThrottleScopeAccount, "john@example.com").Return(domain.LoginThrottle{}, gorm.ErrRecordNotFound)
+	mockThrottleRepo.On("Find", &gorm.DB{}, domain.LoginThrottleScopeIP, "203.0.113.7").Return(domain.LoginThrottle{}, gorm.ErrRecordNotFound)
 	mockRepo.On("FindByEmail", &gorm.DB{}, "john@example.com").Return(expectedUser, nil)
+	mockThrottleRepo.On("Delete", &gorm.DB{}, domain.LoginThrottleScopeAccount, "john@example.com").Return(nil)
 
-	result, err := service.Login("john@example.com", password)
+	result, err := service.Login("john@example.com", password, "203.0.113.7")
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser.ID, result.ID)
 	assert.Equal(t, expectedUser.Email, result.Email)
 	mockRepo.AssertExpectations(t)
+	mockThrottleRepo.AssertExpectations(t)
 }
 
 func TestUserService_Login_UserNotFound(t *testing.T) {
 	mockRepo := new(mock.UserRepositoryMock)
-	service := NewUserService(mockRepo, &gorm.DB{})
+	mockThrottleRepo := new(mock.LoginThrottleRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewUserService(mockRepo, nil, nil, mockThrottleRepo, new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, db)
 
-	mockRepo.On("FindByEmail", &gorm.DB{}, "notfound@example.com").Return(domain.User{}, gorm.ErrRecordNotFound)
+	mockThrottleRepo.On("Find", testifymock.Anything, domain.LoginThrottleScopeAccount, "notfound@example.com").Return(domain.LoginThrottle{}, gorm.ErrRecordNotFound)
+	mockRepo.On("FindByEmail", testifymock.Anything, "notfound@example.com").Return(domain.User{}, gorm.ErrRecordNotFound)
+	sqlMock.ExpectBegin()
+	mockThrottleRepo.On("Lock", testifymock.Anything, domain.LoginThrottleScopeAccount, "notfound@example.com").Return(domain.LoginThrottle{ID: 1, Scope: domain.LoginThrottleScopeAccount, Key: "notfound@example.com"}, nil)
+	mockThrottleRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(throttle domain.LoginThrottle) bool {
+		return throttle.Failures == 1 && throttle.LockedUntil == nil
+	})).Return(nil)
+	sqlMock.ExpectCommit()
 
-	result, err := service.Login("notfound@example.com", "password123")
+	result, err := service.Login("notfound@example.com", "password123", "")
 
 	assert.Error(t, err)
 	assert.Equal(t, domain.User{}, result)
 	customErr, ok := err.(*exception.CustomError)
 	assert.True(t, ok)
-	assert.Equal(t, "user not found", customErr.Message)
-	mockRepo.AssertExpectations(t)
+	assert.Equal(t, 401, customErr.Code)
+	assert.Equal(t, "invalid email or password", customErr.Message)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mockThrottleRepo.AssertExpectations(t)
 }
 
 func TestUserService_Login_InvalidPassword(t *testing.T) {
 	mockRepo := new(mock.UserRepositoryMock)
-	service := NewUserService(mockRepo, &gorm.DB{})
+	mockThrottleRepo := new(mock.LoginThrottleRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewUserService(mockRepo, nil, nil, mockThrottleRepo, new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, db)
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correctpassword"), bcrypt.DefaultCost)
 
@@ -124,27 +151,114 @@ func TestUserService_Login_InvalidPassword(t *testing.T) {
 		Password: string(hashedPassword),
 	}
 
-	mockRepo.On("FindByEmail", &gorm.DB{}, "john@example.com").Return(existingUser, nil)
-
-	result, err := service.Login("john@example.com", "wrongpassword")
+	lastFailure := time.Now().Add(-time.Minute)
+	mockThrottleRepo.On("Find", testifymock.Anything, testifymock.Anything, testifymock.Anything).Return(domain.LoginThrottle{}, gorm.ErrRecordNotFound)
+	mockRepo.On("FindByEmail", testifymock.Anything, "John@Example.com").Return(existingUser, nil)
+	sqlMock.ExpectBegin()
+	mockThrottleRepo.On("Lock", testifymock.Anything, domain.LoginThrottleScopeAccount, "john@example.com").Return(domain.LoginThrottle{ID: 1, Scope: domain.LoginThrottleScopeAccount, Key: "john@example.com", Failures: 4, LastFailureAt: &lastFailure}, nil)
+	mockThrottleRepo.On("Lock", testifymock.Anything, domain.LoginThrottleScopeIP, "203.0.113.7").Return(domain.LoginThrottle{ID: 2, Scope: domain.LoginThrottleScopeIP, Key: "203.0.113.7"}, nil)
+	mockThrottleRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(throttle domain.LoginThrottle) bool {
+		// The fifth failure is the second past the free attempts, so the
+		// next attempt is held back for two seconds.
+		return throttle.ID == 1 && throttle.Failures == 5 && throttle.LockedUntil != nil &&
+			throttle.LockedUntil.Sub(*throttle.LastFailureAt) == 2*time.Second
+	})).Return(nil)
+	mockThrottleRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(throttle domain.LoginThrottle) bool {
+		return throttle.ID == 2 && throttle.Failures == 1 && throttle.LockedUntil == nil
+	})).Return(nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.Login("John@Example.com", "wrongpassword", "203.0.113.7")
 
 	assert.Error(t, err)
 	assert.Equal(t, domain.User{}, result)
 	customErr, ok := err.(*exception.CustomError)
 	assert.True(t, ok)
-	assert.Equal(t, "invalid credentials", customErr.Message)
-	mockRepo.AssertExpectations(t)
+	assert.Equal(t, "invalid email or password", customErr.Message)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mockThrottleRepo.AssertExpectations(t)
+}
+
+func TestUserService_Login_LockedOut(t *testing.T) {
+	mockRepo := new(mock.UserRepositoryMock)
+	mockThrottleRepo := new(mock.LoginThrottleRepositoryMock)
+	service := NewUserService(mockRepo, nil, nil, mockThrottleRepo, new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, &gorm.DB{})
+
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	mockThrottleRepo.On("Find", &gorm.DB{}, domain.LoginThrottleScopeAccount, "john@example.com").Return(domain.LoginThrottle{ID: 1, LockedUntil: &lockedUntil}, nil)
+
+	_, err := service.Login("john@example.com", "password123", "203.0.113.7")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, 429, customErr.Code)
+	mockRepo.AssertNotCalled(t, "FindByEmail", testifymock.Anything, testifymock.Anything)
+}
+
+func TestUserService_Login_LockoutIsAudited(t *testing.T) {
+	mockRepo := new(mock.UserRepositoryMock)
+	mockThrottleRepo := new(mock.LoginThrottleRepositoryMock)
+	mockAuditLogRepo := new(mock.AuditLogRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewUserService(mockRepo, nil, nil, mockThrottleRepo, mockAuditLogRepo, nil, testLoginPolicy, db)
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correctpassword"), bcrypt.MinCost)
+	lastFailure := time.Now().Add(-time.Minute)
+
+	mockThrottleRepo.On("Find", testifymock.Anything, domain.LoginThrottleScopeAccount, "john@example.com").Return(domain.LoginThrottle{}, gorm.ErrRecordNotFound)
+	mockRepo.On("FindByEmail", testifymock.Anything, "john@example.com").Return(domain.User{ID: 1, Email: "john@example.com", Password: string(hashedPassword)}, nil)
+	sqlMock.ExpectBegin()
+	mockThrottleRepo.On("Lock", testifymock.Anything, domain.LoginThrottleScopeAccount, "john@example.com").Return(domain.LoginThrottle{ID: 1, Scope: domain.LoginThrottleScopeAccount, Key: "john@example.com", Failures: 9, LastFailureAt: &lastFailure}, nil)
+	mockThrottleRepo.On("Update", testifymock.Anything, testifymock.MatchedBy(func(throttle domain.LoginThrottle) bool {
+		return throttle.LockedUntil != nil && throttle.LockedUntil.Sub(*throttle.LastFailureAt) == 15*time.Minute
+	})).Return(nil)
+	mockAuditLogRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(auditLog domain.AuditLog) bool {
+		return auditLog.Action == domain.AuditActionLoginLockout && auditLog.UserID != nil && *auditLog.UserID == 1
+	})).Return(domain.AuditLog{ID: 1}, nil)
+	sqlMock.ExpectCommit()
+
+	_, err := service.Login("john@example.com", "wrongpassword", "")
+
+	assert.Error(t, err)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mockAuditLogRepo.AssertExpectations(t)
+}
+
+func TestUserService_Unlock_Success(t *testing.T) {
+	mockRepo := new(mock.UserRepositoryMock)
+	mockThrottleRepo := new(mock.LoginThrottleRepositoryMock)
+	mockAuditLogRepo := new(mock.AuditLogRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewUserService(mockRepo, nil, nil, mockThrottleRepo, mockAuditLogRepo, nil, testLoginPolicy, db)
+
+	mockRepo.On("FindById", testifymock.Anything, 2).Return(domain.User{ID: 2, Email: "Jane@Example.com"}, nil)
+	sqlMock.ExpectBegin()
+	mockThrottleRepo.On("Delete", testifymock.Anything, domain.LoginThrottleScopeAccount, "jane@example.com").Return(nil)
+	mockThrottleRepo.On("Delete", testifymock.Anything, domain.LoginThrottleScopeTwoFactor, "2").Return(nil)
+	mockAuditLogRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(auditLog domain.AuditLog) bool {
+		return auditLog.Action == domain.AuditActionLoginUnlock && *auditLog.UserID == 2 && *auditLog.ActorID == 1
+	})).Return(domain.AuditLog{ID: 1}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.Unlock(1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.ID)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mockThrottleRepo.AssertExpectations(t)
+	mockAuditLogRepo.AssertExpectations(t)
 }
 
 func TestUserService_GetById_Success(t *testing.T) {
 	mockRepo := new(mock.UserRepositoryMock)
-	service := NewUserService(mockRepo, &gorm.DB{})
+	service := NewUserService(mockRepo, nil, nil, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, &gorm.DB{})
 
 	expectedUser := domain.User{
 		ID:      1,
 		Name:    "John Doe",
 		Email:   "john@example.com",
-		Balance: 100.0,
+		Balance: domain.NewMoney(100),
 	}
 
 	mockRepo.On("FindById", &gorm.DB{}, 1).Return(expectedUser, nil)
@@ -159,7 +273,7 @@ func TestUserService_GetById_Success(t *testing.T) {
 
 func TestUserService_GetById_UserNotFound(t *testing.T) {
 	mockRepo := new(mock.UserRepositoryMock)
-	service := NewUserService(mockRepo, &gorm.DB{})
+	service := NewUserService(mockRepo, nil, nil, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, &gorm.DB{})
 
 	mockRepo.On("FindById", &gorm.DB{}, 999).Return(domain.User{}, gorm.ErrRecordNotFound)
 
@@ -172,3 +286,242 @@ func TestUserService_GetById_UserNotFound(t *testing.T) {
 	assert.Equal(t, "user not found", customErr.Message)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestUserService_UpdateRole_Success(t *testing.T) {
+	mockRepo := new(mock.UserRepositoryMock)
+	mockRefreshTokenRepo := new(mock.RefreshTokenRepositoryMock)
+	mockRevokedTokenRepo := new(mock.RevokedTokenRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	tokenService := NewTokenService(mockRefreshTokenRepo, mockRevokedTokenRepo, mockRepo, testTokenIssuer, helper.NewTokenDenylist(), 30*24*time.Hour, db)
+	service := NewUserService(mockRepo, nil, nil, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), tokenService, testLoginPolicy, db)
+
+	user := domain.User{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Role: domain.RoleStaff}
+	session := domain.RefreshToken{ID: 7, UserID: 2, FamilyID: "family", AccessTokenJTI: "staff-jti", AccessExpiresAt: time.Now().Add(10 * time.Minute)}
+
+	sqlMock.ExpectBegin()
+	mockRepo.On("FindById", testifymock.Anything, 2).Return(user, nil)
+	mockRepo.On("UpdateRole", testifymock.Anything, 2, domain.RoleGuest).Return(nil)
+	sqlMock.ExpectCommit()
+	sqlMock.ExpectBegin()
+	mockRefreshTokenRepo.On("FindLiveByUserId", testifymock.Anything, 2, testifymock.Anything).Return([]domain.RefreshToken{session}, nil)
+	mockRefreshTokenRepo.On("Revoke", testifymock.Anything, []int{7}, testifymock.Anything).Return(nil)
+	mockRevokedTokenRepo.On("Create", testifymock.Anything, testifymock.MatchedBy(func(revoked []domain.RevokedToken) bool {
+		return len(revoked) == 1 && revoked[0].JTI == "staff-jti"
+	})).Return(nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.UpdateRole(2, domain.RoleGuest)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoleGuest, result.Role)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mockRepo.AssertExpectations(t)
+	mockRefreshTokenRepo.AssertExpectations(t)
+	mockRevokedTokenRepo.AssertExpectations(t)
+}
+
+func TestUserService_UpdateRole_InvalidRole(t *testing.T) {
+	mockRepo := new(mock.UserRepositoryMock)
+	service := NewUserService(mockRepo, nil, nil, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, &gorm.DB{})
+
+	_, err := service.UpdateRole(2, "superuser")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "invalid role", customErr.Message)
+	mockRepo.AssertNotCalled(t, "UpdateRole")
+}
+
+func TestUserService_UpdateRole_LastAdmin(t *testing.T) {
+	mockRepo := new(mock.UserRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewUserService(mockRepo, nil, nil, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, db)
+
+	admin := domain.User{ID: 1, Name: "Admin", Email: "admin@example.com", Role: domain.RoleAdmin}
+
+	sqlMock.ExpectBegin()
+	mockRepo.On("FindById", testifymock.Anything, 1).Return(admin, nil)
+	mockRepo.On("LockByRole", testifymock.Anything, domain.RoleAdmin).Return(int64(1), nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.UpdateRole(1, domain.RoleGuest)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "cannot demote the last admin", customErr.Message)
+	mockRepo.AssertNotCalled(t, "UpdateRole")
+}
+
+func TestUserService_BootstrapAdmin_PromotesUser(t *testing.T) {
+	mockRepo := new(mock.UserRepositoryMock)
+	service := NewUserService(mockRepo, nil, nil, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, &gorm.DB{})
+
+	user := domain.User{ID: 1, Name: "John Doe", Email: "john@example.com", Role: domain.RoleGuest}
+
+	mockRepo.On("CountByRole", &gorm.DB{}, domain.RoleAdmin).Return(int64(0), nil)
+	mockRepo.On("FindByEmail", &gorm.DB{}, "john@example.com").Return(user, nil)
+	mockRepo.On("UpdateRole", &gorm.DB{}, 1, domain.RoleAdmin).Return(nil)
+
+	result, err := service.BootstrapAdmin("john@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoleAdmin, result.Role)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_BootstrapAdmin_AdminAlreadyExists(t *testing.T) {
+	mockRepo := new(mock.UserRepositoryMock)
+	service := NewUserService(mockRepo, nil, nil, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, &gorm.DB{})
+
+	mockRepo.On("CountByRole", &gorm.DB{}, domain.RoleAdmin).Return(int64(1), nil)
+
+	result, err := service.BootstrapAdmin("john@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.User{}, result)
+	mockRepo.AssertNotCalled(t, "FindByEmail")
+	mockRepo.AssertNotCalled(t, "UpdateRole")
+}
+
+func TestUserService_Delete_Success(t *testing.T) {
+	mockRepo := new(mock.UserRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	mockRefreshTokenRepo := new(mock.RefreshTokenRepositoryMock)
+	mockRevokedTokenRepo := new(mock.RevokedTokenRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	tokenService := NewTokenService(mockRefreshTokenRepo, mockRevokedTokenRepo, mockRepo, testTokenIssuer, helper.NewTokenDenylist(), 30*24*time.Hour, db)
+	service := NewUserService(mockRepo, mockBookRoomRepo, mockTopupRepo, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), tokenService, testLoginPolicy, db)
+
+	user := domain.User{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Role: domain.RoleGuest}
+	session := domain.RefreshToken{ID: 7, UserID: 2, FamilyID: "family", AccessTokenJTI: "guest-jti", AccessExpiresAt: time.Now().Add(10 * time.Minute)}
+
+	sqlMock.ExpectBegin()
+	mockRepo.On("LockById", testifymock.Anything, 2).Return(user, nil)
+	mockBookRoomRepo.On("CountActiveByUserId", testifymock.Anything, 2).Return(int64(0), nil)
+	mockTopupRepo.On("CountPendingByUserId", testifymock.Anything, 2).Return(int64(0), nil)
+	mockRepo.On("Delete", testifymock.Anything, 2).Return(nil)
+	sqlMock.ExpectCommit()
+	sqlMock.ExpectBegin()
+	mockRefreshTokenRepo.On("FindLiveByUserId", testifymock.Anything, 2, testifymock.Anything).Return([]domain.RefreshToken{session}, nil)
+	mockRefreshTokenRepo.On("Revoke", testifymock.Anything, []int{7}, testifymock.Anything).Return(nil)
+	mockRevokedTokenRepo.On("Create", testifymock.Anything, testifymock.Anything).Return(nil)
+	sqlMock.ExpectCommit()
+
+	err := service.Delete(2)
+
+	assert.NoError(t, err)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	mockRepo.AssertExpectations(t)
+	mockRefreshTokenRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "LockByRole", testifymock.Anything, testifymock.Anything)
+}
+
+func TestUserService_Delete_PositiveBalance(t *testing.T) {
+	mockRepo := new(mock.UserRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewUserService(mockRepo, mockBookRoomRepo, mockTopupRepo, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, db)
+
+	user := domain.User{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Role: domain.RoleGuest, Balance: domain.NewMoney(50000)}
+
+	sqlMock.ExpectBegin()
+	mockRepo.On("LockById", testifymock.Anything, 2).Return(user, nil)
+	sqlMock.ExpectRollback()
+
+	err := service.Delete(2)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, 409, customErr.Code)
+	mockBookRoomRepo.AssertNotCalled(t, "CountActiveByUserId", testifymock.Anything, testifymock.Anything)
+	mockRepo.AssertNotCalled(t, "Delete", testifymock.Anything, testifymock.Anything)
+}
+
+func TestUserService_Delete_UpcomingBookings(t *testing.T) {
+	mockRepo := new(mock.UserRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewUserService(mockRepo, mockBookRoomRepo, mockTopupRepo, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, db)
+
+	user := domain.User{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Role: domain.RoleGuest}
+
+	sqlMock.ExpectBegin()
+	mockRepo.On("LockById", testifymock.Anything, 2).Return(user, nil)
+	mockBookRoomRepo.On("CountActiveByUserId", testifymock.Anything, 2).Return(int64(1), nil)
+	sqlMock.ExpectRollback()
+
+	err := service.Delete(2)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "cannot delete a user with upcoming bookings", customErr.Message)
+	mockRepo.AssertNotCalled(t, "Delete", testifymock.Anything, testifymock.Anything)
+}
+
+func TestUserService_Delete_LastAdmin(t *testing.T) {
+	mockRepo := new(mock.UserRepositoryMock)
+	mockBookRoomRepo := new(mock.BookRoomRepositoryMock)
+	mockTopupRepo := new(mock.TopupRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewUserService(mockRepo, mockBookRoomRepo, mockTopupRepo, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, db)
+
+	admin := domain.User{ID: 1, Name: "Admin", Email: "admin@example.com", Role: domain.RoleAdmin}
+
+	sqlMock.ExpectBegin()
+	mockRepo.On("LockById", testifymock.Anything, 1).Return(admin, nil)
+	mockBookRoomRepo.On("CountActiveByUserId", testifymock.Anything, 1).Return(int64(0), nil)
+	mockTopupRepo.On("CountPendingByUserId", testifymock.Anything, 1).Return(int64(0), nil)
+	mockRepo.On("LockByRole", testifymock.Anything, domain.RoleAdmin).Return(int64(1), nil)
+	sqlMock.ExpectRollback()
+
+	err := service.Delete(1)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "cannot delete the last admin", customErr.Message)
+	mockRepo.AssertNotCalled(t, "Delete", testifymock.Anything, testifymock.Anything)
+}
+
+func TestUserService_Restore_Success(t *testing.T) {
+	mockRepo := new(mock.UserRepositoryMock)
+	service := NewUserService(mockRepo, nil, nil, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, &gorm.DB{})
+
+	user := domain.User{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Role: domain.RoleGuest, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}
+
+	mockRepo.On("FindDeletedById", &gorm.DB{}, 2).Return(user, nil)
+	mockRepo.On("FindByEmail", &gorm.DB{}, "jane@example.com").Return(domain.User{}, gorm.ErrRecordNotFound)
+	mockRepo.On("Restore", &gorm.DB{}, 2).Return(nil)
+
+	result, err := service.Restore(2)
+
+	assert.NoError(t, err)
+	assert.False(t, result.DeletedAt.Valid)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_Restore_EmailTaken(t *testing.T) {
+	mockRepo := new(mock.UserRepositoryMock)
+	service := NewUserService(mockRepo, nil, nil, new(mock.LoginThrottleRepositoryMock), new(mock.AuditLogRepositoryMock), nil, testLoginPolicy, &gorm.DB{})
+
+	user := domain.User{ID: 2, Name: "Jane Doe", Email: "jane@example.com", Role: domain.RoleGuest, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}
+
+	mockRepo.On("FindDeletedById", &gorm.DB{}, 2).Return(user, nil)
+	mockRepo.On("FindByEmail", &gorm.DB{}, "jane@example.com").Return(domain.User{ID: 5, Email: "jane@example.com"}, nil)
+
+	_, err := service.Restore(2)
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, 409, customErr.Code)
+	assert.Equal(t, "email already exists", customErr.Message)
+	mockRepo.AssertNotCalled(t, "Restore", testifymock.Anything, testifymock.Anything)
+}
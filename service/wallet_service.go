@@ -0,0 +1,83 @@
+package service
+
+import (
+	"errors"
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type WalletService interface {
+	FindTransactions(userId int, pagination domain.Pagination) ([]domain.WalletTransaction, int64, error)
+	Adjust(userId int, amount domain.Money, description string) (domain.WalletTransaction, error)
+	FindUnreconciled() ([]domain.WalletReconciliation, error)
+}
+
+type walletServiceImpl struct {
+	WalletTransactionRepository repository.WalletTransactionRepository
+	UserRepository              repository.UserRepository
+	DB                          *gorm.DB
+}
+
+func NewWalletService(walletTransactionRepository repository.WalletTransactionRepository, userRepository repository.UserRepository, db *gorm.DB) WalletService {
+	return &walletServiceImpl{
+		WalletTransactionRepository: walletTransactionRepository,
+		UserRepository:              userRepository,
+		DB:                          db,
+	}
+}
+
+func (service *walletServiceImpl) FindTransactions(userId int, pagination domain.Pagination) ([]domain.WalletTransaction, int64, error) {
+	return service.WalletTransactionRepository.FindByUserId(service.DB, userId, pagination)
+}
+
+// Adjust posts a manual correction to a user's wallet. Positive amounts
+// credit the wallet and negative amounts debit it.
+func (service *walletServiceImpl) Adjust(userId int, amount domain.Money, description string) (domain.WalletTransaction, error) {
+	var result domain.WalletTransaction
+
+	err := service.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := service.UserRepository.FindById(tx, userId)
+		if err != nil {
+			return exception.NewCustomError(http.StatusNotFound, "user not found")
+		}
+
+		if user.Balance+amount < 0 {
+			return exception.NewCustomError(http.StatusBadRequest, "adjustment would make the balance negative")
+		}
+
+		transaction := domain.WalletTransaction{
+			UserID:        userId,
+			Type:          domain.WalletTransactionAdjustment,
+			Amount:        amount,
+			ContraAccount: domain.LedgerAccountAdjustments,
+			Description:   description,
+		}
+		if amount < 0 {
+			result, err = service.WalletTransactionRepository.Debit(tx, transaction)
+		} else {
+			result, err = service.WalletTransactionRepository.Create(tx, transaction)
+		}
+		if errors.Is(err, repository.ErrInsufficientBalance) {
+			return exception.NewCustomError(http.StatusBadRequest, "adjustment would make the balance negative")
+		}
+		if err != nil {
+			return exception.NewCustomError(http.StatusInternalServerError, "failed to record adjustment")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return domain.WalletTransaction{}, err
+	}
+
+	return result, nil
+}
+
+func (service *walletServiceImpl) FindUnreconciled() ([]domain.WalletReconciliation, error) {
+	return service.WalletTransactionRepository.FindUnreconciled(service.DB)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"hotel_ip-p2/exception"
+	"hotel_ip-p2/model/domain"
+	"hotel_ip-p2/repository/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestWalletService_FindTransactions_Success(t *testing.T) {
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	service := NewWalletService(mockWalletRepo, mockUserRepo, &gorm.DB{})
+
+	pagination := domain.NewPagination(2, 10)
+	referenceID := 7
+	expectedTransactions := []domain.WalletTransaction{
+		{ID: 12, UserID: 1, Type: domain.WalletTransactionBookingDebit, Amount: domain.NewMoney(-500000), BalanceAfter: domain.NewMoney(100000), ReferenceType: domain.ReferenceTypeBookRoom, ReferenceID: &referenceID},
+		{ID: 11, UserID: 1, Type: domain.WalletTransactionTopupCredit, Amount: domain.NewMoney(600000), BalanceAfter: domain.NewMoney(600000)},
+	}
+
+	mockWalletRepo.On("FindByUserId", &gorm.DB{}, 1, pagination).Return(expectedTransactions, int64(12), nil)
+
+	result, total, err := service.FindTransactions(1, pagination)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, int64(12), total)
+	assert.Equal(t, 12, result[0].ID)
+	mockWalletRepo.AssertExpectations(t)
+}
+
+func TestWalletService_Adjust_Success(t *testing.T) {
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewWalletService(mockWalletRepo, mockUserRepo, db)
+
+	sqlMock.ExpectBegin()
+	mockUserRepo.On("FindById", testifymock.Anything, 1).Return(domain.User{ID: 1, Balance: domain.NewMoney(100000)}, nil)
+	mockWalletRepo.On("Debit", testifymock.Anything, testifymock.MatchedBy(func(w domain.WalletTransaction) bool {
+		return w.UserID == 1 && w.Type == domain.WalletTransactionAdjustment && w.Amount == domain.NewMoney(-40000) && w.ContraAccount == domain.LedgerAccountAdjustments
+	})).Return(domain.WalletTransaction{ID: 3, UserID: 1, Amount: domain.NewMoney(-40000), BalanceAfter: domain.NewMoney(60000)}, nil)
+	sqlMock.ExpectCommit()
+
+	result, err := service.Adjust(1, domain.NewMoney(-40000), "Goodwill correction")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(60000), result.BalanceAfter)
+	mockWalletRepo.AssertExpectations(t)
+}
+
+func TestWalletService_Adjust_NegativeBalance(t *testing.T) {
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	db, sqlMock, _ := setupMockDB()
+	service := NewWalletService(mockWalletRepo, mockUserRepo, db)
+
+	sqlMock.ExpectBegin()
+	mockUserRepo.On("FindById", testifymock.Anything, 1).Return(domain.User{ID: 1, Balance: domain.NewMoney(10000)}, nil)
+	sqlMock.ExpectRollback()
+
+	_, err := service.Adjust(1, domain.NewMoney(-40000), "Correction")
+
+	assert.Error(t, err)
+	customErr, ok := err.(*exception.CustomError)
+	assert.True(t, ok)
+	assert.Equal(t, "adjustment would make the balance negative", customErr.Message)
+	mockWalletRepo.AssertNotCalled(t, "Debit", testifymock.Anything, testifymock.Anything)
+}
+
+func TestWalletService_FindUnreconciled_Success(t *testing.T) {
+	mockWalletRepo := new(mock.WalletTransactionRepositoryMock)
+	mockUserRepo := new(mock.UserRepositoryMock)
+	service := NewWalletService(mockWalletRepo, mockUserRepo, &gorm.DB{})
+
+	expected := []domain.WalletReconciliation{
+		{UserID: 4, Balance: domain.NewMoney(100000), LedgerBalance: domain.NewMoney(90000)},
+	}
+
+	mockWalletRepo.On("FindUnreconciled", &gorm.DB{}).Return(expected, nil)
+
+	result, err := service.FindUnreconciled()
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	mockWalletRepo.AssertExpectations(t)
+}